@@ -0,0 +1,64 @@
+const processBatch = require('@app/services/payment-instructions/process-batch');
+const createMemoryDailyDebitStore = require('@app/services/payment-instructions/stores/create-memory-daily-debit-store');
+
+// Items-mode batch timed at several pool sizes. The daily debit store answers after 1ms, the
+// way a networked store would, which is where concurrency pays off: parsing itself still runs
+// on the one event loop.
+//   node bench/process-batch [items] [rounds]
+
+const ITEMS = Number(process.argv[2]) || 500;
+const ROUNDS = Number(process.argv[3]) || 5;
+
+function createNetworkedDailyDebitStore() {
+  const store = createMemoryDailyDebitStore();
+  const roundTrip = () => new Promise((resolve) => setTimeout(resolve, 1));
+  return {
+    async getTotal(accountId, day) {
+      await roundTrip();
+      return store.getTotal(accountId, day);
+    },
+    async add(accountId, day, amount) {
+      await roundTrip();
+      return store.add(accountId, day, amount);
+    },
+  };
+}
+
+function makeItems() {
+  const items = [];
+  for (let i = 0; i < ITEMS; i++) {
+    items.push({
+      accounts: [
+        { id: `acc${i % 50}`, balance: 100000, currency: 'NGN', daily_limit: 50000 },
+        { id: `dest${i}`, balance: 0, currency: 'NGN' },
+      ],
+      instruction: `DEBIT ${100 + i} NGN FROM ACCOUNT acc${i % 50} FOR CREDIT TO ACCOUNT dest${i}`,
+    });
+  }
+  return items;
+}
+
+async function time(concurrency) {
+  let total = 0;
+  for (let round = 0; round < ROUNDS; round++) {
+    const start = process.hrtime.bigint();
+    // eslint-disable-next-line no-await-in-loop
+    await processBatch(
+      { items: makeItems() },
+      { concurrency, dailyDebitStore: createNetworkedDailyDebitStore() }
+    );
+    total += Number(process.hrtime.bigint() - start) / 1e6;
+  }
+  return total / ROUNDS;
+}
+
+async function main() {
+  const sizes = [1, 4, 16, 64];
+  for (let i = 0; i < sizes.length; i++) {
+    // eslint-disable-next-line no-await-in-loop
+    const ms = await time(sizes[i]);
+    process.stdout.write(`concurrency ${sizes[i]}: ${ms.toFixed(1)} ms per ${ITEMS} items\n`);
+  }
+}
+
+main();
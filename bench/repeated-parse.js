@@ -0,0 +1,48 @@
+const paymentInstructions = require('@app/services/payment-instructions/payment-instructions');
+
+// The first parse of each instruction shape against the mean of the next ones. The parser has no
+// regular expressions to compile and builds its lookup tables once at load, so after the first
+// call (which also pays for lazy JIT work) every call costs the same.
+//   node bench/repeated-parse [rounds]
+
+const ROUNDS = Number(process.argv[2]) || 2000;
+
+const INSTRUCTIONS = [
+  'DEBIT 500 USD FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc2',
+  'DEBIT 1.5k USD FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc2',
+  'DEBIT two hundred and fifty USD FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc2',
+  'SPLIT 900 USD FROM acc1 EQUALLY BETWEEN acc2 AND acc3',
+  'hello there',
+];
+
+function makeAccounts() {
+  return [
+    { id: 'acc1', balance: 5000, currency: 'USD' },
+    { id: 'acc2', balance: 0, currency: 'USD' },
+    { id: 'acc3', balance: 0, currency: 'USD' },
+  ];
+}
+
+async function timeOne(instruction) {
+  const start = process.hrtime.bigint();
+  await paymentInstructions({ accounts: makeAccounts(), instruction });
+  return Number(process.hrtime.bigint() - start) / 1e3;
+}
+
+async function main() {
+  for (let i = 0; i < INSTRUCTIONS.length; i++) {
+    // eslint-disable-next-line no-await-in-loop
+    const first = await timeOne(INSTRUCTIONS[i]);
+    let total = 0;
+    for (let round = 0; round < ROUNDS; round++) {
+      // eslint-disable-next-line no-await-in-loop
+      total += await timeOne(INSTRUCTIONS[i]);
+    }
+    const mean = total / ROUNDS;
+    process.stdout.write(
+      `${INSTRUCTIONS[i]}\n  first ${first.toFixed(1)} us, then ${mean.toFixed(1)} us per parse\n`
+    );
+  }
+}
+
+main();
@@ -1,7 +1,7 @@
 // @ts-check
 
 /**
- * @typedef {200|201|204|400|401|403|500} HttpStatusCodeNumber
+ * @typedef {200|201|204|400|401|403|422|500} HttpStatusCodeNumber
  */
 
 /**
@@ -12,6 +12,7 @@
  * @property {HttpStatusCodeNumber} HTTP_400_BAD_REQUEST - HTTP 400 Bad request
  * @property {HttpStatusCodeNumber} HTTP_401_UNAUTHORIZED - HTTP 401 Unauthorized
  * @property {HttpStatusCodeNumber} HTTP_403_FORBIDDEN - HTTP 403 Forbidden
+ * @property {HttpStatusCodeNumber} HTTP_422_UNPROCESSABLE_ENTITY - HTTP 422 Unprocessable Entity
  */
 
 /**
@@ -38,6 +39,9 @@ const HTTPStatusCode = {
   /** HTTP 403 Forbidden */
   /** @type HttpStatusCodeNumber */
   HTTP_403_FORBIDDEN: 403,
+  /** HTTP 422 Unprocessable Entity */
+  /** @type HttpStatusCodeNumber */
+  HTTP_422_UNPROCESSABLE_ENTITY: 422,
   /** HTTP 500 Server Error */
   /** @type HttpStatusCodeNumber */
   HTTP_500_SERVER_ERROR: 500,
@@ -0,0 +1,47 @@
+const { createHandler } = require('@app-core/server');
+const { appLogger } = require('@app-core/logger');
+const { getHttpStatus } = require('@app/messages/payment-instruction-status-codes');
+const processReversalService = require('@app/services/payment-instructions/process-reversal');
+
+module.exports = createHandler({
+  path: '/payment-instructions/reversal',
+  method: 'post',
+  middlewares: [], // No authentication
+
+  async onResponseEnd(rc, rs) {
+    appLogger.info(
+      {
+        requestContext: rc,
+        response: rs,
+      },
+      'payment-instructions-reversal-request-completed'
+    );
+  },
+
+  async handler(rc, helpers) {
+    try {
+      const serviceResponse = await processReversalService(rc.body);
+
+      // Reversed (or already reversed) → HTTP 200; an unknown transaction or funds no
+      // longer available → HTTP 422
+      return {
+        status: getHttpStatus(serviceResponse.status_code),
+        data: serviceResponse,
+      };
+    } catch (err) {
+      // Malformed requests are shaped application errors (HTTP 400); anything else is hidden
+      if (err && err.isApplicationError) {
+        throw err;
+      }
+
+      return {
+        status: helpers.http_statuses.HTTP_500_SERVER_ERROR,
+        data: {
+          status: 'failed',
+          status_reason: 'Internal server error',
+          status_code: 'INTERNAL',
+        },
+      };
+    }
+  },
+});
@@ -0,0 +1,32 @@
+const { createHandler } = require('@app-core/server');
+const { appLogger } = require('@app-core/logger');
+const processBatchService = require('@app/services/payment-instructions/process-batch');
+
+module.exports = createHandler({
+  path: '/payment-instructions/batch',
+  method: 'post',
+  middlewares: [], // No authentication
+
+  async onResponseEnd(rc, rs) {
+    appLogger.info(
+      {
+        requestContext: rc,
+        response: rs,
+      },
+      'payment-instructions-batch-request-completed'
+    );
+  },
+
+  async handler(rc, helpers) {
+    // Items never fail the request as a whole: each result carries its own status and
+    // status_code. Malformed batch payloads are thrown and mapped by the server (HTTP 400).
+    // BATCH_CONCURRENCY sets how many independent items run at once (default 1).
+    const concurrency = Number(process.env.BATCH_CONCURRENCY) || 1;
+    const serviceResponse = await processBatchService(rc.body, { concurrency });
+
+    return {
+      status: helpers.http_statuses.HTTP_200_OK,
+      data: serviceResponse,
+    };
+  },
+});
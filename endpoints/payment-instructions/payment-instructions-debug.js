@@ -0,0 +1,31 @@
+const { createHandler } = require('@app-core/server');
+const { appLogger } = require('@app-core/logger');
+const debugInstructionService = require('@app/services/payment-instructions/debug-instruction');
+
+module.exports = createHandler({
+  path: '/payment-instructions/debug',
+  method: 'post',
+  middlewares: [], // No authentication
+
+  async onResponseEnd(rc, rs) {
+    appLogger.info(
+      {
+        requestContext: rc,
+        response: rs,
+      },
+      'payment-instructions-debug-request-completed'
+    );
+  },
+
+  async handler(rc, helpers) {
+    // Explains how the instruction is read; nothing is executed, so an instruction that
+    // does not parse is still HTTP 200 with the failure in data.outcome. Malformed payloads
+    // are thrown and mapped by the server (HTTP 400).
+    const serviceResponse = await debugInstructionService(rc.body);
+
+    return {
+      status: helpers.http_statuses.HTTP_200_OK,
+      data: serviceResponse,
+    };
+  },
+});
@@ -1,6 +1,7 @@
 const { createHandler } = require('@app-core/server');
 const { appLogger } = require('@app-core/logger');
-const paymentInstructionsService = require('@app/services/payment-instructions/payment-instructions');
+const { getHttpStatus } = require('@app/messages/payment-instruction-status-codes');
+const paymentInstructionsService = require('@app/services/payment-instructions/process-idempotent-instruction');
 
 module.exports = createHandler({
   path: '/payment-instructions',
@@ -18,33 +19,28 @@ module.exports = createHandler({
   },
 
   async handler(rc, helpers) {
-    const payload = rc.body;
+    // An Idempotency-Key header takes the place of body.idempotency_key
+    const idempotencyKey = rc.headers && rc.headers['idempotency-key'];
+    const payload = idempotencyKey ? { ...rc.body, idempotency_key: idempotencyKey } : rc.body;
+    // ?dry_run=true previews the instruction without moving money (same as body.dry_run)
+    const dryRun = rc.query && rc.query.dry_run === 'true';
 
     try {
-      const serviceResponse = await paymentInstructionsService(payload);
+      const serviceResponse = await paymentInstructionsService(payload, { dryRun });
 
-      // Successful or pending transactions → HTTP 200
-      if (serviceResponse.status === 'successful' || serviceResponse.status === 'pending') {
-        return {
-          status: helpers.http_statuses.HTTP_200_OK,
-          data: serviceResponse,
-        };
-      }
-
-      // All failed responses → HTTP 400
+      // Successful or pending transactions → HTTP 200; failures by status code: an
+      // instruction that cannot be read → HTTP 400, one the accounts or rules refuse → 422
       return {
-        status: helpers.http_statuses.HTTP_400_BAD_REQUEST,
+        status: getHttpStatus(serviceResponse.status_code),
         data: serviceResponse,
       };
     } catch (err) {
-      // If the service throws a throwAppError, it is shaped already.
+      // If the service throws a throwAppError, it is shaped already: let the server map its
+      // error code to the HTTP status (400 validation, 409 reused idempotency key).
       // If it's an unexpected error, convert to safe response.
 
-      if (err && err.isAppError) {
-        return {
-          status: helpers.http_statuses.HTTP_400_BAD_REQUEST,
-          data: err.toResponseJSON(),
-        };
+      if (err && err.isApplicationError) {
+        throw err;
       }
 
       // Unexpected technical error (never leak stack traces)
@@ -0,0 +1,275 @@
+const PaymentMessages = require('./payment-instructions');
+
+/**
+ * Every status_code a payment instruction response can carry, keyed by itself so callers
+ * can write StatusCodes.AC01 instead of the bare string.
+ *
+ * @typedef {keyof typeof StatusCodes} StatusCode
+ */
+const StatusCodes = Object.freeze({
+  // Success
+  AP00: 'AP00', // executed
+  AP02: 'AP02', // scheduled for later execution
+
+  // Syntax / parsing
+  SY01: 'SY01',
+  SY02: 'SY02',
+  SY03: 'SY03',
+  SY04: 'SY04',
+  SY05: 'SY05',
+  SY06: 'SY06', // strict mode refused a guess
+
+  // Amount
+  AM01: 'AM01',
+  AM02: 'AM02',
+  AM03: 'AM03',
+  AM04: 'AM04', // a range or an estimate
+
+  // Currency
+  CU01: 'CU01',
+  CU02: 'CU02',
+  CU03: 'CU03',
+  CU04: 'CU04', // not an ISO 4217 code
+  CU05: 'CU05',
+  CU06: 'CU06',
+
+  // Accounts
+  AC01: 'AC01',
+  AC02: 'AC02',
+  AC03: 'AC03',
+  AC04: 'AC04',
+  AC05: 'AC05',
+  AC06: 'AC06',
+  AC07: 'AC07', // duplicate account id in the request
+  AC08: 'AC08', // account is frozen
+  AC09: 'AC09', // account is closed
+  AC10: 'AC10', // starting balance below what the negative balance policy allows
+  AC11: 'AC11', // wallet holds no balance in the instruction currency
+
+  // Business rules
+  BL01: 'BL01', // fee account cannot cover the fee
+  BL02: 'BL02',
+  BL03: 'BL03', // partially executed (successful)
+  LM01: 'LM01',
+  LM02: 'LM02',
+
+  // Conditions
+  CD01: 'CD01', // the instruction's balance guard was not met
+
+  // Dates / scheduling
+  DT01: 'DT01',
+  DT02: 'DT02',
+  DT03: 'DT03',
+  DT04: 'DT04',
+
+  // Reversal
+  RV01: 'RV01',
+
+  // Batch
+  DUP01: 'DUP01', // repeats an earlier instruction (reject_duplicates)
+
+  // Cancellation (status "cancelled": options.signal aborted before the instruction ran)
+  CANCELLED: 'CANCELLED',
+});
+
+// Default human message per code. Where a code has several more specific reasons (AM01
+// covers non-numeric and zero amounts) this is the general one.
+const StatusMessages = Object.freeze({
+  AP00: PaymentMessages.TRANSACTION_EXECUTED,
+  AP02: PaymentMessages.TRANSACTION_SCHEDULED,
+  SY01: PaymentMessages.MISSING_REQUIRED_KEYWORD,
+  SY02: PaymentMessages.INVALID_KEYWORD_ORDER,
+  SY03: PaymentMessages.MALFORMED_INSTRUCTION,
+  SY04: PaymentMessages.INSTRUCTION_TOO_LONG,
+  SY05: PaymentMessages.INSTRUCTION_EMPTY,
+  SY06: PaymentMessages.STRICT_MODE_GUESS,
+  AM01: PaymentMessages.AMOUNT_MUST_BE_POSITIVE_NUMBER,
+  AM02: PaymentMessages.SPLIT_AMOUNTS_MISMATCH,
+  AM03: PaymentMessages.AMOUNT_MUST_NOT_BE_NEGATIVE,
+  AM04: PaymentMessages.AMBIGUOUS_AMOUNT,
+  CU01: PaymentMessages.ACCOUNT_CURRENCY_MISMATCH,
+  CU02: PaymentMessages.UNSUPPORTED_CURRENCY,
+  CU03: PaymentMessages.AMOUNT_TOO_MANY_DECIMALS,
+  CU04: PaymentMessages.UNKNOWN_CURRENCY_CODE,
+  CU05: PaymentMessages.EXCHANGE_RATE_UNAVAILABLE,
+  CU06: PaymentMessages.AMBIGUOUS_CURRENCY_SYMBOL,
+  AC01: PaymentMessages.INSUFFICIENT_FUNDS,
+  AC02: PaymentMessages.DEBIT_CREDIT_SAME_ACCOUNT,
+  AC03: PaymentMessages.ACCOUNT_NOT_FOUND,
+  AC04: PaymentMessages.INVALID_ACCOUNT_ID_FORMAT,
+  AC05: PaymentMessages.AMBIGUOUS_ACCOUNT_ALIAS,
+  AC06: PaymentMessages.AMBIGUOUS_ACCOUNT_REFERENCE,
+  AC07: PaymentMessages.DUPLICATE_ACCOUNT_ID,
+  AC08: PaymentMessages.ACCOUNT_FROZEN,
+  AC09: PaymentMessages.ACCOUNT_CLOSED,
+  AC10: PaymentMessages.NEGATIVE_BALANCE,
+  AC11: PaymentMessages.WALLET_POCKET_MISSING,
+  BL01: PaymentMessages.FEE_ACCOUNT_INSUFFICIENT_FUNDS,
+  BL02: PaymentMessages.MINIMUM_BALANCE_BREACH,
+  BL03: PaymentMessages.PARTIALLY_EXECUTED,
+  LM01: PaymentMessages.DAILY_LIMIT_EXCEEDED,
+  LM02: PaymentMessages.AMOUNT_EXCEEDS_TRANSACTION_LIMIT,
+  CD01: PaymentMessages.CONDITION_NOT_MET,
+  DT01: PaymentMessages.INVALID_DATE_FORMAT,
+  DT02: PaymentMessages.SCHEDULE_DATE_IN_PAST,
+  DT03: PaymentMessages.INVALID_RECURRENCE,
+  DT04: PaymentMessages.INVALID_TIMEZONE,
+  RV01: PaymentMessages.TRANSACTION_NOT_FOUND,
+  DUP01: PaymentMessages.DUPLICATE_INSTRUCTION,
+  CANCELLED: PaymentMessages.INSTRUCTION_CANCELLED,
+});
+
+/**
+ * What kind of outcome a status code reports, for grouping results without reading code
+ * prefixes. Failed results carry theirs as reason_category.
+ *
+ * @typedef {typeof ReasonCategories[keyof typeof ReasonCategories]} ReasonCategory
+ */
+const ReasonCategories = Object.freeze({
+  SUCCESS: 'success',
+  PARSE_ERROR: 'parse_error',
+  VALIDATION: 'validation',
+  FUNDS: 'funds',
+  LIMITS: 'limits',
+  ACCOUNT: 'account',
+  CANCELLED: 'cancelled',
+});
+
+// The category of every code; a new code goes here as well as in StatusCodes
+const StatusCategories = Object.freeze({
+  AP00: ReasonCategories.SUCCESS,
+  AP02: ReasonCategories.SUCCESS,
+  SY01: ReasonCategories.PARSE_ERROR,
+  SY02: ReasonCategories.PARSE_ERROR,
+  SY03: ReasonCategories.PARSE_ERROR,
+  SY04: ReasonCategories.PARSE_ERROR,
+  SY05: ReasonCategories.PARSE_ERROR,
+  SY06: ReasonCategories.PARSE_ERROR,
+  AM01: ReasonCategories.VALIDATION,
+  AM02: ReasonCategories.VALIDATION,
+  AM03: ReasonCategories.VALIDATION,
+  AM04: ReasonCategories.VALIDATION,
+  CU01: ReasonCategories.VALIDATION,
+  CU02: ReasonCategories.VALIDATION,
+  CU03: ReasonCategories.VALIDATION,
+  CU04: ReasonCategories.VALIDATION,
+  CU05: ReasonCategories.VALIDATION,
+  CU06: ReasonCategories.VALIDATION,
+  AC01: ReasonCategories.FUNDS,
+  AC02: ReasonCategories.ACCOUNT,
+  AC03: ReasonCategories.ACCOUNT,
+  AC04: ReasonCategories.ACCOUNT,
+  AC05: ReasonCategories.ACCOUNT,
+  AC06: ReasonCategories.ACCOUNT,
+  AC07: ReasonCategories.ACCOUNT,
+  AC08: ReasonCategories.ACCOUNT,
+  AC09: ReasonCategories.ACCOUNT,
+  AC10: ReasonCategories.ACCOUNT,
+  AC11: ReasonCategories.ACCOUNT,
+  BL01: ReasonCategories.FUNDS,
+  BL02: ReasonCategories.FUNDS,
+  BL03: ReasonCategories.SUCCESS,
+  LM01: ReasonCategories.LIMITS,
+  LM02: ReasonCategories.LIMITS,
+  CD01: ReasonCategories.FUNDS,
+  DT01: ReasonCategories.VALIDATION,
+  DT02: ReasonCategories.VALIDATION,
+  DT03: ReasonCategories.VALIDATION,
+  DT04: ReasonCategories.VALIDATION,
+  RV01: ReasonCategories.VALIDATION,
+  DUP01: ReasonCategories.VALIDATION,
+  CANCELLED: ReasonCategories.CANCELLED,
+});
+
+// HTTP status of a result with each code: 200 when it executed or was scheduled, 400 when
+// the instruction (or a request field it relies on) cannot be read, 422 when it reads but
+// the accounts or the rules refuse it. A new code goes here as well as in StatusCodes.
+const StatusHttpStatuses = Object.freeze({
+  AP00: 200,
+  AP02: 200,
+  SY01: 400,
+  SY02: 400,
+  SY03: 400,
+  SY04: 400,
+  SY05: 400,
+  SY06: 400,
+  AM01: 400,
+  AM02: 422,
+  AM03: 400,
+  AM04: 400,
+  CU01: 422,
+  CU02: 422,
+  CU03: 422,
+  CU04: 400,
+  CU05: 422,
+  CU06: 400,
+  AC01: 422,
+  AC02: 422,
+  AC03: 422,
+  AC04: 400,
+  AC05: 422,
+  AC06: 422,
+  AC07: 422,
+  AC08: 422,
+  AC09: 422,
+  AC10: 422,
+  AC11: 422,
+  BL01: 422,
+  BL02: 422,
+  BL03: 200,
+  LM01: 422,
+  LM02: 422,
+  CD01: 422,
+  DT01: 400,
+  DT02: 422,
+  DT03: 400,
+  DT04: 400,
+  RV01: 422,
+  DUP01: 422,
+  CANCELLED: 400,
+});
+
+/**
+ * Default message for a status code.
+ *
+ * @param {string} code
+ * @returns {string|null} null for an unknown code
+ */
+function getStatusMessage(code) {
+  return Object.prototype.hasOwnProperty.call(StatusMessages, code) ? StatusMessages[code] : null;
+}
+
+/**
+ * Category of a status code (see ReasonCategories).
+ *
+ * @param {string} code
+ * @returns {string|null} null for an unknown code
+ */
+function getReasonCategory(code) {
+  return Object.prototype.hasOwnProperty.call(StatusCategories, code)
+    ? StatusCategories[code]
+    : null;
+}
+
+/**
+ * HTTP status for a result with a status code (see StatusHttpStatuses).
+ *
+ * @param {string} code
+ * @returns {number} 400 for an unknown code
+ */
+function getHttpStatus(code) {
+  return Object.prototype.hasOwnProperty.call(StatusHttpStatuses, code)
+    ? StatusHttpStatuses[code]
+    : 400;
+}
+
+module.exports = {
+  StatusCodes,
+  StatusMessages,
+  ReasonCategories,
+  StatusCategories,
+  StatusHttpStatuses,
+  getStatusMessage,
+  getReasonCategory,
+  getHttpStatus,
+};
@@ -3,30 +3,160 @@ const PaymentInstructionsMessages = {
   MISSING_REQUIRED_KEYWORD: 'Missing required keyword in instruction', // SY01
   INVALID_KEYWORD_ORDER: 'Invalid keyword order in instruction', // SY02
   MALFORMED_INSTRUCTION: 'Malformed instruction: unable to parse keywords', // SY03
+  INVALID_SPLIT_RECIPIENTS:
+    'Invalid split recipients. Expected accounts separated by commas or "and", each with or without its own amount', // SY03
+  INVALID_DEBIT_SOURCES:
+    'Invalid debit accounts. Expected accounts separated by commas or "and", without amounts', // SY03
+  INSTRUCTION_TOO_LONG: 'Instruction is too long', // SY04
+  INSTRUCTION_EMPTY: 'Instruction is empty', // SY05
+  STRICT_MODE_GUESS: 'Strict mode: instruction must be literal', // SY06
 
   // Amount / Number validation
-  AMOUNT_MUST_BE_POSITIVE_INTEGER: 'Amount must be a positive integer', // AM01
+  AMOUNT_MUST_BE_POSITIVE_NUMBER: 'Amount must be a positive number', // AM01
+  AMOUNT_MUST_BE_POSITIVE: 'Amount must be greater than zero', // AM01
+  AMOUNT_MUST_NOT_BE_NEGATIVE: 'Amount cannot be negative', // AM03
+  AMBIGUOUS_AMOUNT: 'Ambiguous amount; give one exact figure', // AM04
+  SPLIT_AMOUNTS_MISMATCH: 'Split amounts must add up to the total amount', // AM02
+  SPLIT_PERCENTAGES_MISMATCH: 'Split percentages must add up to 100', // AM02
+  INVALID_SPLIT_SHARE:
+    'Split shares must be positive percentages with up to two decimals or whole numbers of parts', // AM01
 
   // Currency validation
-  UNSUPPORTED_CURRENCY: 'Unsupported currency. Only NGN, USD, GBP, and GHS are supported', // CU02
+  UNKNOWN_CURRENCY_CODE: 'Unknown currency code: not an ISO 4217 code', // CU04
+  UNSUPPORTED_CURRENCY:
+    'Unsupported currency. Only NGN, USD, GBP, GHS, KES, ZAR, EUR, UGX, JPY, and KWD are supported', // CU02
   ACCOUNT_CURRENCY_MISMATCH: 'Account currency mismatch', // CU01
+  INSTRUCTION_CURRENCY_NOT_HELD: 'Instruction currency does not match the accounts', // CU02
+  CURRENCY_NOT_GIVEN: 'Instruction names no currency and the accounts hold different ones', // CU02
+  AMOUNT_TOO_MANY_DECIMALS: 'Amount has more decimal places than the currency allows', // CU03
+  AMBIGUOUS_CURRENCY_SYMBOL: 'Ambiguous currency symbol', // CU06
+  EXCHANGE_RATE_UNAVAILABLE: 'No exchange rate available', // CU05
+  EXCHANGE_RATE_APPLIED: 'exchange rate applied',
 
   // Account validation
   ACCOUNT_NOT_FOUND: 'Account not found', // AC03
+  KNOWN_ACCOUNT_NAMES: 'Known account names',
+  AMBIGUOUS_ACCOUNT_ALIAS: 'Ambiguous account name', // AC05
+  AMBIGUOUS_ACCOUNT_CASE: 'Account ids differ only by case', // AC05
+  AMBIGUOUS_ACCOUNT_REFERENCE: 'More than one account ends with these digits', // AC06
   INVALID_ACCOUNT_ID_FORMAT:
     'Invalid account ID format. Allowed characters: letters, numbers, hyphen (-), dot (.), at (@).', // AC04
+  MALFORMED_ACCOUNT_REFERENCE: 'Malformed account reference, not in the account id format', // AC04
+  INVALID_ACCOUNT_ID_FORMAT_OPTION: 'Account id format must be a predicate or a format object',
   DEBIT_CREDIT_SAME_ACCOUNT: 'Debit and credit accounts cannot be the same', // AC02
+  DUPLICATE_ACCOUNT_ID: 'More than one account has this id', // AC07
+  ACCOUNT_FROZEN: 'Account is frozen', // AC08
+  ACCOUNT_CLOSED: 'Account is closed', // AC09
+  NEGATIVE_BALANCE: 'Invalid negative balance', // AC10
+  WALLET_POCKET_MISSING: 'No wallet pocket in the instruction currency', // AC11
+  DUPLICATE_SPLIT_RECIPIENT: 'Split recipients must be different accounts', // AC02
+  DUPLICATE_DEBIT_SOURCE: 'Debit accounts must be different accounts', // AC02
 
   // Funds / business rules
   INSUFFICIENT_FUNDS: 'Insufficient funds in debit account', // AC01
+  INSUFFICIENT_COMBINED_FUNDS: 'Insufficient funds across debit accounts', // AC01
+  FEE_ACCOUNT_INSUFFICIENT_FUNDS: 'Insufficient funds in fee account', // BL01
+  OVERDRAFT: 'overdraft',
+  FEE: 'fee',
+  ACCOUNT_OVERDRAWN: 'debit account overdrawn', // AP00
+  MINIMUM_BALANCE_BREACH: 'Debit would take the account below its minimum balance', // BL02
+  DAILY_LIMIT_EXCEEDED: 'Daily debit limit exceeded for debit account', // LM01
+  AMOUNT_EXCEEDS_TRANSACTION_LIMIT: 'Amount exceeds per-transaction limit', // LM02
+  PARTIALLY_EXECUTED: 'Transaction partially executed', // BL03
+  CONDITION_NOT_MET: 'Condition not met', // CD01
+  MALFORMED_CONDITION:
+    'Malformed condition. Expected only if balance above, below or at least <amount>', // SY03
+  UNREAD_CLAUSE: 'Could not read the clause after the transfer', // SY03
 
   // Date / scheduling
   INVALID_DATE_FORMAT: 'Invalid date format. Expected YYYY-MM-DD', // DT01
+  INVALID_SCHEDULE_DATE:
+    'Invalid schedule date. Expected today, tomorrow, next <weekday>, in <n> days, end of month, YYYY-MM-DD or DD/MM/YYYY', // DT01
+  INVALID_CALENDAR_DATE: 'Invalid date: no such day in the calendar', // DT01
+  INVALID_TIME_OF_DAY: 'Invalid time of day. Expected HH:MM or h[:MM]am/pm', // DT01
+  UNKNOWN_SCHEDULE_ANCHOR: 'Unknown schedule anchor', // DT01 / DT03
+  INVALID_SCHEDULE_ANCHOR: 'Schedule anchor rule names no day of the month', // DT01 / DT03
+  SCHEDULE_DATE_IN_PAST: 'Scheduled date is in the past', // DT02
+  INVALID_RECURRENCE:
+    'Invalid recurrence. Expected daily, weekly, monthly, every <n> days/weeks/months or every <weekday>', // DT03
+  INVALID_TIMEZONE: 'Invalid timezone. Expected an IANA name such as Africa/Lagos', // DT04
   TRANSACTION_SCHEDULED: 'Transaction scheduled for future execution', // AP02
   TRANSACTION_EXECUTED: 'Transaction executed successfully', // AP00
+  DRY_RUN_WOULD_EXECUTE: 'Dry run: transaction would succeed, no balances changed', // AP00
+  KEYWORD_CORRECTED: 'corrected',
+  COMPOUND_EXECUTED: 'Every clause of the compound instruction was processed', // AP00
+  COMPOUND_CLAUSES_FAILED: 'Some clauses of the compound instruction failed',
+
+  // Reversal
+  MALFORMED_REVERSAL: 'Malformed reversal request. Expected accounts and a transaction_id',
+  TRANSACTION_NOT_FOUND: 'Transaction not found', // RV01
+  INSUFFICIENT_FUNDS_FOR_REVERSAL: 'Insufficient funds to reverse the transaction', // AC01
+  TRANSACTION_REVERSED: 'Transaction reversed successfully', // AP00
+
+  // Idempotency
+  IDEMPOTENCY_KEY_REUSED: 'Idempotency key was already used with a different request',
+  IDEMPOTENCY_KEY_IN_PROGRESS: 'A request with this idempotency key is still being processed',
+
+  // Batch processing
+  INVALID_BATCH: 'Batch must contain either items, or accounts with instructions',
+  DUPLICATE_INSTRUCTION: 'Instruction repeats an earlier one in the batch', // DUP01
+  NDJSON_INVALID_LINE: 'Line is not a JSON object', // SY03
+  NDJSON_NO_ACCOUNTS: 'Line has no accounts and no account set came before it', // SY03
+
+  // Export (pain.001, MT103)
+  EXPORT_NOT_SINGLE_TRANSFER:
+    'Only executed or scheduled single transfers (DEBIT, CREDIT, SCHEDULE) can be exported',
+  SWIFT_INVALID_CHARACTER:
+    "Character not allowed in SWIFT field (letters, digits, space and / - ? : ( ) . , ' + only)",
+  SWIFT_FIELD_TOO_LONG: 'Too long for SWIFT field',
+  SWIFT_INVALID_BIC: 'BIC must be 8 or 11 characters',
+  SWIFT_INVALID_CHARGES: 'Charges must be OUR, SHA or BEN',
+
+  // Parsed instruction JSON
+  INVALID_PARSED_INSTRUCTION_JSON: 'Invalid parsed instruction JSON',
+
+  // Accounts CSV import
+  INVALID_ACCOUNTS_CSV: 'Invalid accounts CSV',
+  CSV_MISSING_COLUMNS: 'expected columns id, balance, currency and an optional alias',
+  CSV_UNCLOSED_QUOTE: 'has a quote that is never closed',
+  CSV_BALANCE_NOT_A_NUMBER: 'balance is not a number',
+  CSV_MISSING_CURRENCY: 'currency is missing',
+  CSV_INVALID_ACCOUNT_ID: 'account id may only use letters, numbers, hyphen, dot and at',
+  CSV_DUPLICATE_ID: 'duplicate account id',
+  CSV_DUPLICATE_ALIAS: 'duplicate alias',
+  CSV_UNSUPPORTED_CURRENCY: 'Currency not supported by the parser',
+
+  // Schedule preview
+  PREVIEW_NOT_RECURRING: 'Only a standing order with a recurrence and a first run has a schedule',
+  INVALID_PREVIEW_COUNT: 'Preview count must be a whole number from 1 to',
+
+  // Cancellation
+  INSTRUCTION_CANCELLED: 'Cancelled before the instruction ran', // CANCELLED
+
+  // Warnings on successful results, keyed by warning code
+  WARNINGS: {
+    currency_inferred: 'Currency was inferred; check it is the one meant',
+    amount_rounded: "Amount was rounded to the currency's minor units",
+    alias_used: 'An account was named by its alias',
+    keyword_corrected: 'A mistyped keyword was corrected',
+    account_by_digits: 'An account was found by its trailing digits',
+    account_case_corrected: 'An account id was matched ignoring case',
+  },
+
+  // What a strict-mode refusal names, keyed by confidence signal
+  STRICT_MODE_GUESSES: {
+    fuzzy: 'mistyped keyword',
+    alias: 'account named by alias',
+    partial_account: 'account named by trailing digits',
+    case_insensitive: 'account id in a different case',
+    currency_inferred: 'currency not named exactly',
+    word_amount: 'amount in words',
+    balance_share: 'amount as a share of the balance',
+  },
 
   // Generic / fallback
   INTERNAL_ERROR: 'Internal server error',
+  AUDIT_WRITE_FAILED: 'Audit record could not be written',
 };
 
 module.exports = PaymentInstructionsMessages;
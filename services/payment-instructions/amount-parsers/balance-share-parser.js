@@ -0,0 +1,102 @@
+const {
+  AMOUNT_FRACTIONS,
+  FRACTION_ARTICLES,
+  SWEEP_WORDS,
+  SWEEP_BALANCE_WORDS,
+  SWEEP_FILLER_WORDS,
+} = require('../helpers/constants');
+const splitDecimal = require('../helpers/split-decimal');
+
+function has(table, word) {
+  return Object.prototype.hasOwnProperty.call(table, word);
+}
+
+/**
+ * The index of the last token of an "of [my|the] balance" tail right after tokens[end], or
+ * end when there is none.
+ */
+function balanceTailEnd(tokens, end) {
+  const word = (i) => (i < tokens.length ? String(tokens[i]).toLowerCase() : '');
+  if (word(end + 1) !== 'of') return end;
+  let j = end + 2;
+  if (SWEEP_FILLER_WORDS.indexOf(word(j)) !== -1) j++;
+  return word(j) === 'balance' ? j : end;
+}
+
+/**
+ * Parse a percentage starting at tokens[start]: "10%", "12.5%", "10 %" or "10 percent", with
+ * an optional "of my balance".
+ * Returns { ratio, consumed } or null.
+ */
+function parsePercentage(tokens, start) {
+  const token = String(tokens[start]);
+  let numeric = null;
+  let consumed = 1;
+  if (token.length > 1 && token[token.length - 1] === '%') {
+    numeric = token.substring(0, token.length - 1);
+  } else if (start + 1 < tokens.length) {
+    const next = String(tokens[start + 1]).toLowerCase();
+    if (next === '%' || next === 'percent') {
+      numeric = token;
+      consumed = 2;
+    }
+  }
+  const parts = splitDecimal(numeric);
+  if (parts === null) return null;
+  consumed = balanceTailEnd(tokens, start + consumed - 1) - start + 1;
+  const numerator = parseInt(parts.intDigits + parts.fracDigits, 10);
+  const denominator = 100 * 10 ** parts.fracDigits.length;
+  // "100%" is the same as sweeping the whole balance
+  return { ratio: { numerator, denominator }, sweep: numerator === denominator, consumed };
+}
+
+/**
+ * Parse a simple fraction starting at tokens[start]: "half", "a quarter", "one third", with
+ * an optional "of my balance".
+ * Returns { ratio, consumed } or null.
+ */
+function parseFraction(tokens, start) {
+  let i = start;
+  const first = String(tokens[i]).toLowerCase();
+  if (FRACTION_ARTICLES.indexOf(first) !== -1 && i + 1 < tokens.length) i++;
+  const word = String(tokens[i]).toLowerCase();
+  if (!has(AMOUNT_FRACTIONS, word)) return null;
+  const end = balanceTailEnd(tokens, i);
+  return { ratio: { ...AMOUNT_FRACTIONS[word] }, consumed: end - start + 1 };
+}
+
+/**
+ * Parse a full-sweep phrase starting at tokens[start]: "all", "everything",
+ * "all of my balance", "entire balance", "my full balance".
+ * Returns { ratio, sweep, consumed } or null.
+ */
+function parseSweep(tokens, start) {
+  const word = (i) => (i < tokens.length ? String(tokens[i]).toLowerCase() : '');
+  let i = start;
+  if (SWEEP_FILLER_WORDS.indexOf(word(i)) !== -1) i++;
+
+  if (SWEEP_WORDS.indexOf(word(i)) !== -1) {
+    i = balanceTailEnd(tokens, i);
+  } else if (SWEEP_BALANCE_WORDS.indexOf(word(i)) !== -1 && word(i + 1) === 'balance') {
+    i++;
+  } else {
+    return null;
+  }
+  return { ratio: { numerator: 1, denominator: 1 }, sweep: true, consumed: i - start + 1 };
+}
+
+/**
+ * Amounts that are a share of the debit balance: the whole of it ("all", "everything",
+ * "entire balance", "100%"), a percentage ("10%", "10 percent") or a simple fraction ("half",
+ * "a quarter"). They parse to { amount: null, ratio, sweep, consumed } and are resolved once
+ * the debit account is known.
+ */
+module.exports = {
+  name: 'balance_share',
+  parse(tokens, start) {
+    const share =
+      parseSweep(tokens, start) || parsePercentage(tokens, start) || parseFraction(tokens, start);
+    if (share === null) return null;
+    return { amount: null, ratio: share.ratio, sweep: !!share.sweep, consumed: share.consumed };
+  },
+};
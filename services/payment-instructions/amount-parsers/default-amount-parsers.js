@@ -0,0 +1,18 @@
+const balanceShareParser = require('./balance-share-parser');
+const numberWordParser = require('./number-word-parser');
+const digitAmountParser = require('./digit-amount-parser');
+
+/**
+ * The amount parsers the services try, in order, at the amount position of an instruction.
+ * The first one that reads an amount wins. Any object with the same interface can be added
+ * through options.amountParsers, which replaces this chain; put a market-specific one first
+ * and keep these after it, e.g. [lakhParser, ...DEFAULT_AMOUNT_PARSERS]:
+ *   name                             -> label for logs and tests
+ *   parse(tokens, start, context)    -> { amount, consumed } or null when tokens[start]
+ *                                       does not start an amount this parser reads
+ * amount is in major units ("1,000.50" -> 1000.5) and consumed counts the tokens used. A
+ * balance share parses to { amount: null, ratio: { numerator, denominator }, sweep, consumed }
+ * instead. context is { decimalSeparator } ('.' or ','). Currency symbols ("₦5000") are
+ * split off before the chain runs, so parsers only ever see the amount itself.
+ */
+module.exports = [balanceShareParser, numberWordParser, digitAmountParser];
@@ -0,0 +1,73 @@
+const { AMOUNT_SUFFIXES } = require('../helpers/constants');
+const splitDecimal = require('../helpers/split-decimal');
+const shiftDecimal = require('../helpers/shift-decimal');
+
+function has(table, word) {
+  return Object.prototype.hasOwnProperty.call(table, word);
+}
+
+/**
+ * Break a single token into a numeric part and a trailing alphabetic suffix.
+ * "1.5m" -> { numeric: '1.5', suffix: 'm' }, "1,000" -> { numeric: '1,000', suffix: '' }.
+ */
+function splitSuffix(token) {
+  let i = 0;
+  while (
+    i < token.length &&
+    ((token[i] >= '0' && token[i] <= '9') || token[i] === '.' || token[i] === ',')
+  ) {
+    i++;
+  }
+  return { numeric: token.substring(0, i), suffix: token.substring(i).toLowerCase() };
+}
+
+/**
+ * Rewrite a numeric string with grouping separators into plain "1000.50" form.
+ * With decimalSeparator '.' the group separator is ',' ("1,000.50"); with ',' the roles
+ * swap ("1.000,50"). Groups after the first must have exactly three digits, and there
+ * may be at most one decimal separator, so "1,00,0" and "1.2.3" return null.
+ */
+function normalizeSeparators(numeric, decimalSeparator) {
+  const groupSeparator = decimalSeparator === ',' ? '.' : ',';
+  const pieces = numeric.split(decimalSeparator);
+  if (pieces.length > 2) return null;
+  const fracDigits = pieces.length === 2 ? pieces[1] : null;
+  if (fracDigits !== null && fracDigits.indexOf(groupSeparator) !== -1) return null;
+
+  const groups = pieces[0].split(groupSeparator);
+  if (groups.length > 1 && (groups[0].length === 0 || groups[0].length > 3)) return null;
+  for (let g = 1; g < groups.length; g++) {
+    if (groups[g].length !== 3) return null;
+  }
+  const intDigits = groups.join('');
+  return fracDigits !== null ? `${intDigits}.${fracDigits}` : intDigits;
+}
+
+/**
+ * Amounts written in digits, optionally grouped ("5000", "1,000.50", or "1.000,50" when the
+ * decimal separator is ','), with an optional shorthand multiplier attached ("5k", "1.5m",
+ * "2bn") or in its own token ("5 k"). Malformed groupings ("1,00,0") and unknown suffixes
+ * ("5km") are not amounts.
+ */
+module.exports = {
+  name: 'digits',
+  parse(tokens, start, context) {
+    const { numeric, suffix } = splitSuffix(String(tokens[start]));
+    const normalized = normalizeSeparators(numeric, context.decimalSeparator);
+    const parts = normalized !== null ? splitDecimal(normalized) : null;
+    if (parts === null) return null;
+
+    if (suffix.length > 0) {
+      if (!has(AMOUNT_SUFFIXES, suffix)) return null;
+      return { amount: shiftDecimal(parts, AMOUNT_SUFFIXES[suffix]), consumed: 1 };
+    }
+
+    // Suffix separated by a space ("5 k"); only when something still follows it
+    const next = start + 1 < tokens.length ? String(tokens[start + 1]).toLowerCase() : '';
+    if (next.length > 0 && start + 2 < tokens.length && has(AMOUNT_SUFFIXES, next)) {
+      return { amount: shiftDecimal(parts, AMOUNT_SUFFIXES[next]), consumed: 2 };
+    }
+
+    return { amount: shiftDecimal(parts, 0), consumed: 1 };
+  },
+};
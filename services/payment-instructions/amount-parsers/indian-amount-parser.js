@@ -0,0 +1,76 @@
+const { INDIAN_AMOUNT_WORDS } = require('../helpers/constants');
+const splitDecimal = require('../helpers/split-decimal');
+const shiftDecimal = require('../helpers/shift-decimal');
+
+function has(table, word) {
+  return Object.prototype.hasOwnProperty.call(table, word);
+}
+
+/**
+ * Break a single token into its digits and a trailing word ("2lakh" -> '2', 'lakh').
+ */
+function splitWord(token) {
+  let i = 0;
+  while (
+    i < token.length &&
+    ((token[i] >= '0' && token[i] <= '9') || token[i] === '.' || token[i] === ',')
+  ) {
+    i++;
+  }
+  return { numeric: token.substring(0, i), word: token.substring(i).toLowerCase() };
+}
+
+/**
+ * Rewrite an Indian-grouped number into plain "100000.50" form: the last group before the
+ * decimal point has three digits and every one before it two, the first one or two
+ * ("1,00,000", "12,34,567.50"). Returns the plain digits and the number of groups, or null
+ * when the grouping is not Indian ("100,000", "1,00,0").
+ */
+function normalizeIndianGrouping(numeric) {
+  const pieces = numeric.split('.');
+  if (pieces.length > 2) return null;
+  const groups = pieces[0].split(',');
+  const last = groups.length - 1;
+  if (groups.length > 1 && (groups[0].length === 0 || groups[0].length > 2)) return null;
+  if (groups.length > 1 && groups[last].length !== 3) return null;
+  for (let g = 1; g < last; g++) {
+    if (groups[g].length !== 2) return null;
+  }
+  const intDigits = groups.join('');
+  const plain = pieces.length === 2 ? `${intDigits}.${pieces[1]}` : intDigits;
+  return { plain, groups: groups.length };
+}
+
+/**
+ * Amounts as written in India: digits in lakh and crore grouping ("1,00,000",
+ * "1,50,00,000.50"), optionally scaled by a lakh or crore word attached ("2lakh") or in its
+ * own token ("2 lakh", "1.5 crore"). It reads only what the Western digits parser would not
+ * (a number with two-digit groups, or one with a scale word), so "5000", "12,345" and "5k"
+ * fall through to the rest of the chain unchanged. Nothing is read when the decimal
+ * separator is ','.
+ */
+module.exports = {
+  name: 'indian_digits',
+  parse(tokens, start, context) {
+    if (context.decimalSeparator === ',') return null;
+    const { numeric, word } = splitWord(String(tokens[start]));
+    const normalized = numeric.length > 0 ? normalizeIndianGrouping(numeric) : null;
+    const parts = normalized !== null ? splitDecimal(normalized.plain) : null;
+    if (parts === null) return null;
+
+    if (word.length > 0) {
+      if (!has(INDIAN_AMOUNT_WORDS, word)) return null;
+      return { amount: shiftDecimal(parts, INDIAN_AMOUNT_WORDS[word]), consumed: 1 };
+    }
+
+    // Scale word in its own token ("2 lakh"); only when something still follows it
+    const next = start + 1 < tokens.length ? String(tokens[start + 1]).toLowerCase() : '';
+    if (start + 2 < tokens.length && has(INDIAN_AMOUNT_WORDS, next)) {
+      return { amount: shiftDecimal(parts, INDIAN_AMOUNT_WORDS[next]), consumed: 2 };
+    }
+
+    // Without a scale word, only a grouping the Western parser refuses is read here
+    if (normalized.groups < 3) return null;
+    return { amount: shiftDecimal(parts, 0), consumed: 1 };
+  },
+};
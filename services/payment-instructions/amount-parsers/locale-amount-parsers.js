@@ -0,0 +1,38 @@
+const DEFAULT_AMOUNT_PARSERS = require('./default-amount-parsers');
+const indianAmountParser = require('./indian-amount-parser');
+
+// Amount parser chains by locale region; a locale of any other region, or none, gets the
+// default chain
+const REGION_AMOUNT_PARSERS = {
+  IN: [indianAmountParser, ...DEFAULT_AMOUNT_PARSERS],
+};
+
+// The region subtag of a BCP 47 locale ("en-IN", "hi_IN" -> 'IN'), or null
+function localeRegion(locale) {
+  const subtags = String(locale || '')
+    .split('_')
+    .join('-')
+    .split('-');
+  for (let i = 1; i < subtags.length; i++) {
+    if (subtags[i].length === 2) return subtags[i].toUpperCase();
+  }
+  return null;
+}
+
+/**
+ * The amount parsers for a request locale: Indian grouping and lakh/crore words first for
+ * an Indian locale ("en-IN", "hi-IN"), the default chain otherwise, so Western grouping is
+ * left as it is everywhere else.
+ *
+ * @param {string} [locale]
+ * @returns {Object[]} amount parsers, in order (see default-amount-parsers.js)
+ */
+function amountParsersForLocale(locale) {
+  const region = localeRegion(locale);
+  if (region !== null && Object.prototype.hasOwnProperty.call(REGION_AMOUNT_PARSERS, region)) {
+    return REGION_AMOUNT_PARSERS[region];
+  }
+  return DEFAULT_AMOUNT_PARSERS;
+}
+
+module.exports = amountParsersForLocale;
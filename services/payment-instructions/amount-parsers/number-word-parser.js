@@ -0,0 +1,58 @@
+const {
+  NUMBER_UNITS,
+  NUMBER_TEENS,
+  NUMBER_TENS,
+  NUMBER_SCALES,
+  NUMBER_ARTICLES,
+} = require('../helpers/constants');
+const parseWordAmount = require('../helpers/parse-word-amount');
+
+function has(table, word) {
+  return Object.prototype.hasOwnProperty.call(table, word);
+}
+
+/**
+ * Check whether a single token belongs to the number-word vocabulary
+ * (including hyphenated forms such as "twenty-five" and the "and" connector).
+ */
+function isNumberWord(token) {
+  const parts = String(token).toLowerCase().split('-');
+  for (let i = 0; i < parts.length; i++) {
+    const w = parts[i];
+    const known =
+      has(NUMBER_UNITS, w) ||
+      has(NUMBER_TEENS, w) ||
+      has(NUMBER_TENS, w) ||
+      has(NUMBER_SCALES, w) ||
+      w === 'hundred';
+    if (!known && (parts.length > 1 || w !== 'and')) return false;
+  }
+  return true;
+}
+
+/**
+ * Check whether an indefinite article at tokens[i] starts a number phrase ("a hundred").
+ */
+function isArticleAmount(tokens, i) {
+  if (i + 1 >= tokens.length) return false;
+  const w = String(tokens[i]).toLowerCase();
+  const next = String(tokens[i + 1]).toLowerCase();
+  return NUMBER_ARTICLES.indexOf(w) !== -1 && (next === 'hundred' || has(NUMBER_SCALES, next));
+}
+
+/**
+ * Amounts written in words: "five hundred", "a thousand and fifty", "twenty-five". The run
+ * of number words is taken whole; one that does not form a valid number parses to
+ * { amount: null, consumed } so the caller can point at every word of it.
+ */
+module.exports = {
+  name: 'number_words',
+  parse(tokens, start) {
+    const token = String(tokens[start]);
+    const isWord = token.toLowerCase() !== 'and' && isNumberWord(token);
+    if (!isWord && !isArticleAmount(tokens, start)) return null;
+    let end = start + 1;
+    while (end < tokens.length && isNumberWord(tokens[end])) end++;
+    return { amount: parseWordAmount(tokens.slice(start, end)), consumed: end - start };
+  },
+};
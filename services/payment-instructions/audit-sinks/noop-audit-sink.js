@@ -0,0 +1,14 @@
+/**
+ * Default audit sink: drops every record.
+ *
+ * Any object with the same interface can be passed in options.auditSink to keep an audit
+ * trail (an append-only table, a Kafka topic, ...):
+ *   write(record)   -> called once per processed instruction, failed and rejected ones
+ *                      included, with a frozen record (see helpers/build-audit-record.js);
+ *                      may return a promise, which is awaited
+ * The result is only returned once write has succeeded: a sink that throws or rejects fails
+ * the request with an internal error instead, so no instruction goes unaudited.
+ */
+module.exports = {
+  write() {},
+};
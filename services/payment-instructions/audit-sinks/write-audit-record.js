@@ -0,0 +1,25 @@
+const { throwAppError, ERROR_CODE } = require('@app-core/errors');
+const { appLogger } = require('@app-core/logger');
+const PaymentMessages = require('@app/messages/payment-instructions');
+
+/**
+ * Hand an audit record to the sink (see noop-audit-sink.js), failing the request with an
+ * internal error when the sink cannot take it.
+ *
+ * @param {{ write: (record: Object) => any }} sink
+ * @param {Object} record - see helpers/build-audit-record.js
+ * @returns {Promise<void>}
+ */
+async function writeAuditRecord(sink, record) {
+  try {
+    await sink.write(record);
+  } catch (err) {
+    appLogger.errorX(
+      { error: err, transaction_id: record.transaction_id },
+      'payment-instructions.audit-write-failed'
+    );
+    throwAppError(PaymentMessages.AUDIT_WRITE_FAILED, ERROR_CODE.APPERR);
+  }
+}
+
+module.exports = writeAuditRecord;
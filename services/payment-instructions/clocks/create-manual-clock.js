@@ -0,0 +1,25 @@
+/**
+ * Clock that only moves when told to, for deterministic runs and tests. It has the clock
+ * interface (see system-clock.js) plus:
+ *   advance(ms)  -> moves the time forward by ms milliseconds and returns it
+ *   set(time)    -> jumps to time (Date or epoch ms) and returns it
+ *
+ * @param {Date|number} [start] - starting time (Date or epoch ms), the epoch by default
+ */
+function createManualClock(start = 0) {
+  let current = new Date(start).getTime();
+
+  return {
+    now: () => current,
+    advance(ms) {
+      current += ms;
+      return current;
+    },
+    set(time) {
+      current = new Date(time).getTime();
+      return current;
+    },
+  };
+}
+
+module.exports = createManualClock;
@@ -0,0 +1,13 @@
+/**
+ * Default clock: the system time.
+ *
+ * Any object with the same interface can be passed to the services in options.clock (and to
+ * the memory stores and id generators as config.clock) instead, e.g. a manual clock for
+ * tests (see create-manual-clock.js):
+ *   now()  -> the current time in epoch milliseconds
+ */
+module.exports = {
+  now() {
+    return Date.now();
+  },
+};
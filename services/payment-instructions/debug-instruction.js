@@ -0,0 +1,155 @@
+const validator = require('@app-core/validator');
+const { throwAppError, ERROR_CODE } = require('@app-core/errors');
+const { appLogger } = require('@app-core/logger');
+const PaymentMessages = require('@app/messages/payment-instructions');
+const paymentInstructions = require('./payment-instructions');
+const {
+  tokenize,
+  tokenSpans,
+  parseAmount,
+  placeCurrencySymbol,
+  resolveCurrency,
+  parseAccountReference,
+  resolveAccountAlias,
+  findAccount,
+  findAccountsBySuffix,
+  findAccountsIgnoringCase,
+  correctKeyword,
+  FUZZY_VERBS,
+  VERB_SYNONYMS,
+} = require('./helpers');
+const { parsedInstructionRequestSpec: parsedSpec } = require('./request-spec');
+
+// Opening words the parser reads, besides the ones fuzzy matching may correct to
+const OPENING_VERBS = [...FUZZY_VERBS, 'pay', 'standing'];
+
+// The verb the token at index reads as, and the one it is closest to when it reads as none
+function verbCandidate(tokens, index) {
+  const token = tokens[index];
+  const lower = String(token).toLowerCase();
+  let readsAs = null;
+  if (Object.prototype.hasOwnProperty.call(VERB_SYNONYMS, lower)) readsAs = VERB_SYNONYMS[lower];
+  else if (OPENING_VERBS.indexOf(lower) !== -1) readsAs = lower;
+  const suggestion = readsAs === null ? correctKeyword(lower, FUZZY_VERBS) : null;
+  return { index, token, reads_as: readsAs, suggestion };
+}
+
+// Every position an amount could be read from, as the parser would read it there
+function amountCandidates(tokens, decimalSeparator) {
+  const candidates = [];
+  for (let i = 0; i < tokens.length; i++) {
+    const placed = placeCurrencySymbol(tokens, i, decimalSeparator);
+    const parsed = parseAmount(placed, i, decimalSeparator);
+    if (parsed !== null) {
+      candidates.push({
+        index: i,
+        text: placed.slice(i, i + parsed.consumed).join(' '),
+        amount: parsed.amount,
+        ratio: parsed.ratio || null,
+      });
+    }
+  }
+  return candidates;
+}
+
+// Tokens naming a currency (codes, words, symbols, a symbol attached to an amount) and the
+// held codes each could be
+function currencyCandidates(tokens, accounts, decimalSeparator) {
+  const held = accounts.map((a) => String(a.currency || '').toUpperCase());
+  const candidates = [];
+  for (let i = 0; i < tokens.length; i++) {
+    let token = tokens[i];
+    let codes = resolveCurrency(token, held);
+    const attached = placeCurrencySymbol([token], 0, decimalSeparator);
+    if (codes.length === 0 && attached.length === 2) {
+      token = attached[1];
+      codes = resolveCurrency(token, held);
+    }
+    if (codes.length > 0) candidates.push({ index: i, token, codes });
+  }
+  return candidates;
+}
+
+// Tokens that name one or more of the accounts, and how: by id, alias, trailing digits or
+// id ignoring case
+function accountCandidates(tokens, accounts, aliases, ignoreCase) {
+  const candidates = [];
+  for (let i = 0; i < tokens.length; i++) {
+    const ref = parseAccountReference(tokens, i, accounts);
+    const alias = aliases !== null ? resolveAccountAlias(ref.token, aliases) : null;
+    let via = null;
+    let ids = [];
+    if (ref.suffix !== null) {
+      via = 'suffix';
+      ids = findAccountsBySuffix(accounts, ref.suffix);
+    } else if (alias !== null) {
+      via = 'alias';
+      ids = alias.ambiguous || [alias.id];
+    } else if (findAccount(accounts, ref.token) !== null) {
+      via = 'id';
+      ids = [ref.token];
+    } else if (ignoreCase) {
+      via = 'case_insensitive';
+      ids = findAccountsIgnoringCase(accounts, ref.token);
+    }
+    if (ids.length > 0) {
+      const text = tokens.slice(i, i + ref.consumed).join(' ');
+      candidates.push({ index: i, text, via, ids });
+    }
+  }
+  return candidates;
+}
+
+/**
+ * Explain how an instruction is read, for debugging parse failures: its tokens with their
+ * positions in the instruction as sent, the candidates for each field (the verb, every
+ * amount, currency and account reading the parser could make) and the outcome of parsing
+ * it (see parse-instruction.js). Nothing is executed: no balance is checked or moved and
+ * no store is read or written.
+ *
+ * @param {Object} serviceData - payment-instructions payload
+ * @returns {Promise<{ instruction: string, tokens: Object[], candidates: Object,
+ *   outcome: Object }>}
+ */
+async function debugInstruction(serviceData) {
+  let data;
+  try {
+    data = validator.validate(serviceData, parsedSpec);
+  } catch (err) {
+    appLogger.errorX({ error: err, payload: serviceData }, 'debug-instruction.validation-failed');
+    throwAppError(PaymentMessages.MALFORMED_INSTRUCTION, ERROR_CODE.VALIDATIONERR);
+  }
+  const instruction =
+    typeof serviceData.instruction === 'string' ? serviceData.instruction : data.instruction;
+  const accounts = data.accounts;
+  const aliases =
+    data.aliases && typeof data.aliases === 'object' && !Array.isArray(data.aliases)
+      ? data.aliases
+      : null;
+  const ignoreCase = data.case_insensitive_ids === true;
+  const decimalSeparator = data.decimal_separator === ',' ? ',' : '.';
+
+  const tokens = tokenize(data.instruction);
+  const spans = tokenSpans(instruction, tokens);
+  const verbIndex = String(tokens[0]).toLowerCase() === 'schedule' ? 1 : 0;
+
+  const result = await paymentInstructions({ ...data, instruction }, { parseOnly: true });
+  return {
+    instruction,
+    tokens: tokens.map((token, index) => ({ index, token, ...spans[index] })),
+    candidates: {
+      verb: verbIndex < tokens.length ? verbCandidate(tokens, verbIndex) : null,
+      amounts: amountCandidates(tokens, decimalSeparator),
+      currencies: currencyCandidates(tokens, accounts, decimalSeparator),
+      accounts: accountCandidates(tokens, accounts, aliases, ignoreCase),
+    },
+    outcome: {
+      status: result.status,
+      status_code: result.status_code || null,
+      status_reason: result.status_reason || null,
+      parse_error: result.parse_error || null,
+    },
+  };
+}
+
+module.exports = debugInstruction;
@@ -0,0 +1,28 @@
+const { throwAppError, ERROR_CODE } = require('@app-core/errors');
+const PaymentMessages = require('@app/messages/payment-instructions');
+
+// Result types that are one debtor-to-creditor transfer; SPLIT, MULTI_DEBIT, COMPOUND,
+// STANDING_ORDER and cash movements have no single bank transfer message to map to.
+const TRANSFER_TYPES = ['DEBIT', 'CREDIT', 'TRANSFER', 'COLLECT', 'SCHEDULE'];
+
+/**
+ * Throw a validation error unless the result is an executed or scheduled single transfer
+ * (not a dry run) with both accounts set.
+ * @param {Object} tx - payment-instructions result
+ */
+function assertSingleTransfer(tx) {
+  const exportable =
+    (tx.status === 'successful' || tx.status === 'pending') &&
+    tx.dry_run !== true &&
+    TRANSFER_TYPES.indexOf(tx.type) !== -1 &&
+    !!tx.debit_account &&
+    !!tx.credit_account;
+  if (!exportable) {
+    throwAppError(
+      `${PaymentMessages.EXPORT_NOT_SINGLE_TRANSFER}: ${tx.type || 'unknown'} (${tx.status})`,
+      ERROR_CODE.VALIDATIONERR
+    );
+  }
+}
+
+module.exports = assertSingleTransfer;
@@ -0,0 +1,17 @@
+/**
+ * Date ("YYYY-MM-DD", UTC) a transfer executes on: execute_by when set, else the day of now.
+ * execute_by is "YYYY-MM-DD" for ON dates and a Unix timestamp (seconds) for SCHEDULE.
+ * @param {Object} tx - payment-instructions result
+ * @param {Date} now
+ * @returns {string}
+ */
+function getExecutionDate(tx, now) {
+  let date = now.toISOString().substring(0, 10);
+  if (typeof tx.execute_by === 'string') date = tx.execute_by.substring(0, 10);
+  if (typeof tx.execute_by === 'number') {
+    date = new Date(tx.execute_by * 1000).toISOString().substring(0, 10);
+  }
+  return date;
+}
+
+module.exports = getExecutionDate;
@@ -0,0 +1,149 @@
+const { throwAppError, ERROR_CODE } = require('@app-core/errors');
+const PaymentMessages = require('@app/messages/payment-instructions');
+const { getCurrencyDecimals, currentTime } = require('../helpers');
+const assertSingleTransfer = require('./assert-single-transfer');
+const getExecutionDate = require('./get-execution-date');
+
+// SWIFT "x" character set: the only characters a text field may carry
+const LETTERS = 'abcdefghijklmnopqrstuvwxyz';
+const SWIFT_CHARACTERS = `${LETTERS}${LETTERS.toUpperCase()}0123456789/-?:().,'+ `;
+
+const REFERENCE_LENGTH = 16; // :20: 16x
+const ACCOUNT_LENGTH = 34; // /34x account line of :50K: and :59:
+const LINE_LENGTH = 35; // 35x per line of names and :70:
+const NARRATION_LINES = 4; // :70: 4*35x
+
+function fail(message, field) {
+  throwAppError(`${message}: ${field}`, ERROR_CODE.VALIDATIONERR);
+}
+
+function checkCharacters(text, field) {
+  for (let i = 0; i < text.length; i++) {
+    if (SWIFT_CHARACTERS.indexOf(text[i]) === -1) {
+      fail(PaymentMessages.SWIFT_INVALID_CHARACTER, field);
+    }
+  }
+  return text;
+}
+
+function checkLength(text, max, field) {
+  if (text.length > max) fail(PaymentMessages.SWIFT_FIELD_TOO_LONG, field);
+  return text;
+}
+
+// "250.5" USD -> "250,50"; "1000" JPY -> "1000," (the comma is always present)
+function formatAmount(amount, currency) {
+  const decimals = getCurrencyDecimals(currency);
+  const fixed = Number(amount).toFixed(decimals);
+  return decimals === 0 ? `${fixed},` : fixed.split('.').join(',');
+}
+
+// Lines of at most LINE_LENGTH, broken between words; longer words are cut
+function wrapLines(text) {
+  const words = [];
+  text.split(' ').forEach((word) => {
+    for (let i = 0; i < word.length; i += LINE_LENGTH) {
+      words.push(word.substring(i, i + LINE_LENGTH));
+    }
+  });
+  const lines = [];
+  words.forEach((word) => {
+    const last = lines.length - 1;
+    if (last >= 0 && lines[last].length + 1 + word.length <= LINE_LENGTH) {
+      lines[last] = `${lines[last]} ${word}`;
+    } else {
+      lines.push(word);
+    }
+  });
+  return lines;
+}
+
+// Logical terminal address: BIC8 or BIC11 padded to 12 characters
+function terminalAddress(bic, field) {
+  const code = String(bic).toUpperCase();
+  if (code.length !== 8 && code.length !== 11) fail(PaymentMessages.SWIFT_INVALID_BIC, field);
+  checkCharacters(code, field);
+  return code.length === 8 ? `${code}XXXX` : `${code.substring(0, 8)}X${code.substring(8)}`;
+}
+
+/**
+ * Render an executed or scheduled single transfer as a SWIFT MT103 message.
+ *
+ * Block 4 carries :20: (reference), :23B:, :32A: (value date, currency, amount), :50K:
+ * (ordering customer), :59: (beneficiary), :70: (narration, when set) and :71A: (charges).
+ * Blocks 1 and 2 are added when both senderBic and receiverBic are given. Lines end in CRLF.
+ *
+ * Every field is checked against the SWIFT character set and its length; anything outside
+ * them is a validation error naming the field, except the narration, which is cut to
+ * 4 lines of 35 unless options.truncateNarration is false.
+ *
+ * @param {Object} transaction - payment-instructions result
+ * @param {{ now?: number, clock?: Object, reference?: string, orderingCustomer?: string,
+ *   beneficiary?: string, charges?: string, senderBic?: string, receiverBic?: string,
+ *   truncateNarration?: boolean }} [options] - now (epoch ms, else read from clock) is the
+ *   value date when the result has no execute_by; reference defaults to the last 16 characters of the
+ *   transaction id; names default to the account ids; charges defaults to SHA
+ * @returns {string}
+ */
+function toMt103(transaction, options = {}) {
+  const tx = transaction || {};
+  assertSingleTransfer(tx);
+
+  const now = currentTime(options);
+  const valueDate = getExecutionDate(tx, now).split('-').join('').substring(2);
+  const id = String(options.reference || tx.transaction_id);
+  const reference = options.reference
+    ? checkLength(id, REFERENCE_LENGTH, ':20:')
+    : id.substring(Math.max(0, id.length - REFERENCE_LENGTH));
+  if (reference[0] === '/' || reference[reference.length - 1] === '/') {
+    fail(PaymentMessages.SWIFT_INVALID_CHARACTER, ':20:');
+  }
+
+  const amount = formatAmount(tx.amount, tx.currency);
+  checkLength(amount, 15, ':32A:');
+  const debitAccount = checkLength(tx.debit_account, ACCOUNT_LENGTH, ':50K:');
+  const creditAccount = checkLength(tx.credit_account, ACCOUNT_LENGTH, ':59:');
+  const orderingCustomer = checkLength(
+    options.orderingCustomer || tx.debit_account,
+    LINE_LENGTH,
+    ':50K:'
+  );
+  const beneficiary = checkLength(options.beneficiary || tx.credit_account, LINE_LENGTH, ':59:');
+  const charges = options.charges || 'SHA';
+  if (['OUR', 'SHA', 'BEN'].indexOf(charges) === -1) {
+    fail(PaymentMessages.SWIFT_INVALID_CHARGES, ':71A:');
+  }
+
+  const fields = [
+    `:20:${checkCharacters(reference, ':20:')}`,
+    ':23B:CRED',
+    `:32A:${valueDate}${tx.currency}${amount}`,
+    `:50K:/${checkCharacters(debitAccount, ':50K:')}`,
+    checkCharacters(orderingCustomer, ':50K:'),
+    `:59:/${checkCharacters(creditAccount, ':59:')}`,
+    checkCharacters(beneficiary, ':59:'),
+  ];
+  if (tx.narration) {
+    let lines = wrapLines(checkCharacters(tx.narration, ':70:'));
+    if (lines.length > NARRATION_LINES) {
+      if (options.truncateNarration === false) fail(PaymentMessages.SWIFT_FIELD_TOO_LONG, ':70:');
+      lines = lines.slice(0, NARRATION_LINES);
+    }
+    // A continuation line starting with ":" or "-" would read as a new field or the block end
+    lines.forEach((line) => {
+      if (line[0] === ':' || line[0] === '-') fail(PaymentMessages.SWIFT_INVALID_CHARACTER, ':70:');
+    });
+    fields.push(`:70:${lines[0]}`, ...lines.slice(1));
+  }
+  fields.push(`:71A:${charges}`);
+
+  let headers = '';
+  if (options.senderBic && options.receiverBic) {
+    const sender = terminalAddress(options.senderBic, 'senderBic');
+    const receiver = terminalAddress(options.receiverBic, 'receiverBic');
+    headers = `{1:F01${sender}0000000000}{2:I103${receiver}N}`;
+  }
+  return `${headers}{4:\r\n${fields.join('\r\n')}\r\n-}`;
+}
+
+module.exports = toMt103;
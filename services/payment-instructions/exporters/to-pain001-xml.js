@@ -0,0 +1,102 @@
+const { getCurrencyDecimals, currentTime } = require('../helpers');
+const assertSingleTransfer = require('./assert-single-transfer');
+const getExecutionDate = require('./get-execution-date');
+
+const PAIN001_NAMESPACE = 'urn:iso:std:iso:20022:tech:xsd:pain.001.001.09';
+
+function escapeXml(value) {
+  return String(value)
+    .split('&')
+    .join('&amp;')
+    .split('<')
+    .join('&lt;')
+    .split('>')
+    .join('&gt;')
+    .split('"')
+    .join('&quot;')
+    .split("'")
+    .join('&apos;');
+}
+
+// One element per line, indented two spaces per level; children is a string or an array
+function element(name, children, depth, attributes = '') {
+  const pad = '  '.repeat(depth);
+  let out;
+  if (Array.isArray(children)) {
+    out = `${pad}<${name}${attributes}>\n${children.join('')}${pad}</${name}>\n`;
+  } else {
+    out = `${pad}<${name}${attributes}>${escapeXml(children)}</${name}>\n`;
+  }
+  return out;
+}
+
+function accountId(id, depth) {
+  return element('Id', [element('Othr', [element('Id', id, depth + 2)], depth + 1)], depth);
+}
+
+/**
+ * Render a successful (or scheduled) single transfer as an ISO 20022 pain.001.001.09
+ * Customer Credit Transfer Initiation document.
+ *
+ * The transaction id is used as message, payment information and end-to-end id; the
+ * requested execution date is execute_by when set, else the creation date (UTC).
+ *
+ * @param {Object} transaction - payment-instructions result
+ * @param {{ now?: number, clock?: Object, debtorName?: string, creditorName?: string }} [options]
+ *   - now is the creation time (epoch ms), else read from clock; names default to the
+ *   account ids
+ * @returns {string} XML document
+ */
+function toPain001Xml(transaction, options = {}) {
+  const tx = transaction || {};
+  assertSingleTransfer(tx);
+
+  const now = currentTime(options);
+  const createdAt = now.toISOString().substring(0, 19);
+  const executionDate = getExecutionDate(tx, now);
+  const amount = Number(tx.amount).toFixed(getCurrencyDecimals(tx.currency));
+  const debtorName = options.debtorName || tx.debit_account;
+  const creditorName = options.creditorName || tx.credit_account;
+
+  const transferInfo = [
+    element('PmtId', [element('EndToEndId', tx.transaction_id, 5)], 4),
+    element('Amt', [element('InstdAmt', amount, 5, ` Ccy="${escapeXml(tx.currency)}"`)], 4),
+    element('Cdtr', [element('Nm', creditorName, 5)], 4),
+    element('CdtrAcct', [accountId(tx.credit_account, 5)], 4),
+  ];
+  if (tx.narration) transferInfo.push(element('RmtInf', [element('Ustrd', tx.narration, 5)], 4));
+
+  const paymentInfo = [
+    element('PmtInfId', tx.transaction_id, 3),
+    element('PmtMtd', 'TRF', 3),
+    element('NbOfTxs', '1', 3),
+    element('CtrlSum', amount, 3),
+    element('ReqdExctnDt', [element('Dt', executionDate, 4)], 3),
+    element('Dbtr', [element('Nm', debtorName, 4)], 3),
+    element('DbtrAcct', [accountId(tx.debit_account, 4)], 3),
+    element(
+      'DbtrAgt',
+      [element('FinInstnId', [element('Othr', [element('Id', 'NOTPROVIDED', 6)], 5)], 4)],
+      3
+    ),
+    element('CdtTrfTxInf', transferInfo, 3),
+  ];
+
+  const groupHeader = [
+    element('MsgId', tx.transaction_id, 3),
+    element('CreDtTm', createdAt, 3),
+    element('NbOfTxs', '1', 3),
+    element('CtrlSum', amount, 3),
+    element('InitgPty', [element('Nm', debtorName, 4)], 3),
+  ];
+
+  const initiation = element(
+    'CstmrCdtTrfInitn',
+    [element('GrpHdr', groupHeader, 2), element('PmtInf', paymentInfo, 2)],
+    1
+  );
+  const document = element('Document', [initiation], 0, ` xmlns="${PAIN001_NAMESPACE}"`);
+  return `<?xml version="1.0" encoding="UTF-8"?>\n${document}`;
+}
+
+module.exports = toPain001Xml;
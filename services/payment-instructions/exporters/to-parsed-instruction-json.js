@@ -0,0 +1,46 @@
+const { PARSED_INSTRUCTION_FIELDS } = require('../helpers');
+
+/**
+ * JSON with object keys sorted, so equal values always produce the same string.
+ */
+function stableStringify(value) {
+  let out;
+  if (Array.isArray(value)) {
+    out = `[${value.map((v) => stableStringify(v)).join(',')}]`;
+  } else if (value !== null && typeof value === 'object') {
+    const keys = Object.keys(value)
+      .filter((k) => value[k] !== undefined)
+      .sort();
+    out = `{${keys.map((k) => `${JSON.stringify(k)}:${stableStringify(value[k])}`).join(',')}}`;
+  } else {
+    out = JSON.stringify(value === undefined ? null : value);
+  }
+  return out;
+}
+
+/**
+ * Serialize a parsed instruction (see parse-instruction.js) for caching it between the parse
+ * and execute stages; parseParsedInstructionJson (importers) reads it back.
+ *
+ * The same instruction always gives the same string: type, amount, currency, debit_account,
+ * credit_account, execute_by, narration and fee come first and are always present (null when
+ * not set, so an unscheduled instruction has "execute_by":null, never 0), then any optional
+ * fields (confidence, fx_rate, recurrence, splits, debits, clauses, ...) in key order.
+ *
+ * @param {Object} parsed - a parsed instruction
+ * @returns {string}
+ */
+function toParsedInstructionJson(parsed) {
+  const tx = parsed || {};
+  const fields = PARSED_INSTRUCTION_FIELDS.map((field) => {
+    const value = tx[field] === undefined ? null : tx[field];
+    return `${JSON.stringify(field)}:${stableStringify(value)}`;
+  });
+  Object.keys(tx)
+    .filter((key) => PARSED_INSTRUCTION_FIELDS.indexOf(key) === -1 && tx[key] !== undefined)
+    .sort()
+    .forEach((key) => fields.push(`${JSON.stringify(key)}:${stableStringify(tx[key])}`));
+  return `{${fields.join(',')}}`;
+}
+
+module.exports = toParsedInstructionJson;
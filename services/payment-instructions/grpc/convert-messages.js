@@ -0,0 +1,235 @@
+// Conversions between the payment-instructions JSON payload and results and the messages of
+// payment-instructions.proto, as @grpc/proto-loader reads them (keepCase, defaults, oneofs):
+// wrappers are { value }, an unset message field is null or missing.
+
+// Optional request fields, by wrapper type
+const REQUEST_WRAPPERS = {
+  dry_run: 'boolean',
+  decimal_separator: 'string',
+  default_currency: 'string',
+  timezone: 'string',
+  case_insensitive_ids: 'boolean',
+  fuzzy_keywords: 'boolean',
+  strict: 'boolean',
+  collection_account: 'string',
+  partial_execution: 'boolean',
+  include_all_accounts: 'boolean',
+  locale: 'string',
+  idempotency_key: 'string',
+};
+
+// Optional account fields, by wrapper type
+const ACCOUNT_WRAPPERS = {
+  overdraft_limit: 'number',
+  minimum_balance: 'number',
+  daily_limit: 'number',
+  status: 'string',
+};
+
+// Typed result fields, by wrapper type ('integer' is an Int64Value)
+const RESPONSE_WRAPPERS = {
+  transaction_id: 'string',
+  type: 'string',
+  amount: 'number',
+  currency: 'string',
+  debit_account: 'string',
+  credit_account: 'string',
+  execute_by: 'integer',
+  narration: 'string',
+  status: 'string',
+  status_reason: 'string',
+  status_code: 'string',
+};
+
+function isSet(field) {
+  return field !== null && field !== undefined;
+}
+
+function fitsWrapper(value, type) {
+  if (type === 'integer') return Number.isSafeInteger(value);
+  if (type === 'number') return typeof value === 'number' && Number.isFinite(value);
+  return typeof value === type;
+}
+
+function toValue(value) {
+  if (value === null || value === undefined) return { null_value: 'NULL_VALUE' };
+  if (Array.isArray(value)) return { list_value: { values: value.map(toValue) } };
+  if (typeof value === 'object') return { struct_value: toStruct(value) };
+  if (typeof value === 'number') return { number_value: value };
+  if (typeof value === 'boolean') return { bool_value: value };
+  return { string_value: String(value) };
+}
+
+/**
+ * A JSON object as a google.protobuf.Struct. Undefined fields are left out, as JSON leaves
+ * them out.
+ */
+function toStruct(object) {
+  const fields = {};
+  const keys = Object.keys(object);
+  for (let i = 0; i < keys.length; i++) {
+    if (object[keys[i]] !== undefined) fields[keys[i]] = toValue(object[keys[i]]);
+  }
+  return { fields };
+}
+
+function fromValue(value) {
+  const kind = value.kind || Object.keys(value).find((key) => value[key] !== undefined);
+  if (kind === 'list_value') return (value.list_value.values || []).map(fromValue);
+  if (kind === 'struct_value') return fromStruct(value.struct_value);
+  if (kind === 'number_value' || kind === 'bool_value' || kind === 'string_value') {
+    return value[kind];
+  }
+  return null;
+}
+
+/**
+ * A google.protobuf.Struct as the JSON object it holds.
+ */
+function fromStruct(struct) {
+  const object = {};
+  const fields = (struct && struct.fields) || {};
+  const keys = Object.keys(fields);
+  for (let i = 0; i < keys.length; i++) {
+    object[keys[i]] = fromValue(fields[keys[i]]);
+  }
+  return object;
+}
+
+function unwrapInto(target, message, wrappers) {
+  const keys = Object.keys(wrappers);
+  for (let i = 0; i < keys.length; i++) {
+    if (isSet(message[keys[i]])) target[keys[i]] = message[keys[i]].value;
+  }
+  return target;
+}
+
+function wrapInto(target, object, wrappers) {
+  const keys = Object.keys(wrappers);
+  for (let i = 0; i < keys.length; i++) {
+    if (isSet(object[keys[i]])) target[keys[i]] = { value: object[keys[i]] };
+  }
+  return target;
+}
+
+/**
+ * The service payload an InstructionRequest carries: unset wrappers and empty maps are left
+ * out, as a JSON payload would leave them out.
+ *
+ * @param {Object} message - InstructionRequest
+ * @returns {Object} payment-instructions payload
+ */
+function fromRequestMessage(message) {
+  const accounts = (message.accounts || []).map((account) =>
+    unwrapInto(
+      { id: account.id, balance: account.balance, currency: account.currency },
+      account,
+      ACCOUNT_WRAPPERS
+    )
+  );
+  const payload = { accounts, instruction: message.instruction };
+  if (message.fx_rates && Object.keys(message.fx_rates).length > 0) {
+    payload.fx_rates = { ...message.fx_rates };
+  }
+  if (message.aliases && Object.keys(message.aliases).length > 0) {
+    payload.aliases = { ...message.aliases };
+  }
+  return unwrapInto(payload, message, REQUEST_WRAPPERS);
+}
+
+/**
+ * The InstructionRequest for a payment-instructions payload, for gRPC clients written in
+ * Node.
+ *
+ * @param {Object} payload - payment-instructions payload
+ * @returns {Object} InstructionRequest
+ */
+function toRequestMessage(payload) {
+  const accounts = (payload.accounts || []).map((account) =>
+    wrapInto(
+      { id: account.id, balance: account.balance, currency: account.currency },
+      account,
+      ACCOUNT_WRAPPERS
+    )
+  );
+  const message = { accounts, instruction: payload.instruction };
+  if (payload.fx_rates) message.fx_rates = payload.fx_rates;
+  if (payload.aliases) message.aliases = payload.aliases;
+  return wrapInto(message, payload, REQUEST_WRAPPERS);
+}
+
+function fitsAccountResult(account) {
+  return (
+    account !== null &&
+    typeof account === 'object' &&
+    typeof account.id === 'string' &&
+    fitsWrapper(account.balance, 'number') &&
+    typeof account.currency === 'string' &&
+    (account.balance_before === undefined || fitsWrapper(account.balance_before, 'number'))
+  );
+}
+
+function toAccountResult(account) {
+  const { id, balance, balance_before: balanceBefore, currency, ...rest } = account;
+  const message = { id, balance, currency };
+  if (balanceBefore !== undefined) message.balance_before = { value: balanceBefore };
+  if (Object.keys(rest).length > 0) message.details = toStruct(rest);
+  return message;
+}
+
+function fromAccountResult(message) {
+  const account = { id: message.id, balance: message.balance };
+  if (isSet(message.balance_before)) account.balance_before = message.balance_before.value;
+  account.currency = message.currency;
+  return { ...account, ...fromStruct(message.details) };
+}
+
+/**
+ * The InstructionResponse for a service result: each field goes in its typed field when it
+ * has a value of that type, in details otherwise (nulls, and the fields the message does
+ * not list).
+ *
+ * @param {Object} result - payment-instructions result
+ * @returns {Object} InstructionResponse
+ */
+function toResponseMessage(result) {
+  const message = { accounts: [] };
+  const details = {};
+  const keys = Object.keys(result);
+  for (let i = 0; i < keys.length; i++) {
+    const key = keys[i];
+    const value = result[key];
+    const type = RESPONSE_WRAPPERS[key];
+    if (type !== undefined && fitsWrapper(value, type)) {
+      message[key] = { value };
+    } else if (key === 'accounts' && Array.isArray(value) && value.every(fitsAccountResult)) {
+      message.accounts = value.map(toAccountResult);
+    } else if (value !== undefined) {
+      details[key] = value;
+    }
+  }
+  message.details = toStruct(details);
+  return message;
+}
+
+/**
+ * The service result an InstructionResponse carries, exactly as the HTTP endpoint returns
+ * it in data.
+ *
+ * @param {Object} message - InstructionResponse
+ * @returns {Object} payment-instructions result
+ */
+function fromResponseMessage(message) {
+  const result = unwrapInto({}, message, RESPONSE_WRAPPERS);
+  result.accounts = (message.accounts || []).map(fromAccountResult);
+  return { ...result, ...fromStruct(message.details) };
+}
+
+module.exports = {
+  toStruct,
+  fromStruct,
+  fromRequestMessage,
+  toRequestMessage,
+  toResponseMessage,
+  fromResponseMessage,
+};
@@ -0,0 +1,48 @@
+const path = require('path');
+const createGrpcService = require('./create-grpc-service');
+
+const PROTO_PATH = path.join(__dirname, 'payment-instructions.proto');
+
+// How the messages are read: field names as in the proto, int64 as numbers, unset fields
+// as null and the set member of a oneof named in kind (see convert-messages.js)
+const LOADER_OPTIONS = {
+  keepCase: true,
+  longs: Number,
+  enums: String,
+  defaults: true,
+  oneofs: true,
+};
+
+/**
+ * Load payment-instructions.proto: the grpc-js module and the paymentinstructions.v1
+ * package, whose PaymentInstructions is both the service definition and the client
+ * constructor.
+ *
+ * @returns {{ grpc: Object, proto: Object }}
+ */
+function loadGrpcPackage() {
+  // Required here so that only gRPC users need the gRPC dependencies installed
+  // eslint-disable-next-line global-require
+  const grpc = require('@grpc/grpc-js');
+  // eslint-disable-next-line global-require
+  const protoLoader = require('@grpc/proto-loader');
+  const definition = protoLoader.loadSync(PROTO_PATH, LOADER_OPTIONS);
+  return { grpc, proto: grpc.loadPackageDefinition(definition).paymentinstructions.v1 };
+}
+
+/**
+ * A gRPC server with the PaymentInstructions service added, not yet bound: bind it with
+ * server.bindAsync(address, credentials, callback) (grpc.ServerCredentials.createInsecure()
+ * behind a TLS-terminating proxy, createSsl() otherwise).
+ *
+ * @param {Object} [options] - passed to the service on every call (see create-grpc-service.js)
+ * @returns {import('@grpc/grpc-js').Server}
+ */
+function createGrpcServer(options = {}) {
+  const { grpc, proto } = loadGrpcPackage();
+  const server = new grpc.Server();
+  server.addService(proto.PaymentInstructions.service, createGrpcService(options));
+  return server;
+}
+
+module.exports = { createGrpcServer, loadGrpcPackage, PROTO_PATH };
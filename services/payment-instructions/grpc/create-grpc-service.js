@@ -0,0 +1,67 @@
+const { ERROR_STATUS_CODE_MAPPING } = require('@app-core/errors');
+const { appLogger } = require('@app-core/logger');
+const paymentInstructions = require('../payment-instructions');
+const processIdempotentInstruction = require('../process-idempotent-instruction');
+const { fromRequestMessage, toResponseMessage } = require('./convert-messages');
+
+// gRPC status codes (see @grpc/grpc-js status), by the HTTP status the same error gets
+const GRPC_STATUS_BY_HTTP_STATUS = {
+  400: 3, // INVALID_ARGUMENT
+  401: 16, // UNAUTHENTICATED
+  403: 7, // PERMISSION_DENIED
+  404: 5, // NOT_FOUND
+  409: 6, // ALREADY_EXISTS
+  500: 13, // INTERNAL
+};
+
+// Run a service call for a unary call and answer it: the result as an InstructionResponse,
+// whatever its status, or the error a thrown application error maps to
+async function answer(call, callback, run, logName) {
+  let result;
+  try {
+    result = await run(fromRequestMessage(call.request));
+  } catch (err) {
+    if (err && err.isApplicationError) {
+      const httpStatus = ERROR_STATUS_CODE_MAPPING[err.errorCode] || 400;
+      callback({ code: GRPC_STATUS_BY_HTTP_STATUS[httpStatus] || 3, details: err.message });
+    } else {
+      appLogger.errorX(err, logName);
+      callback({ code: GRPC_STATUS_BY_HTTP_STATUS[500], details: 'Internal server error' });
+    }
+    return;
+  }
+  callback(null, toResponseMessage(result));
+}
+
+/**
+ * The PaymentInstructions service of payment-instructions.proto, as the implementation
+ * object server.addService() takes: Parse and Execute run the same service calls as the
+ * parse and execute HTTP handlers (see http/), so a request gives the same result over
+ * either transport. A refused instruction is a response like any other, with status
+ * "failed"; only what HTTP answers with an error envelope is a gRPC error (INVALID_ARGUMENT
+ * for a malformed payload, ALREADY_EXISTS for a reused idempotency key).
+ *
+ * An idempotency-key metadata entry on Execute works as the Idempotency-Key header does.
+ *
+ * @param {Object} [options] - passed to the service on every call (feePolicy,
+ *   accountStore, idempotencyStore, ...)
+ * @returns {{ Parse: Function, Execute: Function }}
+ */
+function createGrpcService(options = {}) {
+  return {
+    Parse(call, callback) {
+      const run = (payload) => paymentInstructions(payload, { ...options, parseOnly: true });
+      return answer(call, callback, run, 'payment-instructions.grpc.parse');
+    },
+    Execute(call, callback) {
+      const keys = call.metadata ? call.metadata.get('idempotency-key') : [];
+      const run = (payload) => {
+        const keyed = keys.length > 0 ? { ...payload, idempotency_key: String(keys[0]) } : payload;
+        return processIdempotentInstruction(keyed, options);
+      };
+      return answer(call, callback, run, 'payment-instructions.grpc.execute');
+    },
+  };
+}
+
+module.exports = createGrpcService;
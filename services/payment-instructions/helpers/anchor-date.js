@@ -0,0 +1,49 @@
+const { WEEKDAYS } = require('./constants');
+const getDaysInMonth = require('./get-days-in-month');
+
+const DAY_MS = 24 * 60 * 60 * 1000;
+
+/**
+ * The day a schedule anchor rule falls on in a month (month is 1-12), or null when the rule
+ * names no day. A rule is one of:
+ *   - { day: n }: the nth day of the month, counted from its end when n is negative (-1 is
+ *     the last day); a day past the end of a short month is its last day
+ *   - { weekday: 'monday', week: n }: the nth such weekday of the month (1-4), or the last
+ *     one for -1
+ * with an optional roll: 'previous' moves a Saturday or Sunday back to the Friday before,
+ * 'next' on to the Monday after (which can be in the following month).
+ * @param {Object} rule
+ * @param {number} year
+ * @param {number} month
+ * @returns {{ year: number, month: number, day: number }|null}
+ */
+function anchorDate(rule, year, month) {
+  const days = getDaysInMonth(year, month);
+  const target = rule ? WEEKDAYS.indexOf(String(rule.weekday).toLowerCase()) : -1;
+  let day = null;
+  if (rule && Number.isInteger(rule.day) && rule.day !== 0 && Math.abs(rule.day) <= 31) {
+    day = rule.day > 0 ? Math.min(rule.day, days) : Math.max(days + 1 + rule.day, 1);
+  } else if (target !== -1 && rule.week === -1) {
+    const lastWeekday = new Date(Date.UTC(year, month - 1, days)).getUTCDay();
+    day = days - ((lastWeekday - target + 7) % 7);
+  } else if (target !== -1 && Number.isInteger(rule.week) && rule.week >= 1 && rule.week <= 4) {
+    const firstWeekday = new Date(Date.UTC(year, month - 1, 1)).getUTCDay();
+    day = 1 + ((target - firstWeekday + 7) % 7) + 7 * (rule.week - 1);
+  }
+
+  let result = null;
+  if (day !== null) {
+    let ms = Date.UTC(year, month - 1, day);
+    const weekday = new Date(ms).getUTCDay();
+    if (rule.roll === 'previous' && (weekday === 0 || weekday === 6)) {
+      ms -= (weekday === 6 ? 1 : 2) * DAY_MS;
+    } else if (rule.roll === 'next' && (weekday === 0 || weekday === 6)) {
+      ms += (weekday === 6 ? 2 : 1) * DAY_MS;
+    }
+    const d = new Date(ms);
+    result = { year: d.getUTCFullYear(), month: d.getUTCMonth() + 1, day: d.getUTCDate() };
+  }
+  return result;
+}
+
+module.exports = anchorDate;
@@ -0,0 +1,72 @@
+// Freeze a record and everything in it
+function deepFreeze(value) {
+  if (value !== null && typeof value === 'object' && !Object.isFrozen(value)) {
+    Object.values(value).forEach(deepFreeze);
+    Object.freeze(value);
+  }
+  return value;
+}
+
+/**
+ * The audit record of one request: the raw instruction, the fields it was parsed into, the
+ * outcome and every reported account's balance before and after. A request that threw (a
+ * malformed payload, a reused idempotency key) has status "rejected", no status_code, the
+ * error message as status_reason and its errorCode as error_code. The record is frozen, so
+ * no sink can alter what the next one sees.
+ *
+ * @param {Object} input
+ * @param {Object} input.serviceData - the payload as received
+ * @param {Object} [input.result] - the result, when the request did not throw
+ * @param {Error} [input.error] - the error it threw instead
+ * @param {string} [input.idempotencyKey] - defaults to serviceData.idempotency_key
+ * @param {boolean} [input.replayed] - the result is an idempotent replay
+ * @param {Date} input.time - when the request was processed
+ * @returns {Object} frozen audit record
+ */
+function buildAuditRecord(input) {
+  const serviceData = input.serviceData || {};
+  const result = input.result || null;
+  const key = input.idempotencyKey || serviceData.idempotency_key || null;
+  const outcome =
+    result !== null
+      ? {
+          status: result.status,
+          status_code: result.status_code || null,
+          status_reason: result.status_reason || '',
+        }
+      : {
+          status: 'rejected',
+          status_code: null,
+          status_reason: input.error ? input.error.message : '',
+          error_code: (input.error && input.error.errorCode) || null,
+        };
+  // Unset for a rejected request
+  const fields = result || {};
+  const accounts = Array.isArray(fields.accounts) ? fields.accounts : [];
+  return deepFreeze({
+    transaction_id: fields.transaction_id || null,
+    timestamp: input.time.toISOString(),
+    instruction: typeof serviceData.instruction === 'string' ? serviceData.instruction : null,
+    idempotency_key: key,
+    parsed: {
+      type: fields.type || null,
+      amount: fields.amount !== undefined ? fields.amount : null,
+      currency: fields.currency || null,
+      debit_account: fields.debit_account || null,
+      credit_account: fields.credit_account || null,
+      execute_by: fields.execute_by || null,
+      narration: fields.narration || '',
+    },
+    ...outcome,
+    dry_run: fields.dry_run === true,
+    replayed: input.replayed === true,
+    accounts: accounts.map((a) => ({
+      id: a.id,
+      currency: a.currency,
+      balance_before: a.balance_before !== undefined ? a.balance_before : a.balance,
+      balance: a.balance,
+    })),
+  });
+}
+
+module.exports = buildAuditRecord;
@@ -0,0 +1,24 @@
+const { WARNING_CODES } = require('./constants');
+
+/**
+ * Warnings a successful result carries for the guesses behind it, one per kind of guess in
+ * the order first made; signals without a warning code are left out (see WARNING_CODES).
+ *
+ * @param {string[]} signals - confidence signals, plus 'amount_rounded' for a rounded share
+ * @param {Object} messages - message per warning code (PaymentMessages.WARNINGS)
+ * @returns {{code: string, message: string}[]}
+ */
+function buildWarnings(signals, messages) {
+  const warnings = [];
+  const seen = [];
+  for (let i = 0; i < signals.length; i++) {
+    const code = WARNING_CODES[signals[i]];
+    if (code !== undefined && seen.indexOf(code) === -1) {
+      seen.push(code);
+      warnings.push({ code, message: messages[code] });
+    }
+  }
+  return warnings;
+}
+
+module.exports = buildWarnings;
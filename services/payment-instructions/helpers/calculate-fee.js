@@ -0,0 +1,41 @@
+const toMinorUnits = require('./to-minor-units');
+const fromMinorUnits = require('./from-minor-units');
+const decimalToRatio = require('./decimal-to-ratio');
+const roundRatio = require('./round-ratio');
+const { ROUNDING_POLICY } = require('./constants');
+
+/**
+ * Fee for a transaction under a fee policy keyed by transaction type (see FEE_POLICY).
+ * The flat part is in the transaction currency and the percentage is of the amount; the
+ * percentage part is rounded to the currency's minor units under the rounding policy.
+ * Missing or negative parts count as 0.
+ *
+ * @param {number} amount - Amount in major units
+ * @param {string} type - Transaction type (DEBIT, CREDIT, SCHEDULE, STANDING_ORDER, SPLIT,
+ *   MULTI_DEBIT)
+ * @param {Object<string, { flat?: number, percent?: number }>} policy
+ * @param {string} currency
+ * @param {string} [rounding] - rounding policy (see ROUNDING_POLICY)
+ * @returns {number|null} Fee in major units, or null when the policy has no entry for type
+ */
+function calculateFee(amount, type, policy, currency, rounding = ROUNDING_POLICY) {
+  const rule =
+    policy && Object.prototype.hasOwnProperty.call(policy, type) ? policy[type] : null;
+  let fee = null;
+  if (rule !== null && typeof rule === 'object') {
+    const flat = Number(rule.flat) > 0 ? toMinorUnits(rule.flat, currency) : 0;
+    const percent = decimalToRatio(rule.percent);
+    const percentMinor =
+      percent !== null
+        ? roundRatio(
+            BigInt(toMinorUnits(amount, currency)) * percent.numerator,
+            100n * percent.denominator,
+            rounding
+          )
+        : 0n;
+    fee = fromMinorUnits(BigInt(flat) + percentMinor, currency);
+  }
+  return fee;
+}
+
+module.exports = calculateFee;
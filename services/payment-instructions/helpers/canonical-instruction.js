@@ -0,0 +1,45 @@
+const getCurrencyDecimals = require('./get-currency-decimals');
+
+// " EVERY 1 MONTH", " EVERY 2 WEEK ON MONDAY", " EVERY 1 MONTH ON PAYDAY"
+function recurrenceText(recurrence) {
+  let text = ` EVERY ${recurrence.count} ${String(recurrence.unit).toUpperCase()}`;
+  if (recurrence.weekday) text += ` ON ${String(recurrence.weekday).toUpperCase()}`;
+  if (recurrence.anchor) text += ` ON ${String(recurrence.anchor.name).toUpperCase()}`;
+  return text;
+}
+
+/**
+ * The canonical form of a parsed transfer between two accounts, the same however it was
+ * phrased: "TRANSFER <amount> <currency> FROM <debit id> TO <credit id>", with the amount
+ * in the currency's decimal places and the ids as the accounts have them, whatever verb,
+ * word order, amount notation, currency symbol or word, alias or abbreviated reference was
+ * written. "DEBIT 5k NGN FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc2" and "transfer ₦5,000
+ * from acc1 to rent" (rent an alias of acc2) both give "TRANSFER 5000.00 NGN FROM acc1 TO acc2".
+ *
+ * A dated instruction adds " ON <date>" (the UTC time for a schedule) and a standing order
+ * its recurrence (" EVERY 1 MONTH"), so neither reads the same as an immediate transfer.
+ * Narration, fees and balance guards are left out.
+ *
+ * @param {Object} parsed - amount, currency, debit_account, credit_account and optionally
+ *   execute_by (a date string or epoch seconds) and recurrence
+ * @returns {string|null} null unless the amount, currency and both accounts are known
+ */
+function canonicalInstruction(parsed) {
+  const { amount, currency } = parsed;
+  const debit = parsed.debit_account;
+  const credit = parsed.credit_account;
+  if (typeof amount !== 'number' || !currency || !debit || !credit) return null;
+
+  const decimals = getCurrencyDecimals(currency);
+  let text = `TRANSFER ${amount.toFixed(decimals)} ${currency} FROM ${debit} TO ${credit}`;
+  // An ON date stays the YYYY-MM-DD it was written as; a schedule is a Unix timestamp
+  if (typeof parsed.execute_by === 'string') text += ` ON ${parsed.execute_by}`;
+  else if (parsed.execute_by) {
+    const iso = new Date(parsed.execute_by * 1000).toISOString();
+    text += ` ON ${iso.substring(0, 19)}Z`;
+  }
+  if (parsed.recurrence) text += recurrenceText(parsed.recurrence);
+  return text;
+}
+
+module.exports = canonicalInstruction;
@@ -0,0 +1,514 @@
+// -----------------------------
+// Input normalization
+// -----------------------------
+
+// Code points of the digit zero in scripts whose digits 0-9 are consecutive from there
+// (Arabic-Indic, Extended Arabic-Indic, NKo, Devanagari, Bengali, Gurmukhi, Gujarati, Oriya,
+// Tamil, Telugu, Kannada, Malayalam, Sinhala, Thai, Lao, Tibetan, Myanmar, Khmer, Mongolian).
+// Fullwidth digits need no entry: NFKC already folds them.
+const DIGIT_ZEROS = [
+  0x0660, 0x06f0, 0x07c0, 0x0966, 0x09e6, 0x0a66, 0x0ae6, 0x0b66, 0x0be6, 0x0c66, 0x0ce6,
+  0x0d66, 0x0de6, 0x0e50, 0x0ed0, 0x0f20, 0x1040, 0x17e0, 0x1810,
+];
+
+// Characters read as a space (left over after NFKC, which already maps no-break, ideographic
+// and the other fixed-width spaces to a plain space)
+const SPACE_CHARACTERS = '\t\n\r\v\f\u0085\u1680\u2028\u2029';
+
+// Characters dropped altogether (zero-width spaces and joiners, byte order mark)
+const IGNORED_CHARACTERS = '\u200b\u200c\u200d\u2060\ufeff';
+
+// Other characters folded to ASCII: the Arabic decimal and thousands separators
+const FOLDED_CHARACTERS = {
+  '\u066b': '.',
+  '\u066c': ',',
+};
+
+// Longest instruction read, in characters after trimming (longer ones fail with SY04 before
+// they are tokenized); options.maxInstructionLength overrides it
+const MAX_INSTRUCTION_LENGTH = 512;
+
+// -----------------------------
+// Amount keywords
+// -----------------------------
+
+// Shorthand multipliers expressed as powers of ten (lowercase keys)
+const AMOUNT_SUFFIXES = {
+  k: 3,
+  m: 6,
+  mn: 6,
+  bn: 9,
+};
+
+// Indian scale words as powers of ten ("2 lakh" = 200000, "1.5 crore" = 15000000), read
+// only under an Indian locale (see amount-parsers/indian-amount-parser.js)
+const INDIAN_AMOUNT_WORDS = {
+  lakh: 5,
+  lakhs: 5,
+  lac: 5,
+  lacs: 5,
+  crore: 7,
+  crores: 7,
+};
+
+// Simple fractions of the debit balance
+const AMOUNT_FRACTIONS = {
+  half: { numerator: 1, denominator: 2 },
+  third: { numerator: 1, denominator: 3 },
+  quarter: { numerator: 1, denominator: 4 },
+};
+
+// Optional leading words for fractions ("a quarter", "one third")
+const FRACTION_ARTICLES = ['a', 'an', 'one'];
+
+// Keywords meaning the complete available balance of the debit account
+const SWEEP_WORDS = ['all', 'everything'];
+const SWEEP_BALANCE_WORDS = ['entire', 'full', 'whole'];
+const SWEEP_FILLER_WORDS = ['my', 'the'];
+
+// Words that make an amount an estimate ("about 500") or join two into a range ("100 to 200",
+// "100 or 200", "between 100 and 200"); such an amount is refused rather than guessed at
+const APPROXIMATE_AMOUNT_WORDS = ['about', 'around', 'approximately', 'approx', 'roughly', 'circa'];
+const AMOUNT_RANGE_WORDS = ['to', '-', 'or'];
+
+// -----------------------------
+// Number words
+// -----------------------------
+
+const NUMBER_UNITS = {
+  zero: 0,
+  one: 1,
+  two: 2,
+  three: 3,
+  four: 4,
+  five: 5,
+  six: 6,
+  seven: 7,
+  eight: 8,
+  nine: 9,
+};
+
+const NUMBER_TEENS = {
+  ten: 10,
+  eleven: 11,
+  twelve: 12,
+  thirteen: 13,
+  fourteen: 14,
+  fifteen: 15,
+  sixteen: 16,
+  seventeen: 17,
+  eighteen: 18,
+  nineteen: 19,
+};
+
+const NUMBER_TENS = {
+  twenty: 20,
+  thirty: 30,
+  forty: 40,
+  fifty: 50,
+  sixty: 60,
+  seventy: 70,
+  eighty: 80,
+  ninety: 90,
+};
+
+// Scale words above "hundred" must appear in strictly descending order
+const NUMBER_SCALES = {
+  thousand: 1000,
+  million: 1000000,
+  billion: 1000000000,
+};
+
+// Indefinite articles allowed before hundred/scale words ("a hundred")
+const NUMBER_ARTICLES = ['a', 'an'];
+
+// -----------------------------
+// Currencies
+// -----------------------------
+
+// Supported currencies: ISO 4217 code -> accepted word forms (lowercase).
+// A word listed under more than one code ("shillings") is resolved against the debit account.
+const SUPPORTED_CURRENCIES = {
+  NGN: ['naira'],
+  USD: ['dollar', 'dollars'],
+  GBP: ['pound', 'pounds', 'sterling'],
+  GHS: ['cedi', 'cedis'],
+  KES: ['ksh', 'kshs', 'shilling', 'shillings'],
+  ZAR: ['rand', 'rands'],
+  EUR: ['euro', 'euros'],
+  UGX: ['ush', 'shilling', 'shillings'],
+  JPY: ['yen'],
+  KWD: ['dinar', 'dinars'],
+};
+
+// Currency symbols -> the supported codes they may stand for (matched case-sensitively).
+// "$" would also cover other dollars, but USD is the only dollar supported today. A symbol
+// listed under more than one code ("Sh") is resolved against the request's accounts.
+const CURRENCY_SYMBOLS = {
+  '₦': ['NGN'],
+  $: ['USD'],
+  US$: ['USD'],
+  '£': ['GBP'],
+  '€': ['EUR'],
+  '₵': ['GHS'],
+  'GH₵': ['GHS'],
+  KSh: ['KES'],
+  USh: ['UGX'],
+  Sh: ['KES', 'UGX'],
+  R: ['ZAR'],
+  '¥': ['JPY'],
+};
+
+// Number of decimal places (minor unit exponent) per currency, used when parsing amounts
+// and for all minor-unit arithmetic; codes not listed use DEFAULT_CURRENCY_DECIMALS
+const CURRENCY_DECIMALS = {
+  NGN: 2,
+  USD: 2,
+  GBP: 2,
+  GHS: 2,
+  KES: 2,
+  ZAR: 2,
+  EUR: 2,
+  UGX: 0,
+  JPY: 0,
+  KWD: 3,
+};
+
+const DEFAULT_CURRENCY_DECIMALS = 2;
+
+// Keywords that may follow the amount directly when the currency is left out
+// ("transfer 5000 from acc1 to acc2", "deposit 2000 into acc1")
+const CURRENCYLESS_KEYWORDS = ['from', 'to', 'into'];
+
+// Words a leading sentence may declare the currency in ("Use USD.", "Currency: naira.",
+// "All amounts are in USD."), before the instruction proper
+const CURRENCY_DECLARATION_WORDS = [
+  'use',
+  'using',
+  'currency',
+  'currency:',
+  'is',
+  'in',
+  'all',
+  'amounts',
+  'are',
+  'pay',
+  'the',
+];
+
+// Longest currency declaration sentence read, in tokens
+const CURRENCY_DECLARATION_MAX_TOKENS = 5;
+
+// ISO 4217 alphabetic codes in use (currencies, funds and precious metals). A three-letter
+// currency token outside this list is not a currency code at all (CU04) rather than an
+// unsupported one (CU02); push further codes here to accept them as real.
+const ISO_4217_CODES = [
+  'AED AFN ALL AMD ANG AOA ARS AUD AWG AZN',
+  'BAM BBD BDT BGN BHD BIF BMD BND BOB BOV BRL BSD BTN BWP BYN BZD',
+  'CAD CDF CHE CHF CHW CLF CLP CNY COP COU CRC CUC CUP CVE CZK',
+  'DJF DKK DOP DZD',
+  'EGP ERN ETB EUR',
+  'FJD FKP',
+  'GBP GEL GHS GIP GMD GNF GTQ GYD',
+  'HKD HNL HTG HUF',
+  'IDR ILS INR IQD IRR ISK',
+  'JMD JOD JPY',
+  'KES KGS KHR KMF KPW KRW KWD KYD KZT',
+  'LAK LBP LKR LRD LSL LYD',
+  'MAD MDL MGA MKD MMK MNT MOP MRU MUR MVR MWK MXN MXV MYR MZN',
+  'NAD NGN NIO NOK NPR NZD',
+  'OMR',
+  'PAB PEN PGK PHP PKR PLN PYG',
+  'QAR',
+  'RON RSD RUB RWF',
+  'SAR SBD SCR SDG SEK SGD SHP SLE SLL SOS SRD SSP STN SVC SYP SZL',
+  'THB TJS TMT TND TOP TRY TTD TWD TZS',
+  'UAH UGX USD USN UYI UYU UYW UZS',
+  'VED VES VND VUV',
+  'WST',
+  'XAF XAG XAU XBA XBB XBC XBD XCD XCG XDR XOF XPD XPF XPT XSU XTS XUA XXX',
+  'YER',
+  'ZAR ZMW ZWG ZWL',
+]
+  .join(' ')
+  .split(' ');
+
+// -----------------------------
+// Schedule dates
+// -----------------------------
+
+// Indexed like Date#getUTCDay (0 = sunday)
+const WEEKDAYS = ['sunday', 'monday', 'tuesday', 'wednesday', 'thursday', 'friday', 'saturday'];
+
+// Units accepted in "in <n> <unit>" offsets, expressed in days
+const DAY_OFFSET_UNITS = {
+  day: 1,
+  days: 1,
+  week: 7,
+  weeks: 7,
+};
+
+// Named days a schedule can be set against ("on the last business day of the month", "every
+// first business day"); options.scheduleAnchors adds to or overrides these, e.g.
+// { payday: { day: 25 } }. Rules are read by anchor-date.js
+const SCHEDULE_ANCHORS = {
+  'first business day': { day: 1, roll: 'next' },
+  'last business day': { day: -1, roll: 'previous' },
+  'last day': { day: -1 },
+};
+
+// "first monday" ... "last friday": the week of the month a weekday anchor falls in
+const ANCHOR_WEEKS = {
+  first: 1,
+  second: 2,
+  third: 3,
+  fourth: 4,
+  last: -1,
+};
+
+// -----------------------------
+// Standing order recurrence
+// -----------------------------
+
+// "every <n> <unit>" -> interval unit; nothing shorter than a day is accepted
+const RECURRENCE_UNITS = {
+  day: 'day',
+  days: 'day',
+  week: 'week',
+  weeks: 'week',
+  month: 'month',
+  months: 'month',
+};
+
+// Single-word recurrences
+const RECURRENCE_ADVERBS = {
+  daily: 'day',
+  weekly: 'week',
+  monthly: 'month',
+};
+
+// Most upcoming runs a schedule preview lists
+const PREVIEW_MAX_COUNT = 366;
+
+// -----------------------------
+// Narration
+// -----------------------------
+
+// Longest narration kept in responses (longer text is cut)
+const NARRATION_MAX_LENGTH = 140;
+
+// -----------------------------
+// Fees
+// -----------------------------
+
+// Fee per transaction type, debited from the debit account on top of the amount:
+//   { TYPE: { flat?: <amount in the transaction currency>, percent?: <% of the amount> } }
+// e.g. { DEBIT: { flat: 50 }, SPLIT: { flat: 10, percent: 0.5 } }. Types without an entry
+// pay no fee; empty means fees are disabled. options.feePolicy overrides it per call.
+const FEE_POLICY = {};
+
+// Words that may lead into a fee-source clause ("..., fee from acc3", "and pay the fee from
+// acc3", "with the fees from acc3"); the clause itself is FEE or FEES, FROM, an optional
+// ACCOUNT and the account that bears the fee
+const FEE_SOURCE_FILLERS = ['and', 'pay', 'with', 'the'];
+
+// Comparisons a balance guard may make ("only if balance above 1000"); "at least" is read
+// as two words
+const CONDITION_COMPARISONS = {
+  above: 'above',
+  over: 'above',
+  below: 'below',
+  under: 'below',
+};
+
+// -----------------------------
+// Parsed instruction JSON
+// -----------------------------
+
+// Fields every serialized parsed instruction carries, in this order, null when not set
+// (fee is null until execution works it out); the optional ones follow in key order
+const PARSED_INSTRUCTION_FIELDS = [
+  'type',
+  'amount',
+  'currency',
+  'debit_account',
+  'credit_account',
+  'execute_by',
+  'narration',
+  'fee',
+];
+
+// -----------------------------
+// Transaction limits
+// -----------------------------
+
+// Largest amount one transaction may move, per transaction type and currency:
+//   { TYPE: { CURRENCY: <max amount in major units> } }
+// e.g. { DEBIT: { NGN: 5000000, USD: 10000 }, WITHDRAW: { NGN: 200000 } }. An amount equal to
+// the cap is allowed; types or currencies without an entry have no cap. options.maxAmountPolicy
+// overrides it per call.
+const MAX_AMOUNT_POLICY = {};
+
+// Account statuses that keep an account out of any transaction, and the code each fails with
+// (lowercase keys; a missing status or "active" takes part as usual)
+const BLOCKED_ACCOUNT_STATUSES = {
+  frozen: 'AC08',
+  closed: 'AC09',
+};
+
+// What a negative starting balance means: "overdraft" accepts one down to minus the account's
+// overdraft_limit, "reject" accepts none; anything lower fails with AC10 before the
+// instruction is read. options.negativeBalancePolicy overrides it per call.
+const NEGATIVE_BALANCE_POLICY = 'overdraft';
+
+// How a fraction of a minor unit is rounded wherever an amount is worked out (balance
+// shares, FX conversion, percentage fees): 'half_even' (banker's rounding), 'half_up' or
+// 'truncate' (toward zero). options.roundingPolicy overrides it per call.
+const ROUNDING_POLICY = 'half_even';
+const ROUNDING_POLICIES = ['half_even', 'half_up', 'truncate'];
+
+// -----------------------------
+// Parse confidence
+// -----------------------------
+
+// How much each kind of guess lowers the confidence score (applied multiplicatively, once
+// per occurrence). A literal instruction with exact account ids scores 1.
+const CONFIDENCE_PENALTIES = {
+  case_insensitive: 0.05, // account id matched ignoring case
+  word_amount: 0.05, // amount written in words ("five hundred")
+  alias: 0.1, // account named by alias
+  balance_share: 0.1, // amount inferred from the debit balance ("half", "all")
+  currency_inferred: 0.15, // currency word or symbol shared by several codes
+  partial_account: 0.25, // account found by its trailing digits
+  fuzzy: 0.3, // mistyped verb or currency word corrected ("trasnfer")
+};
+
+// Guesses a successful result warns about, and the warning code each is reported under (see
+// PaymentMessages.WARNINGS). A word amount or a balance share is no warning in itself, but a
+// share the currency's minor units cannot hold exactly is rounded (amount_rounded).
+const WARNING_CODES = {
+  currency_inferred: 'currency_inferred',
+  amount_rounded: 'amount_rounded',
+  alias: 'alias_used',
+  fuzzy: 'keyword_corrected',
+  partial_account: 'account_by_digits',
+  case_insensitive: 'account_case_corrected',
+};
+
+// Verbs an instruction may follow with an account instead of the amount, and the side that
+// account is on: "debit acc1 100 NGN to acc2", "credit acc2 with 100 NGN from acc1",
+// "pay acc2 100 NGN from acc1" (a payment credits the account it names first)
+const ACCOUNT_FIRST_VERBS = {
+  debit: 'DEBIT',
+  credit: 'CREDIT',
+  pay: 'CREDIT',
+};
+
+// Optional filler between that account and the amount ("credit acc2 with 100 NGN")
+const ACCOUNT_FIRST_FILLERS = ['with', 'by'];
+
+// Everyday verbs read as one of the parser's own: "move 100 NGN from acc1 to acc2" is a
+// TRANSFER and "settle 5000 NGN to DSTV" a PAY. The collection verbs (COLLECT_VERBS) read as
+// a transfer, or as a debit when the customer comes first ("charge acc1 100 NGN to acc2")
+const VERB_SYNONYMS = {
+  move: 'transfer',
+  send: 'transfer',
+  wire: 'transfer',
+  remit: 'transfer',
+  collect: 'transfer',
+  charge: 'transfer',
+  settle: 'pay',
+};
+
+// Pull payments: the debit account is the customer and the result is typed COLLECT
+const COLLECT_VERBS = ['collect', 'charge'];
+
+// Leading keywords that opt-in fuzzy matching may correct ("debt" -> debit, "sned" -> send)
+const FUZZY_VERBS = [
+  'debit',
+  'credit',
+  'transfer',
+  'split',
+  'schedule',
+  'withdraw',
+  'deposit',
+  ...Object.keys(VERB_SYNONYMS),
+];
+
+// Characters an account id format may restrict ids to (options.accountIdFormat.characters)
+const ACCOUNT_ID_CHARACTERS = {
+  digits: '0123456789',
+  letters: 'abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ',
+  alphanumeric: 'abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789',
+};
+
+// Words that open an instruction (before its amount); a compound clause without them reuses
+// the first clause's ("DEBIT 100 NGN ... and 200 NGN ..." debits twice)
+const LEADING_KEYWORDS = [
+  'debit',
+  'credit',
+  'transfer',
+  'split',
+  'schedule',
+  'standing',
+  'order',
+  'withdraw',
+  'deposit',
+  ...Object.keys(VERB_SYNONYMS),
+];
+
+module.exports = {
+  DIGIT_ZEROS,
+  SPACE_CHARACTERS,
+  IGNORED_CHARACTERS,
+  FOLDED_CHARACTERS,
+  MAX_INSTRUCTION_LENGTH,
+  AMOUNT_SUFFIXES,
+  INDIAN_AMOUNT_WORDS,
+  AMOUNT_FRACTIONS,
+  FRACTION_ARTICLES,
+  SWEEP_WORDS,
+  SWEEP_BALANCE_WORDS,
+  SWEEP_FILLER_WORDS,
+  APPROXIMATE_AMOUNT_WORDS,
+  AMOUNT_RANGE_WORDS,
+  NUMBER_UNITS,
+  NUMBER_TEENS,
+  NUMBER_TENS,
+  NUMBER_SCALES,
+  NUMBER_ARTICLES,
+  SUPPORTED_CURRENCIES,
+  CURRENCYLESS_KEYWORDS,
+  CURRENCY_DECLARATION_WORDS,
+  CURRENCY_DECLARATION_MAX_TOKENS,
+  CURRENCY_SYMBOLS,
+  CURRENCY_DECIMALS,
+  DEFAULT_CURRENCY_DECIMALS,
+  ISO_4217_CODES,
+  WEEKDAYS,
+  DAY_OFFSET_UNITS,
+  SCHEDULE_ANCHORS,
+  ANCHOR_WEEKS,
+  RECURRENCE_UNITS,
+  RECURRENCE_ADVERBS,
+  PREVIEW_MAX_COUNT,
+  NARRATION_MAX_LENGTH,
+  FEE_POLICY,
+  FEE_SOURCE_FILLERS,
+  CONDITION_COMPARISONS,
+  MAX_AMOUNT_POLICY,
+  BLOCKED_ACCOUNT_STATUSES,
+  NEGATIVE_BALANCE_POLICY,
+  ROUNDING_POLICY,
+  ROUNDING_POLICIES,
+  PARSED_INSTRUCTION_FIELDS,
+  CONFIDENCE_PENALTIES,
+  WARNING_CODES,
+  ACCOUNT_FIRST_VERBS,
+  VERB_SYNONYMS,
+  COLLECT_VERBS,
+  ACCOUNT_FIRST_FILLERS,
+  FUZZY_VERBS,
+  LEADING_KEYWORDS,
+  ACCOUNT_ID_CHARACTERS,
+};
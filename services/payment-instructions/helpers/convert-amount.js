@@ -0,0 +1,31 @@
+const getCurrencyDecimals = require('./get-currency-decimals');
+const toMinorUnits = require('./to-minor-units');
+const fromMinorUnits = require('./from-minor-units');
+const decimalToRatio = require('./decimal-to-ratio');
+const roundRatio = require('./round-ratio');
+const { ROUNDING_POLICY } = require('./constants');
+
+/**
+ * Convert an amount between currencies at the given rate (1 fromCurrency = rate toCurrency),
+ * rounding to the minor units of the destination currency under the rounding policy.
+ *
+ * @param {number} amount - Amount in major units of fromCurrency
+ * @param {number} rate
+ * @param {string} fromCurrency
+ * @param {string} toCurrency
+ * @param {string} [rounding] - rounding policy (see ROUNDING_POLICY)
+ * @returns {number|null} Converted amount in major units of toCurrency, or null for a bad rate
+ */
+function convertAmount(amount, rate, fromCurrency, toCurrency, rounding = ROUNDING_POLICY) {
+  const ratio = decimalToRatio(rate);
+  let converted = null;
+  if (ratio !== null) {
+    const minor = BigInt(toMinorUnits(amount, fromCurrency));
+    const numerator = minor * ratio.numerator * 10n ** BigInt(getCurrencyDecimals(toCurrency));
+    const denominator = ratio.denominator * 10n ** BigInt(getCurrencyDecimals(fromCurrency));
+    converted = fromMinorUnits(roundRatio(numerator, denominator, rounding), toCurrency);
+  }
+  return converted;
+}
+
+module.exports = convertAmount;
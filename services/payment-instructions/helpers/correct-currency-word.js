@@ -0,0 +1,21 @@
+const { SUPPORTED_CURRENCIES } = require('./constants');
+const correctKeyword = require('./correct-keyword');
+
+const CURRENCY_WORDS = [];
+Object.keys(SUPPORTED_CURRENCIES).forEach((code) => {
+  SUPPORTED_CURRENCIES[code].forEach((word) => {
+    if (CURRENCY_WORDS.indexOf(word) === -1) CURRENCY_WORDS.push(word);
+  });
+});
+
+/**
+ * Opt-in typo tolerance for currency words: "niara" -> 'naira', "dolars" -> 'dollars'.
+ * Codes and symbols are never corrected. Returns null when no word is close enough.
+ * @param {string} token
+ * @returns {string|null}
+ */
+function correctCurrencyWord(token) {
+  return correctKeyword(token, CURRENCY_WORDS);
+}
+
+module.exports = correctCurrencyWord;
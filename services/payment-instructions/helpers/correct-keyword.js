@@ -0,0 +1,71 @@
+/**
+ * Edit distance between two words, counting a swap of adjacent letters as one edit
+ * ("trasnfer" is one edit from "transfer"). Gives up and returns max + 1 once every
+ * alignment needs more than max edits.
+ */
+function editDistance(a, b, max) {
+  if (Math.abs(a.length - b.length) > max) return max + 1;
+  let twoBack = [];
+  let previous = [];
+  for (let j = 0; j <= b.length; j++) previous.push(j);
+  for (let i = 1; i <= a.length; i++) {
+    const current = [i];
+    let rowMin = i;
+    for (let j = 1; j <= b.length; j++) {
+      const cost = a[i - 1] === b[j - 1] ? 0 : 1;
+      let d = Math.min(previous[j] + 1, current[j - 1] + 1, previous[j - 1] + cost);
+      if (i > 1 && j > 1 && a[i - 1] === b[j - 2] && a[i - 2] === b[j - 1]) {
+        d = Math.min(d, twoBack[j - 2] + 1);
+      }
+      current.push(d);
+      if (d < rowMin) rowMin = d;
+    }
+    if (rowMin > max) return max + 1;
+    twoBack = previous;
+    previous = current;
+  }
+  return previous[b.length];
+}
+
+/**
+ * Edits tolerated for a word of this length: none up to 3 letters ("pay" never becomes
+ * "day"), one up to 5 letters, two beyond.
+ */
+function allowedEdits(length) {
+  if (length <= 3) return 0;
+  if (length <= 5) return 1;
+  return 2;
+}
+
+/**
+ * Opt-in typo tolerance: the vocabulary word a mistyped token was meant to be, e.g.
+ * "Trasnfer" -> 'transfer', "debt" -> 'debit'. The word must start with the same letter
+ * and be the only closest match within the allowed edits.
+ * Returns null when the token is already a vocabulary word or nothing is close enough.
+ * @param {string} token
+ * @param {string[]} vocabulary - lowercase words
+ * @returns {string|null}
+ */
+function correctKeyword(token, vocabulary) {
+  const lower = String(token || '').toLowerCase();
+  if (lower.length === 0 || vocabulary.indexOf(lower) !== -1) return null;
+  let best = null;
+  let bestDistance = Infinity;
+  let tied = false;
+  for (let i = 0; i < vocabulary.length; i++) {
+    const word = vocabulary[i];
+    const max = allowedEdits(Math.min(word.length, lower.length));
+    const distance = word[0] === lower[0] ? editDistance(lower, word, max) : Infinity;
+    const close = distance <= max;
+    if (close && distance < bestDistance) {
+      best = word;
+      bestDistance = distance;
+      tied = false;
+    } else if (close && distance === bestDistance && word !== best) {
+      tied = true;
+    }
+  }
+  return tied ? null : best;
+}
+
+module.exports = correctKeyword;
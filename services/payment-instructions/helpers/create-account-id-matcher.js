@@ -0,0 +1,74 @@
+const { ACCOUNT_ID_CHARACTERS } = require('./constants');
+
+// Keys a declarative format may have, and the type each takes
+const FORMAT_KEYS = {
+  prefix: 'string',
+  length: 'number',
+  minLength: 'number',
+  maxLength: 'number',
+  characters: 'string',
+};
+
+function isCount(value) {
+  return Number.isInteger(value) && value >= 0;
+}
+
+/**
+ * Throw a TypeError unless format is a declarative account id format (see
+ * createAccountIdMatcher).
+ */
+function checkFormat(format) {
+  if (typeof format !== 'object' || Array.isArray(format)) {
+    throw new TypeError('account id format must be a function or an object');
+  }
+  Object.keys(format).forEach((key) => {
+    const type = FORMAT_KEYS[key];
+    if (type === undefined || typeof format[key] !== type) {
+      throw new TypeError(`account id format: invalid ${key}`);
+    }
+    if (type === 'number' && !isCount(format[key])) {
+      throw new TypeError(`account id format: invalid ${key}`);
+    }
+  });
+  const { characters } = format;
+  const known = Object.prototype.hasOwnProperty.call(ACCOUNT_ID_CHARACTERS, characters);
+  if (characters !== undefined && !known) {
+    throw new TypeError(`account id format: unknown characters ${characters}`);
+  }
+}
+
+/**
+ * A check for the shape of account ids a caller's accounts follow (options.accountIdFormat),
+ * run on every account an instruction names before it is looked up (no regex). The format is
+ * either a predicate, `(id) => boolean`, or a declarative object whose every given rule must
+ * hold:
+ *   prefix               the id starts with it
+ *   length               exactly this many characters
+ *   minLength, maxLength at least / at most this many
+ *   characters           'digits', 'letters' or 'alphanumeric' (after the prefix)
+ * so ten-digit ids are { length: 10, characters: 'digits' }. Without a format every id passes.
+ *
+ * Throws a TypeError for a format that is neither, or has an unknown key or value.
+ *
+ * @param {Function|Object|null|undefined} format
+ * @returns {(id: string) => boolean}
+ */
+function createAccountIdMatcher(format) {
+  if (format === null || format === undefined) return () => true;
+  if (typeof format === 'function') return (id) => Boolean(format(String(id)));
+  checkFormat(format);
+  const allowed = format.characters ? ACCOUNT_ID_CHARACTERS[format.characters] : null;
+  const prefix = format.prefix || '';
+  return (id) => {
+    const text = String(id);
+    if (!text.startsWith(prefix)) return false;
+    if (format.length !== undefined && text.length !== format.length) return false;
+    if (format.minLength !== undefined && text.length < format.minLength) return false;
+    if (format.maxLength !== undefined && text.length > format.maxLength) return false;
+    if (allowed === null) return true;
+    const rest = text.substring(prefix.length);
+    return rest.split('').every((c) => allowed.indexOf(c) !== -1);
+  };
+}
+
+module.exports = createAccountIdMatcher;
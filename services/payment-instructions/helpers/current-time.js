@@ -0,0 +1,16 @@
+const systemClock = require('../clocks/system-clock');
+
+/**
+ * The time an instruction runs at: options.now (a Date or epoch ms) pins it; otherwise it
+ * is read from options.clock, or the system clock without one.
+ *
+ * @param {{ now?: Date|number, clock?: { now: () => number } }} [options]
+ * @returns {Date}
+ */
+function currentTime(options = {}) {
+  if (options.now !== undefined) return new Date(options.now);
+  const clock = options.clock || systemClock;
+  return new Date(clock.now());
+}
+
+module.exports = currentTime;
@@ -0,0 +1,22 @@
+/**
+ * Express a positive decimal number as an exact BigInt ratio (0.00065 -> 65 / 100000).
+ * Returns null when the value is not a finite positive number.
+ *
+ * @param {number|string} value
+ * @returns {{numerator: bigint, denominator: bigint}|null}
+ */
+function decimalToRatio(value) {
+  const n = Number(value);
+  if (!Number.isFinite(n) || n <= 0) return null;
+  let s = String(n);
+  if (s.indexOf('e') !== -1) s = n.toFixed(20);
+  const dot = s.indexOf('.');
+  const intDigits = dot === -1 ? s : s.substring(0, dot);
+  const fracDigits = dot === -1 ? '' : s.substring(dot + 1);
+  return {
+    numerator: BigInt(intDigits + fracDigits),
+    denominator: 10n ** BigInt(fracDigits.length),
+  };
+}
+
+module.exports = decimalToRatio;
@@ -0,0 +1,35 @@
+function listIds(ids) {
+  return ids.length > 1 ? `${ids.slice(0, -1).join(', ')} and ${ids[ids.length - 1]}` : ids[0];
+}
+
+/**
+ * Name the currencies a currency check compared, for the status reason: what each account
+ * holds and, when that does not show it, what the instruction is in.
+ *   "acc1 holds NGN, usd1 holds USD"
+ *   "the instruction is in USD, acc1 and acc2 hold NGN"
+ *   "acc1 holds NGN, usd1 holds USD; the instruction is in GBP"
+ *
+ * @param {string} currency - the instruction currency
+ * @param {{ id: string, currency: string }[]} accounts - the resolved accounts, debit first
+ * @returns {string}
+ */
+function describeCurrencyMismatch(currency, accounts) {
+  const groups = [];
+  accounts.forEach((account) => {
+    const held = String(account.currency || '').toUpperCase();
+    const group = groups.find((g) => g.currency === held);
+    if (group && group.ids.indexOf(account.id) === -1) group.ids.push(account.id);
+    else if (!group) groups.push({ currency: held, ids: [account.id] });
+  });
+  const holdings = groups
+    .map((g) => `${listIds(g.ids)} ${g.ids.length > 1 ? 'hold' : 'holds'} ${g.currency}`)
+    .join(', ');
+  let text = holdings;
+  if (groups.length === 1) text = `the instruction is in ${currency}, ${holdings}`;
+  else if (!groups.some((g) => g.currency === currency)) {
+    text = `${holdings}; the instruction is in ${currency}`;
+  }
+  return text;
+}
+
+module.exports = describeCurrencyMismatch;
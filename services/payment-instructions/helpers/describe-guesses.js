@@ -0,0 +1,17 @@
+/**
+ * The guesses behind a parse in words, one per kind of guess in the order first made and
+ * joined with commas, for a strict-mode refusal.
+ *
+ * @param {string[]} signals - confidence signals
+ * @param {Object} descriptions - wording per signal (PaymentMessages.STRICT_MODE_GUESSES)
+ * @returns {string}
+ */
+function describeGuesses(signals, descriptions) {
+  const seen = [];
+  for (let i = 0; i < signals.length; i++) {
+    if (seen.indexOf(signals[i]) === -1) seen.push(signals[i]);
+  }
+  return seen.map((signal) => descriptions[signal] || signal).join(', ');
+}
+
+module.exports = describeGuesses;
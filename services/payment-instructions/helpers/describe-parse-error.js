@@ -0,0 +1,28 @@
+const tokenSpans = require('./token-spans');
+
+/**
+ * Build the optional parse_error detail for a failed instruction: which segment could not
+ * be resolved (verb, amount, currency, debit, credit) and where it sits in the instruction.
+ *
+ * A segment that is missing altogether points just past the instruction with length 0.
+ *
+ * @param {string} instruction - the instruction as received
+ * @param {string[]} tokens - its tokens
+ * @param {string} segment
+ * @param {number} index - first token of the segment (tokens.length or more when missing)
+ * @param {number} [count] - tokens in the segment ("five hundred" is 2)
+ * @returns {{ segment: string, token: string|null, offset: number, length: number }}
+ */
+function describeParseError(instruction, tokens, segment, index, count = 1) {
+  const text = String(instruction || '');
+  if (index >= tokens.length) {
+    return { segment, token: null, offset: text.trimEnd().length, length: 0 };
+  }
+  const spans = tokenSpans(text, tokens);
+  const last = spans[Math.min(index + count, tokens.length) - 1];
+  const offset = spans[index].offset;
+  const length = Math.max(last.offset + last.length - offset, spans[index].length);
+  return { segment, token: text.substring(offset, offset + length), offset, length };
+}
+
+module.exports = describeParseError;
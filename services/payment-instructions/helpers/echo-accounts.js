@@ -0,0 +1,24 @@
+/**
+ * The involved accounts with their balances unchanged, in request order, as a failed
+ * instruction reports them: balance_before equals balance.
+ * @param {{ id: string, balance: number, currency: string }[]} accounts - request accounts
+ * @param {string[]} ids - ids of the involved accounts
+ * @returns {Object[]}
+ */
+function echoAccounts(accounts, ids) {
+  const accountsOut = [];
+  for (let i = 0; i < accounts.length; i++) {
+    const a = accounts[i];
+    if (ids.indexOf(a.id) !== -1) {
+      accountsOut.push({
+        id: a.id,
+        balance: a.balance,
+        balance_before: a.balance,
+        currency: String(a.currency || '').toUpperCase(),
+      });
+    }
+  }
+  return accountsOut;
+}
+
+module.exports = echoAccounts;
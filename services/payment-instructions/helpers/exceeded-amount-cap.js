@@ -0,0 +1,26 @@
+const toMinorUnits = require('./to-minor-units');
+
+/**
+ * The per-transaction cap an amount goes over, under a policy keyed by transaction type and
+ * then currency (see MAX_AMOUNT_POLICY). Amounts are compared in minor units, so an amount
+ * equal to the cap is within it.
+ *
+ * @param {number} amount - Amount in major units
+ * @param {string} type - Transaction type (DEBIT, CREDIT, SCHEDULE, STANDING_ORDER, SPLIT,
+ *   MULTI_DEBIT, WITHDRAW, DEPOSIT, PAY)
+ * @param {string} currency
+ * @param {Object<string, Object<string, number>>} policy
+ * @returns {number|null} The cap when amount exceeds it; null when within it or uncapped
+ */
+function exceededAmountCap(amount, type, currency, policy) {
+  const caps = policy && Object.prototype.hasOwnProperty.call(policy, type) ? policy[type] : null;
+  const hasCap =
+    caps !== null &&
+    typeof caps === 'object' &&
+    Object.prototype.hasOwnProperty.call(caps, currency);
+  const cap = hasCap ? Number(caps[currency]) : NaN;
+  if (Number.isNaN(cap)) return null;
+  return toMinorUnits(amount, currency) > toMinorUnits(cap, currency) ? cap : null;
+}
+
+module.exports = exceededAmountCap;
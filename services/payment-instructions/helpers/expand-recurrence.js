@@ -0,0 +1,48 @@
+const getDaysInMonth = require('./get-days-in-month');
+const anchorDate = require('./anchor-date');
+
+const DAY_MS = 24 * 60 * 60 * 1000;
+
+/**
+ * The first count run dates of a recurrence, starting with its first run. Monthly runs keep
+ * the day of the first run, moved back to the last day of a month too short for it (a
+ * recurrence starting on the 31st runs on the 28th or 29th in February and on the 31st again
+ * in March); daily and weekly runs are every count days or weeks. A recurrence with an anchor
+ * ("every payday") runs on the day its rule gives in each month instead (see anchor-date.js).
+ * @param {{ year: number, month: number, day: number }} first
+ * @param {{ unit: string, count: number, anchor?: { rule: Object } }} recurrence - unit is
+ *   day, week or month
+ * @param {number} count
+ * @returns {{ year: number, month: number, day: number }[]}
+ */
+function expandRecurrence(first, recurrence, count) {
+  const dates = [];
+  const step = recurrence.count;
+  const start = Date.UTC(first.year, first.month - 1, first.day);
+  if (recurrence.anchor) {
+    // A day rolled back across a month's start belongs to the month after, so months are
+    // read from the one before the first run and only days from the first run on kept
+    for (let k = -1; dates.length < count && k <= count; k++) {
+      const months = first.month - 1 + k * step;
+      const year = first.year + Math.floor(months / 12);
+      const d = anchorDate(recurrence.anchor.rule, year, (((months % 12) + 12) % 12) + 1);
+      if (d !== null && Date.UTC(d.year, d.month - 1, d.day) >= start) dates.push(d);
+    }
+    return dates;
+  }
+  for (let k = 0; k < count; k++) {
+    if (recurrence.unit === 'month') {
+      const months = first.month - 1 + k * step;
+      const year = first.year + Math.floor(months / 12);
+      const month = (months % 12) + 1;
+      dates.push({ year, month, day: Math.min(first.day, getDaysInMonth(year, month)) });
+    } else {
+      const days = (recurrence.unit === 'week' ? 7 : 1) * step * k;
+      const d = new Date(start + days * DAY_MS);
+      dates.push({ year: d.getUTCFullYear(), month: d.getUTCMonth() + 1, day: d.getUTCDate() });
+    }
+  }
+  return dates;
+}
+
+module.exports = expandRecurrence;
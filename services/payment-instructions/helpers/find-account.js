@@ -0,0 +1,15 @@
+/**
+ * Find account in provided accounts array preserving original order knowledge.
+ * Returns { index, account } or null.
+ * @param {{ id: string }[]} accounts
+ * @param {string} id
+ * @returns {{ index: number, account: Object }|null}
+ */
+function findAccount(accounts, id) {
+  for (let i = 0; i < accounts.length; i++) {
+    if (accounts[i].id === id) return { index: i, account: accounts[i] };
+  }
+  return null;
+}
+
+module.exports = findAccount;
@@ -0,0 +1,18 @@
+/**
+ * Ids of the accounts whose id ends with the given digits, in request order.
+ * @param {{ id: string }[]} accounts
+ * @param {string} suffix
+ * @returns {string[]}
+ */
+function findAccountsBySuffix(accounts, suffix) {
+  const ids = [];
+  for (let i = 0; i < accounts.length; i++) {
+    const id = String(accounts[i].id);
+    if (id.length >= suffix.length && id.substring(id.length - suffix.length) === suffix) {
+      ids.push(accounts[i].id);
+    }
+  }
+  return ids;
+}
+
+module.exports = findAccountsBySuffix;
@@ -0,0 +1,17 @@
+/**
+ * Ids of the accounts whose id equals the given id ignoring case, in request order.
+ * More than one result means the accounts differ only by case.
+ * @param {{ id: string }[]} accounts
+ * @param {string} id
+ * @returns {string[]}
+ */
+function findAccountsIgnoringCase(accounts, id) {
+  const wanted = String(id).toLowerCase();
+  const ids = [];
+  for (let i = 0; i < accounts.length; i++) {
+    if (String(accounts[i].id).toLowerCase() === wanted) ids.push(accounts[i].id);
+  }
+  return ids;
+}
+
+module.exports = findAccountsIgnoringCase;
@@ -0,0 +1,51 @@
+const parseAmount = require('./parse-amount');
+const findAccount = require('./find-account');
+const { APPROXIMATE_AMOUNT_WORDS, AMOUNT_RANGE_WORDS } = require('./constants');
+
+/**
+ * Find an amount that is an estimate or a range where the amount of an instruction should
+ * be: "about 500", "~500", "100 to 200", "100-200", "100 or 200", "between 100 and 200". A
+ * second number is only read as the end of a range when it is no account id, so
+ * "transfer 100 to acc200" or, with an account "200", "transfer 100 to 200" are left alone.
+ * @param {string[]} tokens
+ * @param {number} index - position of the amount
+ * @param {Object[]} [accounts]
+ * @param {string} [decimalSeparator] - '.' (default) or ','
+ * @returns {{ text: string, consumed: number }|null}
+ */
+function findAmbiguousAmount(tokens, index, accounts = [], decimalSeparator = '.') {
+  // Tokens from i on read as an amount that is not an account id; how many they take, or 0
+  const amountAt = (list, i) => {
+    const parsed = i < list.length ? parseAmount(list, i, decimalSeparator) : null;
+    const isAmount = parsed !== null && (parsed.amount !== null || parsed.ratio);
+    return isAmount && findAccount(accounts, list[i]) === null ? parsed.consumed : 0;
+  };
+  const word = String(tokens[index]).toLowerCase();
+  let consumed = 0;
+  if (APPROXIMATE_AMOUNT_WORDS.indexOf(word) !== -1) {
+    const rest = amountAt(tokens, index + 1);
+    if (rest > 0) consumed = 1 + rest;
+  } else if (word.length > 1 && word[0] === '~' && amountAt([word.substring(1)], 0) > 0) {
+    consumed = 1;
+  } else if (word === 'between') {
+    const low = amountAt(tokens, index + 1);
+    const and = index + 1 + low;
+    const joined = low > 0 && String(tokens[and]).toLowerCase() === 'and';
+    const high = joined ? amountAt(tokens, and + 1) : 0;
+    if (high > 0) consumed = 2 + low + high;
+  } else {
+    const low = amountAt(tokens, index);
+    const joiner = String(tokens[index + low]).toLowerCase();
+    const high =
+      low > 0 && AMOUNT_RANGE_WORDS.indexOf(joiner) !== -1 ? amountAt(tokens, index + low + 1) : 0;
+    if (high > 0) consumed = low + 1 + high;
+    const dash = word.indexOf('-');
+    if (low === 0 && dash > 0) {
+      const halves = [word.substring(0, dash), word.substring(dash + 1)];
+      if (amountAt(halves, 0) > 0 && amountAt(halves, 1) > 0) consumed = 1;
+    }
+  }
+  return consumed > 0 ? { text: tokens.slice(index, index + consumed).join(' '), consumed } : null;
+}
+
+module.exports = findAmbiguousAmount;
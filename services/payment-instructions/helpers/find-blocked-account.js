@@ -0,0 +1,19 @@
+const { BLOCKED_ACCOUNT_STATUSES } = require('./constants');
+
+/**
+ * The first of the accounts a transaction involves that may not take part in it because it
+ * is frozen or closed, with the code it fails with. Statuses are matched ignoring case.
+ * @param {{ id: string, status?: string }[]} accounts - involved accounts, debit side first
+ * @returns {{ account: Object, status: string, code: string }|null}
+ */
+function findBlockedAccount(accounts) {
+  for (let i = 0; i < accounts.length; i++) {
+    const status = String(accounts[i].status || '').toLowerCase();
+    if (Object.prototype.hasOwnProperty.call(BLOCKED_ACCOUNT_STATUSES, status)) {
+      return { account: accounts[i], status, code: BLOCKED_ACCOUNT_STATUSES[status] };
+    }
+  }
+  return null;
+}
+
+module.exports = findBlockedAccount;
@@ -0,0 +1,26 @@
+/**
+ * The first id given to more than one account, with every spelling of it in request order,
+ * or null when the ids are distinct. With ignoreCase, "acc1" and "ACC1" are the same id.
+ * @param {{ id: string }[]} accounts
+ * @param {boolean} [ignoreCase]
+ * @returns {string[]|null}
+ */
+function findDuplicateAccountIds(accounts, ignoreCase = false) {
+  const key = (id) => (ignoreCase ? String(id).toLowerCase() : String(id));
+  const seen = {};
+  for (let i = 0; i < accounts.length; i++) {
+    const k = key(accounts[i].id);
+    if (Object.prototype.hasOwnProperty.call(seen, k)) {
+      const spellings = [];
+      for (let j = 0; j <= i; j++) {
+        const id = accounts[j].id;
+        if (key(id) === k && spellings.indexOf(id) === -1) spellings.push(id);
+      }
+      return spellings;
+    }
+    seen[k] = true;
+  }
+  return null;
+}
+
+module.exports = findDuplicateAccountIds;
@@ -0,0 +1,19 @@
+/**
+ * The first account whose starting balance is below zero by more than the policy allows,
+ * or null. With "reject" no negative balance is allowed; with "overdraft" a balance may sit
+ * down to minus the account's overdraft_limit, as a debit could have left it.
+ * @param {{ id: string, balance: number, overdraft_limit?: number }[]} accounts
+ * @param {'reject'|'overdraft'} policy
+ * @returns {Object|null}
+ */
+function findNegativeBalance(accounts, policy) {
+  for (let i = 0; i < accounts.length; i++) {
+    const account = accounts[i];
+    const overdraftLimit =
+      policy === 'overdraft' ? Math.max(Number(account.overdraft_limit) || 0, 0) : 0;
+    if (Number(account.balance) < -overdraftLimit) return account;
+  }
+  return null;
+}
+
+module.exports = findNegativeBalance;
@@ -0,0 +1,26 @@
+const parseNarration = require('./parse-narration');
+
+/**
+ * Find an "and" clause left over after a transfer has named both of its accounts, as in
+ * "transfer 100 NGN from acc1 to acc2 and blah blah": not another instruction (those are
+ * split off by splitCompoundInstruction) and not a fee-source clause (read out beforehand),
+ * so nothing would read it. An "and" inside the narration ("for bread and butter") is part
+ * of the narration.
+ *
+ * @param {string[]} tokens
+ * @returns {{ index: number, text: string }|null} the "and" token and the clause it opens
+ */
+function findTrailingClause(tokens) {
+  const lowerTokens = tokens.map((t) => String(t).toLowerCase());
+  const iFrom = lowerTokens.indexOf('from');
+  const iTo = lowerTokens.indexOf('to');
+  if (iFrom === -1 || iTo === -1) return null;
+  // The preposition of whichever account is named second
+  const iSecond = Math.max(iFrom, iTo);
+  const iNarration = parseNarration(tokens, iSecond + 1).start;
+  const iAnd = lowerTokens.indexOf('and', iSecond + 1);
+  if (iAnd === -1 || iAnd > iNarration) return null;
+  return { index: iAnd, text: tokens.slice(iAnd).join(' ') };
+}
+
+module.exports = findTrailingClause;
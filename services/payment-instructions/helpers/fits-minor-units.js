@@ -0,0 +1,15 @@
+const toMinorUnits = require('./to-minor-units');
+const fromMinorUnits = require('./from-minor-units');
+
+/**
+ * Check that an amount has no more decimal places than its currency's minor units
+ * (1000.5 NGN fits, 10.005 USD and 10.5 UGX do not).
+ * @param {number} amount - Amount in major units
+ * @param {string} currency
+ * @returns {boolean}
+ */
+function fitsMinorUnits(amount, currency) {
+  return fromMinorUnits(toMinorUnits(amount, currency), currency) === amount;
+}
+
+module.exports = fitsMinorUnits;
@@ -0,0 +1,13 @@
+const getCurrencyDecimals = require('./get-currency-decimals');
+
+/**
+ * Convert integer minor units back to a major-unit number.
+ * @param {number|bigint} minor
+ * @param {string} currency
+ * @returns {number}
+ */
+function fromMinorUnits(minor, currency) {
+  return Number(minor) / 10 ** getCurrencyDecimals(currency);
+}
+
+module.exports = fromMinorUnits;
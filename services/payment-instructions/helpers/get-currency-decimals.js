@@ -0,0 +1,18 @@
+const { CURRENCY_DECIMALS, DEFAULT_CURRENCY_DECIMALS } = require('./constants');
+
+/**
+ * Decimal places (minor unit exponent) used by a currency, defaulting to 2 for anything
+ * not in the table.
+ * @param {string} currency
+ * @returns {number}
+ */
+function getCurrencyDecimals(currency) {
+  const code = String(currency || '').toUpperCase();
+  let decimals = DEFAULT_CURRENCY_DECIMALS;
+  if (Object.prototype.hasOwnProperty.call(CURRENCY_DECIMALS, code)) {
+    decimals = CURRENCY_DECIMALS[code];
+  }
+  return decimals;
+}
+
+module.exports = getCurrencyDecimals;
@@ -0,0 +1,12 @@
+/**
+ * Number of days in a month of the Gregorian calendar (month is 1-12).
+ * @param {number} year
+ * @param {number} month
+ * @returns {number}
+ */
+function getDaysInMonth(year, month) {
+  // Day 0 of the following month is the last day of this one
+  return new Date(Date.UTC(year, month, 0)).getUTCDate();
+}
+
+module.exports = getDaysInMonth;
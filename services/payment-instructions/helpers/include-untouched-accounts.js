@@ -0,0 +1,33 @@
+/**
+ * The post-state of every input account, in input order: an account the instruction moved
+ * is reported as the response has it, any other with balance_before equal to balance (and,
+ * for a dry run, to projected_balance).
+ *
+ * @param {Object[]} inputAccounts - the accounts the instruction was given
+ * @param {Object[]} responseAccounts - the accounts a response reports
+ * @param {boolean} [dryRun]
+ * @returns {Object[]}
+ */
+function includeUntouchedAccounts(inputAccounts, responseAccounts, dryRun = false) {
+  if (!Array.isArray(inputAccounts)) return responseAccounts;
+  const reported = responseAccounts || [];
+  const out = [];
+  for (let i = 0; i < inputAccounts.length; i++) {
+    const a = inputAccounts[i];
+    if (a && typeof a === 'object') {
+      const moved = reported.find((r) => r.id === a.id);
+      out.push(
+        moved || {
+          id: a.id,
+          balance: a.balance,
+          balance_before: a.balance,
+          ...(dryRun ? { projected_balance: a.balance } : {}),
+          currency: String(a.currency || '').toUpperCase(),
+        }
+      );
+    }
+  }
+  return out;
+}
+
+module.exports = includeUntouchedAccounts;
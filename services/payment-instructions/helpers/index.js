@@ -0,0 +1,191 @@
+const parseAmount = require('./parse-amount');
+const splitDecimal = require('./split-decimal');
+const shiftDecimal = require('./shift-decimal');
+const parseWordAmount = require('./parse-word-amount');
+const parseNegativeAmount = require('./parse-negative-amount');
+const findAmbiguousAmount = require('./find-ambiguous-amount');
+const getCurrencyDecimals = require('./get-currency-decimals');
+const toMinorUnits = require('./to-minor-units');
+const fromMinorUnits = require('./from-minor-units');
+const fitsMinorUnits = require('./fits-minor-units');
+const resolveRatioAmount = require('./resolve-ratio-amount');
+const isRatioRounded = require('./is-ratio-rounded');
+const convertAmount = require('./convert-amount');
+const decimalToRatio = require('./decimal-to-ratio');
+const roundRatio = require('./round-ratio');
+const describeCurrencyMismatch = require('./describe-currency-mismatch');
+const resolveCurrency = require('./resolve-currency');
+const isUnknownCurrencyCode = require('./is-unknown-currency-code');
+const isCurrencySymbol = require('./is-currency-symbol');
+const omitsCurrency = require('./omits-currency');
+const readDefaultCurrency = require('./read-default-currency');
+const listHeldCurrencies = require('./list-held-currencies');
+const placeCurrencySymbol = require('./place-currency-symbol');
+const currentTime = require('./current-time');
+const parseRelativeDate = require('./parse-relative-date');
+const parseAbsoluteDate = require('./parse-absolute-date');
+const parseTimeOfDay = require('./parse-time-of-day');
+const zoneOffsetSeconds = require('./zone-offset-seconds');
+const getDaysInMonth = require('./get-days-in-month');
+const parseCount = require('./parse-count');
+const parseRecurrence = require('./parse-recurrence');
+const expandRecurrence = require('./expand-recurrence');
+const matchScheduleAnchor = require('./match-schedule-anchor');
+const anchorDate = require('./anchor-date');
+const nextAnchorDate = require('./next-anchor-date');
+const resolveAccountAlias = require('./resolve-account-alias');
+const parseAccountReference = require('./parse-account-reference');
+const findAccountsBySuffix = require('./find-accounts-by-suffix');
+const findAccountsIgnoringCase = require('./find-accounts-ignoring-case');
+const findDuplicateAccountIds = require('./find-duplicate-account-ids');
+const findBlockedAccount = require('./find-blocked-account');
+const findNegativeBalance = require('./find-negative-balance');
+const normalizeInstruction = require('./normalize-instruction');
+const tokenize = require('./tokenize');
+const tokenSpans = require('./token-spans');
+const describeParseError = require('./describe-parse-error');
+const isValidAccountId = require('./is-valid-account-id');
+const createAccountIdMatcher = require('./create-account-id-matcher');
+const findAccount = require('./find-account');
+const splitAmount = require('./split-amount');
+const splitAmountByWeights = require('./split-amount-by-weights');
+const parseAccountList = require('./parse-account-list');
+const calculateFee = require('./calculate-fee');
+const largestAffordableAmount = require('./largest-affordable-amount');
+const exceededAmountCap = require('./exceeded-amount-cap');
+const parseNarration = require('./parse-narration');
+const parseFeeSource = require('./parse-fee-source');
+const parseBalanceCondition = require('./parse-balance-condition');
+const isConditionMet = require('./is-condition-met');
+const scoreConfidence = require('./score-confidence');
+const buildWarnings = require('./build-warnings');
+const describeGuesses = require('./describe-guesses');
+const correctKeyword = require('./correct-keyword');
+const correctCurrencyWord = require('./correct-currency-word');
+const splitCompoundInstruction = require('./split-compound-instruction');
+const reorderAccountFirst = require('./reorder-account-first');
+const parseBillPayment = require('./parse-bill-payment');
+const referencedAccountIds = require('./referenced-account-ids');
+const includeUntouchedAccounts = require('./include-untouched-accounts');
+const echoAccounts = require('./echo-accounts');
+const isWalletAccount = require('./is-wallet-account');
+const selectWalletPockets = require('./select-wallet-pockets');
+const isCompleteAccount = require('./is-complete-account');
+const isBlankInstruction = require('./is-blank-instruction');
+const buildAuditRecord = require('./build-audit-record');
+const parseCurrencyDeclaration = require('./parse-currency-declaration');
+const canonicalInstruction = require('./canonical-instruction');
+const findTrailingClause = require('./find-trailing-clause');
+const reorderTransferFromLast = require('./reorder-transfer-from-last');
+const readShareOfAccount = require('./read-share-of-account');
+const {
+  SUPPORTED_CURRENCIES,
+  ISO_4217_CODES,
+  FEE_POLICY,
+  MAX_AMOUNT_POLICY,
+  NEGATIVE_BALANCE_POLICY,
+  ROUNDING_POLICY,
+  PREVIEW_MAX_COUNT,
+  SCHEDULE_ANCHORS,
+  MAX_INSTRUCTION_LENGTH,
+  PARSED_INSTRUCTION_FIELDS,
+  FUZZY_VERBS,
+  VERB_SYNONYMS,
+  COLLECT_VERBS,
+} = require('./constants');
+
+module.exports = {
+  parseAmount,
+  splitDecimal,
+  shiftDecimal,
+  parseWordAmount,
+  parseNegativeAmount,
+  findAmbiguousAmount,
+  getCurrencyDecimals,
+  toMinorUnits,
+  fromMinorUnits,
+  fitsMinorUnits,
+  resolveRatioAmount,
+  isRatioRounded,
+  convertAmount,
+  decimalToRatio,
+  roundRatio,
+  describeCurrencyMismatch,
+  resolveCurrency,
+  isUnknownCurrencyCode,
+  isCurrencySymbol,
+  omitsCurrency,
+  readDefaultCurrency,
+  listHeldCurrencies,
+  placeCurrencySymbol,
+  currentTime,
+  parseRelativeDate,
+  parseAbsoluteDate,
+  parseTimeOfDay,
+  zoneOffsetSeconds,
+  getDaysInMonth,
+  parseCount,
+  parseRecurrence,
+  expandRecurrence,
+  matchScheduleAnchor,
+  anchorDate,
+  nextAnchorDate,
+  resolveAccountAlias,
+  parseAccountReference,
+  findAccountsBySuffix,
+  findAccountsIgnoringCase,
+  findDuplicateAccountIds,
+  findBlockedAccount,
+  findNegativeBalance,
+  normalizeInstruction,
+  tokenize,
+  tokenSpans,
+  describeParseError,
+  isValidAccountId,
+  createAccountIdMatcher,
+  findAccount,
+  splitAmount,
+  splitAmountByWeights,
+  parseAccountList,
+  calculateFee,
+  largestAffordableAmount,
+  exceededAmountCap,
+  parseNarration,
+  parseFeeSource,
+  parseBalanceCondition,
+  isConditionMet,
+  scoreConfidence,
+  buildWarnings,
+  describeGuesses,
+  correctKeyword,
+  correctCurrencyWord,
+  splitCompoundInstruction,
+  reorderAccountFirst,
+  parseBillPayment,
+  referencedAccountIds,
+  includeUntouchedAccounts,
+  echoAccounts,
+  isWalletAccount,
+  selectWalletPockets,
+  isCompleteAccount,
+  isBlankInstruction,
+  buildAuditRecord,
+  parseCurrencyDeclaration,
+  canonicalInstruction,
+  findTrailingClause,
+  reorderTransferFromLast,
+  readShareOfAccount,
+  SUPPORTED_CURRENCIES,
+  ISO_4217_CODES,
+  FEE_POLICY,
+  MAX_AMOUNT_POLICY,
+  NEGATIVE_BALANCE_POLICY,
+  ROUNDING_POLICY,
+  PREVIEW_MAX_COUNT,
+  SCHEDULE_ANCHORS,
+  MAX_INSTRUCTION_LENGTH,
+  PARSED_INSTRUCTION_FIELDS,
+  FUZZY_VERBS,
+  VERB_SYNONYMS,
+  COLLECT_VERBS,
+};
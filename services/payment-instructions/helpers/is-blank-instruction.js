@@ -0,0 +1,21 @@
+const normalizeInstruction = require('./normalize-instruction');
+
+/**
+ * Whether an instruction has nothing to read: no letter or digit once normalized, as for "",
+ * "   " or "???". ASCII punctuation and symbols alone are blank; any other character counts
+ * as text, since it may be a letter of a script without case or a currency sign ("₦").
+ * @param {string} text
+ * @returns {boolean}
+ */
+function isBlankInstruction(text) {
+  const s = normalizeInstruction(text);
+  for (let i = 0; i < s.length; i++) {
+    const ch = s[i];
+    const isDigit = ch >= '0' && ch <= '9';
+    const isLetter = ch.toLowerCase() !== ch.toUpperCase();
+    if (isDigit || isLetter || s.charCodeAt(i) > 0x7f) return false;
+  }
+  return true;
+}
+
+module.exports = isBlankInstruction;
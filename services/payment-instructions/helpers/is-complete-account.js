@@ -0,0 +1,15 @@
+const isWalletAccount = require('./is-wallet-account');
+
+/**
+ * Whether an input account carries what the executor reads: a numeric balance and a
+ * currency, or for a multi-currency wallet its `balances`. The request specs leave balance
+ * and currency optional so that wallets pass, which leaves this check to the flows.
+ * @param {Object} account
+ * @returns {boolean}
+ */
+function isCompleteAccount(account) {
+  if (isWalletAccount(account)) return true;
+  return typeof account.balance === 'number' && typeof account.currency === 'string';
+}
+
+module.exports = isCompleteAccount;
@@ -0,0 +1,20 @@
+const toMinorUnits = require('./to-minor-units');
+
+/**
+ * Whether a balance meets a balance guard (see parseBalanceCondition), compared in the
+ * minor units of the currency.
+ * @param {number} balance
+ * @param {{ comparison: string, threshold: number }} condition - comparison is above, below
+ *   or at_least
+ * @param {string} currency
+ * @returns {boolean}
+ */
+function isConditionMet(balance, condition, currency) {
+  const balanceMinor = toMinorUnits(Number(balance), currency);
+  const thresholdMinor = toMinorUnits(condition.threshold, currency);
+  if (condition.comparison === 'above') return balanceMinor > thresholdMinor;
+  if (condition.comparison === 'below') return balanceMinor < thresholdMinor;
+  return balanceMinor >= thresholdMinor;
+}
+
+module.exports = isConditionMet;
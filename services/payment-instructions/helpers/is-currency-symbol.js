@@ -0,0 +1,12 @@
+const { CURRENCY_SYMBOLS } = require('./constants');
+
+/**
+ * Check whether a token is one of the supported currency symbols ("₦", "$", "Sh").
+ * @param {string} token
+ * @returns {boolean}
+ */
+function isCurrencySymbol(token) {
+  return Object.prototype.hasOwnProperty.call(CURRENCY_SYMBOLS, token);
+}
+
+module.exports = isCurrencySymbol;
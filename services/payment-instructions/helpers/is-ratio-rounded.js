@@ -0,0 +1,19 @@
+const toMinorUnits = require('./to-minor-units');
+
+/**
+ * Whether resolving a fraction of a balance (see resolveRatioAmount) has to round, i.e. the
+ * share is not a whole number of the currency's minor units.
+ *
+ * @param {number} balance - Balance in major units
+ * @param {{numerator: number|bigint, denominator: number|bigint}} ratio
+ * @param {string} currency
+ * @returns {boolean}
+ */
+function isRatioRounded(balance, ratio, currency) {
+  const minor = BigInt(toMinorUnits(balance, currency));
+  const numerator = BigInt(ratio.numerator);
+  const denominator = BigInt(ratio.denominator);
+  return minor > 0n && numerator > 0n && (minor * numerator) % denominator !== 0n;
+}
+
+module.exports = isRatioRounded;
@@ -0,0 +1,18 @@
+const { ISO_4217_CODES } = require('./constants');
+
+/**
+ * A currency token shaped like an ISO 4217 code (three letters, any case) that is not one
+ * ("XYZ"); real codes, word forms and symbols are not unknown codes.
+ * @param {string} token
+ * @returns {boolean}
+ */
+function isUnknownCurrencyCode(token) {
+  const upper = String(token || '').toUpperCase();
+  let letters = upper.length === 3;
+  for (let i = 0; i < upper.length && letters; i++) {
+    if (upper[i] < 'A' || upper[i] > 'Z') letters = false;
+  }
+  return letters && ISO_4217_CODES.indexOf(upper) === -1;
+}
+
+module.exports = isUnknownCurrencyCode;
@@ -0,0 +1,15 @@
+/**
+ * Validate account ID characters: letters, numbers, hyphen (-), dot (.), at (@)
+ * @param {string} id
+ * @returns {boolean}
+ */
+function isValidAccountId(id) {
+  if (typeof id !== 'string' || id.length === 0) return false;
+  const allowed = 'abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-.@';
+  for (let i = 0; i < id.length; i++) {
+    if (allowed.indexOf(id[i]) === -1) return false;
+  }
+  return true;
+}
+
+module.exports = isValidAccountId;
@@ -0,0 +1,17 @@
+/**
+ * Whether an input account is a multi-currency wallet: one id holding a balance per currency
+ * in `balances` ({ NGN: 1000, USD: 50 }) instead of a single balance and currency.
+ * @param {Object} account
+ * @returns {boolean}
+ */
+function isWalletAccount(account) {
+  return (
+    account !== null &&
+    typeof account === 'object' &&
+    account.balances !== null &&
+    typeof account.balances === 'object' &&
+    !Array.isArray(account.balances)
+  );
+}
+
+module.exports = isWalletAccount;
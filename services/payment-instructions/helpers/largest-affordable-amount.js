@@ -0,0 +1,31 @@
+const toMinorUnits = require('./to-minor-units');
+const fromMinorUnits = require('./from-minor-units');
+const calculateFee = require('./calculate-fee');
+
+/**
+ * Largest amount whose total debit (amount plus its fee under the policy) fits in
+ * availableMinor, found by binary search over minor units.
+ * @param {number} availableMinor - what the debit account can give, in minor units
+ * @param {string} type - Transaction type, for the fee policy
+ * @param {Object} policy - fee policy (see FEE_POLICY)
+ * @param {string} currency
+ * @param {string} [rounding] - rounding policy for the fee (see ROUNDING_POLICY)
+ * @returns {number} amount in major units (0 when nothing fits)
+ */
+function largestAffordableAmount(availableMinor, type, policy, currency, rounding) {
+  const totalMinor = (amountMinor) => {
+    const amount = fromMinorUnits(amountMinor, currency);
+    const fee = calculateFee(amount, type, policy, currency, rounding);
+    return amountMinor + (fee !== null ? toMinorUnits(fee, currency) : 0);
+  };
+  let low = 0;
+  let high = Math.max(availableMinor, 0);
+  while (low < high) {
+    const mid = Math.ceil((low + high) / 2);
+    if (totalMinor(mid) <= availableMinor) low = mid;
+    else high = mid - 1;
+  }
+  return fromMinorUnits(low, currency);
+}
+
+module.exports = largestAffordableAmount;
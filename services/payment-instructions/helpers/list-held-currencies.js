@@ -0,0 +1,19 @@
+const { SUPPORTED_CURRENCIES } = require('./constants');
+
+/**
+ * The supported currencies the accounts hold, each once, in request order: what an
+ * instruction that names no currency could be in.
+ * @param {{ currency: string }[]} accounts
+ * @returns {string[]}
+ */
+function listHeldCurrencies(accounts) {
+  const held = [];
+  accounts.forEach((account) => {
+    const code = String(account.currency || '').toUpperCase();
+    const supported = Object.prototype.hasOwnProperty.call(SUPPORTED_CURRENCIES, code);
+    if (supported && held.indexOf(code) === -1) held.push(code);
+  });
+  return held;
+}
+
+module.exports = listHeldCurrencies;
@@ -0,0 +1,76 @@
+const { SCHEDULE_ANCHORS, ANCHOR_WEEKS, WEEKDAYS } = require('./constants');
+
+// -----------------------------
+// Named schedule anchors (no regex)
+// -----------------------------
+
+function has(table, word) {
+  return Object.prototype.hasOwnProperty.call(table, word);
+}
+
+// Lowercase words split on spaces and joined by single spaces, so " Pay  Day" names "pay day"
+function normalizeName(name) {
+  const words = String(name).toLowerCase().split(' ');
+  const kept = [];
+  for (let i = 0; i < words.length; i++) {
+    if (words[i] !== '') kept.push(words[i]);
+  }
+  return kept.join(' ');
+}
+
+function isLetters(word) {
+  let letters = word.length > 0;
+  for (let i = 0; i < word.length; i++) {
+    const code = word.charCodeAt(i);
+    if (code < 97 || code > 122) letters = false;
+  }
+  return letters;
+}
+
+/**
+ * Read a date phrase as a named schedule anchor: one of SCHEDULE_ANCHORS or the anchors
+ * passed in (which override them by name), or "<first..fourth|last> <weekday>". A leading
+ * "the" and a trailing "of the month" / "of month" are ignored, so "the last business day of
+ * the month" names "last business day".
+ *
+ * A phrase of words alone that names no anchor comes back with a null rule, so the caller can
+ * say which anchor was unknown; a phrase with digits or symbols in it is no anchor (null).
+ *
+ * @param {string[]} words
+ * @param {Object} [anchors] - extra anchors, name -> rule (see anchor-date.js)
+ * @returns {{ name: string, rule: Object|null, known: string[] }|null}
+ */
+function matchScheduleAnchor(words, anchors = {}) {
+  let list = [];
+  for (let i = 0; i < words.length; i++) list.push(String(words[i]).toLowerCase());
+  if (list[0] === 'the') list = list.slice(1);
+  const n = list.length;
+  if (n >= 3 && list[n - 3] === 'of' && list[n - 2] === 'the' && list[n - 1] === 'month') {
+    list = list.slice(0, n - 3);
+  } else if (n >= 2 && list[n - 2] === 'of' && list[n - 1] === 'month') {
+    list = list.slice(0, n - 2);
+  }
+
+  const table = { ...SCHEDULE_ANCHORS };
+  const extra = anchors && typeof anchors === 'object' ? Object.keys(anchors) : [];
+  for (let i = 0; i < extra.length; i++) table[normalizeName(extra[i])] = anchors[extra[i]];
+
+  const name = list.join(' ');
+  let result = null;
+  if (list.length > 0 && list.every(isLetters)) {
+    let rule = null;
+    if (has(table, name) && table[name] && typeof table[name] === 'object') {
+      rule = table[name];
+    } else if (
+      list.length === 2 &&
+      has(ANCHOR_WEEKS, list[0]) &&
+      WEEKDAYS.indexOf(list[1]) !== -1
+    ) {
+      rule = { weekday: list[1], week: ANCHOR_WEEKS[list[0]] };
+    }
+    result = { name, rule, known: Object.keys(table).sort() };
+  }
+  return result;
+}
+
+module.exports = matchScheduleAnchor;
@@ -0,0 +1,22 @@
+const anchorDate = require('./anchor-date');
+
+/**
+ * The first day on or after from that a schedule anchor rule falls on, or null when the rule
+ * names no day (see anchor-date.js). From's month and the two after it are looked at, since a
+ * day rolled back can fall before from while next month's has not come yet.
+ * @param {Object} rule
+ * @param {{ year: number, month: number, day: number }} from
+ * @returns {{ year: number, month: number, day: number }|null}
+ */
+function nextAnchorDate(rule, from) {
+  const start = Date.UTC(from.year, from.month - 1, from.day);
+  let result = null;
+  for (let k = 0; k < 3 && result === null; k++) {
+    const months = from.month - 1 + k;
+    const d = anchorDate(rule, from.year + Math.floor(months / 12), (months % 12) + 1);
+    if (d !== null && Date.UTC(d.year, d.month - 1, d.day) >= start) result = d;
+  }
+  return result;
+}
+
+module.exports = nextAnchorDate;
@@ -0,0 +1,50 @@
+const {
+  DIGIT_ZEROS,
+  SPACE_CHARACTERS,
+  IGNORED_CHARACTERS,
+  FOLDED_CHARACTERS,
+} = require('./constants');
+
+// ASCII digit for a decimal digit of another script ("٣" -> "3"), or null
+function foldDigit(ch) {
+  const code = ch.charCodeAt(0);
+  for (let i = 0; i < DIGIT_ZEROS.length; i++) {
+    if (code >= DIGIT_ZEROS[i] && code <= DIGIT_ZEROS[i] + 9) {
+      return String(code - DIGIT_ZEROS[i]);
+    }
+  }
+  return null;
+}
+
+/**
+ * Normalize an instruction for parsing: Unicode NFKC (fullwidth letters and digits, no-break
+ * and other odd spaces, ligatures), then digits of other scripts (Arabic-Indic "١٠٠٠",
+ * Devanagari, Thai, ...) folded to ASCII, remaining line breaks and tabs read as spaces and
+ * zero-width characters dropped.
+ *
+ * Ids accepted in instructions are plain ASCII (see isValidAccountId), on which this is the
+ * identity, so an id written as-is always matches exactly.
+ *
+ * @param {string} text
+ * @returns {string}
+ */
+function normalizeInstruction(text) {
+  const s = String(text).normalize('NFKC');
+  let out = '';
+  for (let i = 0; i < s.length; i++) {
+    const ch = s[i];
+    const digit = ch >= '0' && ch <= '9' ? null : foldDigit(ch);
+    if (digit !== null) {
+      out += digit;
+    } else if (SPACE_CHARACTERS.indexOf(ch) !== -1) {
+      out += ' ';
+    } else if (Object.prototype.hasOwnProperty.call(FOLDED_CHARACTERS, ch)) {
+      out += FOLDED_CHARACTERS[ch];
+    } else if (IGNORED_CHARACTERS.indexOf(ch) === -1) {
+      out += ch;
+    }
+  }
+  return out;
+}
+
+module.exports = normalizeInstruction;
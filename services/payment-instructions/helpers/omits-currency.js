@@ -0,0 +1,25 @@
+const { CURRENCYLESS_KEYWORDS, ISO_4217_CODES } = require('./constants');
+const findAccount = require('./find-account');
+const resolveCurrency = require('./resolve-currency');
+
+/**
+ * Check whether the token read where the currency belongs is instead the keyword that
+ * follows it, i.e. the instruction leaves the currency out ("transfer 5000 from acc1 ...").
+ * An id of one of the accounts ("usdaccount") leaves it out too: it is only read as a
+ * currency when, as written, it is a currency code, word or symbol.
+ * @param {string|undefined} token - token right after the amount
+ * @param {{ id: string }[]} [accounts]
+ * @returns {boolean}
+ */
+function omitsCurrency(token, accounts = []) {
+  if (token === undefined) return false;
+  const text = String(token);
+  const isCurrency =
+    resolveCurrency(text).length > 0 || ISO_4217_CODES.indexOf(text.toUpperCase()) !== -1;
+  return (
+    CURRENCYLESS_KEYWORDS.indexOf(text.toLowerCase()) !== -1 ||
+    (!isCurrency && findAccount(accounts, text) !== null)
+  );
+}
+
+module.exports = omitsCurrency;
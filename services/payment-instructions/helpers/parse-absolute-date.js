@@ -0,0 +1,132 @@
+const getDaysInMonth = require('./get-days-in-month');
+
+// -----------------------------
+// Absolute schedule dates (no regex)
+// -----------------------------
+
+function isDigits(s) {
+  if (s.length === 0) return false;
+  for (let i = 0; i < s.length; i++) {
+    if (s[i] < '0' || s[i] > '9') return false;
+  }
+  return true;
+}
+
+/**
+ * Parse "HH:MM" or "HH:MM:SS" into seconds since midnight, or null.
+ */
+function parseClock(s) {
+  const parts = s.split(':');
+  if (parts.length < 2 || parts.length > 3) return null;
+  for (let i = 0; i < parts.length; i++) {
+    if (parts[i].length !== 2 || !isDigits(parts[i])) return null;
+  }
+  const h = parseInt(parts[0], 10);
+  const m = parseInt(parts[1], 10);
+  const sec = parts.length === 3 ? parseInt(parts[2], 10) : 0;
+  if (h > 23 || m > 59 || sec > 59) return null;
+  return h * 3600 + m * 60 + sec;
+}
+
+/**
+ * Parse the time part of an ISO-8601 date-time ("14:30", "14:30:00Z", "09:00+01:00").
+ * Returns { seconds, offsetSeconds } where seconds is the local time of day, or null.
+ */
+function parseIsoTime(s) {
+  let clock = s;
+  let offsetSeconds = 0;
+  const last = s[s.length - 1];
+  const signAt = Math.max(s.lastIndexOf('+'), s.lastIndexOf('-'));
+  if (last === 'Z' || last === 'z') {
+    clock = s.substring(0, s.length - 1);
+  } else if (signAt > 0) {
+    clock = s.substring(0, signAt);
+    const offset = parseClock(s.substring(signAt + 1));
+    if (offset === null || s.substring(signAt + 1).length !== 5) return null;
+    offsetSeconds = s[signAt] === '-' ? -offset : offset;
+  }
+  const seconds = parseClock(clock);
+  return seconds === null ? null : { seconds, offsetSeconds };
+}
+
+/**
+ * Parse an explicit date token.
+ *
+ * Supported formats:
+ *   - ISO-8601 dates "2025-03-15", optionally with a time "2025-03-15T14:30[:00][Z|+01:00]"
+ *     (a time without an offset is UTC)
+ *   - day/month/year "15/03/2025", also with "-" or "." separators
+ *
+ * A day/month/year where both leading parts could be a month ("03/04/2025") follows
+ * options.dayFirst (default true); "15/03/2025" and "03/15/2025" are unambiguous.
+ *
+ * Returns null when the token is not in a supported format, otherwise
+ * { year, month, day, timestamp, hasTime, valid } where timestamp is Unix seconds (start of
+ * the UTC day when no time is given) and valid is false for dates that do not exist
+ * ("2025-02-30").
+ *
+ * @param {string} token
+ * @param {{ dayFirst?: boolean }} [options]
+ * @returns {object|null}
+ */
+function parseAbsoluteDate(token, options = {}) {
+  const s = String(token);
+  const dayFirst = options.dayFirst !== false;
+  let result = null;
+
+  const iT = s.indexOf('T') !== -1 ? s.indexOf('T') : s.indexOf('t');
+  const datePart = iT === -1 ? s : s.substring(0, iT);
+  const timePart = iT === -1 ? null : parseIsoTime(s.substring(iT + 1));
+  let parts = null;
+
+  let sep = '';
+  if (datePart.indexOf('/') !== -1) sep = '/';
+  else if (datePart.indexOf('.') !== -1) sep = '.';
+  else if (datePart.indexOf('-') !== -1) sep = '-';
+  const fields = sep === '' ? [] : datePart.split(sep);
+  const shaped =
+    fields.length === 3 &&
+    isDigits(fields[0]) &&
+    isDigits(fields[1]) &&
+    isDigits(fields[2]) &&
+    (iT === -1 || timePart !== null);
+
+  if (shaped && sep === '-' && fields[0].length === 4) {
+    // ISO-8601: YYYY-MM-DD
+    if (fields[1].length === 2 && fields[2].length === 2) {
+      parts = {
+        year: parseInt(fields[0], 10),
+        month: parseInt(fields[1], 10),
+        day: parseInt(fields[2], 10),
+      };
+    }
+  } else if (shaped && iT === -1 && fields[2].length === 4) {
+    // D/M/YYYY or M/D/YYYY
+    if (fields[0].length <= 2 && fields[1].length <= 2) {
+      const a = parseInt(fields[0], 10);
+      const b = parseInt(fields[1], 10);
+      let first = dayFirst;
+      if (a > 12 && b <= 12) first = true;
+      else if (b > 12 && a <= 12) first = false;
+      parts = {
+        year: parseInt(fields[2], 10),
+        month: first ? b : a,
+        day: first ? a : b,
+      };
+    }
+  }
+
+  if (parts !== null) {
+    const valid =
+      parts.month >= 1 &&
+      parts.month <= 12 &&
+      parts.day >= 1 &&
+      parts.day <= getDaysInMonth(parts.year, parts.month);
+    const dayStart = Date.UTC(parts.year, parts.month - 1, parts.day) / 1000;
+    const timestamp = timePart ? dayStart + timePart.seconds - timePart.offsetSeconds : dayStart;
+    result = { ...parts, timestamp, hasTime: timePart !== null, valid };
+  }
+  return result;
+}
+
+module.exports = parseAbsoluteDate;
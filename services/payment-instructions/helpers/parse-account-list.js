@@ -0,0 +1,141 @@
+const parseAmount = require('./parse-amount');
+const parseAccountReference = require('./parse-account-reference');
+
+// -----------------------------
+// Account lists: SPLIT recipients, MULTI_DEBIT sources (no regex)
+// -----------------------------
+
+/**
+ * Separate trailing commas into their own "," tokens ("acc2," -> "acc2", ",").
+ */
+function separateCommas(tokens) {
+  const list = [];
+  for (let i = 0; i < tokens.length; i++) {
+    let t = String(tokens[i]);
+    let commas = 0;
+    while (t.length > 0 && t[t.length - 1] === ',') {
+      t = t.substring(0, t.length - 1);
+      commas++;
+    }
+    if (t.length > 0) list.push(t);
+    for (let c = 0; c < commas; c++) list.push(',');
+  }
+  return list;
+}
+
+function isSeparator(token) {
+  return token === ',' || token.toLowerCase() === 'and';
+}
+
+function isPartWord(token) {
+  const lower = String(token || '').toLowerCase();
+  return lower === 'part' || lower === 'parts';
+}
+
+/**
+ * A recipient's share at list[i]: a percentage ("60%", "60 %", "60 percent") or a number of
+ * parts ("2 parts"). Percentages are kept in hundredths of a percent and parts as a count;
+ * the value is NaN when it is not one of those (more than two decimals, "1.5 parts").
+ * Returns { share: { unit: 'percent'|'part', value }, consumed } or null for no share.
+ */
+function parseShare(list, i, decimalSeparator) {
+  const token = i < list.length ? list[i] : '';
+  if (token.length === 0 || token[0] < '0' || token[0] > '9') return null;
+  if (isPartWord(list[i + 1])) {
+    const parsed = parseAmount([token], 0, decimalSeparator);
+    const count = parsed !== null ? parsed.amount : NaN;
+    return { share: { unit: 'part', value: Number.isInteger(count) ? count : NaN }, consumed: 2 };
+  }
+  const percentage = parseAmount(list.slice(i, i + 2), 0, decimalSeparator);
+  if (percentage === null || percentage.ratio === null || percentage.ratio === undefined) {
+    return null;
+  }
+  const { numerator, denominator } = percentage.ratio;
+  const hundredths = (numerator * 10000) / denominator;
+  const value = Number.isInteger(hundredths) ? hundredths : NaN;
+  return { share: { unit: 'percent', value }, consumed: percentage.consumed };
+}
+
+// "2 parts to acc2": a share written before its TO and account
+function isLeadingShare(list, i) {
+  return isPartWord(list[i + 1]) && String(list[i + 2] || '').toLowerCase() === 'to';
+}
+
+/**
+ * Parse a list of accounts (SPLIT recipients, MULTI_DEBIT sources).
+ *
+ * Entries are account references separated by commas and/or "and", each optionally
+ * preceded by ACCOUNT and optionally followed by its own amount or share:
+ *   "acc2, acc3 and acc4"
+ *   "acc2 5000, acc3 3000 and account acc4 1000"
+ *   "acc2 60% and acc3 40%", "acc2 2 parts, acc3 1 part"
+ * A number of parts may also come first, each entry with its own TO and no separator
+ * needed: "2 parts to acc2 1 part to acc3". Either every entry has the same kind of value
+ * (amount, percentage or parts) or none has.
+ *
+ * @param {string[]} tokens - the list, from its first entry to its last token
+ * @param {string} [decimalSeparator] - '.' (default) or ',' for the entry amounts
+ * @param {{ id: string }[]|null} [accounts] - for abbreviated references (see
+ *   parseAccountReference)
+ * @returns {{ entries: { ref: { token: string, suffix: string|null, consumed: number },
+ *   amount: number|null, share: { unit: string, value: number }|null }[], explicit: boolean,
+ *   shares: 'percent'|'part'|null }|null} null when the list is malformed; explicit is set
+ *   for amounts and shares names the kind of share
+ */
+function parseAccountList(tokens, decimalSeparator = '.', accounts = null) {
+  const list = separateCommas(tokens);
+  const entries = [];
+  let malformed = false;
+  let i = 0;
+  while (i < list.length && !malformed) {
+    let share = null;
+    if (isLeadingShare(list, i)) {
+      share = parseShare(list, i, decimalSeparator).share;
+      i += 3;
+    }
+    if (i < list.length && list[i].toLowerCase() === 'account') i++;
+    if (i >= list.length || isSeparator(list[i])) {
+      malformed = true;
+    } else {
+      const ref = parseAccountReference(list, i, accounts);
+      i += ref.consumed;
+      let amount = null;
+      const trailing = share === null ? parseShare(list, i, decimalSeparator) : null;
+      const next = i < list.length ? list[i] : '';
+      if (trailing !== null) {
+        share = trailing.share;
+        i += trailing.consumed;
+      } else if (share === null && next.length > 0 && next[0] >= '0' && next[0] <= '9') {
+        const parsed = parseAmount([next], 0, decimalSeparator);
+        amount = parsed !== null && parsed.amount !== null ? parsed.amount : NaN;
+        i++;
+      }
+      entries.push({ ref, amount, share });
+      // One or more separators (", and") between entries; none after the last, and none
+      // needed before a leading share
+      let separators = 0;
+      while (i < list.length && isSeparator(list[i])) {
+        separators++;
+        i++;
+      }
+      const joined = separators > 0 || isLeadingShare(list, i);
+      if (i < list.length ? !joined : separators > 0) malformed = true;
+    }
+  }
+
+  const counts = { amount: 0, percent: 0, part: 0 };
+  for (let e = 0; e < entries.length; e++) {
+    if (entries[e].amount !== null) counts.amount++;
+    if (entries[e].share !== null) counts[entries[e].share.unit]++;
+  }
+  const kinds = Object.keys(counts).filter((kind) => counts[kind] > 0);
+  if (kinds.length > 1 || (kinds.length === 1 && counts[kinds[0]] !== entries.length)) {
+    malformed = true;
+  }
+  const shares = kinds.length === 1 && kinds[0] !== 'amount' ? kinds[0] : null;
+
+  const explicit = counts.amount > 0;
+  return malformed || entries.length === 0 ? null : { entries, explicit, shares };
+}
+
+module.exports = parseAccountList;
@@ -0,0 +1,100 @@
+const findAccount = require('./find-account');
+
+function isDigits(s) {
+  if (s.length === 0) return false;
+  for (let i = 0; i < s.length; i++) {
+    if (s[i] < '0' || s[i] > '9') return false;
+  }
+  return true;
+}
+
+// Trailing-digit references need at least this many digits to be meaningful
+const MIN_SUFFIX_DIGITS = 4;
+
+// Abbreviations written before an account id: a marker ("a/c 1", "acct1"), a number word
+// ("number 1", "#1"), or both ("a/c no. 1"), as words of their own or glued to the id
+const ACCOUNT_MARKERS = ['a/c', 'acct.', 'acct'];
+const ACCOUNT_NUMBER_MARKERS = ['number', 'no.', 'no', '#'];
+
+/**
+ * The text left once leading markers from `markers` are cut off `text`, trying the longest
+ * first, one marker at most.
+ */
+function stripMarker(text, markers) {
+  const lower = text.toLowerCase();
+  for (let m = 0; m < markers.length; m++) {
+    if (lower.startsWith(markers[m])) return text.substring(markers[m].length);
+  }
+  return text;
+}
+
+/**
+ * The id an abbreviated reference at tokens[start] names ("a/c 1", "acct1", "account number
+ * 1" after ACCOUNT, "#1"), with the tokens it takes, or null when there is no marker.
+ */
+function readAbbreviatedReference(tokens, start) {
+  let i = start;
+  const lowerAt = (k) => (k < tokens.length ? String(tokens[k]).toLowerCase() : '');
+  if (ACCOUNT_MARKERS.indexOf(lowerAt(i)) !== -1) i++;
+  if (ACCOUNT_NUMBER_MARKERS.indexOf(lowerAt(i)) !== -1) i++;
+  if (i > start) {
+    return i < tokens.length ? { id: String(tokens[i]), consumed: i - start + 1 } : null;
+  }
+  const first = String(tokens[start]);
+  const id = stripMarker(stripMarker(first, ACCOUNT_MARKERS), ['no.', '#']);
+  return id.length > 0 && id !== first ? { id, consumed: 1 } : null;
+}
+
+/**
+ * Read the account reference that starts at tokens[start].
+ *
+ * Besides a plain account id, two explicit trailing-digit forms are recognised:
+ *   - masked: "***4821"
+ *   - "ending 4821" / "ending in 4821"
+ * Only these markers trigger suffix matching, so ordinary ids that merely end in digits
+ * ("acc4821") are read as ids.
+ *
+ * Given the accounts, abbreviated references ("a/c 1", "acct1", "number 1", "#1") are read
+ * as the id after the abbreviation, but only when the reference as written is not an
+ * account id itself and the id after it is: "acct1" stays "acct1" when an account has that
+ * id, and "#1" is left alone when no account is called "1".
+ *
+ * @param {string[]} tokens
+ * @param {number} start
+ * @param {{ id: string }[]|null} [accounts] - the request's accounts, for abbreviations
+ * @returns {{ token: string, suffix: string|null, consumed: number }}
+ *   token is the reference as written (the id for an abbreviation), suffix the trailing
+ *   digits (or null for a plain id)
+ */
+function parseAccountReference(tokens, start, accounts = null) {
+  const first = String(tokens[start]);
+  let result = { token: first, suffix: null, consumed: 1 };
+
+  let stars = 0;
+  while (stars < first.length && first[stars] === '*') stars++;
+  const masked = first.substring(stars);
+  if (stars > 0 && masked.length >= MIN_SUFFIX_DIGITS && isDigits(masked)) {
+    result = { token: first, suffix: masked, consumed: 1 };
+  } else if (first.toLowerCase() === 'ending') {
+    const hasIn = start + 1 < tokens.length && String(tokens[start + 1]).toLowerCase() === 'in';
+    const iDigits = hasIn ? start + 2 : start + 1;
+    const digits = iDigits < tokens.length ? String(tokens[iDigits]) : '';
+    if (digits.length >= MIN_SUFFIX_DIGITS && isDigits(digits)) {
+      result = {
+        token: tokens.slice(start, iDigits + 1).join(' '),
+        suffix: digits,
+        consumed: iDigits - start + 1,
+      };
+    }
+  }
+  const abbreviated =
+    accounts !== null && result.suffix === null && findAccount(accounts, first) === null
+      ? readAbbreviatedReference(tokens, start)
+      : null;
+  if (abbreviated !== null && findAccount(accounts, abbreviated.id) !== null) {
+    result = { token: abbreviated.id, suffix: null, consumed: abbreviated.consumed };
+  }
+  return result;
+}
+
+module.exports = parseAccountReference;
@@ -0,0 +1,48 @@
+const DEFAULT_AMOUNT_PARSERS = require('../amount-parsers/default-amount-parsers');
+
+// -----------------------------
+// Amount extraction (no regex)
+// -----------------------------
+
+/**
+ * Extract the amount that starts at tokens[start].
+ *
+ * Only the amount position of the instruction is ever passed in, so tokens that
+ * merely contain digits elsewhere (e.g. account id "acc5k") are never read as amounts.
+ * Supported forms:
+ *   - plain numbers, optionally grouped: "5000", "1,000", "1,000.50" ("1.000,50" when
+ *     decimalSeparator is ',')
+ *   - shorthand suffixes (case-insensitive): "5k", "1.5m", "3mn", "2bn"
+ *   - a suffix in its own token: "5 k"
+ *   - number words: "five hundred", "a thousand and fifty"
+ *   - a share of the debit balance: "10%", "10 percent", "half", "a quarter", "a third"
+ *   - the whole debit balance: "all", "everything", "entire balance", "100%"
+ *
+ * Returns { amount, consumed } where consumed is the number of tokens used, or null when
+ * the token is not a recognisable amount (e.g. "5km", "5kg", "abc").
+ * The amount may be fractional ("1,000.50", "1.2345k"); checking its decimal places against
+ * the currency is left to the caller. Malformed groupings ("1,00,0") are not amounts.
+ * A run of number words that does not form a valid number yields { amount: null, consumed }.
+ * Balance shares yield { amount: null, ratio: { numerator, denominator }, sweep, consumed }
+ * and are resolved by the caller once the debit account is known; sweep marks a full-balance
+ * amount.
+ *
+ * Each form is read by one of the default amount parsers, tried in turn (see
+ * amount-parsers/default-amount-parsers.js); a different chain can be passed instead.
+ *
+ * @param {string[]} tokens
+ * @param {number} start
+ * @param {string} [decimalSeparator] - '.' (default) or ',' for European-style input
+ * @param {Object[]} [parsers] - amount parsers to try, in order
+ */
+function parseAmount(tokens, start, decimalSeparator = '.', parsers = DEFAULT_AMOUNT_PARSERS) {
+  if (!Array.isArray(tokens) || start >= tokens.length) return null;
+  const context = { decimalSeparator };
+  for (let i = 0; i < parsers.length; i++) {
+    const parsed = parsers[i].parse(tokens, start, context);
+    if (parsed) return parsed;
+  }
+  return null;
+}
+
+module.exports = parseAmount;
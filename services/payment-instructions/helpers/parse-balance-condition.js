@@ -0,0 +1,64 @@
+const parseAmount = require('./parse-amount');
+const { CONDITION_COMPARISONS, SUPPORTED_CURRENCIES } = require('./constants');
+
+/**
+ * Find a balance guard ("only if balance above 1000", "if the balance is at least 5k NGN")
+ * and read it out of the instruction. The guard is IF, optionally after ONLY, then BALANCE
+ * (optionally after THE or MY and before IS), a comparison (above or over, below or under,
+ * at least) and an amount with an optional currency code. It is looked for before any
+ * narration, as fee-source clauses are (see parseFeeSource), and a comma closing the words
+ * before it goes with it.
+ *
+ * @param {string[]} tokens
+ * @param {string} [decimalSeparator]
+ * @returns {{ tokens: string[], condition: { comparison: string, threshold: number,
+ *   currency: string|null }|null, text: string }|null} the tokens without the guard, the
+ *   condition (null when IF BALANCE is not followed by a comparison and a number) and the
+ *   guard as written; null when there is no guard
+ */
+function parseBalanceCondition(tokens, decimalSeparator = '.') {
+  const lowerAt = (k) => (k < tokens.length ? String(tokens[k]).toLowerCase() : '');
+  let found = null;
+  let narration = false;
+  for (let i = 1; i < tokens.length && found === null && !narration; i++) {
+    const lower = lowerAt(i);
+    narration = lower.indexOf('ref:') === 0 || (lower === 'for' && lowerAt(i + 1) !== 'credit');
+    let k = i + 1;
+    if (lowerAt(k) === 'the' || lowerAt(k) === 'my') k++;
+    if (lower === 'if' && lowerAt(k) === 'balance') {
+      const start = lowerAt(i - 1) === 'only' && i > 1 ? i - 1 : i;
+      k++;
+      if (lowerAt(k) === 'is') k++;
+      let comparison = null;
+      if (Object.prototype.hasOwnProperty.call(CONDITION_COMPARISONS, lowerAt(k))) {
+        comparison = CONDITION_COMPARISONS[lowerAt(k)];
+        k++;
+      } else if (lowerAt(k) === 'at' && lowerAt(k + 1) === 'least') {
+        comparison = 'at_least';
+        k += 2;
+      }
+      const parsed = comparison !== null ? parseAmount(tokens, k, decimalSeparator) : null;
+      let condition = null;
+      // A guard that does not read ends at the word it stopped on
+      let end = Math.min(k + 1, tokens.length);
+      if (parsed !== null && typeof parsed.amount === 'number') {
+        end = k + parsed.consumed;
+        const code = String(tokens[end] || '').toUpperCase();
+        const currency = SUPPORTED_CURRENCIES[code] !== undefined ? code : null;
+        if (currency !== null) end++;
+        condition = { comparison, threshold: parsed.amount, currency };
+      }
+      const before = tokens.slice(0, start);
+      const last = String(before[before.length - 1]);
+      if (last.endsWith(',')) before[before.length - 1] = last.slice(0, -1);
+      found = {
+        tokens: [...before, ...tokens.slice(end)],
+        condition,
+        text: tokens.slice(start, end).join(' '),
+      };
+    }
+  }
+  return found;
+}
+
+module.exports = parseBalanceCondition;
@@ -0,0 +1,86 @@
+const parseAmount = require('./parse-amount');
+const parseNegativeAmount = require('./parse-negative-amount');
+const placeCurrencySymbol = require('./place-currency-symbol');
+
+/**
+ * Read a bill payment: PAY and an amount to a biller, who is named either before the amount
+ * or after TO, with the account to pay from after FROM.
+ *
+ *   PAY [OF] <amount> [<currency>] TO <biller> [FROM [ACCOUNT] <acct>]  ("pay 5000 to DSTV")
+ *   PAY [OF] <amount> [<currency>] FROM [ACCOUNT] <acct> TO <biller>
+ *   PAY <biller> <amount> [<currency>] [FROM [ACCOUNT] <acct>]   ("pay electricity 3000 from acc1")
+ *
+ * A trailing "for <text>" / "ref: <text>" narration is kept. The biller may be several words
+ * ("Ikeja Electric"). Without FROM the account is defaultSource when one is given.
+ *
+ * @param {string[]} tokens
+ * @param {string} [decimalSeparator]
+ * @param {string|null} [defaultSource] - account to pay from when the instruction names none
+ * @returns {{ biller: string, tokens: string[] }|null} the biller and the instruction without
+ *   it ("PAY 5000 NGN FROM acc1 for March"); null when no biller can be told apart
+ */
+function parseBillPayment(tokens, decimalSeparator = '.', defaultSource = null) {
+  let lower = tokens.map((t) => String(t).toLowerCase());
+  if (lower[0] !== 'pay') return null;
+  const isMarker = (k) => lower[k] === 'for' || lower[k].indexOf('ref:') === 0;
+  const isBoundary = (k) => lower[k] === 'to' || lower[k] === 'from' || isMarker(k);
+  // Tokens the amount spans at k, once a currency symbol is moved out of the way (0 for none);
+  // a negative amount still counts, so that it fails as one
+  const amountAt = (k) => {
+    const placed = placeCurrencySymbol(tokens, k, decimalSeparator);
+    const parsed = parseAmount(placed, k, decimalSeparator);
+    let consumed = parseNegativeAmount(placed[k], decimalSeparator) !== null ? 1 : 0;
+    if (parsed !== null) consumed = parsed.consumed;
+    return { placed, consumed };
+  };
+
+  // "pay electricity 3000 ...": the words before the amount are the biller
+  let i = lower[1] === 'of' ? 2 : 1;
+  const leading = [];
+  let amount = i < tokens.length ? amountAt(i) : null;
+  while (amount !== null && amount.consumed === 0) {
+    if (isBoundary(i)) return null;
+    leading.push(tokens[i]);
+    i++;
+    amount = i < tokens.length ? amountAt(i) : null;
+  }
+  if (amount === null) return null;
+  // A symbol split off the amount ("₦5000") shifts the tokens after it
+  const { placed } = amount;
+  lower = placed.map((t) => String(t).toLowerCase());
+  const out = [tokens[0], ...placed.slice(i, i + amount.consumed)];
+  let j = i + amount.consumed;
+  if (j < placed.length && !isBoundary(j)) {
+    out.push(placed[j]);
+    j++;
+  }
+
+  // TO <biller> and FROM [ACCOUNT] <acct>, in either order, then the narration
+  let trailing = null;
+  let source = null;
+  while (j < placed.length && !isMarker(j)) {
+    if (lower[j] === 'to' && trailing === null) {
+      trailing = [];
+      j++;
+      while (j < placed.length && !isBoundary(j)) {
+        trailing.push(placed[j]);
+        j++;
+      }
+    } else if (lower[j] === 'from' && source === null) {
+      const length = lower[j + 1] === 'account' ? 3 : 2;
+      source = placed.slice(j, j + length);
+      j += length;
+    } else {
+      return null;
+    }
+  }
+  const biller = leading.length > 0 ? leading : trailing;
+  if (biller === null || biller.length === 0 || (leading.length > 0 && trailing !== null)) {
+    return null;
+  }
+  if (source === null && defaultSource !== null) source = ['FROM', defaultSource];
+
+  return { biller: biller.join(' '), tokens: [...out, ...(source || []), ...placed.slice(j)] };
+}
+
+module.exports = parseBillPayment;
@@ -0,0 +1,23 @@
+const parseWordAmount = require('./parse-word-amount');
+
+/**
+ * Parse a small positive count written as digits ("3") or number words ("three").
+ * Used by "in <n> days" and "every <n> weeks". Returns a positive integer or null.
+ * @param {string[]} words
+ * @returns {number|null}
+ */
+function parseCount(words) {
+  let count = null;
+  if (words.length === 1 && words[0].length > 0) {
+    let digits = true;
+    for (let i = 0; i < words[0].length; i++) {
+      if (words[0][i] < '0' || words[0][i] > '9') digits = false;
+    }
+    count = digits ? parseInt(words[0], 10) : parseWordAmount(words);
+  } else if (words.length > 1) {
+    count = parseWordAmount(words);
+  }
+  return count !== null && count > 0 ? count : null;
+}
+
+module.exports = parseCount;
@@ -0,0 +1,49 @@
+const {
+  CURRENCY_DECLARATION_WORDS,
+  CURRENCY_DECLARATION_MAX_TOKENS,
+  ISO_4217_CODES,
+} = require('./constants');
+const resolveCurrency = require('./resolve-currency');
+
+// The code a declared currency stands for: a supported code, word or symbol naming just one
+// currency, or any ISO 4217 code (so an unsupported one still fails as a currency); null
+// for anything else, a word shared by several currencies ("shillings") included
+function declaredCode(token) {
+  const codes = resolveCurrency(token);
+  if (codes.length === 1) return codes[0];
+  const upper = String(token).toUpperCase();
+  return codes.length === 0 && ISO_4217_CODES.indexOf(upper) !== -1 ? upper : null;
+}
+
+/**
+ * Read a leading sentence that declares the currency of the instruction after it: "Use
+ * USD. Transfer 500 from acc1 to acc2." The sentence ends at the first token ending in a
+ * period, has at most CURRENCY_DECLARATION_MAX_TOKENS tokens, names the currency last and
+ * is otherwise made of CURRENCY_DECLARATION_WORDS ("Currency: naira.", "All amounts are in
+ * USD."). With a declaration, the period ending the instruction is a sentence end and is
+ * dropped too.
+ *
+ * @param {string[]} tokens
+ * @returns {{ tokens: string[], currency: string }|null} the instruction without the
+ *   declaration and the ISO code declared, or null when it does not open with one
+ */
+function parseCurrencyDeclaration(tokens) {
+  let end = -1;
+  const limit = Math.min(tokens.length - 1, CURRENCY_DECLARATION_MAX_TOKENS);
+  for (let i = 0; i < limit && end === -1; i++) {
+    if (String(tokens[i]).endsWith('.')) end = i;
+  }
+  if (end < 1) return null;
+  for (let i = 0; i < end; i++) {
+    if (CURRENCY_DECLARATION_WORDS.indexOf(String(tokens[i]).toLowerCase()) === -1) return null;
+  }
+  const currency = declaredCode(String(tokens[end]).slice(0, -1));
+  if (currency === null) return null;
+
+  const rest = tokens.slice(end + 1);
+  const last = String(rest[rest.length - 1]);
+  if (last.length > 1 && last.endsWith('.')) rest[rest.length - 1] = last.slice(0, -1);
+  return { tokens: rest, currency };
+}
+
+module.exports = parseCurrencyDeclaration;
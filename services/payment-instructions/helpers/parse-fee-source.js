@@ -0,0 +1,38 @@
+const parseAccountReference = require('./parse-account-reference');
+const { FEE_SOURCE_FILLERS } = require('./constants');
+
+/**
+ * Find a fee-source clause ("..., fee from acc3", "and pay the fee from account acc3") and
+ * read it out of the instruction. The clause is looked for before any narration ("for
+ * <text>" other than the FOR CREDIT of the DEBIT form, "ref: <text>"), so a narration
+ * such as "for school fees from dad" is left alone. A comma closing the words before the
+ * clause goes with it.
+ *
+ * @param {string[]} tokens
+ * @param {Object[]|null} [accounts] - for abbreviated references (see parseAccountReference)
+ * @returns {{ tokens: string[], ref: Object }|null} the tokens without the clause and the
+ *   fee account reference, or null when there is no clause
+ */
+function parseFeeSource(tokens, accounts = null) {
+  const lowerAt = (k) => (k < tokens.length ? String(tokens[k]).toLowerCase() : '');
+  let found = null;
+  let narration = false;
+  for (let i = 1; i < tokens.length && found === null && !narration; i++) {
+    const lower = lowerAt(i);
+    narration = lower.indexOf('ref:') === 0 || (lower === 'for' && lowerAt(i + 1) !== 'credit');
+    const refIndex = lowerAt(i + 2) === 'account' ? i + 3 : i + 2;
+    const isFee = lower === 'fee' || lower === 'fees';
+    if (isFee && lowerAt(i + 1) === 'from' && refIndex < tokens.length) {
+      let start = i;
+      while (start > 1 && FEE_SOURCE_FILLERS.indexOf(lowerAt(start - 1)) !== -1) start--;
+      const ref = parseAccountReference(tokens, refIndex, accounts);
+      const before = tokens.slice(0, start);
+      const last = String(before[before.length - 1]);
+      if (last.endsWith(',')) before[before.length - 1] = last.slice(0, -1);
+      found = { tokens: [...before, ...tokens.slice(refIndex + ref.consumed)], ref };
+    }
+  }
+  return found;
+}
+
+module.exports = parseFeeSource;
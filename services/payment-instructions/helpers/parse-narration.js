@@ -0,0 +1,36 @@
+const { NARRATION_MAX_LENGTH } = require('./constants');
+
+/**
+ * Find the narration (purpose / reference) clause at or after tokens[start].
+ *
+ * Two markers are recognised, and everything after the first one is the narration:
+ *   - "for <text>"             ("... to acc2 for rent")
+ *   - "ref: <text>" / "ref:<text>"
+ * Callers pass the index right after the last account reference, so the keyword FOR of
+ * the DEBIT/CREDIT forms is never read as a narration marker. The text is trimmed and
+ * capped at NARRATION_MAX_LENGTH characters.
+ *
+ * @param {string[]} tokens
+ * @param {number} start
+ * @returns {{ start: number, text: string }} start is the marker index (tokens.length and
+ *   an empty text when there is no narration)
+ */
+function parseNarration(tokens, start) {
+  let result = { start: tokens.length, text: '' };
+  for (let i = start; i < tokens.length && result.start === tokens.length; i++) {
+    const lower = String(tokens[i]).toLowerCase();
+    let words = null;
+    if (lower === 'for') {
+      words = tokens.slice(i + 1);
+    } else if (lower.indexOf('ref:') === 0) {
+      words = [String(tokens[i]).substring(4), ...tokens.slice(i + 1)];
+    }
+    if (words !== null) {
+      const text = words.join(' ').trim();
+      result = { start: i, text: text.substring(0, NARRATION_MAX_LENGTH).trim() };
+    }
+  }
+  return result;
+}
+
+module.exports = parseNarration;
@@ -0,0 +1,20 @@
+const parseAmount = require('./parse-amount');
+
+/**
+ * Read a negative amount written with a leading minus sign ("-50", "-1.5k").
+ * parseAmount never accepts a sign, so this only runs for tokens it rejected.
+ * @param {string} token
+ * @param {string} [decimalSeparator] - '.' (default) or ','
+ * @returns {number|null} The (negative) amount, or null when the token is not a signed amount
+ */
+function parseNegativeAmount(token, decimalSeparator = '.') {
+  const s = String(token);
+  let amount = null;
+  if (s.length > 1 && s[0] === '-') {
+    const parsed = parseAmount([s.substring(1)], 0, decimalSeparator);
+    if (parsed !== null && parsed.amount !== null && parsed.amount > 0) amount = -parsed.amount;
+  }
+  return amount;
+}
+
+module.exports = parseNegativeAmount;
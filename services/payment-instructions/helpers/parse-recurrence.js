@@ -0,0 +1,74 @@
+const { WEEKDAYS, RECURRENCE_UNITS, RECURRENCE_ADVERBS } = require('./constants');
+const parseCount = require('./parse-count');
+
+// -----------------------------
+// Standing order recurrence (no regex)
+// -----------------------------
+
+const DAY_MS = 24 * 60 * 60 * 1000;
+
+function has(table, word) {
+  return Object.prototype.hasOwnProperty.call(table, word);
+}
+
+/**
+ * Parse a recurrence phrase and find its first occurrence.
+ *
+ * Supported phrases (case-insensitive):
+ *   - "daily", "weekly", "monthly"
+ *   - "every day|week|month", "every <n> days|weeks|months" (n as digits or words)
+ *   - "every <weekday>"
+ *   - "every other day|week|month|<weekday>" (count 2)
+ *
+ * Intervals shorter than a day ("every hour") and zero counts ("every 0 days") are rejected.
+ * The first occurrence is today for plain intervals and the next matching weekday on or
+ * after today for weekday recurrences.
+ *
+ * @param {string[]} words
+ * @param {Date} now - reference time
+ * @returns {{ unit: string, count: number, weekday: string|null,
+ *   first: { year: number, month: number, day: number } }|null}
+ */
+function parseRecurrence(words, now) {
+  const list = [];
+  for (let i = 0; i < words.length; i++) list.push(String(words[i]).toLowerCase());
+
+  let unit = null;
+  let count = null;
+  let weekday = null;
+  if (list.length === 1 && has(RECURRENCE_ADVERBS, list[0])) {
+    unit = RECURRENCE_ADVERBS[list[0]];
+    count = 1;
+  } else if (list.length >= 2 && list[0] === 'every') {
+    const last = list[list.length - 1];
+    let middle = list.slice(1, list.length - 1);
+    if (middle.length === 1 && middle[0] === 'other') {
+      count = 2;
+      middle = [];
+    }
+    if (middle.length === 0 && WEEKDAYS.indexOf(last) !== -1) {
+      unit = 'week';
+      weekday = last;
+      count = count || 1;
+    } else if (has(RECURRENCE_UNITS, last)) {
+      unit = RECURRENCE_UNITS[last];
+      count = middle.length === 0 ? count || 1 : parseCount(middle);
+    }
+  }
+
+  let result = null;
+  if (unit !== null && count !== null) {
+    const today = Date.UTC(now.getUTCFullYear(), now.getUTCMonth(), now.getUTCDate());
+    const ahead = weekday === null ? 0 : (WEEKDAYS.indexOf(weekday) - now.getUTCDay() + 7) % 7;
+    const d = new Date(today + ahead * DAY_MS);
+    result = {
+      unit,
+      count,
+      weekday,
+      first: { year: d.getUTCFullYear(), month: d.getUTCMonth() + 1, day: d.getUTCDate() },
+    };
+  }
+  return result;
+}
+
+module.exports = parseRecurrence;
@@ -0,0 +1,64 @@
+const { WEEKDAYS, DAY_OFFSET_UNITS } = require('./constants');
+const parseCount = require('./parse-count');
+
+// -----------------------------
+// Relative schedule dates (no regex)
+// -----------------------------
+
+const DAY_MS = 24 * 60 * 60 * 1000;
+
+/**
+ * Calendar date of a UTC millisecond timestamp as { year, month, day }.
+ */
+function toDateParts(ms) {
+  const d = new Date(ms);
+  return { year: d.getUTCFullYear(), month: d.getUTCMonth() + 1, day: d.getUTCDate() };
+}
+
+/**
+ * Resolve a relative date phrase against a reference time.
+ *
+ * Supported phrases (case-insensitive):
+ *   - "today", "tomorrow"
+ *   - "next <weekday>": the first such weekday strictly after today
+ *   - "in <n> day(s)|week(s)": n as digits or number words
+ *   - "end of month" / "end of the month": the last day of the current month
+ *
+ * All calculations use calendar days in UTC.
+ *
+ * @param {string[]} words - the date clause, without any leading "on"
+ * @param {Date} now - reference time ("today" is now's UTC date)
+ * @returns {{ year: number, month: number, day: number }|null}
+ */
+function parseRelativeDate(words, now) {
+  const list = [];
+  for (let i = 0; i < words.length; i++) list.push(String(words[i]).toLowerCase());
+  const today = Date.UTC(now.getUTCFullYear(), now.getUTCMonth(), now.getUTCDate());
+
+  let result = null;
+  if (list.length === 1 && list[0] === 'today') {
+    result = toDateParts(today);
+  } else if (list.length === 1 && list[0] === 'tomorrow') {
+    result = toDateParts(today + DAY_MS);
+  } else if (list.length === 2 && list[0] === 'next' && WEEKDAYS.indexOf(list[1]) !== -1) {
+    const ahead = (WEEKDAYS.indexOf(list[1]) - now.getUTCDay() + 7) % 7 || 7;
+    result = toDateParts(today + ahead * DAY_MS);
+  } else if (list.length >= 3 && list[0] === 'in') {
+    const unit = list[list.length - 1];
+    const count = parseCount(list.slice(1, list.length - 1));
+    if (Object.prototype.hasOwnProperty.call(DAY_OFFSET_UNITS, unit) && count !== null) {
+      result = toDateParts(today + count * DAY_OFFSET_UNITS[unit] * DAY_MS);
+    }
+  } else if (
+    list[0] === 'end' &&
+    list[1] === 'of' &&
+    ((list.length === 3 && list[2] === 'month') ||
+      (list.length === 4 && list[2] === 'the' && list[3] === 'month'))
+  ) {
+    // Day 0 of the next month is the last day of this one
+    result = toDateParts(Date.UTC(now.getUTCFullYear(), now.getUTCMonth() + 1, 0));
+  }
+  return result;
+}
+
+module.exports = parseRelativeDate;
@@ -0,0 +1,57 @@
+// -----------------------------
+// Schedule times of day (no regex)
+// -----------------------------
+
+function isDigits(s) {
+  if (s.length === 0) return false;
+  for (let i = 0; i < s.length; i++) {
+    if (s[i] < '0' || s[i] > '9') return false;
+  }
+  return true;
+}
+
+/**
+ * Parse a time of day written after a schedule date.
+ *
+ * Supported forms (case-insensitive):
+ *   - 24-hour "14:30", "9:05" or "14:30:15"
+ *   - 12-hour "2:30pm", "2pm", "11:45 am" (the am/pm may stand apart)
+ *
+ * Returns null when the words are not shaped like a time at all, otherwise
+ * { seconds, valid } where seconds is the time since midnight and valid is false for times
+ * that do not exist ("25:00", "13pm", "9:75").
+ *
+ * @param {string[]} words - one or two tokens
+ * @returns {{ seconds: number, valid: boolean }|null}
+ */
+function parseTimeOfDay(words) {
+  if (words.length === 0 || words.length > 2) return null;
+  let clock = words.join('').toLowerCase();
+  let meridiem = null;
+  const suffix = clock.substring(clock.length - 2);
+  if (suffix === 'am' || suffix === 'pm') {
+    meridiem = suffix;
+    clock = clock.substring(0, clock.length - 2);
+  }
+  // Only the am/pm may be a word of its own
+  if (words.length === 2 && String(words[1]).toLowerCase() !== meridiem) return null;
+
+  const parts = clock.split(':');
+  if (parts.length > 3 || (parts.length === 1 && meridiem === null)) return null;
+  if (meridiem !== null && parts.length === 3) return null;
+  for (let i = 0; i < parts.length; i++) {
+    const width = i === 0 ? parts[i].length <= 2 : parts[i].length === 2;
+    if (!width || !isDigits(parts[i])) return null;
+  }
+
+  const hour = parseInt(parts[0], 10);
+  const minute = parts.length > 1 ? parseInt(parts[1], 10) : 0;
+  const second = parts.length > 2 ? parseInt(parts[2], 10) : 0;
+  const hourValid = meridiem === null ? hour <= 23 : hour >= 1 && hour <= 12;
+  const valid = hourValid && minute <= 59 && second <= 59;
+  let hours = hour;
+  if (meridiem !== null) hours = (hour % 12) + (meridiem === 'pm' ? 12 : 0);
+  return { seconds: hours * 3600 + minute * 60 + second, valid };
+}
+
+module.exports = parseTimeOfDay;
@@ -0,0 +1,111 @@
+const {
+  NUMBER_UNITS,
+  NUMBER_TEENS,
+  NUMBER_TENS,
+  NUMBER_SCALES,
+  NUMBER_ARTICLES,
+} = require('./constants');
+
+// -----------------------------
+// Written-out number words (no regex)
+// -----------------------------
+
+function has(table, word) {
+  return Object.prototype.hasOwnProperty.call(table, word);
+}
+
+/**
+ * Split hyphenated words ("twenty-five") and lowercase everything.
+ */
+function expandWords(words) {
+  const out = [];
+  for (let i = 0; i < words.length; i++) {
+    const parts = String(words[i]).toLowerCase().split('-');
+    for (let j = 0; j < parts.length; j++) out.push(parts[j]);
+  }
+  return out;
+}
+
+/**
+ * Convert English number words into an integer.
+ *
+ * Supports compound forms up to the billions ("one thousand two hundred fifty"),
+ * "and" as a connector after hundred/scale words ("one thousand and fifty"), and a
+ * leading indefinite article ("a hundred" = 100, "a million" = 1000000).
+ *
+ * Returns null for ambiguous or incomplete sequences ("five five", "one thousand and",
+ * "thousand million") rather than guessing.
+ *
+ * @param {string[]} words
+ * @returns {number|null}
+ */
+function parseWordAmount(words) {
+  if (!Array.isArray(words) || words.length === 0) return null;
+  const list = expandWords(words);
+
+  let total = 0;
+  let current = 0;
+  let lastScale = Infinity; // last thousand/million/billion value applied
+  let last = null; // kind of the previous word
+  let seenNumber = false;
+
+  for (let i = 0; i < list.length; i++) {
+    const w = list[i];
+    if (w.length === 0) return null;
+
+    if (NUMBER_ARTICLES.indexOf(w) !== -1) {
+      // Only as the very first word and directly before a scale word
+      if (i !== 0 || i + 1 >= list.length) return null;
+      if (list[i + 1] !== 'hundred' && !has(NUMBER_SCALES, list[i + 1])) return null;
+      current = 1;
+      last = 'article';
+    } else if (w === 'zero') {
+      if (list.length !== 1) return null;
+      return 0;
+    } else if (has(NUMBER_UNITS, w)) {
+      if (last === 'unit' || last === 'teen' || last === 'article') return null;
+      if (current % 10 !== 0 || (current % 100 !== 0 && current % 100 < 20)) return null;
+      current += NUMBER_UNITS[w];
+      last = 'unit';
+      seenNumber = true;
+    } else if (has(NUMBER_TEENS, w)) {
+      if (last === 'unit' || last === 'teen' || last === 'tens' || last === 'article') return null;
+      if (current % 100 !== 0) return null;
+      current += NUMBER_TEENS[w];
+      last = 'teen';
+      seenNumber = true;
+    } else if (has(NUMBER_TENS, w)) {
+      if (last === 'unit' || last === 'teen' || last === 'tens' || last === 'article') return null;
+      if (current % 100 !== 0) return null;
+      current += NUMBER_TENS[w];
+      last = 'tens';
+      seenNumber = true;
+    } else if (w === 'hundred') {
+      // "five hundred", "fifteen hundred", but never "hundred hundred"
+      if (current <= 0 || current >= 100 || last === 'and') return null;
+      current *= 100;
+      last = 'hundred';
+      seenNumber = true;
+    } else if (has(NUMBER_SCALES, w)) {
+      const scale = NUMBER_SCALES[w];
+      if (current <= 0 || scale >= lastScale || last === 'and') return null;
+      total += current * scale;
+      current = 0;
+      lastScale = scale;
+      last = 'scale';
+      seenNumber = true;
+    } else if (w === 'and') {
+      // Connector is only meaningful after hundred/scale and before more number words
+      if (last !== 'hundred' && last !== 'scale') return null;
+      if (i + 1 >= list.length) return null;
+      last = 'and';
+    } else {
+      return null;
+    }
+  }
+
+  if (!seenNumber || last === 'and' || last === 'article') return null;
+  return total + current;
+}
+
+module.exports = parseWordAmount;
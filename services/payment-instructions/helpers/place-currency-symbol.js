@@ -0,0 +1,72 @@
+const { CURRENCY_SYMBOLS, ISO_4217_CODES } = require('./constants');
+const parseAmount = require('./parse-amount');
+const resolveCurrency = require('./resolve-currency');
+
+// Longest first, so "US$" wins over "$" and "KSh" over "Sh"
+const SYMBOLS = Object.keys(CURRENCY_SYMBOLS).sort((a, b) => b.length - a.length);
+
+function startsWithDigit(s) {
+  return s.length > 0 && s[0] >= '0' && s[0] <= '9';
+}
+
+/**
+ * Split a currency symbol off an amount token: "₦5000" -> { symbol: '₦', rest: '5000' },
+ * "20€" -> { symbol: '€', rest: '20' }. The rest must be a number ("$abc" is not split).
+ * Returns null when the token carries no symbol.
+ */
+function splitSymbol(token) {
+  for (let i = 0; i < SYMBOLS.length; i++) {
+    const symbol = SYMBOLS[i];
+    if (token.length > symbol.length && token.startsWith(symbol)) {
+      const rest = token.substring(symbol.length);
+      if (startsWithDigit(rest)) return { symbol, rest };
+    }
+    if (token.length > symbol.length && token.endsWith(symbol)) {
+      const rest = token.substring(0, token.length - symbol.length);
+      if (startsWithDigit(rest)) return { symbol, rest };
+    }
+  }
+  return null;
+}
+
+// A currency code or word standing before the amount ("NGN 5000", "naira 5000"); ISO codes
+// that are not supported still count, so they fail as currencies (CU02/CU04), not as amounts
+function isCurrencyWord(token) {
+  return resolveCurrency(token).length > 0 || ISO_4217_CODES.indexOf(token.toUpperCase()) !== -1;
+}
+
+/**
+ * Move a currency symbol written with the amount into the currency position, so
+ * "DEBIT ₦5000 FROM ...", "DEBIT $ 20 FROM ..." and "DEBIT NGN 5000 FROM ..." read like
+ * "DEBIT 5000 ₦ FROM ...". The symbol may be attached before or after the number, or stand
+ * alone just before it, as may a currency code or word. Only the token at the amount
+ * position is looked at, so an account such as "NGN-holder" is never taken for a currency.
+ *
+ * @param {string[]} tokens
+ * @param {number} start - index of the amount
+ * @param {string} [decimalSeparator] - '.' (default) or ','
+ * @param {Object[]} [parsers] - amount parsers reading the amount (see parse-amount.js)
+ * @returns {string[]} tokens unchanged when there is no symbol, else a rearranged copy
+ */
+function placeCurrencySymbol(tokens, start, decimalSeparator = '.', parsers) {
+  const token = tokens[start];
+  if (token === undefined) return tokens;
+
+  let symbol = null;
+  let rest = tokens.slice(start + 1);
+  const attached = splitSymbol(token);
+  if (attached !== null) {
+    symbol = attached.symbol;
+    rest = [attached.rest, ...rest];
+  } else if (SYMBOLS.indexOf(token) !== -1 && rest.length > 0) {
+    symbol = token;
+  }
+  const parsed = parseAmount(rest, 0, decimalSeparator, parsers);
+  if (symbol === null && parsed !== null && isCurrencyWord(token)) symbol = token;
+  if (symbol === null) return tokens;
+
+  const consumed = parsed ? parsed.consumed : 1;
+  return [...tokens.slice(0, start), ...rest.slice(0, consumed), symbol, ...rest.slice(consumed)];
+}
+
+module.exports = placeCurrencySymbol;
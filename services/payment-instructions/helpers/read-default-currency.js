@@ -0,0 +1,13 @@
+/**
+ * The currency to read for an instruction that names none: the payload's default_currency,
+ * else options.defaultCurrency, upper-cased; null when neither is set.
+ * @param {Object} data - validated payload
+ * @param {Object} options - service options
+ * @returns {string|null}
+ */
+function readDefaultCurrency(data, options) {
+  const code = data.default_currency || options.defaultCurrency;
+  return code ? String(code).toUpperCase() : null;
+}
+
+module.exports = readDefaultCurrency;
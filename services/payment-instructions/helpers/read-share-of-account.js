@@ -0,0 +1,29 @@
+const parseAmount = require('./parse-amount');
+
+/**
+ * Rewrite a balance share of a named account into the FROM form the parser reads, when the
+ * instruction has no FROM of its own:
+ *
+ *   "move half of savings to current" -> move half from savings to current
+ *   "transfer 10% of acc1 to acc2"    -> transfer 10% from acc1 to acc2
+ *
+ * "half of my balance" is a share of the debit balance and is read by the amount parser.
+ *
+ * @param {string[]} tokens
+ * @param {number} start - index of the amount
+ * @param {string} [decimalSeparator]
+ * @param {Object[]} [parsers] - amount parsers (see parseAmount)
+ * @returns {string[]|null} the rewritten tokens, or null when the amount is not a share
+ *   followed by OF
+ */
+function readShareOfAccount(tokens, start, decimalSeparator, parsers) {
+  const lowerTokens = tokens.map((t) => String(t).toLowerCase());
+  if (lowerTokens.indexOf('from') !== -1) return null;
+  const share = parseAmount(tokens, start, decimalSeparator, parsers);
+  if (share === null || !share.ratio) return null;
+  const iOf = start + share.consumed;
+  if (lowerTokens[iOf] !== 'of' || iOf + 1 >= tokens.length) return null;
+  return [...tokens.slice(0, iOf), 'from', ...tokens.slice(iOf + 1)];
+}
+
+module.exports = readShareOfAccount;
@@ -0,0 +1,33 @@
+const isValidAccountId = require('./is-valid-account-id');
+const resolveAccountAlias = require('./resolve-account-alias');
+
+/**
+ * Ids an instruction could refer to, for loading just those accounts from a store.
+ *
+ * Every token shaped like an account id is a candidate (list punctuation such as the comma
+ * in "acc2, acc3" is dropped), and an alias name stands for the id it maps to. Keywords,
+ * amounts and currencies are candidates too; a store simply does not know them. References
+ * by trailing digits ("***4821"), by other casing or by an abbreviation glued to the id
+ * ("acct1") need the whole account set and are not resolved here.
+ *
+ * @param {string[]} tokens
+ * @param {Object<string, string>|null} aliases
+ * @returns {string[]} distinct ids, in instruction order
+ */
+function referencedAccountIds(tokens, aliases) {
+  const ids = [];
+  const add = (id) => {
+    if (isValidAccountId(id) && ids.indexOf(id) === -1) ids.push(id);
+  };
+  for (let i = 0; i < tokens.length; i++) {
+    let token = String(tokens[i]);
+    while (token.endsWith(',') || token.endsWith(';')) token = token.slice(0, -1);
+    add(token);
+    const alias = aliases ? resolveAccountAlias(token, aliases) : null;
+    if (alias && alias.id) add(alias.id);
+    if (alias && alias.ambiguous) alias.ambiguous.forEach(add);
+  }
+  return ids;
+}
+
+module.exports = referencedAccountIds;
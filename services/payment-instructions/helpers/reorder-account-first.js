@@ -0,0 +1,71 @@
+const parseAmount = require('./parse-amount');
+const placeCurrencySymbol = require('./place-currency-symbol');
+const resolveCurrency = require('./resolve-currency');
+const parseAccountReference = require('./parse-account-reference');
+const isValidAccountId = require('./is-valid-account-id');
+const findAccount = require('./find-account');
+const { ACCOUNT_FIRST_VERBS, ACCOUNT_FIRST_FILLERS, ISO_4217_CODES } = require('./constants');
+
+/**
+ * Rewrite an instruction that names an account before its amount into the keyword form the
+ * parser reads, deciding debit and credit by the verb and the preposition rather than by
+ * position:
+ *
+ *   "debit acc1 100 NGN to acc2"          -> DEBIT 100 NGN FROM ACCOUNT acc1 FOR CREDIT TO ...
+ *   "credit acc2 with 100 NGN from acc1"  -> CREDIT 100 NGN TO ACCOUNT acc2 FOR DEBIT FROM ...
+ *   "pay acc2 100 NGN from account acc1"  -> CREDIT 100 NGN TO ACCOUNT acc2 FOR DEBIT FROM ...
+ *
+ * The account after a debit is the debit account and the other one follows TO; the account
+ * after a credit or pay is the credit account and the other one follows FROM. ACCOUNT before
+ * either id is optional and anything after the second account (ON date, narration) is kept.
+ * A first account spelled like a currency ("dollar") is read as an account only when it is
+ * the id of one of the accounts.
+ *
+ * @param {string[]} tokens
+ * @param {{ id: string }[]} [accounts]
+ * @returns {string[]|null} the rewritten tokens, or null when the instruction does not open
+ *   with one of these verbs followed by an account reference
+ */
+function reorderAccountFirst(tokens, accounts = []) {
+  const lowerTokens = tokens.map((t) => String(t).toLowerCase());
+  const verb = lowerTokens[0];
+  if (!Object.prototype.hasOwnProperty.call(ACCOUNT_FIRST_VERBS, verb)) return null;
+  const type = ACCOUNT_FIRST_VERBS[verb];
+
+  // The token after the verb must be an account reference, not an amount or a currency...
+  const firstIndex = lowerTokens[1] === 'account' ? 2 : 1;
+  if (firstIndex >= tokens.length || parseAmount(tokens, firstIndex) !== null) return null;
+  const first = parseAccountReference(tokens, firstIndex);
+  const isCurrency =
+    findAccount(accounts, first.token) === null &&
+    (ISO_4217_CODES.indexOf(first.token.toUpperCase()) !== -1 ||
+      resolveCurrency(first.token).length > 0);
+  if (first.suffix === null && (!isValidAccountId(first.token) || isCurrency)) return null;
+
+  let amountStart = firstIndex + first.consumed;
+  if (ACCOUNT_FIRST_FILLERS.indexOf(lowerTokens[amountStart]) !== -1) amountStart++;
+  // ...and an amount must follow it ("Sh500" and "-500" are amounts, not accounts)
+  if (parseAmount(placeCurrencySymbol(tokens, amountStart), amountStart) === null) return null;
+
+  // The other account follows TO after a debit and FROM after a credit
+  const preposition = type === 'DEBIT' ? 'to' : 'from';
+  const iPreposition = lowerTokens.indexOf(preposition, amountStart + 1);
+  if (iPreposition === -1) return null;
+  const secondIndex = iPreposition + (lowerTokens[iPreposition + 1] === 'account' ? 2 : 1);
+  if (secondIndex >= tokens.length) return null;
+  const second = parseAccountReference(tokens, secondIndex);
+
+  const amount = tokens.slice(amountStart, iPreposition);
+  const firstAccount = tokens.slice(firstIndex, firstIndex + first.consumed);
+  const secondAccount = tokens.slice(secondIndex, secondIndex + second.consumed);
+  const rest = tokens.slice(secondIndex + second.consumed);
+  const debitAccount = type === 'DEBIT' ? firstAccount : secondAccount;
+  const creditAccount = type === 'DEBIT' ? secondAccount : firstAccount;
+  const debitClause = ['FROM', 'ACCOUNT', ...debitAccount];
+  const creditClause = ['TO', 'ACCOUNT', ...creditAccount];
+  return type === 'DEBIT'
+    ? ['DEBIT', ...amount, ...debitClause, 'FOR', 'CREDIT', ...creditClause, ...rest]
+    : ['CREDIT', ...amount, ...creditClause, 'FOR', 'DEBIT', ...debitClause, ...rest];
+}
+
+module.exports = reorderAccountFirst;
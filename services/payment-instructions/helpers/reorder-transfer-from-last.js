@@ -0,0 +1,34 @@
+const parseAccountReference = require('./parse-account-reference');
+
+/**
+ * Rewrite a TRANSFER that names its credit account first into the FROM ... TO order the
+ * parser reads, keeping anything after the debit account (ON date, narration):
+ *
+ *   "transfer 100 NGN to acc2 from acc1 for rent" -> transfer 100 NGN from acc1 to acc2 for rent
+ *
+ * Only for one debit account; a list after FROM is a MULTI_DEBIT (see
+ * process-multi-debit-instruction.js).
+ *
+ * @param {string[]} tokens
+ * @param {{ id: string }[]} [accounts]
+ * @returns {string[]|null} the rewritten tokens, or null unless FROM comes after the only TO
+ */
+function reorderTransferFromLast(tokens, accounts = []) {
+  const lowerTokens = tokens.map((t) => String(t).toLowerCase());
+  const iTo = lowerTokens.indexOf('to');
+  const iFrom = lowerTokens.indexOf('from');
+  // A TO after FROM is the credit account's ("100 to 200 NGN from acc1 to acc2")
+  if (lowerTokens[0] !== 'transfer' || iTo === -1 || iFrom < iTo) return null;
+  if (lowerTokens.indexOf('to', iFrom) !== -1) return null;
+  const debitIndex = iFrom + (lowerTokens[iFrom + 1] === 'account' ? 2 : 1);
+  if (debitIndex >= tokens.length) return null;
+  const debitEnd = debitIndex + parseAccountReference(tokens, debitIndex, accounts).consumed;
+  return [
+    ...tokens.slice(0, iTo),
+    ...tokens.slice(iFrom, debitEnd),
+    ...tokens.slice(iTo, iFrom),
+    ...tokens.slice(debitEnd),
+  ];
+}
+
+module.exports = reorderTransferFromLast;
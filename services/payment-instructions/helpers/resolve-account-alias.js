@@ -0,0 +1,31 @@
+const normalizeInstruction = require('./normalize-instruction');
+
+/**
+ * Look up an account token in an alias map (alias -> account id), ignoring case.
+ *
+ * Returns { id } for a single match, { ambiguous: [ids] } when differently-cased aliases
+ * point at different accounts ("Rent" and "rent"), or null when no alias matches.
+ * @param {string} token
+ * @param {Object<string, string>} aliases
+ * @returns {{ id: string }|{ ambiguous: string[] }|null}
+ */
+function resolveAccountAlias(token, aliases) {
+  const wanted = String(token).toLowerCase();
+  const names = Object.keys(aliases || {});
+  const ids = [];
+  for (let i = 0; i < names.length; i++) {
+    const id = aliases[names[i]];
+    // Tokens are normalized (see normalizeInstruction); names match as written or normalized
+    const name = names[i].toLowerCase();
+    const matches = name === wanted || normalizeInstruction(name) === wanted;
+    if (matches && typeof id === 'string' && ids.indexOf(id) === -1) {
+      ids.push(id);
+    }
+  }
+  let result = null;
+  if (ids.length === 1) result = { id: ids[0] };
+  else if (ids.length > 1) result = { ambiguous: ids };
+  return result;
+}
+
+module.exports = resolveAccountAlias;
@@ -0,0 +1,35 @@
+const { SUPPORTED_CURRENCIES, CURRENCY_SYMBOLS } = require('./constants');
+const isCurrencySymbol = require('./is-currency-symbol');
+
+/**
+ * Map a currency token to the supported ISO 4217 codes it may stand for.
+ * Codes match case-insensitively ("kes", "KES"); word forms come from the
+ * SUPPORTED_CURRENCIES table ("rand" -> ['ZAR'], "shillings" -> ['KES', 'UGX']) and
+ * symbols from CURRENCY_SYMBOLS ("₦" -> ['NGN'], "Sh" -> ['KES', 'UGX']).
+ * A symbol shared by several codes is narrowed to the codes in heldCurrencies, when any
+ * of them is held.
+ * Returns an empty array for unknown tokens.
+ * @param {string} token
+ * @param {string[]} [heldCurrencies] - currencies of the accounts in the request
+ * @returns {string[]}
+ */
+function resolveCurrency(token, heldCurrencies = []) {
+  const upper = String(token || '').toUpperCase();
+  const lower = upper.toLowerCase();
+  const codes = Object.keys(SUPPORTED_CURRENCIES);
+  let candidates = [];
+  if (isCurrencySymbol(token)) {
+    candidates = CURRENCY_SYMBOLS[token].slice();
+    const held = candidates.filter((code) => heldCurrencies.indexOf(code) !== -1);
+    if (held.length > 0) candidates = held;
+  } else if (codes.indexOf(upper) !== -1) {
+    candidates.push(upper);
+  } else {
+    for (let i = 0; i < codes.length; i++) {
+      if (SUPPORTED_CURRENCIES[codes[i]].indexOf(lower) !== -1) candidates.push(codes[i]);
+    }
+  }
+  return candidates;
+}
+
+module.exports = resolveCurrency;
@@ -0,0 +1,28 @@
+const toMinorUnits = require('./to-minor-units');
+const fromMinorUnits = require('./from-minor-units');
+const roundRatio = require('./round-ratio');
+const { ROUNDING_POLICY } = require('./constants');
+
+/**
+ * Resolve a fraction of a balance (e.g. 10% or 1/3) to an amount rounded to the currency's
+ * minor units under the rounding policy. Integer (BigInt) arithmetic keeps large balances
+ * exact.
+ *
+ * @param {number} balance - Balance in major units
+ * @param {{numerator: number|bigint, denominator: number|bigint}} ratio
+ * @param {string} currency
+ * @param {string} [rounding] - rounding policy (see ROUNDING_POLICY)
+ * @returns {number} Amount in major units
+ */
+function resolveRatioAmount(balance, ratio, currency, rounding = ROUNDING_POLICY) {
+  const minor = BigInt(toMinorUnits(balance, currency));
+  const numerator = BigInt(ratio.numerator);
+  const denominator = BigInt(ratio.denominator);
+  let amount = 0;
+  if (minor > 0n && numerator > 0n) {
+    amount = fromMinorUnits(roundRatio(minor * numerator, denominator, rounding), currency);
+  }
+  return amount;
+}
+
+module.exports = resolveRatioAmount;
@@ -0,0 +1,25 @@
+const { ROUNDING_POLICY, ROUNDING_POLICIES } = require('./constants');
+
+/**
+ * Round numerator / denominator (non-negative BigInts) to a whole number under a rounding
+ * policy: 'half_even' (a tie goes to the even neighbour), 'half_up' (a tie goes up) or
+ * 'truncate' (toward zero). An unknown policy reads as ROUNDING_POLICY.
+ *
+ * @param {bigint} numerator
+ * @param {bigint} denominator - > 0
+ * @param {string} [policy]
+ * @returns {bigint}
+ */
+function roundRatio(numerator, denominator, policy = ROUNDING_POLICY) {
+  const mode = ROUNDING_POLICIES.indexOf(policy) !== -1 ? policy : ROUNDING_POLICY;
+  const quotient = numerator / denominator;
+  const twiceRest = 2n * (numerator % denominator);
+  let rounded = quotient;
+  if (mode !== 'truncate' && twiceRest > denominator) rounded = quotient + 1n;
+  if (mode !== 'truncate' && twiceRest === denominator && twiceRest > 0n) {
+    rounded = mode === 'half_up' ? quotient + 1n : quotient + (quotient % 2n);
+  }
+  return rounded;
+}
+
+module.exports = roundRatio;
@@ -0,0 +1,18 @@
+const { CONFIDENCE_PENALTIES } = require('./constants');
+
+/**
+ * Confidence (0-1, two decimals) that a parse means what the user intended, from the
+ * guesses the parser had to make; see CONFIDENCE_PENALTIES.
+ * @param {string[]} signals - one entry per guess, e.g. ['alias', 'alias', 'word_amount']
+ * @returns {number}
+ */
+function scoreConfidence(signals) {
+  let score = 1;
+  for (let i = 0; i < signals.length; i++) {
+    const penalty = CONFIDENCE_PENALTIES[signals[i]] || 0;
+    score *= 1 - penalty;
+  }
+  return Math.round(score * 100) / 100;
+}
+
+module.exports = scoreConfidence;
@@ -0,0 +1,29 @@
+const isWalletAccount = require('./is-wallet-account');
+
+/**
+ * The accounts with every wallet shown as one of its pockets: the one in currency when it
+ * holds it, else its first, as an ordinary account with that pocket's balance and currency.
+ * Other accounts are kept as they are. Pocket currency codes are matched ignoring case.
+ * @param {Object[]} accounts
+ * @param {string|null} currency
+ * @returns {Object[]}
+ */
+function selectWalletPockets(accounts, currency) {
+  const wanted = String(currency || '').toUpperCase();
+  return accounts.map((account) => {
+    if (!isWalletAccount(account)) return account;
+    const { balances, ...rest } = account;
+    const codes = Object.keys(balances);
+    let code = codes.length > 0 ? codes[0] : null;
+    for (let i = 0; i < codes.length; i++) {
+      if (codes[i].toUpperCase() === wanted) code = codes[i];
+    }
+    return {
+      ...rest,
+      balance: code !== null ? balances[code] : 0,
+      currency: code !== null ? code.toUpperCase() : '',
+    };
+  });
+}
+
+module.exports = selectWalletPockets;
@@ -0,0 +1,26 @@
+/**
+ * Apply a power-of-ten multiplier to a decimal string without floating point drift.
+ * "1.5" x 10^6 -> 1500000, "0.5" x 10^3 -> 500. Fractional leftovers are preserved
+ * ("1.2345" x 10^3 -> 1234.5) so the caller can reject non-integer results.
+ * @param {{ intDigits: string, fracDigits: string }} parts - as splitDecimal returns them
+ * @param {number} exponent
+ * @returns {number}
+ */
+function shiftDecimal(parts, exponent) {
+  let { intDigits, fracDigits } = parts;
+  for (let i = 0; i < exponent; i++) {
+    if (fracDigits.length > 0) {
+      intDigits += fracDigits[0];
+      fracDigits = fracDigits.substring(1);
+    } else {
+      intDigits += '0';
+    }
+  }
+  // Drop trailing zeros so "1.50k" is still a whole number
+  while (fracDigits.length > 0 && fracDigits[fracDigits.length - 1] === '0') {
+    fracDigits = fracDigits.substring(0, fracDigits.length - 1);
+  }
+  return fracDigits.length > 0 ? Number(`${intDigits}.${fracDigits}`) : parseInt(intDigits, 10);
+}
+
+module.exports = shiftDecimal;
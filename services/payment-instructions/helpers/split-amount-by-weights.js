@@ -0,0 +1,32 @@
+const toMinorUnits = require('./to-minor-units');
+const fromMinorUnits = require('./from-minor-units');
+
+/**
+ * Split an amount into shares proportional to positive integer weights (percentages in
+ * hundredths, numbers of parts), in the currency's minor units. Each share gets its whole
+ * part of the amount; the minor units left over go one each to the shares with the largest
+ * remainders, the first recipient winning a tie, so 100.00 NGN by 1:2 gives [33.33, 66.67].
+ *
+ * @param {number} amount - Amount in major units
+ * @param {number[]} weights - Positive integers
+ * @param {string} currency
+ * @returns {number[]} Shares in major units, summing exactly to amount
+ */
+function splitAmountByWeights(amount, weights, currency) {
+  const minor = BigInt(toMinorUnits(amount, currency));
+  const total = weights.reduce((sum, w) => sum + BigInt(w), 0n);
+  const base = weights.map((w) => (minor * BigInt(w)) / total);
+  const remainders = weights.map((w, i) => ({ i, rest: (minor * BigInt(w)) % total }));
+  let left = minor - base.reduce((sum, b) => sum + b, 0n);
+  remainders.sort((a, b) => {
+    if (a.rest === b.rest) return a.i - b.i;
+    return a.rest > b.rest ? -1 : 1;
+  });
+  for (let k = 0; left > 0n; k++) {
+    base[remainders[k].i] += 1n;
+    left -= 1n;
+  }
+  return base.map((b) => fromMinorUnits(b, currency));
+}
+
+module.exports = splitAmountByWeights;
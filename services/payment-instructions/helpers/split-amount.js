@@ -0,0 +1,27 @@
+const toMinorUnits = require('./to-minor-units');
+const fromMinorUnits = require('./from-minor-units');
+
+/**
+ * Split an amount into `count` equal shares in the currency's minor units.
+ * Minor units left over by the division go one each to the first shares, so
+ * 100.00 NGN over 3 gives [33.34, 33.33, 33.33].
+ *
+ * @param {number} amount - Amount in major units
+ * @param {number} count - Number of shares (> 0)
+ * @param {string} currency
+ * @returns {number[]} Shares in major units, summing exactly to amount
+ */
+function splitAmount(amount, count, currency) {
+  const minor = BigInt(toMinorUnits(amount, currency));
+  const n = BigInt(count);
+  const base = minor / n;
+  const remainder = minor % n;
+  const shares = [];
+  for (let i = 0; i < count; i++) {
+    const extra = BigInt(i) < remainder ? 1n : 0n;
+    shares.push(fromMinorUnits(base + extra, currency));
+  }
+  return shares;
+}
+
+module.exports = splitAmount;
@@ -0,0 +1,46 @@
+const { LEADING_KEYWORDS } = require('./constants');
+
+function hasBothDirections(lowerTokens) {
+  return lowerTokens.indexOf('from') !== -1 && lowerTokens.indexOf('to') !== -1;
+}
+
+/**
+ * Split a compound instruction into its clauses:
+ * "DEBIT 100 NGN FROM a FOR CREDIT TO b and 200 NGN FROM a FOR CREDIT TO c".
+ *
+ * An "and" separates clauses only when the clause before it already names both accounts
+ * (has FROM and TO) and the text after it does too, so "one thousand and fifty",
+ * "BETWEEN b AND c" and "FROM a AND b" stay inside their clause. A clause that does not
+ * open with a verb takes the leading keywords of the first clause.
+ *
+ * @param {string[]} tokens
+ * @returns {string[][]} the clauses' tokens; a single clause for a plain instruction
+ */
+function splitCompoundInstruction(tokens) {
+  const lowerTokens = tokens.map((t) => String(t).toLowerCase());
+  const clauses = [];
+  let start = 0;
+  for (let i = 0; i < tokens.length; i++) {
+    if (
+      lowerTokens[i] === 'and' &&
+      hasBothDirections(lowerTokens.slice(start, i)) &&
+      hasBothDirections(lowerTokens.slice(i + 1))
+    ) {
+      clauses.push(tokens.slice(start, i));
+      start = i + 1;
+    }
+  }
+  clauses.push(tokens.slice(start));
+  if (clauses.length === 1) return clauses;
+
+  let leadingCount = 0;
+  while (LEADING_KEYWORDS.indexOf(lowerTokens[leadingCount]) !== -1) leadingCount++;
+  const leading = tokens.slice(0, leadingCount);
+  for (let c = 1; c < clauses.length; c++) {
+    const opener = String(clauses[c][0]).toLowerCase();
+    if (LEADING_KEYWORDS.indexOf(opener) === -1) clauses[c] = [...leading, ...clauses[c]];
+  }
+  return clauses;
+}
+
+module.exports = splitCompoundInstruction;
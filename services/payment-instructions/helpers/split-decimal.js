@@ -0,0 +1,22 @@
+/**
+ * Split a numeric string into its integer and fractional digit strings.
+ * Accepts "5", "1.5", "0.5" but not "", ".5", "5." or anything with a sign.
+ * Returns { intDigits, fracDigits } or null.
+ * @param {string} s
+ * @returns {{ intDigits: string, fracDigits: string }|null}
+ */
+function splitDecimal(s) {
+  if (typeof s !== 'string' || s.length === 0) return null;
+  const dot = s.indexOf('.');
+  const intDigits = dot === -1 ? s : s.substring(0, dot);
+  const fracDigits = dot === -1 ? '' : s.substring(dot + 1);
+  if (intDigits.length === 0) return null;
+  if (dot !== -1 && fracDigits.length === 0) return null;
+  const all = intDigits + fracDigits;
+  for (let i = 0; i < all.length; i++) {
+    if (all[i] < '0' || all[i] > '9') return null;
+  }
+  return { intDigits, fracDigits };
+}
+
+module.exports = splitDecimal;
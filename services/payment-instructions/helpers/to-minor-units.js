@@ -0,0 +1,13 @@
+const getCurrencyDecimals = require('./get-currency-decimals');
+
+/**
+ * Convert a major-unit number (e.g. 1000.05) to integer minor units (100005).
+ * @param {number} value
+ * @param {string} currency
+ * @returns {number}
+ */
+function toMinorUnits(value, currency) {
+  return Math.round(Number(value) * 10 ** getCurrencyDecimals(currency));
+}
+
+module.exports = toMinorUnits;
@@ -0,0 +1,27 @@
+/**
+ * Locate each token in the instruction it came from, as character offsets.
+ *
+ * Tokens are searched for in order; a token found only before the previous one (a
+ * currency symbol moved behind its amount, see placeCurrencySymbol) is located by
+ * searching backwards instead.
+ *
+ * @param {string} instruction - the instruction as received
+ * @param {string[]} tokens - tokens of that instruction, possibly rearranged
+ * @returns {{ offset: number, length: number }[]} one span per token
+ */
+function tokenSpans(instruction, tokens) {
+  const text = String(instruction || '');
+  const spans = [];
+  let cursor = 0;
+  for (let i = 0; i < tokens.length; i++) {
+    const token = String(tokens[i]);
+    let offset = text.indexOf(token, cursor);
+    if (offset === -1) offset = text.lastIndexOf(token, cursor);
+    if (offset === -1) offset = cursor;
+    spans.push({ offset, length: token.length });
+    cursor = Math.max(cursor, offset + token.length);
+  }
+  return spans;
+}
+
+module.exports = tokenSpans;
@@ -0,0 +1,21 @@
+const normalizeInstruction = require('./normalize-instruction');
+
+/**
+ * Tokenize instruction string into non-empty tokens.
+ * The text is normalized first (see normalizeInstruction: fullwidth and other-script digits,
+ * tabs, line breaks and odd spaces), then trimmed and split on single spaces, and empty
+ * tokens are filtered out.
+ */
+function tokenize(instruction) {
+  if (instruction === undefined || instruction === null) return [];
+  const s = normalizeInstruction(instruction).trim();
+  if (s.length === 0) return [];
+  const parts = s.split(' ');
+  const tokens = [];
+  for (let i = 0; i < parts.length; i++) {
+    if (parts[i].length > 0) tokens.push(parts[i]);
+  }
+  return tokens;
+}
+
+module.exports = tokenize;
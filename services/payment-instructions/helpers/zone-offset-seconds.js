@@ -0,0 +1,41 @@
+/**
+ * How far ahead of UTC an IANA timezone ("Africa/Lagos", "America/New_York") is at a given
+ * instant, in seconds, daylight saving included. Returns null for a name the runtime does not
+ * know as a timezone.
+ * @param {string} timeZone
+ * @param {number} ms - epoch milliseconds
+ * @returns {number|null}
+ */
+function zoneOffsetSeconds(timeZone, ms) {
+  if (typeof timeZone !== 'string' || timeZone.length === 0) return null;
+  let parts;
+  try {
+    parts = new Intl.DateTimeFormat('en-US', {
+      timeZone,
+      hourCycle: 'h23',
+      year: 'numeric',
+      month: 'numeric',
+      day: 'numeric',
+      hour: 'numeric',
+      minute: 'numeric',
+      second: 'numeric',
+    }).formatToParts(new Date(ms));
+  } catch (err) {
+    return null;
+  }
+  const field = {};
+  parts.forEach((part) => {
+    field[part.type] = parseInt(part.value, 10);
+  });
+  const local = Date.UTC(
+    field.year,
+    field.month - 1,
+    field.day,
+    field.hour,
+    field.minute,
+    field.second
+  );
+  return Math.round((local - Math.floor(ms / 1000) * 1000) / 1000);
+}
+
+module.exports = zoneOffsetSeconds;
@@ -0,0 +1,154 @@
+const { appLogger } = require('@app-core/logger');
+const PaymentMessages = require('@app/messages/payment-instructions');
+const { getReasonCategory } = require('@app/messages/payment-instruction-status-codes');
+const processBatch = require('../process-batch');
+
+/**
+ * Result line for an input line that could not be run at all.
+ */
+function buildFailedLine(line, statusReason, statusCode) {
+  return {
+    line,
+    type: null,
+    amount: null,
+    currency: null,
+    debit_account: null,
+    credit_account: null,
+    execute_by: null,
+    narration: '',
+    status: 'failed',
+    status_reason: statusReason,
+    status_code: statusCode,
+    reason_category: getReasonCategory(statusCode),
+    accounts: [],
+  };
+}
+
+/**
+ * A plain Node request handler, (req, res), for bulk runs too large to post as one JSON
+ * array: the body is newline-delimited JSON, one object per line, and every line's result is
+ * written back as an NDJSON line (application/x-ndjson) as soon as it is processed, so neither
+ * the request nor the response is ever held in memory whole.
+ *
+ * A line with accounts and no instruction sets the shared account set, and its other fields
+ * (fx_rates, aliases, default_currency, ...) apply to the lines after it. A line with just an
+ * instruction runs on that set, in stream order: it sees the balances the lines before it
+ * left, as in a shared-mode batch (see process-batch.js). A line bringing its own accounts runs
+ * on those alone. Results carry the 1-based input line number as line; blank lines are
+ * skipped, and a line that is not a JSON object, or has no accounts to run on, fails with
+ * SY03 without stopping the stream. After the last result comes { accounts } with the final
+ * shared set, when one was given.
+ *
+ * The response is HTTP 200 once streaming starts; each line says how it went.
+ *
+ * @param {Object} [options] - passed to the service for every line
+ * @returns {(req: import('http').IncomingMessage, res: import('http').ServerResponse)
+ *   => Promise<void>}
+ */
+function createBatchStreamHandler(options = {}) {
+  return async function batchStreamHandler(req, res) {
+    let settings = {};
+    let sharedAccounts = null;
+    let lineNumber = 0;
+
+    function write(body) {
+      return new Promise((resolve) => {
+        if (res.write(`${JSON.stringify(body)}\n`)) resolve();
+        else res.once('drain', resolve);
+      });
+    }
+
+    async function runLine(text) {
+      lineNumber++;
+      const line = lineNumber;
+      if (text.trim() === '') return;
+      let body = null;
+      try {
+        body = JSON.parse(text);
+      } catch (e) {
+        body = null;
+      }
+      if (body === null || typeof body !== 'object' || Array.isArray(body)) {
+        await write(buildFailedLine(line, PaymentMessages.NDJSON_INVALID_LINE, 'SY03'));
+        return;
+      }
+      const { accounts, instruction, ...fields } = body;
+      if (instruction === undefined && Array.isArray(accounts)) {
+        settings = fields;
+        sharedAccounts = accounts;
+        return;
+      }
+      const own = Array.isArray(accounts);
+      if (!own && sharedAccounts === null) {
+        await write(buildFailedLine(line, PaymentMessages.NDJSON_NO_ACCOUNTS, 'SY03'));
+        return;
+      }
+      const payload = own
+        ? { ...settings, ...fields, items: [{ accounts, instruction }] }
+        : { ...settings, ...fields, accounts: sharedAccounts, instructions: [instruction] };
+      try {
+        const batch = await processBatch(payload, options);
+        if (!own) sharedAccounts = batch.accounts;
+        await write({ line, ...batch.results[0] });
+      } catch (err) {
+        if (err && err.isApplicationError) {
+          await write(buildFailedLine(line, err.message, 'SY03'));
+        } else {
+          appLogger.errorX(err, 'payment-instructions.http.batch-stream');
+          await write(buildFailedLine(line, PaymentMessages.INTERNAL_ERROR, 'INTERNAL'));
+        }
+      }
+    }
+
+    // Complete lines are run one at a time while the request is paused, so a fast sender
+    // waits for the results instead of piling lines up in memory
+    async function runLines(lines) {
+      for (let i = 0; i < lines.length; i++) {
+        // Sequential on purpose: each line must see the balances left by the previous one
+        // eslint-disable-next-line no-await-in-loop
+        await runLine(lines[i]);
+      }
+    }
+
+    res.statusCode = 200;
+    res.setHeader('Content-Type', 'application/x-ndjson; charset=utf-8');
+    let reading = null;
+    if (typeof req.body === 'string') {
+      reading = runLines(req.body.split('\n'));
+    } else {
+      reading = new Promise((resolve, reject) => {
+        let pending = '';
+        let queue = Promise.resolve();
+        req.setEncoding('utf8');
+        req.on('data', (chunk) => {
+          pending += chunk;
+          const lines = [];
+          let end = pending.indexOf('\n');
+          while (end !== -1) {
+            lines.push(pending.slice(0, end));
+            pending = pending.slice(end + 1);
+            end = pending.indexOf('\n');
+          }
+          if (lines.length > 0) {
+            req.pause();
+            queue = queue.then(() => runLines(lines)).then(() => req.resume());
+          }
+        });
+        req.on('error', reject);
+        req.on('end', () => {
+          queue.then(() => runLines([pending])).then(resolve, reject);
+        });
+      });
+    }
+    try {
+      await reading;
+      if (sharedAccounts !== null) await write({ accounts: sharedAccounts });
+    } catch (err) {
+      // The request broke off; the lines already answered stand
+      appLogger.errorX(err, 'payment-instructions.http.batch-stream');
+    }
+    res.end();
+  };
+}
+
+module.exports = createBatchStreamHandler;
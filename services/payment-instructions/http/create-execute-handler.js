@@ -0,0 +1,32 @@
+const processIdempotentInstruction = require('../process-idempotent-instruction');
+const { respond } = require('./send-json');
+
+/**
+ * A plain Node request handler, (req, res), executing the payment instruction posted as
+ * the body: the logic of POST /payment-instructions without the framework binding, for
+ * mounting on a router of your own behind your own middleware (http.createServer(handler),
+ * app.post('/pay', auth, handler), ...).
+ *
+ * Successful and pending instructions are HTTP 200, failed ones HTTP 400 when they cannot be
+ * read and 422 when the accounts or rules refuse them, all with the result in data. An
+ * Idempotency-Key header and ?dry_run=true work as on the endpoint.
+ *
+ * @param {Object} [options] - passed to the service on every request (feePolicy,
+ *   accountStore, idempotencyStore, ...)
+ * @returns {(req: import('http').IncomingMessage, res: import('http').ServerResponse)
+ *   => Promise<void>}
+ */
+function createExecuteHandler(options = {}) {
+  return function executeHandler(req, res) {
+    const query = new URL(req.url || '/', 'http://localhost').searchParams;
+    const idempotencyKey = req.headers['idempotency-key'];
+    const dryRun = query.get('dry_run') === 'true';
+    const run = (body) => {
+      const payload = idempotencyKey ? { ...body, idempotency_key: idempotencyKey } : body;
+      return processIdempotentInstruction(payload, { ...options, dryRun });
+    };
+    return respond(req, res, run, ['successful', 'pending'], 'payment-instructions.http.execute');
+  };
+}
+
+module.exports = createExecuteHandler;
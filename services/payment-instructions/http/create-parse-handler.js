@@ -0,0 +1,25 @@
+const paymentInstructions = require('../payment-instructions');
+const { respond } = require('./send-json');
+
+/**
+ * A plain Node request handler, (req, res), parsing the payment instruction posted as the
+ * body without executing it (see parse-instruction.js): nothing is moved, checked against a
+ * balance or stored.
+ *
+ * An instruction that parses is HTTP 200 with the parsed response (status "parsed") in
+ * data; one that does not is HTTP 400 (422 when it reads but names, say, an unknown account)
+ * with the failure (status_code, status_reason, parse_error) in data, as the execute handler
+ * returns it.
+ *
+ * @param {Object} [options] - passed to the service on every request
+ * @returns {(req: import('http').IncomingMessage, res: import('http').ServerResponse)
+ *   => Promise<void>}
+ */
+function createParseHandler(options = {}) {
+  return function parseHandler(req, res) {
+    const run = (body) => paymentInstructions(body, { ...options, parseOnly: true });
+    return respond(req, res, run, ['parsed'], 'payment-instructions.http.parse');
+  };
+}
+
+module.exports = createParseHandler;
@@ -0,0 +1,94 @@
+const { ERROR_STATUS_CODE_MAPPING } = require('@app-core/errors');
+const { appLogger } = require('@app-core/logger');
+const { getHttpStatus } = require('@app/messages/payment-instruction-status-codes');
+
+/**
+ * Read the JSON body of a request. A body already parsed by the router (express.json() and
+ * the like set req.body) is used as it is; otherwise the stream is read and parsed.
+ *
+ * @param {import('http').IncomingMessage} req
+ * @returns {Promise<Object>} the body, or null when it is not valid JSON
+ */
+function readJsonBody(req) {
+  if (req.body !== undefined) return Promise.resolve(req.body);
+  return new Promise((resolve, reject) => {
+    const chunks = [];
+    req.on('data', (chunk) => chunks.push(chunk));
+    req.on('error', reject);
+    req.on('end', () => {
+      const text = Buffer.concat(chunks).toString('utf8');
+      let body = null;
+      try {
+        body = text.length > 0 ? JSON.parse(text) : {};
+      } catch (e) {
+        body = null;
+      }
+      resolve(body);
+    });
+  });
+}
+
+/**
+ * Write a JSON response, without relying on a framework's res.status().json().
+ */
+function sendJson(res, statusCode, body) {
+  const payload = JSON.stringify(body);
+  res.statusCode = statusCode;
+  res.setHeader('Content-Type', 'application/json; charset=utf-8');
+  res.setHeader('Content-Length', Buffer.byteLength(payload));
+  res.end(payload);
+}
+
+/**
+ * Run a payment-instructions service call for a request and write its response in the
+ * envelope the server uses ({ status, message, data } or, for errors, { status: 'error',
+ * message, errors, data }): okStatuses say which result statuses are HTTP 200, every other
+ * result gets the HTTP status of its status code (400 unreadable, 422 refused; see
+ * StatusHttpStatuses). Thrown application errors get the status their error code maps to
+ * (400 unless mapped) and anything else is HTTP 500 without its details.
+ *
+ * @param {import('http').IncomingMessage} req
+ * @param {import('http').ServerResponse} res
+ * @param {(body: Object) => Promise<Object>} run - the service call
+ * @param {string[]} okStatuses
+ * @param {string} logName - logged when the call fails unexpectedly
+ */
+async function respond(req, res, run, okStatuses, logName) {
+  let body;
+  try {
+    body = await readJsonBody(req);
+  } catch (err) {
+    body = null;
+  }
+  if (body === null || typeof body !== 'object' || Array.isArray(body)) {
+    sendJson(res, 400, {
+      status: 'error',
+      message: 'Error encountered in parsing request payload. Please check payload and try again',
+    });
+    return;
+  }
+
+  try {
+    const result = await run(body);
+    const statusCode =
+      okStatuses.indexOf(result.status) !== -1 ? 200 : getHttpStatus(result.status_code);
+    sendJson(res, statusCode, { status: 'success', data: result });
+  } catch (err) {
+    if (err && err.isApplicationError) {
+      sendJson(res, ERROR_STATUS_CODE_MAPPING[err.errorCode] || 400, {
+        status: 'error',
+        message: err.message,
+        errors: err.details || undefined,
+        data: err.context,
+      });
+    } else {
+      appLogger.errorX(err, logName);
+      sendJson(res, 500, {
+        status: 'error',
+        data: { status: 'failed', status_reason: 'Internal server error', status_code: 'INTERNAL' },
+      });
+    }
+  }
+}
+
+module.exports = { readJsonBody, sendJson, respond };
@@ -0,0 +1,40 @@
+const systemClock = require('../clocks/system-clock');
+
+function base36(value, width) {
+  return value.toString(36).padStart(width, '0');
+}
+
+/**
+ * Default transaction id generator: "txn_" + time + sequence + random, all base 36 and fixed
+ * width, so ids from one generator sort in the order they were made.
+ *
+ * Any object with the same interface can be passed to the services instead,
+ * e.g. a ULID generator:
+ *   next()  -> a new unique id string
+ *
+ * @param {{ clock?: { now: () => number }, now?: () => number }} [config] - clock (see
+ *   clocks/system-clock.js) or clock function (epoch ms) to use instead of the system time
+ */
+function createSortableIdGenerator(config = {}) {
+  const source = config.clock || systemClock;
+  const clock = typeof config.now === 'function' ? config.now : () => source.now();
+  // Ids made in the same millisecond are told apart (and kept in order) by a sequence number
+  let lastTime = -1;
+  let sequence = 0;
+
+  function next() {
+    const time = clock();
+    if (time === lastTime) {
+      sequence++;
+    } else {
+      lastTime = time;
+      sequence = 0;
+    }
+    const random = Math.floor(Math.random() * 36 ** 4);
+    return `txn_${base36(time, 9)}${base36(sequence, 4)}${base36(random, 4)}`;
+  }
+
+  return { next };
+}
+
+module.exports = createSortableIdGenerator;
@@ -0,0 +1,5 @@
+const createSortableIdGenerator = require('./create-sortable-id-generator');
+
+// One generator per process, shared by every service so ids stay in order across them;
+// options.idGenerator replaces it
+module.exports = createSortableIdGenerator();
@@ -0,0 +1,161 @@
+const { throwAppError, ERROR_CODE } = require('@app-core/errors');
+const PaymentMessages = require('@app/messages/payment-instructions');
+const { SUPPORTED_CURRENCIES, isUnknownCurrencyCode, isValidAccountId } = require('../helpers');
+
+const COLUMNS = ['id', 'balance', 'currency', 'alias'];
+
+/**
+ * Split CSV text into rows of cells with their 1-based line numbers. Cells may be quoted
+ * ("a, b" and "say ""hi""") and quoted cells may span lines; blank lines are skipped.
+ */
+function readRows(text) {
+  const rows = [];
+  let cells = [];
+  let cell = '';
+  let quoted = false;
+  let line = 1;
+  let rowLine = 1;
+  let i = 0;
+  function endRow() {
+    cells.push(cell);
+    if (cells.length > 1 || cells[0].trim() !== '') rows.push({ line: rowLine, cells });
+    cells = [];
+    cell = '';
+  }
+  while (i < text.length) {
+    const ch = text[i];
+    if (quoted) {
+      if (ch === '"' && text[i + 1] === '"') {
+        cell += '"';
+        i++;
+      } else if (ch === '"') {
+        quoted = false;
+      } else {
+        if (ch === '\n') line++;
+        cell += ch;
+      }
+    } else if (ch === '"') {
+      quoted = true;
+    } else if (ch === ',') {
+      cells.push(cell);
+      cell = '';
+    } else if (ch === '\n' || ch === '\r') {
+      if (ch === '\r' && text[i + 1] === '\n') i++;
+      endRow();
+      line++;
+      rowLine = line;
+    } else {
+      cell += ch;
+    }
+    i++;
+  }
+  if (quoted) {
+    const reason = `row ${rowLine}: ${PaymentMessages.CSV_UNCLOSED_QUOTE}`;
+    throwAppError(`${PaymentMessages.INVALID_ACCOUNTS_CSV}: ${reason}`, ERROR_CODE.VALIDATIONERR);
+  }
+  endRow();
+  return rows;
+}
+
+// Plain decimal numbers only: an optional minus, digits, at most one point ("-12.50")
+function isPlainNumber(value) {
+  let digits = 0;
+  let points = 0;
+  for (let i = 0; i < value.length; i++) {
+    const ch = value[i];
+    if (ch >= '0' && ch <= '9') digits++;
+    else if (ch === '.') points++;
+    else if (!(ch === '-' && i === 0)) return false;
+  }
+  return digits > 0 && points <= 1;
+}
+
+/**
+ * Parse a CSV of accounts (id, balance, currency, optional alias) into the accounts array
+ * and aliases object the payment-instructions and batch endpoints take.
+ *
+ * A first row naming the columns ("id,balance,currency,alias" in any order) is used as the
+ * header; without one the columns are taken in that order. Invalid ids, duplicate ids or
+ * aliases and balances that are not plain numbers are all collected and thrown together as
+ * one validation error, each with its row number (error.details lists them). Currencies that
+ * are not supported are not an error but are flagged in warnings, since instructions on those
+ * accounts will fail with CU02/CU04.
+ *
+ * @param {string} csv
+ * @returns {{ accounts: Object[], aliases: Object, warnings: { row: number, message: string }[] }}
+ */
+function parseAccountsCsv(csv) {
+  const rows = readRows(String(csv || ''));
+  let columns = COLUMNS;
+  const firstRow = rows.length > 0 ? rows[0].cells.map((c) => c.trim().toLowerCase()) : [];
+  if (firstRow.indexOf('id') !== -1) {
+    columns = firstRow;
+    rows.shift();
+  }
+
+  const accounts = [];
+  const aliases = {};
+  const warnings = [];
+  const errors = [];
+  const idRows = {};
+  const aliasRows = {};
+  function reject(row, message) {
+    errors.push({ row, message });
+  }
+
+  if (['id', 'balance', 'currency'].some((column) => columns.indexOf(column) === -1)) {
+    reject(1, PaymentMessages.CSV_MISSING_COLUMNS);
+  }
+  for (let r = 0; r < rows.length; r++) {
+    const { line, cells } = rows[r];
+    const field = (name) => {
+      const index = columns.indexOf(name);
+      return index !== -1 && index < cells.length ? cells[index].trim() : '';
+    };
+    const id = field('id');
+    const balance = field('balance');
+    const currency = field('currency').toUpperCase();
+    const alias = field('alias');
+
+    if (!isValidAccountId(id)) {
+      reject(line, `${PaymentMessages.CSV_INVALID_ACCOUNT_ID}: "${id}"`);
+    } else if (Object.prototype.hasOwnProperty.call(idRows, id)) {
+      reject(line, `${PaymentMessages.CSV_DUPLICATE_ID} "${id}" (first on row ${idRows[id]})`);
+    } else {
+      idRows[id] = line;
+    }
+    if (!isPlainNumber(balance)) {
+      reject(line, `${PaymentMessages.CSV_BALANCE_NOT_A_NUMBER}: "${balance}"`);
+    }
+    if (currency === '') {
+      reject(line, PaymentMessages.CSV_MISSING_CURRENCY);
+    } else if (isUnknownCurrencyCode(currency)) {
+      const message = `${PaymentMessages.UNKNOWN_CURRENCY_CODE}: ${currency}`;
+      warnings.push({ row: line, message });
+    } else if (!Object.prototype.hasOwnProperty.call(SUPPORTED_CURRENCIES, currency)) {
+      const message = `${PaymentMessages.CSV_UNSUPPORTED_CURRENCY}: ${currency}`;
+      warnings.push({ row: line, message });
+    }
+    if (alias !== '') {
+      // Aliases are matched case-insensitively, so "Rent" and "rent" collide
+      const key = alias.toLowerCase();
+      if (Object.prototype.hasOwnProperty.call(aliasRows, key)) {
+        const first = aliasRows[key];
+        reject(line, `${PaymentMessages.CSV_DUPLICATE_ALIAS} "${alias}" (first on row ${first})`);
+      } else {
+        aliasRows[key] = line;
+        aliases[alias] = id;
+      }
+    }
+    accounts.push({ id, balance: Number(balance), currency });
+  }
+
+  if (errors.length > 0) {
+    const reasons = errors.map((e) => `row ${e.row}: ${e.message}`).join('; ');
+    const message = `${PaymentMessages.INVALID_ACCOUNTS_CSV}: ${reasons}`;
+    throwAppError(message, ERROR_CODE.VALIDATIONERR, { details: errors });
+  }
+  return { accounts, aliases, warnings };
+}
+
+module.exports = parseAccountsCsv;
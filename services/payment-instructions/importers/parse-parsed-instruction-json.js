@@ -0,0 +1,58 @@
+const { throwAppError, ERROR_CODE } = require('@app-core/errors');
+const PaymentMessages = require('@app/messages/payment-instructions');
+const { PARSED_INSTRUCTION_FIELDS } = require('../helpers');
+
+// What each always-present field may hold; null is allowed where the field can be unset
+const FIELD_TYPES = {
+  type: { type: 'string', nullable: false },
+  amount: { type: 'number', nullable: false },
+  currency: { type: 'string', nullable: false },
+  debit_account: { type: 'string', nullable: true },
+  credit_account: { type: 'string', nullable: true },
+  execute_by: { type: 'number', nullable: true },
+  narration: { type: 'string', nullable: false },
+  fee: { type: 'number', nullable: true },
+};
+
+/**
+ * Read back a parsed instruction serialized by toParsedInstructionJson (exporters).
+ *
+ * The result has exactly the fields of the JSON; null stays null, so a null execute_by is
+ * read as null and not as 0. Text that is not JSON, or a field missing or of the wrong
+ * type, is a validation error naming the field.
+ *
+ * @param {string} json
+ * @returns {Object} the parsed instruction
+ */
+function parseParsedInstructionJson(json) {
+  let parsed;
+  try {
+    parsed = JSON.parse(String(json));
+  } catch (err) {
+    throwAppError(
+      `${PaymentMessages.INVALID_PARSED_INSTRUCTION_JSON}: ${err.message}`,
+      ERROR_CODE.VALIDATIONERR
+    );
+  }
+  if (parsed === null || typeof parsed !== 'object' || Array.isArray(parsed)) {
+    throwAppError(
+      `${PaymentMessages.INVALID_PARSED_INSTRUCTION_JSON}: expected an object`,
+      ERROR_CODE.VALIDATIONERR
+    );
+  }
+  PARSED_INSTRUCTION_FIELDS.forEach((field) => {
+    const value = parsed[field];
+    const rule = FIELD_TYPES[field];
+    const valid = value === null ? rule.nullable : typeof value === rule.type;
+    if (!valid) {
+      throwAppError(
+        `${PaymentMessages.INVALID_PARSED_INSTRUCTION_JSON}: ${field} must be a ${rule.type}${rule.nullable ? ' or null' : ''}`,
+        ERROR_CODE.VALIDATIONERR,
+        { details: { field } }
+      );
+    }
+  });
+  return parsed;
+}
+
+module.exports = parseParsedInstructionJson;
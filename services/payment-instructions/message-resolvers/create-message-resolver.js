@@ -0,0 +1,36 @@
+/**
+ * Message resolver over per-locale catalogs of status_reason texts keyed by status code:
+ *   createMessageResolver({ fr: { AC01: 'Fonds insuffisants', ... }, sw: { ... } })
+ *
+ * Passed in options.messageResolver, it replaces a response's status_reason with the
+ * message for its status_code in the request locale. resolve(code, locale) tries the exact
+ * locale first ("fr-CA"), then its language ("fr"); it returns null when neither has the
+ * code, and the service then keeps its default English reason. Any object with the same
+ * resolve method can be passed instead.
+ *
+ * @param {Object<string, Object<string, string>>} catalogs - locale -> code -> message
+ */
+function createMessageResolver(catalogs = {}) {
+  const byLocale = {};
+  Object.keys(catalogs).forEach((locale) => {
+    byLocale[locale.toLowerCase()] = catalogs[locale] || {};
+  });
+
+  function lookup(locale, code) {
+    const catalog = byLocale[locale];
+    const message = catalog ? catalog[code] : undefined;
+    return typeof message === 'string' ? message : null;
+  }
+
+  function resolve(code, locale) {
+    const wanted = String(locale || '').toLowerCase();
+    const language = wanted.split('-')[0].split('_')[0];
+    let message = lookup(wanted, code);
+    if (message === null && language !== wanted) message = lookup(language, code);
+    return message;
+  }
+
+  return { resolve };
+}
+
+module.exports = createMessageResolver;
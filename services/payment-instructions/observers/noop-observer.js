@@ -0,0 +1,16 @@
+/**
+ * Default observer: ignores every event.
+ *
+ * Any object with the same interface can be passed in options.observer to follow an
+ * instruction through its lifecycle, e.g. to log or trace it:
+ *   notify(event, fields)   -> called in order with
+ *     'instruction.received'   { instruction }
+ *     'instruction.parsed'     { transaction_id, type, amount, currency } (parseable only)
+ *     'instruction.validated'  { transaction_id, status, status_code, amount, currency }
+ *     'instruction.executed'   { transaction_id, status_code, amount, currency, accounts }
+ *                              (balances moved: successful and not a dry run)
+ * Observers are called synchronously and must not throw.
+ */
+module.exports = {
+  notify() {},
+};
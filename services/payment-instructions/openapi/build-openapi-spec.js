@@ -0,0 +1,415 @@
+const {
+  StatusCodes,
+  ReasonCategories,
+} = require('@app/messages/payment-instruction-status-codes');
+const PaymentMessages = require('@app/messages/payment-instructions');
+const { SUPPORTED_CURRENCIES } = require('../helpers');
+
+// -----------------------------
+// OpenAPI 3 contract for POST /payment-instructions
+// -----------------------------
+// Kept in step with specs/payment-instructions/endpoint/payment-instructions.endpoint.go;
+// status codes come from the catalog so a new code shows up here without an edit.
+
+const INSTRUCTION_TYPES = [
+  'DEBIT',
+  'CREDIT',
+  'TRANSFER',
+  'COLLECT',
+  'SCHEDULE',
+  'STANDING_ORDER',
+  'SPLIT',
+  'MULTI_DEBIT',
+  'COMPOUND',
+  'WITHDRAW',
+  'DEPOSIT',
+  'PAY',
+];
+
+function ref(name) {
+  return { $ref: `#/components/schemas/${name}` };
+}
+
+function nullable(schema) {
+  return { ...schema, nullable: true };
+}
+
+function accountShareSchema() {
+  return {
+    type: 'object',
+    required: ['account', 'amount'],
+    properties: { account: { type: 'string' }, amount: { type: 'number' } },
+  };
+}
+
+function buildSchemas() {
+  const currencies = Object.keys(SUPPORTED_CURRENCIES);
+
+  const Account = {
+    type: 'object',
+    required: ['id'],
+    anyOf: [{ required: ['balance', 'currency'] }, { required: ['balances'] }],
+    properties: {
+      id: { type: 'string', description: 'Account identifier (case-sensitive)' },
+      balance: { type: 'number' },
+      currency: { type: 'string', description: `One of ${currencies.join(', ')}` },
+      balances: {
+        type: 'object',
+        additionalProperties: { type: 'number' },
+        description: 'Multi-currency wallet: a balance per currency instead of balance/currency',
+      },
+      overdraft_limit: {
+        type: 'number',
+        description: 'Debits may go down to -overdraft_limit (default 0)',
+      },
+      minimum_balance: { type: 'number', description: 'Debits below this floor fail with BL02' },
+      daily_limit: {
+        type: 'number',
+        description: 'Max total debited per UTC day; over it fails with LM01',
+      },
+      status: {
+        type: 'string',
+        enum: ['active', 'frozen', 'closed'],
+        default: 'active',
+        description: 'A frozen (AC08) or closed (AC09) account can be neither debited nor credited',
+      },
+    },
+  };
+
+  const PaymentInstructionRequest = {
+    type: 'object',
+    required: ['accounts', 'instruction'],
+    properties: {
+      accounts: { type: 'array', minItems: 1, items: ref('Account') },
+      instruction: { type: 'string', minLength: 1 },
+      fx_rates: {
+        type: 'object',
+        additionalProperties: { type: 'number' },
+        description: 'Keyed "FROM/TO" (1 FROM = rate TO), e.g. { "NGN/USD": 0.00065 }',
+      },
+      aliases: {
+        type: 'object',
+        additionalProperties: { type: 'string' },
+        description: 'Account names (alias -> account id), e.g. { "salary": "acc-001" }',
+      },
+      dry_run: { type: 'boolean', description: 'Preview only; also accepted as ?dry_run=true' },
+      case_insensitive_ids: { type: 'boolean', default: false },
+      fuzzy_keywords: { type: 'boolean', default: false },
+      strict: {
+        type: 'boolean',
+        default: false,
+        description: 'Refuse any guess (alias, inferred currency, corrected keyword) with SY06',
+      },
+      collection_account: {
+        type: 'string',
+        description: 'Payee of a COLLECT instruction that names none ("charge acc1 3000 NGN")',
+      },
+      partial_execution: { type: 'boolean', default: false },
+      include_all_accounts: {
+        type: 'boolean',
+        default: false,
+        description: 'Report every input account, untouched ones with balance_before == balance',
+      },
+      decimal_separator: { type: 'string', enum: ['.', ','], default: '.' },
+      default_currency: {
+        type: 'string',
+        description: 'Currency of an instruction that names none, e.g. "NGN"',
+      },
+      timezone: {
+        type: 'string',
+        description: 'IANA timezone schedule dates and times are read in, e.g. "Africa/Lagos"',
+      },
+      locale: {
+        type: 'string',
+        description: 'status_reason language, e.g. "fr"; "en-IN" also reads "1,00,000", "2 lakh"',
+      },
+      idempotency_key: {
+        type: 'string',
+        maxLength: 255,
+        description: 'Or the Idempotency-Key header; retries replay the first result',
+      },
+    },
+  };
+
+  const AccountResult = {
+    type: 'object',
+    required: ['id', 'balance', 'balance_before', 'currency'],
+    properties: {
+      id: { type: 'string' },
+      balance: { type: 'number', description: 'Unchanged for failures and dry runs' },
+      balance_before: { type: 'number' },
+      projected_balance: {
+        type: 'number',
+        description: 'Dry run only: balance the instruction would leave',
+      },
+      currency: { type: 'string' },
+    },
+  };
+
+  const ParseError = {
+    type: 'object',
+    required: ['segment', 'token', 'offset', 'length'],
+    properties: {
+      segment: { type: 'string', enum: ['verb', 'amount', 'currency', 'debit', 'credit'] },
+      token: nullable({
+        type: 'string',
+        description: 'Text at fault as written; null when the segment is missing',
+      }),
+      offset: { type: 'integer', description: 'Character offset in instruction' },
+      length: { type: 'integer', description: 'Characters covered (0 when missing)' },
+    },
+  };
+
+  // Fields every result carries; failures leave the unparsed ones null
+  const resultProperties = {
+    transaction_id: { type: 'string' },
+    type: nullable({ type: 'string', enum: INSTRUCTION_TYPES }),
+    amount: nullable({ type: 'number' }),
+    currency: nullable({ type: 'string' }),
+    debit_account: nullable({ type: 'string' }),
+    credit_account: nullable({ type: 'string' }),
+    execute_by: nullable({
+      type: 'number',
+      description: 'Timestamp for scheduled instructions, else null',
+    }),
+    narration: { type: 'string' },
+    confidence: { type: 'number', minimum: 0, maximum: 1 },
+    matched_verb: {
+      type: 'string',
+      description: 'The verb as written ("wire", "send"), lowercased; type is what it reads as',
+    },
+    canonical_instruction: {
+      type: 'string',
+      description: 'Normalized form, e.g. "TRANSFER 5000.00 NGN FROM acc1 TO acc2"',
+    },
+    status_reason: { type: 'string' },
+    status_code: { type: 'string', enum: Object.keys(StatusCodes) },
+    reason_category: {
+      type: 'string',
+      enum: Object.values(ReasonCategories),
+      description: 'Failures only: the kind of failure status_code reports',
+    },
+  };
+  const resultRequired = [
+    'transaction_id',
+    'type',
+    'amount',
+    'currency',
+    'debit_account',
+    'credit_account',
+    'execute_by',
+    'narration',
+    'status',
+    'status_reason',
+    'status_code',
+  ];
+
+  const InstructionResult = {
+    type: 'object',
+    required: [...resultRequired, 'accounts'],
+    properties: {
+      ...resultProperties,
+      status: { type: 'string', enum: ['successful', 'pending'] },
+      converted_amount: { type: 'number' },
+      converted_currency: { type: 'string' },
+      fx_rate: { type: 'number' },
+      fee: { type: 'number' },
+      fee_account: { type: 'string', description: 'Account bearing the fee ("fee from acc3")' },
+      condition: {
+        type: 'object',
+        description: 'Balance guard ("only if balance above 1000"), checked when the transfer runs',
+        properties: {
+          comparison: { type: 'string', enum: ['above', 'below', 'at_least'] },
+          threshold: { type: 'number' },
+          currency: nullable({ type: 'string' }),
+        },
+      },
+      biller: { type: 'string', description: 'PAY only: the outside payee' },
+      requested_amount: { type: 'number', description: 'BL03 only: amount asked for' },
+      dry_run: { type: 'boolean' },
+      splits: { type: 'array', items: accountShareSchema() },
+      debits: { type: 'array', items: accountShareSchema() },
+      warnings: {
+        type: 'array',
+        description: 'Guesses the parse made that are worth a second look; omitted when none',
+        items: {
+          type: 'object',
+          required: ['code', 'message'],
+          properties: {
+            code: { type: 'string', enum: Object.keys(PaymentMessages.WARNINGS) },
+            message: { type: 'string' },
+          },
+        },
+      },
+      sub_results: {
+        type: 'array',
+        items: {
+          allOf: [
+            { oneOf: [ref('InstructionResult'), ref('FailedInstructionResult')] },
+            { type: 'object', properties: { instruction: { type: 'string' } } },
+          ],
+        },
+      },
+      recurrence: {
+        type: 'object',
+        required: ['unit', 'count', 'weekday'],
+        properties: {
+          unit: { type: 'string', enum: ['day', 'week', 'month'] },
+          count: { type: 'integer' },
+          weekday: nullable({ type: 'string' }),
+          anchor: {
+            type: 'object',
+            description: 'Set for a named day such as "every payday"',
+            properties: { name: { type: 'string' }, rule: { type: 'object' } },
+          },
+        },
+      },
+      accounts: { type: 'array', items: ref('AccountResult') },
+    },
+  };
+
+  const FailedInstructionResult = {
+    type: 'object',
+    required: resultRequired,
+    properties: {
+      ...resultProperties,
+      status: { type: 'string', enum: ['failed', 'cancelled'] },
+      parse_error: ref('ParseError'),
+      candidates: {
+        type: 'array',
+        items: { type: 'string' },
+        description: 'Account ids an ambiguous reference could mean (AC05, AC06 only)',
+      },
+      accounts: {
+        type: 'array',
+        items: ref('AccountResult'),
+        description: 'Balances as given; [] when the instruction could not be parsed (SY03)',
+      },
+    },
+  };
+
+  function envelope(dataSchema) {
+    return {
+      type: 'object',
+      required: ['status', 'data'],
+      properties: {
+        status: { type: 'string', enum: ['success'] },
+        message: nullable({ type: 'string' }),
+        data: ref(dataSchema),
+      },
+    };
+  }
+
+  // Thrown application errors (validation, reused idempotency key): data may be absent
+  const ErrorResponse = {
+    type: 'object',
+    required: ['status', 'message'],
+    properties: {
+      status: { type: 'string', enum: ['error'] },
+      message: { type: 'string' },
+      errors: { type: 'array', items: { type: 'object' } },
+      data: nullable({ type: 'object' }),
+    },
+  };
+
+  // Unexpected failures are still wrapped, with a fixed INTERNAL result as data
+  const InternalErrorResponse = {
+    type: 'object',
+    required: ['status', 'data'],
+    properties: {
+      status: { type: 'string' },
+      message: nullable({ type: 'string' }),
+      data: {
+        type: 'object',
+        required: ['status', 'status_reason', 'status_code'],
+        properties: {
+          status: { type: 'string', enum: ['failed'] },
+          status_reason: { type: 'string' },
+          status_code: { type: 'string', enum: ['INTERNAL'] },
+        },
+      },
+    },
+  };
+
+  return {
+    Account,
+    PaymentInstructionRequest,
+    AccountResult,
+    ParseError,
+    InstructionResult,
+    FailedInstructionResult,
+    SuccessResponse: envelope('InstructionResult'),
+    FailedResponse: envelope('FailedInstructionResult'),
+    ErrorResponse,
+    InternalErrorResponse,
+  };
+}
+
+function jsonResponse(description, schemaName) {
+  return { description, content: { 'application/json': { schema: ref(schemaName) } } };
+}
+
+/**
+ * Build the OpenAPI 3 document for the payment-instructions endpoint.
+ *
+ * `npm run openapi` prints it as JSON (this file run directly).
+ *
+ * @returns {Object} plain JSON-serialisable OpenAPI 3.0 document
+ */
+function buildOpenApiSpec() {
+  return {
+    openapi: '3.0.3',
+    info: {
+      title: 'Payment Instructions API',
+      version: '1.0.0',
+      description: 'Parses and executes plain-text payment instructions against given accounts',
+    },
+    paths: {
+      '/payment-instructions': {
+        post: {
+          operationId: 'processPaymentInstruction',
+          parameters: [
+            {
+              name: 'Idempotency-Key',
+              in: 'header',
+              required: false,
+              schema: { type: 'string', maxLength: 255 },
+            },
+            { name: 'dry_run', in: 'query', required: false, schema: { type: 'boolean' } },
+          ],
+          requestBody: {
+            required: true,
+            content: { 'application/json': { schema: ref('PaymentInstructionRequest') } },
+          },
+          responses: {
+            200: jsonResponse('Executed, partially executed or scheduled', 'SuccessResponse'),
+            400: {
+              description: 'Instruction cannot be read (data is the failed result) or invalid body',
+              content: {
+                'application/json': {
+                  schema: { oneOf: [ref('FailedResponse'), ref('ErrorResponse')] },
+                },
+              },
+            },
+            422: jsonResponse(
+              'Instruction reads but the accounts or rules refuse it (data is the failed result)',
+              'FailedResponse'
+            ),
+            409: jsonResponse(
+              'Idempotency key reused with a different body, or still being processed',
+              'ErrorResponse'
+            ),
+            500: jsonResponse('Unexpected server error', 'InternalErrorResponse'),
+          },
+        },
+      },
+    },
+    components: { schemas: buildSchemas() },
+  };
+}
+
+if (require.main === module) {
+  process.stdout.write(`${JSON.stringify(buildOpenApiSpec(), null, 2)}\n`);
+}
+
+module.exports = buildOpenApiSpec;
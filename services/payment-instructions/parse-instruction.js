@@ -0,0 +1,68 @@
+const { throwAppError, ERROR_CODE } = require('@app-core/errors');
+const paymentInstructions = require('./payment-instructions');
+
+// Response fields that describe a run of the instruction rather than the instruction itself
+const RUN_FIELDS = [
+  'transaction_id',
+  'status',
+  'status_reason',
+  'status_code',
+  'accounts',
+  'dry_run',
+];
+
+function toParsedInstruction(result) {
+  const parsed = {};
+  Object.keys(result).forEach((key) => {
+    if (RUN_FIELDS.indexOf(key) === -1 && key !== 'sub_results') parsed[key] = result[key];
+  });
+  if (result.sub_results) parsed.clauses = result.sub_results.map(toParsedInstruction);
+  return parsed;
+}
+
+/**
+ * Parse a payment instruction against a set of accounts without executing it.
+ *
+ * The instruction goes through the payment-instructions service's parse step
+ * (parsePaymentInstruction: verb, amount, currency, aliases, trailing-digit and
+ * case-insensitive references, dates, FX rate) and the executor is never called: no fee is
+ * worked out, no balance is checked or moved and no store (daily debits, transactions) is
+ * read or written. A balance share ("half of my balance") is still resolved from the balance.
+ *
+ * The parsed instruction has the fields of a response without the run (no transaction_id,
+ * status or accounts): type, amount, currency, debit_account, credit_account, execute_by,
+ * narration, confidence, matched_verb (the verb as written, "wire" for a TRANSFER) and
+ * canonical_instruction ("TRANSFER 500.00 USD FROM acc1 TO acc2" however it was phrased), plus
+ * fx_rate, fee_account, condition (a balance guard, checked only on execution), recurrence,
+ * warnings, splits (SPLIT), debits (MULTI_DEBIT, with null amounts since they depend on the
+ * balances) or clauses (COMPOUND) when they apply.
+ *
+ * An instruction that cannot be parsed or resolved is a validation error carrying the status
+ * code the service would have returned and, for syntax errors, the parse_error detail. An
+ * ambiguous reference (AC05, AC06) also carries the candidate account ids.
+ *
+ * @param {string} instruction
+ * @param {Object} context - the rest of a payment-instructions payload: accounts, and
+ *   optionally aliases, fx_rates, case_insensitive_ids, fuzzy_keywords, decimal_separator,
+ *   default_currency, timezone
+ * @param {Object} [options] - payment-instructions options (now, observer, ...)
+ * @returns {Promise<Object>}
+ */
+async function parseInstruction(instruction, context = {}, options = {}) {
+  const result = await paymentInstructions(
+    { ...context, instruction },
+    { ...options, parseOnly: true }
+  );
+  if (result.status !== 'parsed') {
+    throwAppError(result.status_reason, ERROR_CODE.VALIDATIONERR, {
+      details: {
+        status_code: result.status_code,
+        parse_error: result.parse_error || null,
+        ...(result.candidates ? { candidates: result.candidates } : {}),
+      },
+    });
+  }
+  return toParsedInstruction(result);
+}
+
+module.exports = parseInstruction;
@@ -2,73 +2,103 @@ const validator = require('@app-core/validator');
 const { throwAppError, ERROR_CODE } = require('@app-core/errors');
 const { appLogger, TimeLogger } = require('@app-core/logger');
 const PaymentMessages = require('@app/messages/payment-instructions');
+const { getReasonCategory } = require('@app/messages/payment-instruction-status-codes');
+const {
+  parseAmount,
+  parseNegativeAmount,
+  findAmbiguousAmount,
+  resolveRatioAmount,
+  isRatioRounded,
+  convertAmount,
+  resolveCurrency,
+  isCurrencySymbol,
+  omitsCurrency,
+  readDefaultCurrency,
+  parseCurrencyDeclaration,
+  canonicalInstruction,
+  findTrailingClause,
+  reorderTransferFromLast,
+  readShareOfAccount,
+  listHeldCurrencies,
+  isUnknownCurrencyCode,
+  placeCurrencySymbol,
+  parseRelativeDate,
+  parseAbsoluteDate,
+  parseTimeOfDay,
+  zoneOffsetSeconds,
+  getDaysInMonth,
+  parseRecurrence,
+  matchScheduleAnchor,
+  nextAnchorDate,
+  resolveAccountAlias,
+  parseAccountReference,
+  findAccountsBySuffix,
+  findAccountsIgnoringCase,
+  findDuplicateAccountIds,
+  tokenize,
+  describeParseError,
+  isValidAccountId,
+  createAccountIdMatcher,
+  findAccount,
+  findBlockedAccount,
+  findNegativeBalance,
+  toMinorUnits,
+  fromMinorUnits,
+  fitsMinorUnits,
+  calculateFee,
+  exceededAmountCap,
+  largestAffordableAmount,
+  parseNarration,
+  parseFeeSource,
+  parseBalanceCondition,
+  isConditionMet,
+  scoreConfidence,
+  buildWarnings,
+  describeGuesses,
+  correctKeyword,
+  correctCurrencyWord,
+  splitCompoundInstruction,
+  reorderAccountFirst,
+  parseBillPayment,
+  includeUntouchedAccounts,
+  echoAccounts,
+  isWalletAccount,
+  selectWalletPockets,
+  isCompleteAccount,
+  isBlankInstruction,
+  buildAuditRecord,
+  FEE_POLICY,
+  MAX_AMOUNT_POLICY,
+  NEGATIVE_BALANCE_POLICY,
+  ROUNDING_POLICY,
+  MAX_INSTRUCTION_LENGTH,
+  FUZZY_VERBS,
+  VERB_SYNONYMS,
+  COLLECT_VERBS,
+  currentTime,
+  describeCurrencyMismatch,
+} = require('./helpers');
+const processSplitInstruction = require('./process-split-instruction');
+const processMultiDebitInstruction = require('./process-multi-debit-instruction');
+const processCompoundInstruction = require('./process-compound-instruction');
+const processCashInstruction = require('./process-cash-instruction');
+const processWalletInstruction = require('./process-wallet-instruction');
+const createMemoryDailyDebitStore = require('./stores/create-memory-daily-debit-store');
+const defaultTransactionStore = require('./stores/default-transaction-store');
+const defaultIdGenerator = require('./id-generators/default-id-generator');
+const noopObserver = require('./observers/noop-observer');
+const noopAuditSink = require('./audit-sinks/noop-audit-sink');
+const writeAuditRecord = require('./audit-sinks/write-audit-record');
+const amountParsersForLocale = require('./amount-parsers/locale-amount-parsers');
+const { parsedInstructionRequestSpec: parsedSpec } = require('./request-spec');
 
-// -----------------------------
-// VSL Spec (validate incoming payload)
-// -----------------------------
-const spec = `root {
-  accounts[] {
-    id string
-    balance number
-    currency string
-  }
-  instruction string<trim>
-}`;
-
-const parsedSpec = validator.parse(spec);
+// Per-account debited totals for daily limits; options.dailyDebitStore replaces it
+const defaultDailyDebitStore = createMemoryDailyDebitStore();
 
 // -----------------------------
 // Helper utilities (no regex)
 // -----------------------------
 
-/**
- * Tokenize instruction string into non-empty tokens.
- * Handles multiple spaces and tabs by replacing tabs with spaces, trimming,
- * and splitting on single-space then filtering empty tokens.
- */
-function tokenize(instruction) {
-  if (instruction === undefined || instruction === null) return [];
-  let s = String(instruction);
-  // Replace tab characters with single space (no regex)
-  while (s.indexOf('\t') !== -1) s = s.replace('\t', ' ');
-  s = s.trim();
-  if (s.length === 0) return [];
-  const parts = s.split(' ');
-  const tokens = [];
-  for (let i = 0; i < parts.length; i++) {
-    if (parts[i].length > 0) tokens.push(parts[i]);
-  }
-  return tokens;
-}
-
-/**
- * Validate account ID characters: letters, numbers, hyphen (-), dot (.), at (@)
- */
-function isValidAccountId(id) {
-  if (typeof id !== 'string' || id.length === 0) return false;
-  const allowed = 'abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-.@';
-  for (let i = 0; i < id.length; i++) {
-    if (allowed.indexOf(id[i]) === -1) return false;
-  }
-  return true;
-}
-
-/**
- * Check if a string represents a positive integer (no decimals, no sign).
- */
-function isPositiveIntegerString(s) {
-  if (typeof s !== 'string' || s.length === 0) return false;
-  // each char must be 0-9
-  for (let i = 0; i < s.length; i++) {
-    const ch = s[i];
-    if (ch < '0' || ch > '9') return false;
-  }
-  // parse and ensure > 0
-  const n = parseInt(s, 10);
-  if (Number.isNaN(n) || n <= 0) return false;
-  return true;
-}
-
 /**
  * Parse YYYY-MM-DD to { year, month, day } or return null for invalid format.
  * DOES NOT use regex.
@@ -88,16 +118,66 @@ function parseDateYYYYMMDD(dateStr) {
   const mi = parseInt(m, 10);
   const di = parseInt(d, 10);
   if (mi < 1 || mi > 12) return null;
-  if (di < 1 || di > 31) return null;
+  if (di < 1 || di > getDaysInMonth(yi, mi)) return null;
   return { year: yi, month: mi, day: di };
 }
 
 /**
- * Compare parsed date object {year,month,day} with today's UTC date.
+ * Split a time of day off the end of a schedule date clause: the words after its last AT
+ * ("tomorrow at 2:30pm"), or else a last word or two that read as a time ("on 2025-04-01
+ * 14:30"). Words after AT that are no time at all read as an invalid one.
+ * Returns { words, time, text } with time null when the clause names none (see
+ * parseTimeOfDay) and text the time as written.
+ */
+function splitTimeOfDay(words) {
+  const iAt = words.map((w) => String(w).toLowerCase()).lastIndexOf('at');
+  if (iAt > 0) {
+    const timeWords = words.slice(iAt + 1);
+    const time = parseTimeOfDay(timeWords) || { seconds: 0, valid: false };
+    return { words: words.slice(0, iAt), time, text: timeWords.join(' ') };
+  }
+  for (let n = 2; n >= 1; n--) {
+    const time = words.length > n ? parseTimeOfDay(words.slice(words.length - n)) : null;
+    if (time !== null) {
+      const text = words.slice(words.length - n).join(' ');
+      return { words: words.slice(0, words.length - n), time, text };
+    }
+  }
+  return { words, time: null, text: '' };
+}
+
+/**
+ * Status reason for a date phrase read as a schedule anchor that gave no date (see
+ * matchScheduleAnchor): an unknown name lists the known ones.
+ */
+function describeAnchorFailure(anchor) {
+  if (anchor.rule === null) {
+    const known = anchor.known.join(', ');
+    return `${PaymentMessages.UNKNOWN_SCHEDULE_ANCHOR}: "${anchor.name}" (known: ${known})`;
+  }
+  return `${PaymentMessages.INVALID_SCHEDULE_ANCHOR}: ${anchor.name}`;
+}
+
+/**
+ * Whether the FROM at tokens[iFrom] names a list of debit accounts ("FROM acc1 AND acc2",
+ * "FROM acc1, acc2"), which makes a MULTI_DEBIT; a fee-source clause and the narration are
+ * not part of the list.
+ */
+function namesSeveralSources(tokens, iFrom, accounts) {
+  const feeSource = parseFeeSource(tokens, accounts);
+  const rest = feeSource !== null ? feeSource.tokens : tokens;
+  const end = parseNarration(rest, iFrom + 1).start;
+  return rest.slice(iFrom + 1, end).some((token) => {
+    const lower = String(token).toLowerCase();
+    return lower === 'and' || lower[lower.length - 1] === ',';
+  });
+}
+
+/**
+ * Compare parsed date object {year,month,day} with the UTC date of `now`.
  * Returns -1 if date < today, 0 if equal, 1 if date > today.
  */
-function compareDateToTodayUTC(dateObj) {
-  const now = new Date();
+function compareDateToTodayUTC(dateObj, now) {
   const ty = now.getUTCFullYear();
   const tm = now.getUTCMonth() + 1;
   const td = now.getUTCDate();
@@ -111,24 +191,428 @@ function compareDateToTodayUTC(dateObj) {
 }
 
 /**
- * Find account in provided accounts array preserving original order knowledge.
- * Returns { index, account } or null.
+ * Execute a parsed single transfer: after the per-transaction cap (LM02) and the account
+ * statuses (AC08, AC09) the date decides whether it runs now or stays pending (AP02), a
+ * balance guard is checked against the debit balance when it runs (CD01), then partial
+ * execution, FX conversion and the fee apply and the balance rules (BL02, AC01, LM01) are
+ * checked before the balances move. Nothing is parsed here; the instruction is read only
+ * through `parsed` (see parse-instruction.js for its fields).
+ *
+ * @param {Object} parsed - type, amount, currency, debit_account, credit_account, execute_by
+ *   and the optional fx_rate, condition and recurrence
+ * @param {{ data: Object, options: Object, accounts: Object[], baseResponse: Object,
+ *   now: Date, dryRun: boolean, scheduledDate: Object|null, correctionReason: string }} context
+ * @returns {Promise<Object>}
  */
-function findAccount(accounts, id) {
+async function executeParsedInstruction(parsed, context) {
+  const { data, options, accounts, baseResponse, now, dryRun, correctionReason } = context;
+  let result;
+
+  const { type, currency } = parsed;
+  let { amount } = parsed;
+  const debitAccountId = parsed.debit_account;
+  const creditAccountId = parsed.credit_account;
+  const executeBy = parsed.execute_by;
+  const fxRate = parsed.fx_rate !== undefined ? parsed.fx_rate : null;
+  const recurrenceFields = parsed.recurrence ? { recurrence: parsed.recurrence } : {};
+  const conditionFields = parsed.condition ? { condition: parsed.condition } : {};
+  const warningFields = parsed.warnings ? { warnings: parsed.warnings } : {};
+  const parsedDateObj = context.scheduledDate;
+  const debitEntry = findAccount(accounts, debitAccountId);
+  const creditEntry = findAccount(accounts, creditAccountId);
+  const debitAccCurr = String(debitEntry.account.currency || '').toUpperCase();
+  const creditAccCurr = String(creditEntry.account.currency || '').toUpperCase();
+  // Another account may bear the fee ("fee from acc3"); results echo it with the two sides
+  const feeEntry = parsed.fee_account ? findAccount(accounts, parsed.fee_account) : null;
+  const feeAccountFields = feeEntry !== null ? { fee_account: feeEntry.account.id } : {};
+  const involvedIds = [debitEntry.account.id, creditEntry.account.id];
+  if (feeEntry !== null) involvedIds.push(feeEntry.account.id);
+
+  // Per-transaction cap on the amount as requested (LM02), for scheduled transfers too
+  const maxAmountPolicy = options.maxAmountPolicy || MAX_AMOUNT_POLICY;
+  const cap = exceededAmountCap(amount, type, currency, maxAmountPolicy);
+  if (cap !== null) {
+    result = {
+      ...baseResponse,
+      type,
+      amount,
+      currency,
+      debit_account: debitAccountId,
+      credit_account: creditAccountId,
+      execute_by: executeBy || null,
+      status_reason: `${PaymentMessages.AMOUNT_EXCEEDS_TRANSACTION_LIMIT}: limit is ${cap} ${currency} for ${type}`,
+      status_code: 'LM02',
+      accounts: echoAccounts(accounts, involvedIds),
+    };
+    return result;
+  }
+
+  // A frozen (AC08) or closed (AC09) account takes no part, scheduled or not, and is checked
+  // before any balance rule
+  const blocked = findBlockedAccount(
+    feeEntry !== null
+      ? [debitEntry.account, creditEntry.account, feeEntry.account]
+      : [debitEntry.account, creditEntry.account]
+  );
+  if (blocked !== null) {
+    const blockedMessage =
+      blocked.status === 'frozen' ? PaymentMessages.ACCOUNT_FROZEN : PaymentMessages.ACCOUNT_CLOSED;
+    result = {
+      ...baseResponse,
+      type,
+      amount,
+      currency,
+      debit_account: debitAccountId,
+      credit_account: creditAccountId,
+      execute_by: executeBy || null,
+      status_reason: `${blockedMessage}: ${blocked.account.id}`,
+      status_code: blocked.code,
+      accounts: echoAccounts(accounts, involvedIds),
+    };
+    return result;
+  }
+
+  // Date logic: if parsedDateObj exists and parsedDateObj > today -> pending
+  let willExecuteNow = true;
+  if (parsedDateObj) {
+    // A scheduled time of day is compared exactly; plain dates by calendar day
+    const cmp = parsedDateObj.hasTime
+      ? Math.sign(parsedDateObj.timestamp * 1000 - now.getTime())
+      : compareDateToTodayUTC(parsedDateObj, now);
+    if (cmp === 1) {
+      willExecuteNow = false;
+    } else {
+      willExecuteNow = true;
+    }
+  }
+
+  // "only if balance above 1000": the guard is read against the debit balance as it is when
+  // the transfer runs, so one not due yet is checked then
+  if (parsed.condition && willExecuteNow) {
+    const condition = parsed.condition;
+    const balance = Number(debitEntry.account.balance);
+    if (!isConditionMet(balance, condition, debitAccCurr)) {
+      const comparison = condition.comparison === 'at_least' ? 'at least' : condition.comparison;
+      result = {
+        ...baseResponse,
+        type,
+        amount,
+        currency,
+        debit_account: debitAccountId,
+        credit_account: creditAccountId,
+        execute_by: executeBy || null,
+        ...conditionFields,
+        status_reason: `${PaymentMessages.CONDITION_NOT_MET}: ${debitEntry.account.id} balance is ${balance} ${debitAccCurr}, not ${comparison} ${condition.threshold} ${debitAccCurr}`,
+        status_code: 'CD01',
+        accounts: echoAccounts(accounts, involvedIds),
+      };
+      return result;
+    }
+  }
+
+  // Opt-in partial execution: an amount the debit account cannot cover is cut down to the
+  // most it can give (balance, plus overdraft unless a minimum_balance is set, less the fee
+  // unless a fee account bears it) and the result is BL03. Transfers that are not due yet
+  // keep the requested amount.
+  const feePolicy = options.feePolicy || FEE_POLICY;
+  const roundingPolicy = options.roundingPolicy || ROUNDING_POLICY;
+  const requestedAmount = amount;
+  const partialMode = data.partial_execution === true || options.partialExecution === true;
+  if (partialMode && willExecuteNow) {
+    const account = debitEntry.account;
+    const floor =
+      account.minimum_balance !== undefined
+        ? Number(account.minimum_balance)
+        : -Math.max(Number(account.overdraft_limit) || 0, 0);
+    const availableMinor =
+      toMinorUnits(Number(account.balance), currency) - toMinorUnits(floor, currency);
+    const affordable = largestAffordableAmount(
+      availableMinor,
+      type,
+      feeEntry !== null ? {} : feePolicy,
+      currency,
+      roundingPolicy
+    );
+    if (affordable > 0 && affordable < amount) amount = affordable;
+  }
+  const partiallyExecuted = amount < requestedAmount;
+
+  // Cross-currency transfers credit the converted amount in the credit account's currency
+  let creditAmount = amount;
+  let fxFields = {};
+  let fxReason = '';
+  if (fxRate !== null) {
+    creditAmount = convertAmount(amount, fxRate, debitAccCurr, creditAccCurr, roundingPolicy);
+    fxFields = {
+      converted_amount: creditAmount,
+      converted_currency: creditAccCurr,
+      fx_rate: fxRate,
+    };
+    const rateText = `1 ${debitAccCurr} = ${fxRate} ${creditAccCurr}`;
+    fxReason = ` (${PaymentMessages.EXCHANGE_RATE_APPLIED}: ${rateText})`;
+  }
+
+  // Optional fee for this transaction type, debited on top of the amount from the debit
+  // account or, with a fee-source clause, from the fee account
+  const fee = calculateFee(amount, type, feePolicy, currency, roundingPolicy);
+  const feeFields = fee !== null ? { fee, ...feeAccountFields } : feeAccountFields;
+  const feeMinor = fee !== null ? toMinorUnits(fee, currency) : 0;
+  const debitFeeMinor = feeEntry !== null ? 0 : feeMinor;
+  const totalDebitMinor = toMinorUnits(amount, currency) + debitFeeMinor;
+  const totalDebit = fromMinorUnits(totalDebitMinor, currency);
+  const feeText =
+    debitFeeMinor > 0
+      ? ` (${amount} ${currency} + ${fee} ${currency} ${PaymentMessages.FEE})`
+      : '';
+
+  // If pending -> return pending status, do not modify balances
+  if (!willExecuteNow) {
+
+    result = {
+      ...baseResponse,
+      type,
+      amount,
+      currency,
+      debit_account: debitAccountId,
+      credit_account: creditAccountId,
+      execute_by: executeBy || null,
+      ...fxFields,
+      ...feeFields,
+      ...conditionFields,
+      ...recurrenceFields,
+      ...warningFields,
+      status: 'pending',
+      status_reason: `${PaymentMessages.TRANSACTION_SCHEDULED}${fxReason}${correctionReason}`,
+      status_code: 'AP02',
+      accounts: echoAccounts(accounts, involvedIds),
+    };
+    return result;
+  }
+
+  // Execution now: check sufficient funds in debit account
+  const debitBalanceBefore = Number(debitEntry.account.balance);
+  const creditBalanceBefore = Number(creditEntry.account.balance);
+  if (Number.isNaN(debitBalanceBefore) || Number.isNaN(creditBalanceBefore)) {
+    // Internal data error - throw application error
+    appLogger.error(
+      { debitEntry, creditEntry },
+      'parse-payment-instructions.invalid-account-balances'
+    );
+    throwAppError(PaymentMessages.INTERNAL_ERROR, ERROR_CODE.APPERR);
+  }
+  // Balance arithmetic runs in integer minor units so repeated transfers never drift
+  const debitAfterMinor = toMinorUnits(debitBalanceBefore, currency) - totalDebitMinor;
+  const newDebitBalance = fromMinorUnits(debitAfterMinor, currency);
+  // A fee account that is also the credit account pays the fee out of what it receives
+  const feeFromCredit = feeEntry !== null && feeEntry.account.id === creditEntry.account.id;
+  const creditAfterMinor =
+    toMinorUnits(creditBalanceBefore, creditAccCurr) +
+    toMinorUnits(creditAmount, creditAccCurr) -
+    (feeFromCredit ? feeMinor : 0);
+  const newCreditBalance = fromMinorUnits(creditAfterMinor, creditAccCurr);
+  // Regulatory floor: when set, the debit must leave at least minimum_balance behind
+  const minimumBalance =
+    debitEntry.account.minimum_balance !== undefined
+      ? Number(debitEntry.account.minimum_balance)
+      : null;
+  if (minimumBalance !== null && debitAfterMinor < toMinorUnits(minimumBalance, currency)) {
+    // Minimum balance breach BL02
+    result = {
+      ...baseResponse,
+      type,
+      amount,
+      currency,
+      debit_account: debitAccountId,
+      credit_account: creditAccountId,
+      execute_by: executeBy || null,
+      ...feeFields,
+      status_reason: `${PaymentMessages.MINIMUM_BALANCE_BREACH}: floor is ${minimumBalance} ${currency}, balance would be ${newDebitBalance} ${currency}`,
+      status_code: 'BL02',
+      accounts: echoAccounts(accounts, involvedIds),
+    };
+    return result;
+  }
+  // The debit may take the balance below zero, down to minus the account's overdraft limit
+  const overdraftLimit = Math.max(Number(debitEntry.account.overdraft_limit) || 0, 0);
+  if (debitAfterMinor < -toMinorUnits(overdraftLimit, currency)) {
+    // Insufficient funds AC01
+    const overdraftText =
+      overdraftLimit > 0 ? ` plus ${overdraftLimit} ${currency} ${PaymentMessages.OVERDRAFT}` : '';
+    result = {
+      ...baseResponse,
+      type,
+      amount,
+      currency,
+      debit_account: debitAccountId,
+      credit_account: creditAccountId,
+      execute_by: executeBy || null,
+      ...feeFields,
+      status_reason: `${PaymentMessages.INSUFFICIENT_FUNDS}: has ${debitBalanceBefore} ${currency}${overdraftText}, needs ${totalDebit} ${currency}${feeText}`,
+      status_code: 'AC01',
+      accounts: echoAccounts(accounts, involvedIds),
+    };
+    return result;
+  }
+
+  // The fee account is checked on its own: the fee may take it down to its minimum_balance
+  // when it has one, else to minus its overdraft limit (BL01)
+  const feeBalanceBefore = feeEntry !== null ? Number(feeEntry.account.balance) : null;
+  let newFeeBalance = null;
+  if (feeEntry !== null && feeMinor > 0) {
+    const feeAfterMinor = feeFromCredit
+      ? creditAfterMinor
+      : toMinorUnits(feeBalanceBefore, currency) - feeMinor;
+    newFeeBalance = fromMinorUnits(feeAfterMinor, currency);
+    const feeOverdraft = Math.max(Number(feeEntry.account.overdraft_limit) || 0, 0);
+    const feeFloor =
+      feeEntry.account.minimum_balance !== undefined
+        ? Number(feeEntry.account.minimum_balance)
+        : -feeOverdraft;
+    if (feeAfterMinor < toMinorUnits(feeFloor, currency)) {
+      let floorText = '';
+      if (feeEntry.account.minimum_balance !== undefined) {
+        floorText = `, floor is ${feeFloor} ${currency}`;
+      } else if (feeOverdraft > 0) {
+        floorText = ` plus ${feeOverdraft} ${currency} ${PaymentMessages.OVERDRAFT}`;
+      }
+      result = {
+        ...baseResponse,
+        type,
+        amount,
+        currency,
+        debit_account: debitAccountId,
+        credit_account: creditAccountId,
+        execute_by: executeBy || null,
+        ...feeFields,
+        status_reason: `${PaymentMessages.FEE_ACCOUNT_INSUFFICIENT_FUNDS}: ${feeEntry.account.id} has ${feeBalanceBefore} ${currency}${floorText}, needs ${fee} ${currency} ${PaymentMessages.FEE}`,
+        status_code: 'BL01',
+        accounts: echoAccounts(accounts, involvedIds),
+      };
+      return result;
+    }
+  }
+
+  // Daily limit: today's already-debited total plus this debit may not exceed daily_limit.
+  // "Today" is the UTC date of the reference time.
+  const dailyDebitStore = options.dailyDebitStore || defaultDailyDebitStore;
+  const debitDay = now.toISOString().slice(0, 10);
+  const dailyLimit =
+    debitEntry.account.daily_limit !== undefined ? Number(debitEntry.account.daily_limit) : null;
+  if (dailyLimit !== null) {
+    const debitedToday = await dailyDebitStore.getTotal(debitEntry.account.id, debitDay);
+    const debitedTodayMinor = toMinorUnits(debitedToday, currency);
+    if (debitedTodayMinor + totalDebitMinor > toMinorUnits(dailyLimit, currency)) {
+      // Daily limit exceeded LM01
+      result = {
+        ...baseResponse,
+        type,
+        amount,
+        currency,
+        debit_account: debitAccountId,
+        credit_account: creditAccountId,
+        execute_by: executeBy || null,
+        ...feeFields,
+        status_reason: `${PaymentMessages.DAILY_LIMIT_EXCEEDED}: limit is ${dailyLimit} ${currency}, already debited ${debitedToday} ${currency} today, needs ${totalDebit} ${currency}${feeText}`,
+        status_code: 'LM01',
+        accounts: echoAccounts(accounts, involvedIds),
+      };
+      return result;
+    }
+  }
+
+  // Perform transfer (in-memory only; no persistence required)
+  // Build accountsOut with ordering based on original request order
+  const accountsOutAfter = [];
   for (let i = 0; i < accounts.length; i++) {
-    if (accounts[i].id === id) return { index: i, account: accounts[i] };
+    const a = accounts[i];
+    if (a.id === debitEntry.account.id) {
+      accountsOutAfter.push({
+        id: a.id,
+        balance: dryRun ? debitBalanceBefore : newDebitBalance,
+        balance_before: debitBalanceBefore,
+        ...(dryRun ? { projected_balance: newDebitBalance } : {}),
+        currency: String(a.currency || '').toUpperCase(),
+      });
+    } else if (a.id === creditEntry.account.id) {
+      accountsOutAfter.push({
+        id: a.id,
+        balance: dryRun ? creditBalanceBefore : newCreditBalance,
+        balance_before: creditBalanceBefore,
+        ...(dryRun ? { projected_balance: newCreditBalance } : {}),
+        currency: String(a.currency || '').toUpperCase(),
+      });
+    } else if (feeEntry !== null && a.id === feeEntry.account.id) {
+      const feeBalanceAfter = newFeeBalance !== null ? newFeeBalance : feeBalanceBefore;
+      accountsOutAfter.push({
+        id: a.id,
+        balance: dryRun ? feeBalanceBefore : feeBalanceAfter,
+        balance_before: feeBalanceBefore,
+        ...(dryRun ? { projected_balance: feeBalanceAfter } : {}),
+        currency: String(a.currency || '').toUpperCase(),
+      });
+    }
+  }
+
+  // Count the debit towards today's total (previews move no money, so they are not counted)
+  if (!dryRun) await dailyDebitStore.add(debitEntry.account.id, debitDay, totalDebit);
+
+  // Final successful response; executed transactions are kept for reversal
+  const transactionStore = options.transactionStore || defaultTransactionStore;
+  let executedReason = PaymentMessages.TRANSACTION_EXECUTED;
+  if (partiallyExecuted) executedReason = PaymentMessages.PARTIALLY_EXECUTED;
+  if (dryRun) executedReason = PaymentMessages.DRY_RUN_WOULD_EXECUTE;
+  const partialReason = partiallyExecuted
+    ? `; ${amount} ${currency} of the ${requestedAmount} ${currency} requested`
+    : '';
+  const overdraftReason =
+    newDebitBalance < 0
+      ? `; ${PaymentMessages.ACCOUNT_OVERDRAWN} by ${-newDebitBalance} ${currency}`
+      : '';
+  result = {
+    ...baseResponse,
+    type,
+    amount,
+    currency,
+    debit_account: debitAccountId,
+    credit_account: creditAccountId,
+    execute_by: executeBy || null,
+    ...fxFields,
+    ...feeFields,
+    ...conditionFields,
+    ...recurrenceFields,
+    ...(partiallyExecuted ? { requested_amount: requestedAmount } : {}),
+    ...warningFields,
+    status: 'successful',
+    status_reason: `${executedReason}${partialReason}${fxReason}${overdraftReason}${correctionReason}`,
+    status_code: partiallyExecuted ? 'BL03' : 'AP00',
+    accounts: accountsOutAfter,
+  };
+  if (!dryRun) {
+    await transactionStore.set(result.transaction_id, { transaction: result, reversal: null });
   }
-  return null;
-}
 
-// Supported currencies (uppercase)
-const SUPPORTED_CURRENCIES = ['NGN', 'USD', 'GBP', 'GHS'];
+  return result;
+}
 
 // -----------------------------
 // Main service function
 // -----------------------------
-async function paymentInstructions(serviceData, options = {}) {
-  // Single exit point: result and return are assigned at the end.
+
+/**
+ * The parse step: validate the payload and parse the instruction, resolving it against the
+ * accounts (names, trailing digits, currency, dates, FX rate, fee account). Nothing is read
+ * from or written to a balance store.
+ *
+ * A single transfer comes back as `{ parsed, context }`, the parsed instruction (see
+ * parse-instruction.js for its fields) and what executeParsedInstruction needs to run it.
+ * Anything else is a finished response in `{ result }`: a failure, or an instruction routed
+ * to its own flow (split, multi-debit, cash, compound), which parses and runs it there.
+ *
+ * @param {Object} serviceData
+ * @param {Object} [options]
+ * @returns {Promise<{ parsed: Object, context: Object }|{ result: Object }>}
+ */
+async function parsePaymentInstruction(serviceData, options = {}) {
   let result;
 
   // TIME LOGGER for diagnostic
@@ -139,6 +623,8 @@ async function paymentInstructions(serviceData, options = {}) {
   let data;
   try {
     data = validator.validate(serviceData, parsedSpec);
+    // The spec leaves balance and currency optional for wallets, which arrive here as pockets
+    if (!data.accounts.every(isCompleteAccount)) throw new Error('Account balance missing');
   } catch (err) {
     // Validation failed at schema level - rethrow as application error so framework formats response
     appLogger.errorX(
@@ -152,116 +638,640 @@ async function paymentInstructions(serviceData, options = {}) {
   timeLogger.end('validate-input');
   timeLogger.start('parse-instruction');
 
+  // "Use USD. Transfer 500 from acc1 to acc2.": a leading sentence declaring the currency is
+  // this instruction's default_currency, read wherever the instruction names none
+  const declaration = parseCurrencyDeclaration(tokenize(data.instruction));
+  if (declaration !== null) {
+    data = {
+      ...data,
+      instruction: declaration.tokens.join(' '),
+      default_currency: declaration.currency,
+    };
+  }
+
+  // Reference time for all date handling: options.now pins it (Date or epoch ms), options.clock
+  // supplies it (see clocks/system-clock.js)
+  const now = currentTime(options);
+
+  // Every response carries an id, failures included; options.idGenerator replaces the default
+  const idGenerator = options.idGenerator || defaultIdGenerator;
+
   // Initialize default response skeleton with nulls (for unparseable cases)
   const baseResponse = {
+    transaction_id: idGenerator.next(),
     type: null,
     amount: null,
     currency: null,
     debit_account: null,
     credit_account: null,
     execute_by: null,
+    narration: '',
     status: 'failed',
     status_reason: '',
     status_code: '',
     accounts: [],
   };
 
+  // Dry run: same parse, validation and balance checks, but balances are only projected
+  const dryRun = data.dry_run === true || options.dryRun === true;
+  // "1,000.50" by default; "1.000,50" when the decimal separator is ','
+  const decimalSeparator =
+    data.decimal_separator === ',' || options.decimalSeparator === ',' ? ',' : '.';
+  // The amount parsers options.amountParsers gives, else the request locale's: "en-IN" reads
+  // "1,00,000" and "2 lakh" (see amount-parsers/locale-amount-parsers.js)
+  const amountParsers =
+    options.amountParsers || amountParsersForLocale(data.locale || options.locale);
+  // Strict mode: nothing is guessed (see confidence); the currency must be written too
+  const strict = data.strict === true || options.strict === true;
+  // Currency of instructions that name none; without one the currency must be written
+  const defaultCurrency = strict ? null : readDefaultCurrency(data, options);
+  if (dryRun) baseResponse.dry_run = true;
+
+  // A caller's AbortSignal (cancelled request, AbortSignal.timeout deadline) stops the
+  // instruction before anything is parsed or moved
+  if (options.signal && options.signal.aborted) {
+    result = {
+      ...baseResponse,
+      status: 'cancelled',
+      status_reason: PaymentMessages.INSTRUCTION_CANCELLED,
+      status_code: 'CANCELLED',
+    };
+    timeLogger.end('parse-instruction');
+    return { result };
+  }
+
+  // options.accountIdFormat gives the shape of the caller's account ids; a format that is not
+  // one is refused before any instruction is read
+  let matchesIdFormat;
+  try {
+    matchesIdFormat = createAccountIdMatcher(options.accountIdFormat);
+  } catch (err) {
+    throwAppError(PaymentMessages.INVALID_ACCOUNT_ID_FORMAT_OPTION, ERROR_CODE.VALIDATIONERR);
+  }
+
   try {
     const accounts = Array.isArray(data.accounts) ? data.accounts : [];
     const instructionRaw = data.instruction;
 
+    // A length cap keeps pathological input away from the tokenizer and the parsing below
+    const maxLength = options.maxInstructionLength || MAX_INSTRUCTION_LENGTH;
+    if (instructionRaw.length > maxLength) {
+      result = {
+        ...baseResponse,
+        status_reason: `${PaymentMessages.INSTRUCTION_TOO_LONG}: ${instructionRaw.length} characters, limit is ${maxLength}`,
+        status_code: 'SY04',
+        accounts: [],
+      };
+      timeLogger.end('parse-instruction');
+      return { result };
+    }
+    // Schedules are read in the request's timezone, else options.timeZone (an IANA name)
+    const timeZone = data.timezone || options.timeZone || null;
+    if (timeZone !== null && zoneOffsetSeconds(timeZone, now.getTime()) === null) {
+      result = {
+        ...baseResponse,
+        status_reason: `${PaymentMessages.INVALID_TIMEZONE}: ${timeZone}`,
+        status_code: 'DT04',
+        accounts: [],
+      };
+      timeLogger.end('parse-instruction');
+      return { result };
+    }
+    // Nothing to read at all ("", "???") is told apart from text that does not parse
+    if (isBlankInstruction(instructionRaw)) {
+      result = {
+        ...baseResponse,
+        status_reason: PaymentMessages.INSTRUCTION_EMPTY,
+        status_code: 'SY05',
+        accounts: [],
+      };
+      timeLogger.end('parse-instruction');
+      return { result };
+    }
+
+    // One account per id, checked before the instruction is read; with case-insensitive ids
+    // "acc1" and "ACC1" are the same id
+    const ignoreCase = data.case_insensitive_ids === true || options.caseInsensitiveIds === true;
+    const duplicateIds = findDuplicateAccountIds(accounts, ignoreCase);
+    if (duplicateIds !== null) {
+      result = {
+        ...baseResponse,
+        status_reason: `${PaymentMessages.DUPLICATE_ACCOUNT_ID}: ${duplicateIds.join(', ')}`,
+        status_code: 'AC07',
+        accounts: [],
+      };
+      timeLogger.end('parse-instruction');
+      return { result };
+    }
+
+    // A negative starting balance is accepted deliberately or not at all (AC10), per
+    // options.negativeBalancePolicy
+    const negativeBalancePolicy =
+      options.negativeBalancePolicy === 'reject' ? 'reject' : NEGATIVE_BALANCE_POLICY;
+    const negative = findNegativeBalance(accounts, negativeBalancePolicy);
+    if (negative !== null) {
+      const negativeCurrency = String(negative.currency || '').toUpperCase();
+      const limit = Math.max(Number(negative.overdraft_limit) || 0, 0);
+      const limitText =
+        negativeBalancePolicy === 'overdraft'
+          ? `, overdraft limit is ${limit} ${negativeCurrency}`
+          : '';
+      result = {
+        ...baseResponse,
+        status_reason: `${PaymentMessages.NEGATIVE_BALANCE}: ${negative.id} has ${negative.balance} ${negativeCurrency}${limitText}`,
+        status_code: 'AC10',
+        accounts: [],
+      };
+      timeLogger.end('parse-instruction');
+      return { result };
+    }
+
     // Tokenize
-    const tokens = tokenize(instructionRaw);
+    let tokens = tokenize(instructionRaw);
+    const corrections = [];
+    // Optional detail for parse failures: the segment at fault and where it is in the input.
+    // Offsets count from the instruction as sent, before the validator trimmed it.
+    const sentInstruction =
+      typeof serviceData.instruction === 'string' ? serviceData.instruction : instructionRaw;
+    // Corrected keywords (see fuzzy_keywords below) are located as the user typed them
+    const parseError = (segment, index, count) => {
+      const typed = tokens.slice();
+      corrections.forEach((c) => {
+        typed[c.index] = c.from;
+      });
+      return describeParseError(sentInstruction, typed, segment, index, count);
+    };
     if (!tokens || tokens.length === 0) {
       // Completely unparseable
       result = {
         ...baseResponse,
         status_reason: PaymentMessages.MALFORMED_INSTRUCTION,
         status_code: 'SY03',
+        parse_error: parseError('verb', 0),
         accounts: [],
       };
       timeLogger.end('parse-instruction');
-      return result;
+      return { result };
     }
 
     // Normalize lowercase tokens for keyword detection, keep original tokens for account IDs
-    const lowerTokens = [];
+    let lowerTokens = [];
     for (let i = 0; i < tokens.length; i++) lowerTokens.push(tokens[i].toLowerCase());
 
-    // First token must be DEBIT or CREDIT
-    const first = lowerTokens[0];
-    if (first !== 'debit' && first !== 'credit') {
-      // Missing required starting keyword
+    // "<instruction> and <instruction>": the clauses run in turn on the shared accounts
+    if (splitCompoundInstruction(tokens).length > 1) {
+      const dailyDebitStore = options.dailyDebitStore || defaultDailyDebitStore;
+      result = await processCompoundInstruction(data, {
+        ...options,
+        dailyDebitStore,
+        // Clauses are audited as part of the compound instruction, not one by one
+        processInstruction: runObservedInstruction,
+      });
+      timeLogger.end('parse-instruction');
+      return { result };
+    }
+
+    // Opt-in typo tolerance for the leading verbs and currency words ("trasnfer", "niara");
+    // each correction lowers the confidence and is noted in the status reason
+    const fuzzy = data.fuzzy_keywords === true || options.fuzzyKeywords === true;
+    const correctToken = (index, corrected) => {
+      corrections.push({ index, from: tokens[index], to: corrected });
+      tokens[index] = corrected;
+      lowerTokens[index] = corrected;
+    };
+    if (fuzzy) {
+      const leading = correctKeyword(lowerTokens[0], FUZZY_VERBS);
+      if (leading !== null) correctToken(0, leading);
+      const afterSchedule =
+        lowerTokens[0] === 'schedule' ? correctKeyword(lowerTokens[1], FUZZY_VERBS) : null;
+      if (afterSchedule !== null) correctToken(1, afterSchedule);
+    }
+    // "move", "send", "wire", ... stand for the verb they map to in VERB_SYNONYMS; a synonym
+    // written as such is no guess and leaves the confidence as it is
+    const synonymIndex = lowerTokens[0] === 'schedule' ? 1 : 0;
+    // The verb as written, reported as matched_verb next to the type it reads as
+    const matchedVerb = lowerTokens[synonymIndex];
+    let synonym = Object.prototype.hasOwnProperty.call(VERB_SYNONYMS, lowerTokens[synonymIndex])
+      ? VERB_SYNONYMS[lowerTokens[synonymIndex]]
+      : null;
+    // "collect 2000 NGN from acc1 to acc2", "charge acc1 3000 NGN": a pull payment from the
+    // customer, typed COLLECT. With no TO account the request's collection account is the
+    // payee, named ahead of any date or narration.
+    const collection = COLLECT_VERBS.indexOf(matchedVerb) !== -1;
+    const collectionAccount = data.collection_account || options.collectionAccount || null;
+    const iNarration = collection ? parseNarration(tokens, synonymIndex + 1).start : 0;
+    const namesPayee = lowerTokens.slice(0, iNarration).indexOf('to') !== -1;
+    if (collection && collectionAccount !== null && !namesPayee) {
+      const iOn = lowerTokens.indexOf('on', synonymIndex + 1);
+      const iPayee = iOn !== -1 && iOn < iNarration ? iOn : iNarration;
+      tokens.splice(iPayee, 0, 'to', collectionAccount);
+      lowerTokens.splice(iPayee, 0, 'to', String(collectionAccount).toLowerCase());
+    }
+    // "charge acc1 3000 NGN" with no collection account names no payee at all
+    const namesCustomer = lowerTokens.slice(0, iNarration).indexOf('from') !== -1;
+    if (collection && collectionAccount === null && !namesPayee && !namesCustomer) {
       result = {
         ...baseResponse,
+        type: 'COLLECT',
         status_reason: PaymentMessages.MISSING_REQUIRED_KEYWORD,
         status_code: 'SY01',
+        parse_error: parseError('credit', tokens.length),
         accounts: [],
       };
       timeLogger.end('parse-instruction');
-      return result;
+      return { result };
     }
-    const type = first.toUpperCase();
+    const customerFirst =
+      collection &&
+      reorderAccountFirst(['debit', ...tokens.slice(synonymIndex + 1)], accounts) !== null;
+    if (customerFirst) synonym = 'debit';
+    if (synonym !== null) {
+      tokens[synonymIndex] = synonym;
+      lowerTokens[synonymIndex] = synonym;
+    }
+    // SPLIT, multi-debit and cash instructions re-read the instruction, so they get the
+    // corrected verb
+    const rewritten = corrections.length > 0 || synonym !== null;
+    // "pay 5000 to DSTV", "pay electricity 3000 from acc1": a bill payment to a biller outside
+    // the accounts. A payee that is one of the accounts ("pay account acc2 ...", an alias, a
+    // trailing-digit reference) makes it a transfer to that account.
+    const bill = lowerTokens[0] === 'pay' ? parseBillPayment(tokens, decimalSeparator) : null;
+    const payee = bill !== null ? bill.biller.split(' ') : [];
+    const payeeRef = bill !== null ? parseAccountReference(payee, 0) : null;
+    const payeeIsAccount =
+      bill !== null &&
+      (payee[0].toLowerCase() === 'account' ||
+        payeeRef.suffix !== null ||
+        findAccount(accounts, bill.biller) !== null ||
+        resolveAccountAlias(bill.biller, data.aliases) !== null ||
+        (ignoreCase && findAccountsIgnoringCase(accounts, bill.biller).length > 0));
+    if (bill !== null && !payeeIsAccount) {
+      const dailyDebitStore = options.dailyDebitStore || defaultDailyDebitStore;
+      const billData = rewritten ? { ...data, instruction: tokens.join(' ') } : data;
+      result = await processCashInstruction(billData, {
+        ...options,
+        dailyDebitStore,
+        amountParsers,
+        keywordCorrections: corrections,
+        matchedVerb,
+      });
+      timeLogger.end('parse-instruction');
+      return { result };
+    }
+    if (payeeIsAccount) {
+      tokens = [tokens[0], ...payee, ...bill.tokens.slice(1)];
+      lowerTokens = tokens.map((t) => t.toLowerCase());
+    }
+    // "credit acc2 with 100 NGN from acc1", "debit acc1 100 NGN to acc2": the verb and the
+    // preposition decide which account is which; read from here on in the keyword form
+    const reordered = reorderAccountFirst(tokens, accounts);
+    if (reordered !== null) {
+      tokens = reordered;
+      lowerTokens = tokens.map((t) => t.toLowerCase());
+    }
+    const routedData = rewritten ? { ...data, instruction: tokens.join(' ') } : data;
 
-    // Next tokens expected: amount (token[1]) and currency (token[2])
-    if (tokens.length < 3) {
-      result = {
-        ...baseResponse,
-        status_reason: PaymentMessages.MALFORMED_INSTRUCTION,
-        status_code: 'SY03',
-        accounts: [],
-      };
+    // SPLIT debits one account and credits several; it has its own flow
+    if (lowerTokens[0] === 'split') {
+      const dailyDebitStore = options.dailyDebitStore || defaultDailyDebitStore;
+      result = await processSplitInstruction(routedData, {
+        ...options,
+        dailyDebitStore,
+        amountParsers,
+        keywordCorrections: corrections,
+        matchedVerb,
+      });
       timeLogger.end('parse-instruction');
-      return result;
+      return { result };
+    }
+    // WITHDRAW and DEPOSIT move cash out of or into a single account
+    if (lowerTokens[0] === 'withdraw' || lowerTokens[0] === 'deposit') {
+      const dailyDebitStore = options.dailyDebitStore || defaultDailyDebitStore;
+      result = await processCashInstruction(routedData, {
+        ...options,
+        dailyDebitStore,
+        amountParsers,
+        keywordCorrections: corrections,
+        matchedVerb,
+      });
+      timeLogger.end('parse-instruction');
+      return { result };
+    }
+    // "TRANSFER <amount> <currency> TO <acct> FROM <acct> AND <acct>" draws on several accounts
+    const iTransferTo = lowerTokens.indexOf('to');
+    const iTransferFrom = lowerTokens.indexOf('from');
+    if (
+      lowerTokens[0] === 'transfer' &&
+      iTransferTo !== -1 &&
+      iTransferTo < iTransferFrom &&
+      namesSeveralSources(tokens, iTransferFrom, accounts)
+    ) {
+      const dailyDebitStore = options.dailyDebitStore || defaultDailyDebitStore;
+      result = await processMultiDebitInstruction(routedData, {
+        ...options,
+        dailyDebitStore,
+        amountParsers,
+        keywordCorrections: corrections,
+        matchedVerb,
+      });
+      timeLogger.end('parse-instruction');
+      return { result };
+    }
+    // With a single debit account it is a plain transfer, read in the FROM ... TO order
+    const fromFirst = reorderTransferFromLast(tokens, accounts);
+    if (fromFirst !== null) {
+      tokens = fromFirst;
+      lowerTokens = tokens.map((t) => t.toLowerCase());
+    }
+    // "half of savings to current": the account the share is of is the debit account
+    const shareOf = readShareOfAccount(tokens, synonymIndex + 1, decimalSeparator, amountParsers);
+    if (shareOf !== null) {
+      tokens = shareOf;
+      lowerTokens = tokens.map((t) => t.toLowerCase());
     }
 
-    const amountToken = tokens[1];
-    const currencyToken = tokens[2];
+    // ", fee from acc3" / "and pay the fee from acc3": another account bears the fee. The
+    // clause is read out here; its account is resolved once the debit and credit ones are
+    const feeSource = parseFeeSource(tokens, accounts);
+    if (feeSource !== null) {
+      tokens = feeSource.tokens;
+      lowerTokens = tokens.map((t) => t.toLowerCase());
+    }
 
-    // Amount validation: positive integer string
-    if (!isPositiveIntegerString(String(amountToken))) {
+    // "... to acc2 and blah blah": a clause nothing reads fails the whole instruction rather
+    // than run the part that was understood
+    const trailing = findTrailingClause(tokens);
+    if (trailing !== null) {
       result = {
         ...baseResponse,
-        type,
-        amount: null,
-        currency: currencyToken ? String(currencyToken).toUpperCase() : null,
-        status_reason: PaymentMessages.AMOUNT_MUST_BE_POSITIVE_INTEGER,
-        status_code: 'AM01',
+        status_reason: `${PaymentMessages.UNREAD_CLAUSE}: ${trailing.text}`,
+        status_code: 'SY03',
         accounts: [],
       };
       timeLogger.end('parse-instruction');
-      return result;
+      return { result };
     }
-    const amount = parseInt(amountToken, 10);
 
-    // Currency normalized uppercase
-    const currency = String(currencyToken).toUpperCase();
-    if (SUPPORTED_CURRENCIES.indexOf(currency) === -1) {
-      // Unsupported currency
+    // "only if balance above 1000": a guard on the debit balance, checked when the transfer
+    // runs; the amount after the comparison must be a plain figure
+    const guard = parseBalanceCondition(tokens, decimalSeparator);
+    if (guard !== null && guard.condition === null) {
       result = {
         ...baseResponse,
-        type,
-        amount,
-        currency,
-        status_reason: PaymentMessages.UNSUPPORTED_CURRENCY,
-        status_code: 'CU02',
+        status_reason: `${PaymentMessages.MALFORMED_CONDITION}: ${guard.text}`,
+        status_code: 'SY03',
         accounts: [],
       };
       timeLogger.end('parse-instruction');
-      return result;
+      return { result };
+    }
+    const condition = guard !== null ? guard.condition : null;
+    if (guard !== null) {
+      tokens = guard.tokens;
+      lowerTokens = tokens.map((t) => t.toLowerCase());
     }
 
-    // Now parse the rest depending on type (DEBIT vs CREDIT)
+    // Optional prefixes: "SCHEDULE <instruction> [ON] <date>" and
+    // "STANDING ORDER <amount> <currency> FROM ... TO ... <recurrence> [STARTING <date>]"
+    const scheduled = lowerTokens[0] === 'schedule';
+    const standing = lowerTokens[0] === 'standing' && lowerTokens[1] === 'order';
+    const verbIndex = scheduled ? 1 : 0;
+
+    // Next token must be DEBIT, CREDIT or TRANSFER; standing orders always use the transfer
+    // form and have no verb of their own
+    const first = standing ? 'transfer' : lowerTokens[verbIndex];
+    if (first !== 'debit' && first !== 'credit' && first !== 'transfer') {
+      // Missing required starting keyword
+      result = {
+        ...baseResponse,
+        status_reason: PaymentMessages.MISSING_REQUIRED_KEYWORD,
+        status_code: 'SY01',
+        parse_error: parseError('verb', verbIndex),
+        accounts: [],
+      };
+      timeLogger.end('parse-instruction');
+      return { result };
+    }
+    const verb = first.toUpperCase();
+    if (!standing) baseResponse.matched_verb = matchedVerb;
+    let type = verb;
+    if (collection) type = 'COLLECT';
+    if (scheduled) type = 'SCHEDULE';
+    if (standing) type = 'STANDING_ORDER';
+
+    // "SCHEDULE TRANSFER OF 1000 NGN ..." - the "of" is optional filler
+    let amountStart = standing ? 2 : verbIndex + 1;
+    if (verb === 'TRANSFER' && lowerTokens[amountStart] === 'of') amountStart++;
+
+    // "₦5000", "$ 20" or "20€": the symbol takes the place of the currency token
+    tokens = placeCurrencySymbol(tokens, amountStart, decimalSeparator, amountParsers);
+    lowerTokens = tokens.map((t) => t.toLowerCase());
+
+    // A range or an estimate is no amount to send ("100 to 200", "about 500")
+    const ambiguousAmount = findAmbiguousAmount(tokens, amountStart, accounts, decimalSeparator);
+    if (ambiguousAmount !== null) {
+      result = {
+        ...baseResponse,
+        type,
+        status_reason: `${PaymentMessages.AMBIGUOUS_AMOUNT}: "${ambiguousAmount.text}"`,
+        status_code: 'AM04',
+        parse_error: parseError('amount', amountStart, ambiguousAmount.consumed),
+        accounts: [],
+      };
+      timeLogger.end('parse-instruction');
+      return { result };
+    }
+
+    // Next tokens expected: amount (possibly spanning several tokens) and currency
+    if (tokens.length < amountStart + 2) {
+      result = {
+        ...baseResponse,
+        status_reason: PaymentMessages.MALFORMED_INSTRUCTION,
+        status_code: 'SY03',
+        parse_error: parseError(tokens.length > amountStart ? 'currency' : 'amount', tokens.length),
+        accounts: [],
+      };
+      timeLogger.end('parse-instruction');
+      return { result };
+    }
+
+    const parsedAmount = parseAmount(tokens, amountStart, decimalSeparator, amountParsers);
+    const amountConsumed = parsedAmount ? parsedAmount.consumed : 1;
+    // Percentage / fraction of the debit balance, resolved once the debit account is known
+    const amountRatio = parsedAmount && parsedAmount.ratio ? parsedAmount.ratio : null;
+    // Without a currency ("transfer 5000 from acc1 to acc2") the configured default currency
+    // is read in its place, or else the one both accounts hold once they are resolved; either
+    // way it must be the currency of both accounts, even with FX
+    const currencyOmitted = omitsCurrency(tokens[amountStart + amountConsumed], accounts);
+    const currencyInferred = currencyOmitted && defaultCurrency === null;
+    const currencyToken = currencyOmitted ? defaultCurrency : tokens[amountStart + amountConsumed];
+    const shownCurrency = currencyToken !== null ? String(currencyToken).toUpperCase() : null;
+    // Keyword clauses (FROM/TO ...) start right after the currency token
+    const clauseStart = amountStart + amountConsumed + (currencyOmitted ? 0 : 1);
+
+    // Number words that do not form a valid number, or nothing left for the currency
+    if (
+      (parsedAmount !== null && parsedAmount.amount === null && amountRatio === null) ||
+      currencyToken === undefined
+    ) {
+      result = {
+        ...baseResponse,
+        status_reason: PaymentMessages.MALFORMED_INSTRUCTION,
+        status_code: 'SY03',
+        parse_error:
+          currencyToken === undefined
+            ? parseError('currency', amountStart + amountConsumed)
+            : parseError('amount', amountStart, amountConsumed),
+        accounts: [],
+      };
+      timeLogger.end('parse-instruction');
+      return { result };
+    }
+
+    // Amount validation: no sign, not zero, positive integer (after any k/m/bn multiplier)
+    const negativeAmount =
+      parsedAmount === null ? parseNegativeAmount(tokens[amountStart], decimalSeparator) : null;
+    if (negativeAmount !== null) {
+      result = {
+        ...baseResponse,
+        type,
+        amount: negativeAmount,
+        currency: shownCurrency,
+        status_reason: PaymentMessages.AMOUNT_MUST_NOT_BE_NEGATIVE,
+        status_code: 'AM03',
+        parse_error: parseError('amount', amountStart),
+        accounts: [],
+      };
+      timeLogger.end('parse-instruction');
+      return { result };
+    }
+    if (parsedAmount !== null && parsedAmount.amount === 0) {
+      result = {
+        ...baseResponse,
+        type,
+        amount: 0,
+        currency: shownCurrency,
+        status_reason: PaymentMessages.AMOUNT_MUST_BE_POSITIVE,
+        status_code: 'AM01',
+        parse_error: parseError('amount', amountStart, amountConsumed),
+        accounts: [],
+      };
+      timeLogger.end('parse-instruction');
+      return { result };
+    }
+    if (parsedAmount === null) {
+      result = {
+        ...baseResponse,
+        type,
+        amount: null,
+        currency: shownCurrency,
+        status_reason: PaymentMessages.AMOUNT_MUST_BE_POSITIVE_NUMBER,
+        status_code: 'AM01',
+        parse_error: parseError('amount', amountStart, amountConsumed),
+        accounts: [],
+      };
+      timeLogger.end('parse-instruction');
+      return { result };
+    }
+    let { amount } = parsedAmount;
+
+    // Currency: ISO code, word form ("naira", "rand") or symbol ("₦", "$"); see
+    // SUPPORTED_CURRENCIES and CURRENCY_SYMBOLS
+    const heldCurrencies = accounts.map((a) => String(a.currency || '').toUpperCase());
+    // An inferred currency could be any supported one the accounts hold
+    let currencyCandidates = currencyInferred
+      ? listHeldCurrencies(accounts)
+      : resolveCurrency(currencyToken, heldCurrencies);
+    const correctedCurrency =
+      fuzzy && !currencyInferred && currencyCandidates.length === 0
+        ? correctCurrencyWord(currencyToken)
+        : null;
+    if (correctedCurrency !== null) {
+      correctToken(amountStart + amountConsumed, correctedCurrency);
+      currencyCandidates = resolveCurrency(correctedCurrency, heldCurrencies);
+    }
+    let currency = currencyCandidates.length === 1 ? currencyCandidates[0] : null;
+    // Guesses made while parsing, scored into `confidence` once the accounts are resolved and
+    // reported as `warnings` on success
+    const confidenceSignals = corrections.map(() => 'fuzzy');
+    const amountLead = String(tokens[amountStart])[0];
+    if (amountRatio !== null) confidenceSignals.push('balance_share');
+    else if (amountLead < '0' || amountLead > '9') confidenceSignals.push('word_amount');
+    if (currencyCandidates.length > 1 || currencyInferred) {
+      confidenceSignals.push('currency_inferred');
+    }
+    if (currencyCandidates.length === 0) {
+      // Not an ISO 4217 code (CU04) or not a supported one (CU02)
+      result = {
+        ...baseResponse,
+        type,
+        amount,
+        currency: shownCurrency,
+        status_reason: isUnknownCurrencyCode(currencyToken)
+          ? `${PaymentMessages.UNKNOWN_CURRENCY_CODE}: ${String(currencyToken).toUpperCase()}`
+          : PaymentMessages.UNSUPPORTED_CURRENCY,
+        status_code: isUnknownCurrencyCode(currencyToken) ? 'CU04' : 'CU02',
+        parse_error: parseError('currency', amountStart + amountConsumed),
+        accounts: [],
+      };
+      timeLogger.end('parse-instruction');
+      return { result };
+    }
+    // A symbol several held currencies share ("Sh" with both KES and UGX accounts)
+    if (currencyCandidates.length > 1 && isCurrencySymbol(currencyToken)) {
+      result = {
+        ...baseResponse,
+        type,
+        amount,
+        currency: null,
+        status_reason: `${PaymentMessages.AMBIGUOUS_CURRENCY_SYMBOL}: "${currencyToken}" could be ${currencyCandidates.join(' or ')}`,
+        status_code: 'CU06',
+        parse_error: parseError('currency', amountStart + amountConsumed),
+        accounts: [],
+      };
+      timeLogger.end('parse-instruction');
+      return { result };
+    }
+    // Decimal places must fit the currency's minor units ("10.005 USD" does not)
+    // (an inferred currency is checked once it is known)
+    if (
+      amountRatio === null &&
+      !currencyInferred &&
+      !currencyCandidates.every((c) => fitsMinorUnits(amount, c))
+    ) {
+      result = {
+        ...baseResponse,
+        type,
+        amount,
+        currency,
+        status_reason: PaymentMessages.AMOUNT_TOO_MANY_DECIMALS,
+        status_code: 'CU03',
+        parse_error: parseError('amount', amountStart, amountConsumed),
+        accounts: [],
+      };
+      timeLogger.end('parse-instruction');
+      return { result };
+    }
+
+    // Now parse the rest depending on type (DEBIT vs CREDIT)
     // We must enforce keyword order exactly per spec.
     let debitAccountId = null;
     let creditAccountId = null;
-    let executeBy = null; // string or null
-    const parsedDate = null; // {year,month,day} or null
+    // Account references as written; "***4821" / "ending in 4821" span extra tokens
+    let debitRef = null;
+    let creditRef = null;
+    // Token index of each account reference, for parse_error offsets
+    let debitIndex = null;
+    let creditIndex = null;
+    let executeBy = null; // string (ON clause), Unix timestamp (SCHEDULE) or null
+    let dateClauseStart = tokens.length; // first token after the last account id
+    // Trailing "for <text>" / "ref: <text>" after the last account id; found per form below
+    let narration = { start: tokens.length, text: '' };
 
-    if (type === 'DEBIT') {
+    if (verb === 'DEBIT') {
       // Expect sequence: DEBIT [amount] [currency] FROM ACCOUNT [acct] FOR CREDIT TO ACCOUNT [acct] [ON date]
       // Find 'from' starting search from index 3
-      const iFrom = lowerTokens.indexOf('from', 3);
+      const iFrom = lowerTokens.indexOf('from', clauseStart);
       if (iFrom === -1) {
         result = {
           ...baseResponse,
@@ -270,10 +1280,11 @@ async function paymentInstructions(serviceData, options = {}) {
           currency,
           status_reason: PaymentMessages.MISSING_REQUIRED_KEYWORD,
           status_code: 'SY01',
+          parse_error: parseError('debit', clauseStart),
           accounts: [],
         };
         timeLogger.end('parse-instruction');
-        return result;
+        return { result };
       }
       // Check ACCOUNT
       if (iFrom + 1 >= lowerTokens.length || lowerTokens[iFrom + 1] !== 'account') {
@@ -284,10 +1295,11 @@ async function paymentInstructions(serviceData, options = {}) {
           currency,
           status_reason: PaymentMessages.INVALID_KEYWORD_ORDER,
           status_code: 'SY02',
+          parse_error: parseError('debit', iFrom + 1),
           accounts: [],
         };
         timeLogger.end('parse-instruction');
-        return result;
+        return { result };
       }
       if (iFrom + 2 >= tokens.length) {
         result = {
@@ -297,15 +1309,18 @@ async function paymentInstructions(serviceData, options = {}) {
           currency,
           status_reason: PaymentMessages.MALFORMED_INSTRUCTION,
           status_code: 'SY03',
+          parse_error: parseError('debit', iFrom + 2),
           accounts: [],
         };
         timeLogger.end('parse-instruction');
-        return result;
+        return { result };
       }
-      debitAccountId = tokens[iFrom + 2]; // account IDs are case-sensitive
+      debitIndex = iFrom + 2;
+      debitRef = parseAccountReference(tokens, debitIndex, accounts);
+      debitAccountId = debitRef.token; // account IDs are case-sensitive
 
       // find 'for' after that
-      const iFor = lowerTokens.indexOf('for', iFrom + 3);
+      const iFor = lowerTokens.indexOf('for', iFrom + 2 + debitRef.consumed);
       if (iFor === -1) {
         result = {
           ...baseResponse,
@@ -314,10 +1329,11 @@ async function paymentInstructions(serviceData, options = {}) {
           currency,
           status_reason: PaymentMessages.MISSING_REQUIRED_KEYWORD,
           status_code: 'SY01',
+          parse_error: parseError('credit', iFrom + 2 + debitRef.consumed),
           accounts: [],
         };
         timeLogger.end('parse-instruction');
-        return result;
+        return { result };
       }
       // expect 'credit' 'to' 'account' [id]
       if (iFor + 1 >= lowerTokens.length || lowerTokens[iFor + 1] !== 'credit') {
@@ -328,10 +1344,11 @@ async function paymentInstructions(serviceData, options = {}) {
           currency,
           status_reason: PaymentMessages.INVALID_KEYWORD_ORDER,
           status_code: 'SY02',
+          parse_error: parseError('credit', iFor + 1),
           accounts: [],
         };
         timeLogger.end('parse-instruction');
-        return result;
+        return { result };
       }
       if (iFor + 2 >= lowerTokens.length || lowerTokens[iFor + 2] !== 'to') {
         result = {
@@ -341,10 +1358,11 @@ async function paymentInstructions(serviceData, options = {}) {
           currency,
           status_reason: PaymentMessages.INVALID_KEYWORD_ORDER,
           status_code: 'SY02',
+          parse_error: parseError('credit', iFor + 2),
           accounts: [],
         };
         timeLogger.end('parse-instruction');
-        return result;
+        return { result };
       }
       if (iFor + 3 >= lowerTokens.length || lowerTokens[iFor + 3] !== 'account') {
         result = {
@@ -354,10 +1372,11 @@ async function paymentInstructions(serviceData, options = {}) {
           currency,
           status_reason: PaymentMessages.INVALID_KEYWORD_ORDER,
           status_code: 'SY02',
+          parse_error: parseError('credit', iFor + 3),
           accounts: [],
         };
         timeLogger.end('parse-instruction');
-        return result;
+        return { result };
       }
       if (iFor + 4 >= tokens.length) {
         result = {
@@ -367,17 +1386,22 @@ async function paymentInstructions(serviceData, options = {}) {
           currency,
           status_reason: PaymentMessages.MALFORMED_INSTRUCTION,
           status_code: 'SY03',
+          parse_error: parseError('credit', iFor + 4),
           accounts: [],
         };
         timeLogger.end('parse-instruction');
-        return result;
+        return { result };
       }
-      creditAccountId = tokens[iFor + 4];
+      creditIndex = iFor + 4;
+      creditRef = parseAccountReference(tokens, creditIndex, accounts);
+      creditAccountId = creditRef.token;
+      dateClauseStart = iFor + 4 + creditRef.consumed;
+      narration = parseNarration(tokens, dateClauseStart);
 
-      // optional ON clause after iFor + 5
-      const iOn = lowerTokens.indexOf('on', iFor + 5);
-      if (iOn !== -1) {
-        if (iOn + 1 >= tokens.length) {
+      // optional ON clause after the account (SCHEDULE dates are read further below)
+      const iOn = lowerTokens.slice(0, narration.start).indexOf('on', dateClauseStart);
+      if (iOn !== -1 && !scheduled) {
+        if (iOn + 1 >= narration.start) {
           result = {
             ...baseResponse,
             type,
@@ -390,164 +1414,751 @@ async function paymentInstructions(serviceData, options = {}) {
             accounts: [],
           };
           timeLogger.end('parse-instruction');
-          return result;
+          return { result };
         }
         executeBy = tokens[iOn + 1];
       }
-    } else {
-      // CREDIT format
-      // Expect: CREDIT [amount] [currency] TO ACCOUNT [acct] FOR DEBIT FROM ACCOUNT [acct] [ON date]
-      const iTo = lowerTokens.indexOf('to', 3);
-      if (iTo === -1) {
-        result = {
-          ...baseResponse,
-          type,
-          amount,
-          currency,
-          status_reason: PaymentMessages.MISSING_REQUIRED_KEYWORD,
-          status_code: 'SY01',
-          accounts: [],
-        };
-        timeLogger.end('parse-instruction');
-        return result;
+    } else if (verb === 'CREDIT') {
+      // CREDIT format
+      // Expect: CREDIT [amount] [currency] TO ACCOUNT [acct] FOR DEBIT FROM ACCOUNT [acct] [ON date]
+      const iTo = lowerTokens.indexOf('to', clauseStart);
+      if (iTo === -1) {
+        result = {
+          ...baseResponse,
+          type,
+          amount,
+          currency,
+          status_reason: PaymentMessages.MISSING_REQUIRED_KEYWORD,
+          status_code: 'SY01',
+          parse_error: parseError('credit', clauseStart),
+          accounts: [],
+        };
+        timeLogger.end('parse-instruction');
+        return { result };
+      }
+      if (iTo + 1 >= lowerTokens.length || lowerTokens[iTo + 1] !== 'account') {
+        result = {
+          ...baseResponse,
+          type,
+          amount,
+          currency,
+          status_reason: PaymentMessages.INVALID_KEYWORD_ORDER,
+          status_code: 'SY02',
+          parse_error: parseError('credit', iTo + 1),
+          accounts: [],
+        };
+        timeLogger.end('parse-instruction');
+        return { result };
+      }
+      if (iTo + 2 >= tokens.length) {
+        result = {
+          ...baseResponse,
+          type,
+          amount,
+          currency,
+          status_reason: PaymentMessages.MALFORMED_INSTRUCTION,
+          status_code: 'SY03',
+          parse_error: parseError('credit', iTo + 2),
+          accounts: [],
+        };
+        timeLogger.end('parse-instruction');
+        return { result };
+      }
+      creditIndex = iTo + 2;
+      creditRef = parseAccountReference(tokens, creditIndex, accounts);
+      creditAccountId = creditRef.token;
+
+      // find 'for' after that
+      const iFor = lowerTokens.indexOf('for', iTo + 2 + creditRef.consumed);
+      if (iFor === -1) {
+        result = {
+          ...baseResponse,
+          type,
+          amount,
+          currency,
+          status_reason: PaymentMessages.MISSING_REQUIRED_KEYWORD,
+          status_code: 'SY01',
+          parse_error: parseError('debit', iTo + 2 + creditRef.consumed),
+          accounts: [],
+        };
+        timeLogger.end('parse-instruction');
+        return { result };
+      }
+      // expect 'debit' 'from' 'account' [id]
+      if (iFor + 1 >= lowerTokens.length || lowerTokens[iFor + 1] !== 'debit') {
+        result = {
+          ...baseResponse,
+          type,
+          amount,
+          currency,
+          status_reason: PaymentMessages.INVALID_KEYWORD_ORDER,
+          status_code: 'SY02',
+          parse_error: parseError('debit', iFor + 1),
+          accounts: [],
+        };
+        timeLogger.end('parse-instruction');
+        return { result };
+      }
+      if (iFor + 2 >= lowerTokens.length || lowerTokens[iFor + 2] !== 'from') {
+        result = {
+          ...baseResponse,
+          type,
+          amount,
+          currency,
+          status_reason: PaymentMessages.INVALID_KEYWORD_ORDER,
+          status_code: 'SY02',
+          parse_error: parseError('debit', iFor + 2),
+          accounts: [],
+        };
+        timeLogger.end('parse-instruction');
+        return { result };
+      }
+      if (iFor + 3 >= lowerTokens.length || lowerTokens[iFor + 3] !== 'account') {
+        result = {
+          ...baseResponse,
+          type,
+          amount,
+          currency,
+          status_reason: PaymentMessages.INVALID_KEYWORD_ORDER,
+          status_code: 'SY02',
+          parse_error: parseError('debit', iFor + 3),
+          accounts: [],
+        };
+        timeLogger.end('parse-instruction');
+        return { result };
+      }
+      if (iFor + 4 >= tokens.length) {
+        result = {
+          ...baseResponse,
+          type,
+          amount,
+          currency,
+          status_reason: PaymentMessages.MALFORMED_INSTRUCTION,
+          status_code: 'SY03',
+          parse_error: parseError('debit', iFor + 4),
+          accounts: [],
+        };
+        timeLogger.end('parse-instruction');
+        return { result };
+      }
+      debitIndex = iFor + 4;
+      debitRef = parseAccountReference(tokens, debitIndex, accounts);
+      debitAccountId = debitRef.token;
+      dateClauseStart = iFor + 4 + debitRef.consumed;
+      narration = parseNarration(tokens, dateClauseStart);
+
+      // optional ON clause after the account (SCHEDULE dates are read further below)
+      const iOn = lowerTokens.slice(0, narration.start).indexOf('on', dateClauseStart);
+      if (iOn !== -1 && !scheduled) {
+        if (iOn + 1 >= narration.start) {
+          result = {
+            ...baseResponse,
+            type,
+            amount,
+            currency,
+            debit_account: debitAccountId,
+            credit_account: creditAccountId,
+            status_reason: PaymentMessages.INVALID_DATE_FORMAT,
+            status_code: 'DT01',
+            accounts: [],
+          };
+          timeLogger.end('parse-instruction');
+          return { result };
+        }
+        executeBy = tokens[iOn + 1];
+      }
+    } else {
+      // TRANSFER format (also under SCHEDULE and STANDING ORDER)
+      // Expect: TRANSFER [OF] [amount] [currency] FROM [ACCOUNT] [acct] TO [ACCOUNT] [acct] [date]
+      const iFrom = lowerTokens.indexOf('from', clauseStart);
+      if (iFrom === -1) {
+        result = {
+          ...baseResponse,
+          type,
+          amount,
+          currency,
+          status_reason: PaymentMessages.MISSING_REQUIRED_KEYWORD,
+          status_code: 'SY01',
+          parse_error: parseError('debit', clauseStart),
+          accounts: [],
+        };
+        timeLogger.end('parse-instruction');
+        return { result };
+      }
+      // ACCOUNT keywords are optional in this form
+      const iDebitId = lowerTokens[iFrom + 1] === 'account' ? iFrom + 2 : iFrom + 1;
+      if (iDebitId >= tokens.length) {
+        result = {
+          ...baseResponse,
+          type,
+          amount,
+          currency,
+          status_reason: PaymentMessages.MALFORMED_INSTRUCTION,
+          status_code: 'SY03',
+          parse_error: parseError('debit', iDebitId),
+          accounts: [],
+        };
+        timeLogger.end('parse-instruction');
+        return { result };
+      }
+      debitIndex = iDebitId;
+      debitRef = parseAccountReference(tokens, debitIndex, accounts);
+      debitAccountId = debitRef.token;
+      const iTo = iDebitId + debitRef.consumed;
+
+      if (iTo >= lowerTokens.length) {
+        result = {
+          ...baseResponse,
+          type,
+          amount,
+          currency,
+          status_reason: PaymentMessages.MISSING_REQUIRED_KEYWORD,
+          status_code: 'SY01',
+          parse_error: parseError('credit', iTo),
+          accounts: [],
+        };
+        timeLogger.end('parse-instruction');
+        return { result };
+      }
+      if (lowerTokens[iTo] !== 'to') {
+        result = {
+          ...baseResponse,
+          type,
+          amount,
+          currency,
+          status_reason: PaymentMessages.INVALID_KEYWORD_ORDER,
+          status_code: 'SY02',
+          parse_error: parseError('credit', iTo),
+          accounts: [],
+        };
+        timeLogger.end('parse-instruction');
+        return { result };
+      }
+      const iCreditId = lowerTokens[iTo + 1] === 'account' ? iTo + 2 : iTo + 1;
+      if (iCreditId >= tokens.length) {
+        result = {
+          ...baseResponse,
+          type,
+          amount,
+          currency,
+          status_reason: PaymentMessages.MALFORMED_INSTRUCTION,
+          status_code: 'SY03',
+          parse_error: parseError('credit', iCreditId),
+          accounts: [],
+        };
+        timeLogger.end('parse-instruction');
+        return { result };
+      }
+      creditIndex = iCreditId;
+      creditRef = parseAccountReference(tokens, creditIndex, accounts);
+      creditAccountId = creditRef.token;
+      dateClauseStart = iCreditId + creditRef.consumed;
+      narration = parseNarration(tokens, dateClauseStart);
+
+      // optional ON clause after the account, as for DEBIT and CREDIT
+      const iOn = lowerTokens.slice(0, narration.start).indexOf('on', dateClauseStart);
+      if (iOn !== -1 && !scheduled && !standing) {
+        if (iOn + 1 >= narration.start) {
+          result = {
+            ...baseResponse,
+            type,
+            amount,
+            currency,
+            debit_account: debitAccountId,
+            credit_account: creditAccountId,
+            status_reason: PaymentMessages.INVALID_DATE_FORMAT,
+            status_code: 'DT01',
+            accounts: [],
+          };
+          timeLogger.end('parse-instruction');
+          return { result };
+        }
+        executeBy = tokens[iOn + 1];
+      }
+    }
+
+    baseResponse.narration = narration.text;
+
+    // Optional alias map (alias -> account id): names ("salary") win over exact ids and are
+    // matched case-insensitively; responses always carry the canonical id
+    const aliases =
+      data.aliases && typeof data.aliases === 'object' && !Array.isArray(data.aliases)
+        ? data.aliases
+        : null;
+    const knownNames = aliases !== null ? Object.keys(aliases) : [];
+    if (aliases !== null) {
+      const debitAlias = resolveAccountAlias(debitAccountId, aliases);
+      const creditAlias = resolveAccountAlias(creditAccountId, aliases);
+      let ambiguousToken = null;
+      let ambiguousIds = null;
+      if (debitAlias && debitAlias.ambiguous) {
+        ambiguousToken = debitAccountId;
+        ambiguousIds = debitAlias.ambiguous;
+      } else if (creditAlias && creditAlias.ambiguous) {
+        ambiguousToken = creditAccountId;
+        ambiguousIds = creditAlias.ambiguous;
+      }
+      if (ambiguousToken !== null) {
+        const candidates = `"${ambiguousToken}" could be ${ambiguousIds.join(' or ')}`;
+        result = {
+          ...baseResponse,
+          type,
+          amount,
+          currency,
+          debit_account: debitAccountId,
+          credit_account: creditAccountId,
+          status_reason: `${PaymentMessages.AMBIGUOUS_ACCOUNT_ALIAS}: ${candidates}`,
+          status_code: 'AC05',
+          candidates: ambiguousIds,
+          accounts: [],
+        };
+        timeLogger.end('parse-instruction');
+        return { result };
+      }
+      if (debitAlias) {
+        debitAccountId = debitAlias.id;
+        confidenceSignals.push('alias');
+      }
+      if (creditAlias) {
+        creditAccountId = creditAlias.id;
+        confidenceSignals.push('alias');
+      }
+    }
+
+    // Trailing-digit references ("***4821", "ending in 4821") must match exactly one account
+    let missingRef = null;
+    let ambiguousRef = null;
+    let suffixMatches = [];
+    if (debitRef && debitRef.suffix !== null) {
+      suffixMatches = findAccountsBySuffix(accounts, debitRef.suffix);
+      if (suffixMatches.length === 1) {
+        debitAccountId = suffixMatches[0];
+        confidenceSignals.push('partial_account');
+      } else if (suffixMatches.length === 0) missingRef = debitRef;
+      else ambiguousRef = debitRef;
+    }
+    if (!missingRef && !ambiguousRef && creditRef && creditRef.suffix !== null) {
+      suffixMatches = findAccountsBySuffix(accounts, creditRef.suffix);
+      if (suffixMatches.length === 1) {
+        creditAccountId = suffixMatches[0];
+        confidenceSignals.push('partial_account');
+      } else if (suffixMatches.length === 0) missingRef = creditRef;
+      else ambiguousRef = creditRef;
+    }
+    if (ambiguousRef) {
+      const matches = `"${ambiguousRef.token}" matches ${suffixMatches.join(', ')}`;
+      result = {
+        ...baseResponse,
+        type,
+        amount,
+        currency,
+        debit_account: debitAccountId,
+        credit_account: creditAccountId,
+        status_reason: `${PaymentMessages.AMBIGUOUS_ACCOUNT_REFERENCE}: ${matches}`,
+        status_code: 'AC06',
+        candidates: suffixMatches,
+        accounts: [],
+      };
+      timeLogger.end('parse-instruction');
+      return { result };
+    }
+    if (missingRef) {
+      const noMatch = `no account ending ${missingRef.suffix}`;
+      result = {
+        ...baseResponse,
+        type,
+        amount,
+        currency,
+        debit_account: debitAccountId,
+        credit_account: creditAccountId,
+        status_reason: `${PaymentMessages.ACCOUNT_NOT_FOUND}: ${noMatch}`,
+        status_code: 'AC03',
+        accounts: [],
+      };
+      timeLogger.end('parse-instruction');
+      return { result };
+    }
+
+    // Opt-in case-insensitive ids: "ACC1" finds "acc1" and the response carries "acc1"
+    if (ignoreCase) {
+      const debitMatches = findAccountsIgnoringCase(accounts, debitAccountId);
+      const creditMatches = findAccountsIgnoringCase(accounts, creditAccountId);
+      let ambiguousToken = null;
+      let ambiguousIds = null;
+      if (debitMatches.length > 1) {
+        ambiguousToken = debitAccountId;
+        ambiguousIds = debitMatches;
+      } else if (creditMatches.length > 1) {
+        ambiguousToken = creditAccountId;
+        ambiguousIds = creditMatches;
+      }
+      if (ambiguousToken !== null) {
+        const candidates = `"${ambiguousToken}" could be ${ambiguousIds.join(' or ')}`;
+        result = {
+          ...baseResponse,
+          type,
+          amount,
+          currency,
+          debit_account: debitAccountId,
+          credit_account: creditAccountId,
+          status_reason: `${PaymentMessages.AMBIGUOUS_ACCOUNT_CASE}: ${candidates}`,
+          status_code: 'AC05',
+          candidates: ambiguousIds,
+          accounts: [],
+        };
+        timeLogger.end('parse-instruction');
+        return { result };
+      }
+      if (debitMatches.length === 1 && debitMatches[0] !== debitAccountId) {
+        debitAccountId = debitMatches[0];
+        confidenceSignals.push('case_insensitive');
+      }
+      if (creditMatches.length === 1 && creditMatches[0] !== creditAccountId) {
+        creditAccountId = creditMatches[0];
+        confidenceSignals.push('case_insensitive');
+      }
+    }
+
+    baseResponse.confidence = scoreConfidence(confidenceSignals);
+    if (strict && confidenceSignals.length > 0) {
+      result = {
+        ...baseResponse,
+        type,
+        amount,
+        currency,
+        debit_account: debitAccountId,
+        credit_account: creditAccountId,
+        status_reason: `${PaymentMessages.STRICT_MODE_GUESS}: ${describeGuesses(
+          confidenceSignals,
+          PaymentMessages.STRICT_MODE_GUESSES
+        )}`,
+        status_code: 'SY06',
+        accounts: [],
+      };
+      timeLogger.end('parse-instruction');
+      return { result };
+    }
+    const correctionReason = corrections
+      .map((c) => `; ${PaymentMessages.KEYWORD_CORRECTED} "${c.from}" to ${c.to}`)
+      .join('');
+
+    // Validate account ID formats
+    if (!isValidAccountId(debitAccountId)) {
+      result = {
+        ...baseResponse,
+        type,
+        amount,
+        currency,
+        debit_account: debitAccountId,
+        credit_account: creditAccountId,
+        status_reason: PaymentMessages.INVALID_ACCOUNT_ID_FORMAT,
+        status_code: 'AC04',
+        parse_error: parseError('debit', debitIndex, debitRef.consumed),
+        accounts: [],
+      };
+      timeLogger.end('parse-instruction');
+      return { result };
+    }
+    if (!isValidAccountId(creditAccountId)) {
+      result = {
+        ...baseResponse,
+        type,
+        amount,
+        currency,
+        debit_account: debitAccountId,
+        credit_account: creditAccountId,
+        status_reason: PaymentMessages.INVALID_ACCOUNT_ID_FORMAT,
+        status_code: 'AC04',
+        parse_error: parseError('credit', creditIndex, creditRef.consumed),
+        accounts: [],
+      };
+      timeLogger.end('parse-instruction');
+      return { result };
+    }
+    // Then the caller's own id format, so a malformed reference is not reported as unknown
+    const malformed = [
+      { id: debitAccountId, side: 'debit', index: debitIndex, ref: debitRef },
+      { id: creditAccountId, side: 'credit', index: creditIndex, ref: creditRef },
+    ].find((s) => !matchesIdFormat(s.id));
+    if (malformed) {
+      result = {
+        ...baseResponse,
+        type,
+        amount,
+        currency,
+        debit_account: debitAccountId,
+        credit_account: creditAccountId,
+        status_reason: `${PaymentMessages.MALFORMED_ACCOUNT_REFERENCE}: ${malformed.id}`,
+        status_code: 'AC04',
+        parse_error: parseError(malformed.side, malformed.index, malformed.ref.consumed),
+        accounts: [],
+      };
+      timeLogger.end('parse-instruction');
+      return { result };
+    }
+
+    // Validate and parse date if present
+    let parsedDateObj = null;
+    let recurrenceFields = {};
+    if (scheduled || standing) {
+      // Everything after the last account id is the date (SCHEDULE), or the recurrence with an
+      // optional STARTING <date> (STANDING_ORDER); the date may start with ON
+      let dateWords = tokens.slice(dateClauseStart, narration.start);
+      let recurrenceWords = [];
+      let dateRequired = true;
+      if (standing) {
+        const iStarting = lowerTokens
+          .slice(0, narration.start)
+          .indexOf('starting', dateClauseStart);
+        recurrenceWords = iStarting === -1 ? dateWords : tokens.slice(dateClauseStart, iStarting);
+        dateWords = iStarting === -1 ? [] : tokens.slice(iStarting + 1, narration.start);
+        dateRequired = iStarting !== -1;
+      }
+      if (dateWords.length > 0 && dateWords[0].toLowerCase() === 'on') {
+        dateWords = dateWords.slice(1);
+      }
+      // A time of day ends the date, or the recurrence when there is no STARTING date
+      const timed = splitTimeOfDay(dateRequired ? dateWords : recurrenceWords);
+      if (dateRequired) dateWords = timed.words;
+      else recurrenceWords = timed.words;
+      // The time and "today" are read in the timezone, else at options.utcOffsetMinutes (UTC
+      // by default)
+      const offsetAt = (ms) =>
+        timeZone !== null ? zoneOffsetSeconds(timeZone, ms) : (options.utcOffsetMinutes || 0) * 60;
+      const localNow = new Date(now.getTime() + offsetAt(now.getTime()) * 1000);
+      const relative = dateWords.length > 0 ? parseRelativeDate(dateWords, localNow) : null;
+      let sd = null;
+      if (relative !== null) {
+        // Relative dates run from the start of that UTC day
+        const timestamp = Date.UTC(relative.year, relative.month - 1, relative.day) / 1000;
+        sd = { ...relative, timestamp, hasTime: false, valid: true };
+      } else if (dateWords.length === 1) {
+        sd = parseAbsoluteDate(dateWords[0], { dayFirst: options.dayFirst });
+      }
+      // A named day ("on payday", "on the last business day of the month") is its next
+      // occurrence from today
+      const localToday = {
+        year: localNow.getUTCFullYear(),
+        month: localNow.getUTCMonth() + 1,
+        day: localNow.getUTCDate(),
+      };
+      const dateAnchor =
+        sd === null && dateWords.length > 0
+          ? matchScheduleAnchor(dateWords, options.scheduleAnchors)
+          : null;
+      const anchored =
+        dateAnchor !== null && dateAnchor.rule !== null
+          ? nextAnchorDate(dateAnchor.rule, localToday)
+          : null;
+      if (anchored !== null) {
+        const timestamp = Date.UTC(anchored.year, anchored.month - 1, anchored.day) / 1000;
+        sd = { ...anchored, timestamp, hasTime: false, valid: true };
       }
-      if (iTo + 1 >= lowerTokens.length || lowerTokens[iTo + 1] !== 'account') {
+      if (sd === null && dateRequired) {
+        let reason = PaymentMessages.INVALID_SCHEDULE_DATE;
+        if (dateAnchor !== null) reason = describeAnchorFailure(dateAnchor);
         result = {
           ...baseResponse,
           type,
           amount,
           currency,
-          status_reason: PaymentMessages.INVALID_KEYWORD_ORDER,
-          status_code: 'SY02',
+          debit_account: debitAccountId,
+          credit_account: creditAccountId,
+          status_reason: reason,
+          status_code: 'DT01',
           accounts: [],
         };
         timeLogger.end('parse-instruction');
-        return result;
+        return { result };
       }
-      if (iTo + 2 >= tokens.length) {
+      if (sd !== null && !sd.valid) {
         result = {
           ...baseResponse,
           type,
           amount,
           currency,
-          status_reason: PaymentMessages.MALFORMED_INSTRUCTION,
-          status_code: 'SY03',
+          debit_account: debitAccountId,
+          credit_account: creditAccountId,
+          status_reason: `${PaymentMessages.INVALID_CALENDAR_DATE}: ${dateWords[0]}`,
+          status_code: 'DT01',
           accounts: [],
         };
         timeLogger.end('parse-instruction');
-        return result;
+        return { result };
       }
-      creditAccountId = tokens[iTo + 2];
-
-      // find 'for' after that
-      const iFor = lowerTokens.indexOf('for', iTo + 3);
-      if (iFor === -1) {
+      // A date that carries its own time takes no second one
+      if (timed.time !== null && (!timed.time.valid || (sd !== null && sd.hasTime))) {
         result = {
           ...baseResponse,
           type,
           amount,
           currency,
-          status_reason: PaymentMessages.MISSING_REQUIRED_KEYWORD,
-          status_code: 'SY01',
+          debit_account: debitAccountId,
+          credit_account: creditAccountId,
+          status_reason: `${PaymentMessages.INVALID_TIME_OF_DAY}: ${timed.text}`,
+          status_code: 'DT01',
           accounts: [],
         };
         timeLogger.end('parse-instruction');
-        return result;
+        return { result };
       }
-      // expect 'debit' 'from' 'account' [id]
-      if (iFor + 1 >= lowerTokens.length || lowerTokens[iFor + 1] !== 'debit') {
-        result = {
-          ...baseResponse,
-          type,
-          amount,
-          currency,
-          status_reason: PaymentMessages.INVALID_KEYWORD_ORDER,
-          status_code: 'SY02',
-          accounts: [],
+      if (standing) {
+        // The first run is the first matching day on or after the start date (default today)
+        const reference =
+          sd === null ? localNow : new Date(Date.UTC(sd.year, sd.month - 1, sd.day));
+        let recurrence = parseRecurrence(recurrenceWords, reference);
+        // "every payday" runs monthly on the named day
+        const everyAnchor =
+          recurrence === null && String(recurrenceWords[0]).toLowerCase() === 'every'
+            ? matchScheduleAnchor(recurrenceWords.slice(1), options.scheduleAnchors)
+            : null;
+        if (everyAnchor !== null && everyAnchor.rule !== null) {
+          const first = nextAnchorDate(everyAnchor.rule, {
+            year: reference.getUTCFullYear(),
+            month: reference.getUTCMonth() + 1,
+            day: reference.getUTCDate(),
+          });
+          if (first !== null) {
+            const anchor = { name: everyAnchor.name, rule: everyAnchor.rule };
+            recurrence = { unit: 'month', count: 1, weekday: null, anchor, first };
+          }
+        }
+        if (recurrence === null) {
+          let reason = PaymentMessages.INVALID_RECURRENCE;
+          if (everyAnchor !== null) reason = describeAnchorFailure(everyAnchor);
+          result = {
+            ...baseResponse,
+            type,
+            amount,
+            currency,
+            debit_account: debitAccountId,
+            credit_account: creditAccountId,
+            status_reason: reason,
+            status_code: 'DT03',
+            accounts: [],
+          };
+          timeLogger.end('parse-instruction');
+          return { result };
+        }
+        const f = recurrence.first;
+        if (sd === null || f.year !== sd.year || f.month !== sd.month || f.day !== sd.day) {
+          const timestamp = Date.UTC(f.year, f.month - 1, f.day) / 1000;
+          sd = { ...f, timestamp, hasTime: false, valid: true };
+        }
+        recurrenceFields = {
+          recurrence: {
+            unit: recurrence.unit,
+            count: recurrence.count,
+            weekday: recurrence.weekday,
+            ...(recurrence.anchor ? { anchor: recurrence.anchor } : {}),
+          },
         };
-        timeLogger.end('parse-instruction');
-        return result;
       }
-      if (iFor + 2 >= lowerTokens.length || lowerTokens[iFor + 2] !== 'from') {
+      // In a timezone a date without a time starts at local midnight. The offset is the one
+      // in force at that local time, which a first guess can miss across a DST change
+      if (timed.time !== null || (timeZone !== null && !sd.hasTime)) {
+        const dayStart = Date.UTC(sd.year, sd.month - 1, sd.day) / 1000;
+        const localSeconds = dayStart + (timed.time !== null ? timed.time.seconds : 0);
+        const guess = localSeconds - offsetAt(localSeconds * 1000);
+        const timestamp = localSeconds - offsetAt(guess * 1000);
+        sd = { ...sd, timestamp, hasTime: timed.time !== null };
+      }
+      // execute_by for SCHEDULE / STANDING_ORDER is a Unix timestamp (seconds)
+      executeBy = sd.timestamp;
+      const inPast = sd.hasTime
+        ? sd.timestamp * 1000 < now.getTime()
+        : compareDateToTodayUTC(sd, localNow) === -1;
+      if (inPast) {
         result = {
           ...baseResponse,
           type,
           amount,
           currency,
-          status_reason: PaymentMessages.INVALID_KEYWORD_ORDER,
-          status_code: 'SY02',
+          debit_account: debitAccountId,
+          credit_account: creditAccountId,
+          execute_by: executeBy,
+          status_reason: PaymentMessages.SCHEDULE_DATE_IN_PAST,
+          status_code: 'DT02',
           accounts: [],
         };
         timeLogger.end('parse-instruction');
-        return result;
+        return { result };
       }
-      if (iFor + 3 >= lowerTokens.length || lowerTokens[iFor + 3] !== 'account') {
+      parsedDateObj = sd;
+    } else if (executeBy !== null && executeBy !== undefined) {
+      const pd = parseDateYYYYMMDD(String(executeBy));
+      if (pd === null) {
         result = {
           ...baseResponse,
           type,
           amount,
           currency,
-          status_reason: PaymentMessages.INVALID_KEYWORD_ORDER,
-          status_code: 'SY02',
+          debit_account: debitAccountId,
+          credit_account: creditAccountId,
+          execute_by: executeBy,
+          status_reason: PaymentMessages.INVALID_DATE_FORMAT,
+          status_code: 'DT01',
           accounts: [],
         };
         timeLogger.end('parse-instruction');
-        return result;
+        return { result };
       }
-      if (iFor + 4 >= tokens.length) {
+      parsedDateObj = pd;
+    }
+
+    // Find accounts in provided accounts array preserving original order
+    const debitEntry = findAccount(accounts, debitAccountId);
+    const creditEntry = findAccount(accounts, creditAccountId);
+
+    if (amountRatio !== null) {
+      // A balance share has no base until the debit account is resolved
+      if (!debitEntry) {
         result = {
           ...baseResponse,
           type,
-          amount,
           currency,
+          debit_account: debitAccountId,
+          credit_account: creditAccountId,
+          execute_by: executeBy || null,
           status_reason: PaymentMessages.MALFORMED_INSTRUCTION,
           status_code: 'SY03',
           accounts: [],
         };
         timeLogger.end('parse-instruction');
-        return result;
+        return { result };
       }
-      debitAccountId = tokens[iFor + 4];
-
-      // optional ON clause after iFor + 5
-      const iOn = lowerTokens.indexOf('on', iFor + 5);
-      if (iOn !== -1) {
-        if (iOn + 1 >= tokens.length) {
-          result = {
-            ...baseResponse,
-            type,
-            amount,
-            currency,
-            debit_account: debitAccountId,
-            credit_account: creditAccountId,
-            status_reason: PaymentMessages.INVALID_DATE_FORMAT,
-            status_code: 'DT01',
-            accounts: [],
-          };
-          timeLogger.end('parse-instruction');
-          return result;
-        }
-        executeBy = tokens[iOn + 1];
+      const shareCurrency = debitEntry.account.currency;
+      const shareRounding = options.roundingPolicy || ROUNDING_POLICY;
+      if (parsedAmount.sweep) {
+        // A sweep leaves nothing behind: the amount is the balance less its own fee, unless a
+        // fee account bears the fee
+        amount = largestAffordableAmount(
+          toMinorUnits(Number(debitEntry.account.balance), shareCurrency),
+          type,
+          feeSource !== null ? {} : options.feePolicy || FEE_POLICY,
+          shareCurrency,
+          shareRounding
+        );
+      } else {
+        amount = resolveRatioAmount(
+          debitEntry.account.balance,
+          amountRatio,
+          shareCurrency,
+          shareRounding
+        );
+      }
+      if (isRatioRounded(debitEntry.account.balance, amountRatio, debitEntry.account.currency)) {
+        confidenceSignals.push('amount_rounded');
       }
     }
 
-    // Validate account ID formats
-    if (!isValidAccountId(debitAccountId)) {
-      result = {
-        ...baseResponse,
-        type,
-        amount,
-        currency,
-        debit_account: debitAccountId,
-        credit_account: creditAccountId,
-        status_reason: PaymentMessages.INVALID_ACCOUNT_ID_FORMAT,
-        status_code: 'AC04',
-        accounts: [],
-      };
-      timeLogger.end('parse-instruction');
-      return result;
-    }
-    if (!isValidAccountId(creditAccountId)) {
+    if (!debitEntry || !creditEntry) {
+      // Account not found (AC03) - returned accounts should be empty per spec when accounts cannot be identified
+      // With an alias map, list the names that would have matched
+      const namesHint =
+        knownNames.length > 0
+          ? `. ${PaymentMessages.KNOWN_ACCOUNT_NAMES}: ${knownNames.join(', ')}`
+          : '';
       result = {
         ...baseResponse,
         type,
@@ -555,19 +2166,24 @@ async function paymentInstructions(serviceData, options = {}) {
         currency,
         debit_account: debitAccountId,
         credit_account: creditAccountId,
-        status_reason: PaymentMessages.INVALID_ACCOUNT_ID_FORMAT,
-        status_code: 'AC04',
+        execute_by: executeBy || null,
+        status_reason: `${PaymentMessages.ACCOUNT_NOT_FOUND}${namesHint}`,
+        status_code: 'AC03',
         accounts: [],
       };
       timeLogger.end('parse-instruction');
-      return result;
+      return { result };
     }
 
-    // Validate and parse date if present
-    let parsedDateObj = null;
-    if (executeBy !== null && executeBy !== undefined) {
-      const pd = parseDateYYYYMMDD(String(executeBy));
-      if (pd === null) {
+    // The fee account is an account id or a name from the alias map; naming the debit account
+    // leaves the fee where it would be anyway
+    let feeEntry = null;
+    if (feeSource !== null) {
+      const feeAlias =
+        aliases !== null && !strict ? resolveAccountAlias(feeSource.ref.token, aliases) : null;
+      const feeAccountId = feeAlias && feeAlias.id ? feeAlias.id : feeSource.ref.token;
+      feeEntry = findAccount(accounts, feeAccountId);
+      if (feeEntry === null) {
         result = {
           ...baseResponse,
           type,
@@ -575,23 +2191,38 @@ async function paymentInstructions(serviceData, options = {}) {
           currency,
           debit_account: debitAccountId,
           credit_account: creditAccountId,
-          execute_by: executeBy,
-          status_reason: PaymentMessages.INVALID_DATE_FORMAT,
-          status_code: 'DT01',
+          execute_by: executeBy || null,
+          status_reason: `${PaymentMessages.ACCOUNT_NOT_FOUND}: fee account ${feeSource.ref.token}`,
+          status_code: 'AC03',
           accounts: [],
         };
         timeLogger.end('parse-instruction');
-        return result;
+        return { result };
       }
-      parsedDateObj = pd;
+      if (feeEntry.account.id === debitEntry.account.id) feeEntry = null;
     }
 
-    // Find accounts in provided accounts array preserving original order
-    const debitEntry = findAccount(accounts, debitAccountId);
-    const creditEntry = findAccount(accounts, creditAccountId);
+    // Failures from here on report both accounts, unchanged
+    const pairIds = [debitEntry.account.id, creditEntry.account.id];
 
-    if (!debitEntry || !creditEntry) {
-      // Account not found (AC03) - returned accounts should be empty per spec when accounts cannot be identified
+    // Currency match validation between accounts
+    const debitAccCurr = String(debitEntry.account.currency || '').toUpperCase();
+    const creditAccCurr = String(creditEntry.account.currency || '').toUpperCase();
+
+    // A word shared by several currencies ("shillings") takes the debit account's currency
+    if (currency === null) {
+      const matchesDebit = currencyCandidates.indexOf(debitAccCurr) !== -1;
+      currency = matchesDebit ? debitAccCurr : currencyCandidates[0];
+    }
+
+    // Debit and credit accounts must differ once names, trailing digits and ids are all
+    // resolved ("rent" and "acc1" may be the same account); checked before any currency rule
+    if (debitEntry.account.id === creditEntry.account.id) {
+      // Name the account when the instruction wrote it two different ways
+      const sameAccountHint =
+        debitRef.token !== creditRef.token
+          ? `: ${debitRef.token} and ${creditRef.token} are both ${debitEntry.account.id}`
+          : '';
       result = {
         ...baseResponse,
         type,
@@ -600,62 +2231,78 @@ async function paymentInstructions(serviceData, options = {}) {
         debit_account: debitAccountId,
         credit_account: creditAccountId,
         execute_by: executeBy || null,
-        status_reason: PaymentMessages.ACCOUNT_NOT_FOUND,
-        status_code: 'AC03',
-        accounts: [],
+        status_reason: `${PaymentMessages.DEBIT_CREDIT_SAME_ACCOUNT}${sameAccountHint}`,
+        status_code: 'AC02',
+        accounts: echoAccounts(accounts, pairIds),
       };
       timeLogger.end('parse-instruction');
-      return result;
+      return { result };
     }
 
-    // Currency match validation between accounts
-    const debitAccCurr = String(debitEntry.account.currency || '').toUpperCase();
-    const creditAccCurr = String(creditEntry.account.currency || '').toUpperCase();
-    if (debitAccCurr !== creditAccCurr) {
-      // CU01
-      // Build accounts array (in same order as request) but unchanged balances
-      const accountsOut = [];
-      for (let i = 0; i < accounts.length; i++) {
-        const a = accounts[i];
-        if (a.id === debitEntry.account.id || a.id === creditEntry.account.id) {
-          accountsOut.push({
-            id: a.id,
-            balance: a.balance,
-            balance_before: a.balance,
-            currency: String(a.currency || '').toUpperCase(),
-          });
-        }
-      }
+    // An inferred currency is the one both accounts hold; accounts in different currencies
+    // leave it to the instruction to say which (CU02)
+    const inferredMismatch = currencyInferred && debitAccCurr !== creditAccCurr;
+    if (inferredMismatch || (currencyInferred && !fitsMinorUnits(amount, currency))) {
+      const held = describeCurrencyMismatch(debitAccCurr, [
+        debitEntry.account,
+        creditEntry.account,
+      ]);
       result = {
         ...baseResponse,
         type,
         amount,
-        currency,
+        currency: inferredMismatch ? null : currency,
         debit_account: debitAccountId,
         credit_account: creditAccountId,
         execute_by: executeBy || null,
-        status_reason: PaymentMessages.ACCOUNT_CURRENCY_MISMATCH,
-        status_code: 'CU01',
-        accounts: accountsOut,
+        status_reason: inferredMismatch
+          ? `${PaymentMessages.CURRENCY_NOT_GIVEN}: ${held}`
+          : PaymentMessages.AMOUNT_TOO_MANY_DECIMALS,
+        status_code: inferredMismatch ? 'CU02' : 'CU03',
+        accounts: echoAccounts(accounts, pairIds),
       };
       timeLogger.end('parse-instruction');
-      return result;
+      return { result };
     }
 
-    // Instruction currency must match account currencies
-    if (debitAccCurr !== currency) {
-      const accountsOut = [];
-      for (let i = 0; i < accounts.length; i++) {
-        const a = accounts[i];
-        if (a.id === debitEntry.account.id || a.id === creditEntry.account.id) {
-          accountsOut.push({
-            id: a.id,
-            balance: a.balance,
-            balance_before: a.balance,
-            currency: String(a.currency || '').toUpperCase(),
-          });
-        }
+    // Optional FX mode: a rate table keyed "FROM/TO" (1 FROM = rate TO)
+    const fxRates =
+      data.fx_rates && typeof data.fx_rates === 'object' && !Array.isArray(data.fx_rates)
+        ? data.fx_rates
+        : null;
+    let fxRate = null;
+    if (debitAccCurr !== creditAccCurr && fxRates !== null && !currencyOmitted) {
+      const pair = `${debitAccCurr}/${creditAccCurr}`;
+      const hasRate = Object.prototype.hasOwnProperty.call(fxRates, pair);
+      const rate = hasRate ? Number(fxRates[pair]) : NaN;
+      if (!(rate > 0)) {
+        // CU05 - no usable rate for this pair
+        result = {
+          ...baseResponse,
+          type,
+          amount,
+          currency,
+          debit_account: debitAccountId,
+          credit_account: creditAccountId,
+          execute_by: executeBy || null,
+          status_reason: `${PaymentMessages.EXCHANGE_RATE_UNAVAILABLE}: ${pair}`,
+          status_code: 'CU05',
+          accounts: echoAccounts(accounts, pairIds),
+        };
+        timeLogger.end('parse-instruction');
+        return { result };
       }
+      fxRate = rate;
+    }
+
+    // Without FX both accounts and the instruction are in one currency; checked before any
+    // balance is looked at, and the reason names what each side holds
+    const currencyMismatch = describeCurrencyMismatch(currency, [
+      debitEntry.account,
+      creditEntry.account,
+    ]);
+    if (debitAccCurr !== creditAccCurr && fxRate === null) {
+      // CU01
       result = {
         ...baseResponse,
         type,
@@ -664,28 +2311,16 @@ async function paymentInstructions(serviceData, options = {}) {
         debit_account: debitAccountId,
         credit_account: creditAccountId,
         execute_by: executeBy || null,
-        status_reason: PaymentMessages.UNSUPPORTED_CURRENCY,
-        status_code: 'CU02',
-        accounts: accountsOut,
+        status_reason: `${PaymentMessages.ACCOUNT_CURRENCY_MISMATCH}: ${currencyMismatch}`,
+        status_code: 'CU01',
+        accounts: echoAccounts(accounts, pairIds),
       };
       timeLogger.end('parse-instruction');
-      return result;
+      return { result };
     }
 
-    // Debit and credit accounts must differ
-    if (debitEntry.account.id === creditEntry.account.id) {
-      const accountsOut = [];
-      for (let i = 0; i < accounts.length; i++) {
-        const a = accounts[i];
-        if (a.id === debitEntry.account.id || a.id === creditEntry.account.id) {
-          accountsOut.push({
-            id: a.id,
-            balance: a.balance,
-            balance_before: a.balance,
-            currency: String(a.currency || '').toUpperCase(),
-          });
-        }
-      }
+    // Instruction currency must match account currencies
+    if (debitAccCurr !== currency) {
       result = {
         ...baseResponse,
         type,
@@ -694,16 +2329,23 @@ async function paymentInstructions(serviceData, options = {}) {
         debit_account: debitAccountId,
         credit_account: creditAccountId,
         execute_by: executeBy || null,
-        status_reason: PaymentMessages.DEBIT_CREDIT_SAME_ACCOUNT,
-        status_code: 'AC02',
-        accounts: accountsOut,
+        status_reason: `${PaymentMessages.INSTRUCTION_CURRENCY_NOT_HELD}: ${currencyMismatch}`,
+        status_code: 'CU02',
+        accounts: echoAccounts(accounts, pairIds),
       };
       timeLogger.end('parse-instruction');
-      return result;
+      return { result };
     }
 
-    // Amount is positive integer already validated
-    if (!Number.isInteger(amount) || amount <= 0) {
+    // The fee is in the instruction currency, so the fee account must hold it too
+    const feeAccCurr =
+      feeEntry !== null ? String(feeEntry.account.currency || '').toUpperCase() : null;
+    if (feeEntry !== null && feeAccCurr !== currency) {
+      const feeIds = [...pairIds, feeEntry.account.id];
+      const feeMismatch = describeCurrencyMismatch(currency, [
+        debitEntry.account,
+        feeEntry.account,
+      ]);
       result = {
         ...baseResponse,
         type,
@@ -712,41 +2354,16 @@ async function paymentInstructions(serviceData, options = {}) {
         debit_account: debitAccountId,
         credit_account: creditAccountId,
         execute_by: executeBy || null,
-        status_reason: PaymentMessages.AMOUNT_MUST_BE_POSITIVE_INTEGER,
-        status_code: 'AM01',
-        accounts: [],
+        status_reason: `${PaymentMessages.ACCOUNT_CURRENCY_MISMATCH}: ${feeMismatch}`,
+        status_code: 'CU01',
+        accounts: echoAccounts(accounts, feeIds),
       };
       timeLogger.end('parse-instruction');
-      return result;
+      return { result };
     }
 
-    // Date logic: if parsedDateObj exists and parsedDateObj > today -> pending
-    let willExecuteNow = true;
-    if (parsedDateObj) {
-      const cmp = compareDateToTodayUTC(parsedDateObj);
-      if (cmp === 1) {
-        willExecuteNow = false;
-      } else {
-        willExecuteNow = true;
-      }
-    }
-
-    // If pending -> return pending status, do not modify balances
-    if (!willExecuteNow) {
-      // Build accounts array in the same order as request's accounts but only include the two involved
-      const accountsOut = [];
-      for (let i = 0; i < accounts.length; i++) {
-        const a = accounts[i];
-        if (a.id === debitEntry.account.id || a.id === creditEntry.account.id) {
-          accountsOut.push({
-            id: a.id,
-            balance: a.balance,
-            balance_before: a.balance,
-            currency: String(a.currency || '').toUpperCase(),
-          });
-        }
-      }
-
+    // A guard's amount is in the debit account's currency
+    if (condition !== null && condition.currency !== null && condition.currency !== debitAccCurr) {
       result = {
         ...baseResponse,
         type,
@@ -755,40 +2372,16 @@ async function paymentInstructions(serviceData, options = {}) {
         debit_account: debitAccountId,
         credit_account: creditAccountId,
         execute_by: executeBy || null,
-        status: 'pending',
-        status_reason: PaymentMessages.TRANSACTION_SCHEDULED,
-        status_code: 'AP02',
-        accounts: accountsOut,
+        status_reason: `${PaymentMessages.ACCOUNT_CURRENCY_MISMATCH}: condition is in ${condition.currency}, ${debitEntry.account.id} holds ${debitAccCurr}`,
+        status_code: 'CU01',
+        accounts: [],
       };
       timeLogger.end('parse-instruction');
-      return result;
+      return { result };
     }
 
-    // Execution now: check sufficient funds in debit account
-    const debitBalanceBefore = Number(debitEntry.account.balance);
-    const creditBalanceBefore = Number(creditEntry.account.balance);
-    if (Number.isNaN(debitBalanceBefore) || Number.isNaN(creditBalanceBefore)) {
-      // Internal data error - throw application error
-      appLogger.error(
-        { debitEntry, creditEntry },
-        'parse-payment-instructions.invalid-account-balances'
-      );
-      throwAppError(PaymentMessages.INTERNAL_ERROR, ERROR_CODE.APPERR);
-    }
-    if (debitBalanceBefore < amount) {
-      // Insufficient funds AC01
-      const accountsOut = [];
-      for (let i = 0; i < accounts.length; i++) {
-        const a = accounts[i];
-        if (a.id === debitEntry.account.id || a.id === creditEntry.account.id) {
-          accountsOut.push({
-            id: a.id,
-            balance: a.balance,
-            balance_before: a.balance,
-            currency: String(a.currency || '').toUpperCase(),
-          });
-        }
-      }
+    // Typed amounts are validated as positive above; balance shares can resolve to 0
+    if (!(amount > 0)) {
       result = {
         ...baseResponse,
         type,
@@ -797,56 +2390,56 @@ async function paymentInstructions(serviceData, options = {}) {
         debit_account: debitAccountId,
         credit_account: creditAccountId,
         execute_by: executeBy || null,
-        status_reason: `${PaymentMessages.INSUFFICIENT_FUNDS}: has ${debitBalanceBefore} ${currency}, needs ${amount} ${currency}`,
-        status_code: 'AC01',
-        accounts: accountsOut,
+        status_reason: PaymentMessages.AMOUNT_MUST_BE_POSITIVE,
+        status_code: 'AM01',
+        accounts: [],
       };
       timeLogger.end('parse-instruction');
-      return result;
+      return { result };
     }
 
-    // Perform transfer (in-memory only; no persistence required)
-    const newDebitBalance = debitBalanceBefore - amount;
-    const newCreditBalance = creditBalanceBefore + amount;
-
-    // Build accountsOut with ordering based on original request order
-    const accountsOutAfter = [];
-    for (let i = 0; i < accounts.length; i++) {
-      const a = accounts[i];
-      if (a.id === debitEntry.account.id) {
-        accountsOutAfter.push({
-          id: a.id,
-          balance: newDebitBalance,
-          balance_before: debitBalanceBefore,
-          currency: String(a.currency || '').toUpperCase(),
-        });
-      } else if (a.id === creditEntry.account.id) {
-        accountsOutAfter.push({
-          id: a.id,
-          balance: newCreditBalance,
-          balance_before: creditBalanceBefore,
-          currency: String(a.currency || '').toUpperCase(),
-        });
-      }
-    }
-
-    // Final successful response
-    result = {
-      ...baseResponse,
+    // Parsing ends here: the instruction is fully resolved against the accounts and nothing
+    // has been read from or written to a balance store
+    const warnings = buildWarnings(confidenceSignals, PaymentMessages.WARNINGS);
+    // One string per movement however it was phrased, for logs and de-duplication; carried by
+    // every result from here on, failures included
+    const canonical = canonicalInstruction({
+      amount,
+      currency,
+      debit_account: debitAccountId,
+      credit_account: creditAccountId,
+      execute_by: executeBy || null,
+      ...recurrenceFields,
+    });
+    if (canonical !== null) baseResponse.canonical_instruction = canonical;
+    const parsed = {
       type,
       amount,
       currency,
       debit_account: debitAccountId,
       credit_account: creditAccountId,
       execute_by: executeBy || null,
-      status: 'successful',
-      status_reason: PaymentMessages.TRANSACTION_EXECUTED,
-      status_code: 'AP00',
-      accounts: accountsOutAfter,
+      narration: baseResponse.narration,
+      confidence: baseResponse.confidence,
+      ...(fxRate !== null ? { fx_rate: fxRate } : {}),
+      ...(feeEntry !== null ? { fee_account: feeEntry.account.id } : {}),
+      ...(condition !== null ? { condition } : {}),
+      ...recurrenceFields,
+      ...(warnings.length > 0 ? { warnings } : {}),
+    };
+    const context = {
+      data,
+      options,
+      accounts,
+      baseResponse,
+      now,
+      dryRun,
+      scheduledDate: parsedDateObj,
+      correctionReason,
     };
 
     timeLogger.end('parse-instruction');
-    return result;
+    return { parsed, context };
   } catch (err) {
     // Unexpected internal error - log and throw as framework error
     appLogger.errorX({ error: err }, 'parse-payment-instructions.unexpected-error');
@@ -855,4 +2448,137 @@ async function paymentInstructions(serviceData, options = {}) {
   }
 }
 
+/**
+ * Run one payment instruction: the parse step (parsePaymentInstruction), then the executor
+ * (executeParsedInstruction) on what it parsed. options.parseOnly stops after the parse step
+ * with status 'parsed'.
+ */
+async function runPaymentInstruction(serviceData, options = {}) {
+  const step = await parsePaymentInstruction(serviceData, options);
+  if (step.parsed === undefined) return step.result;
+  const { parsed, context } = step;
+  if (options.parseOnly) {
+    return { ...context.baseResponse, ...parsed, status: 'parsed', accounts: [] };
+  }
+
+  const timeLogger = new TimeLogger('parse-payment-instructions');
+  timeLogger.start('execute-instruction');
+  let result;
+  try {
+    result = await executeParsedInstruction(parsed, context);
+  } catch (err) {
+    appLogger.errorX({ error: err }, 'parse-payment-instructions.unexpected-error');
+    throwAppError(PaymentMessages.INTERNAL_ERROR, ERROR_CODE.APPERR);
+  }
+  timeLogger.end('execute-instruction');
+  return result;
+}
+
+/**
+ * Run one payment instruction (runPaymentInstruction) and shape its response, reporting its
+ * lifecycle to options.observer; see observers/noop-observer.js for the events.
+ */
+async function runObservedInstruction(serviceData, options = {}) {
+  const observer = options.observer || noopObserver;
+  const instruction = serviceData && serviceData.instruction;
+  observer.notify('instruction.received', { instruction });
+
+  // Multi-currency wallets take part through their pocket in the instruction currency
+  const accountsIn = serviceData && Array.isArray(serviceData.accounts) ? serviceData.accounts : [];
+  const wallets = accountsIn.some(isWalletAccount);
+  let result = wallets
+    ? await processWalletInstruction(serviceData, {
+        ...options,
+        processInstruction: runPaymentInstruction,
+      })
+    : await runPaymentInstruction(serviceData, options);
+
+  // Opt-in full post-state: every input account, untouched ones with balance_before == balance
+  const includeAll =
+    (serviceData && serviceData.include_all_accounts === true) ||
+    options.includeAllAccounts === true;
+  if (includeAll && Array.isArray(result.accounts)) {
+    const dryRun = result.dry_run === true && result.status === 'successful';
+    // A wallet is reported as its pocket in the instruction currency
+    const inputAccounts = wallets ? selectWalletPockets(accountsIn, result.currency) : accountsIn;
+    const accounts = includeUntouchedAccounts(inputAccounts, result.accounts, dryRun);
+    result = { ...result, accounts };
+  }
+
+  // status_reason in the caller's language when options.messageResolver has the code;
+  // status_code never changes
+  const locale = (serviceData && serviceData.locale) || options.locale;
+  const localized =
+    options.messageResolver && locale
+      ? options.messageResolver.resolve(result.status_code, locale)
+      : null;
+  if (localized !== null) result = { ...result, status_reason: localized };
+
+  // Failures name the kind of failure too, so callers can group them without reading codes
+  if (result.status === 'failed') {
+    result = { ...result, reason_category: getReasonCategory(result.status_code) };
+  }
+
+  const fields = {
+    transaction_id: result.transaction_id,
+    amount: result.amount,
+    currency: result.currency,
+  };
+  // Syntax failures (SY..) and cancelled instructions never got past parsing
+  const parsed =
+    result.status !== 'cancelled' && String(result.status_code).substring(0, 2) !== 'SY';
+  if (parsed) {
+    observer.notify('instruction.parsed', {
+      transaction_id: result.transaction_id,
+      type: result.type,
+      amount: result.amount,
+      currency: result.currency,
+    });
+  }
+  observer.notify('instruction.validated', {
+    ...fields,
+    status: result.status,
+    status_code: result.status_code,
+  });
+  // Scheduled (pending) instructions and dry runs move no balances
+  if (result.status === 'successful' && !result.dry_run) {
+    observer.notify('instruction.executed', {
+      ...fields,
+      status_code: result.status_code,
+      accounts: result.accounts,
+    });
+  }
+
+  return result;
+}
+
+/**
+ * Parse, validate and execute one payment instruction (runPaymentInstruction), reporting
+ * its lifecycle to options.observer; see observers/noop-observer.js for the events.
+ * options.messageResolver with a locale (body locale or options.locale) localizes the
+ * status_reason; see message-resolvers/create-message-resolver.js. options.parseOnly stops
+ * before execution with status 'parsed'; see parse-instruction.js. include_all_accounts (or
+ * options.includeAllAccounts) reports every input account, not just the ones moved.
+ *
+ * Every instruction that is not parse-only is audited to options.auditSink, failures and
+ * payloads that fail validation included (see audit-sinks/noop-audit-sink.js), under
+ * options.idempotencyKey when processIdempotentInstruction runs it.
+ */
+async function paymentInstructions(serviceData, options = {}) {
+  // Parse-only runs execute nothing and are not audited
+  const auditSink = (!options.parseOnly && options.auditSink) || noopAuditSink;
+  const audit = { serviceData, idempotencyKey: options.idempotencyKey };
+  let result;
+  try {
+    result = await runObservedInstruction(serviceData, options);
+  } catch (err) {
+    const time = currentTime(options);
+    await writeAuditRecord(auditSink, buildAuditRecord({ ...audit, error: err, time }));
+    throw err;
+  }
+  const time = currentTime(options);
+  await writeAuditRecord(auditSink, buildAuditRecord({ ...audit, result, time }));
+  return result;
+}
+
 module.exports = paymentInstructions;
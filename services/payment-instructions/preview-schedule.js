@@ -0,0 +1,56 @@
+const { throwAppError, ERROR_CODE } = require('@app-core/errors');
+const PaymentMessages = require('@app/messages/payment-instructions');
+const { expandRecurrence, zoneOffsetSeconds, PREVIEW_MAX_COUNT } = require('./helpers');
+
+const DAY_SECONDS = 24 * 60 * 60;
+
+/**
+ * List the next runs of a parsed standing order (see parse-instruction.js) as execute_by
+ * timestamps, the first run included. Nothing is executed or stored; the list is worked out
+ * from the parsed execute_by and recurrence alone.
+ *
+ * Every run keeps the time of day of the first one. A monthly order keeps its day of the
+ * month, or runs on the last day of a month too short for it; one set against a named day
+ * ("every last business day") works that day out again for each month. Pass options.timeZone (the
+ * timezone the instruction was parsed in) for the time of day and the calendar days to be
+ * local ones, which keeps a run at the same wall-clock time across a DST change.
+ *
+ * @param {Object} parsed - a parsed STANDING_ORDER instruction
+ * @param {number} count - how many runs to list, 1 to PREVIEW_MAX_COUNT
+ * @param {Object} [options] - timeZone: IANA timezone name
+ * @returns {number[]} Unix timestamps (seconds), earliest first
+ */
+function previewSchedule(parsed, count, options = {}) {
+  const recurring =
+    parsed !== null &&
+    typeof parsed === 'object' &&
+    parsed.type === 'STANDING_ORDER' &&
+    parsed.recurrence &&
+    typeof parsed.execute_by === 'number';
+  if (!recurring) throwAppError(PaymentMessages.PREVIEW_NOT_RECURRING, ERROR_CODE.VALIDATIONERR);
+  if (!Number.isInteger(count) || count < 1 || count > PREVIEW_MAX_COUNT) {
+    throwAppError(
+      `${PaymentMessages.INVALID_PREVIEW_COUNT} ${PREVIEW_MAX_COUNT}`,
+      ERROR_CODE.VALIDATIONERR
+    );
+  }
+  const timeZone = options.timeZone || null;
+  if (timeZone !== null && zoneOffsetSeconds(timeZone, Date.now()) === null) {
+    throwAppError(PaymentMessages.INVALID_TIMEZONE, ERROR_CODE.VALIDATIONERR);
+  }
+  const offsetAt = (ms) => (timeZone === null ? 0 : zoneOffsetSeconds(timeZone, ms));
+
+  const firstLocal = parsed.execute_by + offsetAt(parsed.execute_by * 1000);
+  const timeOfDay = firstLocal - Math.floor(firstLocal / DAY_SECONDS) * DAY_SECONDS;
+  const day = new Date((firstLocal - timeOfDay) * 1000);
+  const first = { year: day.getUTCFullYear(), month: day.getUTCMonth() + 1, day: day.getUTCDate() };
+
+  return expandRecurrence(first, parsed.recurrence, count).map((d) => {
+    // Same two-step offset as the parser, for a run on the far side of a DST change
+    const localSeconds = Date.UTC(d.year, d.month - 1, d.day) / 1000 + timeOfDay;
+    const guess = localSeconds - offsetAt(localSeconds * 1000);
+    return localSeconds - offsetAt(guess * 1000);
+  });
+}
+
+module.exports = previewSchedule;
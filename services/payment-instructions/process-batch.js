@@ -0,0 +1,298 @@
+const validator = require('@app-core/validator');
+const { throwAppError, ERROR_CODE } = require('@app-core/errors');
+const { appLogger, TimeLogger } = require('@app-core/logger');
+const PaymentMessages = require('@app/messages/payment-instructions');
+const { getReasonCategory } = require('@app/messages/payment-instruction-status-codes');
+const paymentInstructions = require('./payment-instructions');
+const { tokenize, isWalletAccount, isCompleteAccount } = require('./helpers');
+const {
+  ACCOUNT_FIELDS_SPEC,
+  REQUEST_OPTION_FIELDS_SPEC,
+  REQUEST_OPTION_FIELDS,
+} = require('./request-spec');
+
+// -----------------------------
+// VSL Spec (validate incoming payload)
+// -----------------------------
+// Either one shared account set with many instructions (balances carry over between
+// instructions), or independent items that each bring their own accounts.
+const spec = `root {
+  accounts[]? ${ACCOUNT_FIELDS_SPEC}
+  instructions[]? string
+  items[]? {
+    accounts[] ${ACCOUNT_FIELDS_SPEC}
+    instruction string<trim>
+  }${REQUEST_OPTION_FIELDS_SPEC}
+  reject_duplicates? boolean
+  balance_trail? boolean
+}`;
+
+const parsedSpec = validator.parse(spec);
+
+/**
+ * Result for an item the single-instruction service rejected outright (thrown error).
+ */
+function buildRejectedItem(err) {
+  const isValidation =
+    !!err && err.isApplicationError === true && err.errorCode === ERROR_CODE.VALIDATIONERR;
+  const statusCode = isValidation ? 'SY03' : 'INTERNAL';
+  return {
+    type: null,
+    amount: null,
+    currency: null,
+    debit_account: null,
+    credit_account: null,
+    execute_by: null,
+    narration: '',
+    status: 'failed',
+    status_reason: isValidation ? err.message : PaymentMessages.INTERNAL_ERROR,
+    status_code: statusCode,
+    reason_category: getReasonCategory(statusCode),
+    accounts: [],
+  };
+}
+
+/**
+ * Result for an item that never ran because options.signal was aborted first.
+ */
+function buildCancelledItem() {
+  return {
+    type: null,
+    amount: null,
+    currency: null,
+    debit_account: null,
+    credit_account: null,
+    execute_by: null,
+    narration: '',
+    status: 'cancelled',
+    status_reason: PaymentMessages.INSTRUCTION_CANCELLED,
+    status_code: 'CANCELLED',
+    accounts: [],
+  };
+}
+
+/**
+ * Result for an item left unexecuted because an earlier item has the same instruction
+ * (reject_duplicates).
+ */
+function buildDuplicateItem(firstIndex) {
+  return {
+    type: null,
+    amount: null,
+    currency: null,
+    debit_account: null,
+    credit_account: null,
+    execute_by: null,
+    narration: '',
+    status: 'failed',
+    status_reason: PaymentMessages.DUPLICATE_INSTRUCTION,
+    status_code: 'DUP01',
+    reason_category: getReasonCategory('DUP01'),
+    duplicate_of: firstIndex,
+    accounts: [],
+  };
+}
+
+/**
+ * For each instruction, the index of the first earlier one that reads the same once
+ * normalized and trimmed (see tokenize), or -1. Letter case counts.
+ */
+function findDuplicateInstructions(instructions) {
+  const firstByText = {};
+  return instructions.map((instruction, i) => {
+    const text = tokenize(instruction).join(' ');
+    if (Object.prototype.hasOwnProperty.call(firstByText, text)) return firstByText[text];
+    firstByText[text] = i;
+    return -1;
+  });
+}
+
+/**
+ * Every balance each account passed through, in input order: one { index, balance_before,
+ * balance } step per executed item that moved it. Pending, failed and cancelled items and
+ * accounts an item left as they were add no step.
+ */
+function buildBalanceTrails(results) {
+  const trails = {};
+  results.forEach((itemResult, index) => {
+    if (itemResult.status !== 'successful') return;
+    itemResult.accounts.forEach((a) => {
+      if (a.balance === a.balance_before) return;
+      if (!Object.prototype.hasOwnProperty.call(trails, a.id)) trails[a.id] = [];
+      trails[a.id].push({ index, balance_before: a.balance_before, balance: a.balance });
+    });
+  });
+  return trails;
+}
+
+/**
+ * Run async tasks at most `size` at a time, in the order they were queued.
+ */
+function createLimiter(size) {
+  let active = 0;
+  const waiting = [];
+  function next() {
+    if (active < size && waiting.length > 0) {
+      active++;
+      const { task, resolve } = waiting.shift();
+      task()
+        .then(resolve)
+        .finally(() => {
+          active--;
+          next();
+        });
+    }
+  }
+  return (task) =>
+    new Promise((resolve) => {
+      waiting.push({ task, resolve });
+      next();
+    });
+}
+
+/**
+ * Carry an executed item's balance into the shared account set: a wallet takes it into the
+ * pocket the item reports (its pocket in the instruction currency), any other account into
+ * its balance.
+ */
+function carryBalance(account, updated) {
+  if (isWalletAccount(account)) {
+    const codes = Object.keys(account.balances);
+    const code = codes.find((c) => c.toUpperCase() === updated.currency) || updated.currency;
+    account.balances = { ...account.balances, [code]: updated.balance };
+  } else {
+    account.balance = updated.balance;
+  }
+}
+
+/**
+ * Process many payment instructions in one call.
+ *
+ * Shared mode ({ accounts, instructions }): instructions run in order against one account set;
+ * balances after a successful instruction are what the next instruction sees.
+ * Items mode ({ items: [{ accounts, instruction }] }): every item runs on its own accounts.
+ *
+ * A failed item never aborts the batch: every item gets its own result (same order as the
+ * input) with its status, status_code and balances. Shared mode also returns the final
+ * account set.
+ *
+ * With reject_duplicates, an instruction that repeats an earlier one in the batch (the same
+ * text once normalized and trimmed) is not executed: it fails with DUP01 and duplicate_of
+ * set to the index of the first, which runs as usual.
+ *
+ * With balance_trail (or options.balanceTrail), balance_trails lists, per account id, each
+ * balance_before -> balance step the executed items took it through, in input order; off by
+ * default since it grows with the batch.
+ *
+ * options.signal (an AbortSignal, e.g. AbortSignal.timeout(ms) for a deadline) is checked
+ * before every item: once it is aborted, the items already run keep their results and the
+ * rest come back with status "cancelled" (CANCELLED), unexecuted.
+ *
+ * options.concurrency (default 1) lets items mode run that many items at once, so their store
+ * calls overlap. An item still waits for every earlier item that names one of its account ids,
+ * so per-account state (daily limits, stored balances) changes in input order and the results
+ * match a sequential run. Shared mode is always sequential.
+ */
+async function processBatch(serviceData, options = {}) {
+  let result;
+
+  const timeLogger = new TimeLogger('process-payment-instruction-batch');
+  timeLogger.start('validate-input');
+
+  let data;
+  try {
+    data = validator.validate(serviceData, parsedSpec);
+    const accounts = (data.accounts || []).concat(...(data.items || []).map((i) => i.accounts));
+    if (!accounts.every(isCompleteAccount)) throw new Error('Account balance missing');
+  } catch (err) {
+    appLogger.errorX({ error: err, payload: serviceData }, 'process-batch.validation-failed');
+    throwAppError(PaymentMessages.MALFORMED_INSTRUCTION, ERROR_CODE.VALIDATIONERR);
+  }
+
+  const shared = Array.isArray(data.instructions);
+  const independent = Array.isArray(data.items);
+  if (shared === independent || (shared && !Array.isArray(data.accounts))) {
+    throwAppError(PaymentMessages.INVALID_BATCH, ERROR_CODE.VALIDATIONERR);
+  }
+
+  timeLogger.end('validate-input');
+  timeLogger.start('process-batch');
+
+  const results = [];
+  // Working copy of the shared account set; the caller's objects are never mutated
+  const sharedAccounts = shared ? data.accounts.map((a) => ({ ...a })) : null;
+  const count = shared ? data.instructions.length : data.items.length;
+  const instructions = shared ? data.instructions : data.items.map((item) => item.instruction);
+  // Opt-in, so that a batch may repeat an instruction on purpose
+  const duplicateOf = data.reject_duplicates
+    ? findDuplicateInstructions(instructions)
+    : instructions.map(() => -1);
+
+  async function runItem(i) {
+    const payload = {
+      accounts: shared ? sharedAccounts : data.items[i].accounts,
+      instruction: shared ? data.instructions[i] : data.items[i].instruction,
+    };
+    REQUEST_OPTION_FIELDS.forEach((field) => {
+      if (data[field] !== undefined) payload[field] = data[field];
+    });
+
+    let itemResult;
+    if (options.signal && options.signal.aborted) {
+      itemResult = buildCancelledItem();
+    } else if (duplicateOf[i] !== -1) {
+      itemResult = buildDuplicateItem(duplicateOf[i]);
+    } else {
+      try {
+        itemResult = await paymentInstructions(payload, options);
+      } catch (err) {
+        itemResult = buildRejectedItem(err);
+      }
+    }
+    return itemResult;
+  }
+
+  const concurrency = Math.floor(Number(options.concurrency)) || 1;
+  if (shared || concurrency <= 1) {
+    for (let i = 0; i < count; i++) {
+      // Sequential on purpose: each instruction must see the balances left by the previous one
+      // eslint-disable-next-line no-await-in-loop
+      const itemResult = await runItem(i);
+      if (shared && itemResult.status === 'successful') {
+        for (let j = 0; j < itemResult.accounts.length; j++) {
+          const updated = itemResult.accounts[j];
+          for (let k = 0; k < sharedAccounts.length; k++) {
+            if (sharedAccounts[k].id === updated.id) carryBalance(sharedAccounts[k], updated);
+          }
+        }
+      }
+      results.push(itemResult);
+    }
+  } else {
+    const limit = createLimiter(concurrency);
+    // Last queued run per account id (lowercased, so case-insensitive ids are covered too)
+    const lastRunByAccount = {};
+    const runs = [];
+    for (let i = 0; i < count; i++) {
+      const ids = data.items[i].accounts.map((a) => String(a.id).toLowerCase());
+      const earlier = ids.filter((id) => lastRunByAccount[id]).map((id) => lastRunByAccount[id]);
+      const run = Promise.all(earlier).then(() => limit(() => runItem(i)));
+      ids.forEach((id) => {
+        lastRunByAccount[id] = run;
+      });
+      runs.push(run);
+    }
+    (await Promise.all(runs)).forEach((itemResult) => results.push(itemResult));
+  }
+
+  result = { results };
+  if (shared) result.accounts = sharedAccounts.map((a) => ({ ...a }));
+  if (data.balance_trail === true || options.balanceTrail === true) {
+    result.balance_trails = buildBalanceTrails(results);
+  }
+
+  timeLogger.end('process-batch');
+  return result;
+}
+
+module.exports = processBatch;
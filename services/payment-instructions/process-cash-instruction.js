@@ -0,0 +1,568 @@
+const validator = require('@app-core/validator');
+const { throwAppError, ERROR_CODE } = require('@app-core/errors');
+const { appLogger, TimeLogger } = require('@app-core/logger');
+const PaymentMessages = require('@app/messages/payment-instructions');
+const {
+  parseAmount,
+  parseNegativeAmount,
+  findAmbiguousAmount,
+  resolveCurrency,
+  isCurrencySymbol,
+  readDefaultCurrency,
+  isUnknownCurrencyCode,
+  placeCurrencySymbol,
+  parseBillPayment,
+  resolveAccountAlias,
+  parseAccountReference,
+  findAccountsBySuffix,
+  findAccountsIgnoringCase,
+  tokenize,
+  isValidAccountId,
+  createAccountIdMatcher,
+  findAccount,
+  findBlockedAccount,
+  toMinorUnits,
+  fromMinorUnits,
+  fitsMinorUnits,
+  calculateFee,
+  exceededAmountCap,
+  parseNarration,
+  scoreConfidence,
+  buildWarnings,
+  describeGuesses,
+  correctCurrencyWord,
+  FEE_POLICY,
+  ROUNDING_POLICY,
+  MAX_AMOUNT_POLICY,
+  currentTime,
+  describeCurrencyMismatch,
+} = require('./helpers');
+const defaultTransactionStore = require('./stores/default-transaction-store');
+const defaultIdGenerator = require('./id-generators/default-id-generator');
+const { parsedInstructionRequestSpec: parsedSpec } = require('./request-spec');
+
+/**
+ * Execute a cash WITHDRAW or DEPOSIT instruction, or a PAY to an outside biller: one account,
+ * no counterparty among the accounts.
+ *
+ *   WITHDRAW [OF] <amount> [<currency>] FROM [ACCOUNT] <acct>
+ *   DEPOSIT [OF] <amount> [<currency>] TO|INTO [ACCOUNT] <acct>
+ *   PAY <amount> [<currency>] TO <biller> FROM [ACCOUNT] <acct>  (see parseBillPayment)
+ *
+ * A withdrawal debits the account (credit_account is null) and is held to the same rules as
+ * a DEBIT: minimum balance (BL02), balance plus overdraft (AC01) and daily limit (LM01), with
+ * any WITHDRAW fee drawn on top. A bill payment is debited the same way; the response names
+ * the payee in biller, and with a single account FROM may be left out. A deposit credits the
+ * account (debit_account is null) and is never declined for funds. Without a currency the
+ * configured default currency (default_currency, options.defaultCurrency) is used, or else
+ * the account's own.
+ *
+ * Called by the payment-instructions service, which passes its daily debit store in
+ * options.dailyDebitStore and any verb it corrected (fuzzy_keywords) in
+ * options.keywordCorrections.
+ */
+async function processCashInstruction(serviceData, options = {}) {
+  let result;
+
+  const timeLogger = new TimeLogger('process-cash-instruction');
+  timeLogger.start('validate-input');
+
+  let data;
+  try {
+    data = validator.validate(serviceData, parsedSpec);
+  } catch (err) {
+    appLogger.errorX({ error: err, payload: serviceData }, 'process-cash.validation-failed');
+    throwAppError(PaymentMessages.MALFORMED_INSTRUCTION, ERROR_CODE.VALIDATIONERR);
+  }
+
+  timeLogger.end('validate-input');
+  timeLogger.start('parse-instruction');
+
+  const now = currentTime(options);
+  const dryRun = data.dry_run === true || options.dryRun === true;
+  // "1,000.50" by default; "1.000,50" when the decimal separator is ','
+  const decimalSeparator =
+    data.decimal_separator === ',' || options.decimalSeparator === ',' ? ',' : '.';
+  const strict = data.strict === true || options.strict === true;
+  const defaultCurrency = strict ? null : readDefaultCurrency(data, options);
+
+  const accounts = data.accounts;
+  const verb = String(tokenize(data.instruction)[0]).toLowerCase();
+  // A bill payment reads like a withdrawal once the biller is taken out
+  const bill =
+    verb === 'pay'
+      ? parseBillPayment(
+          tokenize(data.instruction),
+          decimalSeparator,
+          accounts.length === 1 ? accounts[0].id : null
+        )
+      : null;
+  const rawTokens = bill !== null ? bill.tokens : tokenize(data.instruction);
+  const withdrawal = verb === 'withdraw' || verb === 'pay';
+  let type = withdrawal ? 'WITHDRAW' : 'DEPOSIT';
+  if (verb === 'pay') type = 'PAY';
+
+  // Every response carries an id, failures included; options.idGenerator replaces the default
+  const idGenerator = options.idGenerator || defaultIdGenerator;
+  const baseResponse = {
+    transaction_id: idGenerator.next(),
+    type,
+    amount: null,
+    currency: null,
+    debit_account: null,
+    credit_account: null,
+    ...(verb === 'pay' ? { biller: bill !== null ? bill.biller : null } : {}),
+    execute_by: null,
+    narration: '',
+    status: 'failed',
+    status_reason: '',
+    status_code: '',
+    accounts: [],
+  };
+  if (dryRun) baseResponse.dry_run = true;
+  if (options.matchedVerb) baseResponse.matched_verb = options.matchedVerb;
+  if (verb === 'pay' && bill === null) {
+    result = {
+      ...baseResponse,
+      status_reason: PaymentMessages.MALFORMED_INSTRUCTION,
+      status_code: 'SY03',
+    };
+    timeLogger.end('parse-instruction');
+    return result;
+  }
+  // The one account is the debit side of a withdrawal and the credit side of a deposit
+  const accountField = withdrawal ? 'debit_account' : 'credit_account';
+
+  // "WITHDRAW OF 5000 NGN ..." - the "of" is optional filler
+  const amountStart = String(rawTokens[1]).toLowerCase() === 'of' ? 2 : 1;
+  // "₦5000", "$ 20" or "20€": the symbol takes the place of the currency token
+  const tokens = placeCurrencySymbol(
+    rawTokens,
+    amountStart,
+    decimalSeparator,
+    options.amountParsers
+  );
+  const lowerTokens = [];
+  for (let i = 0; i < tokens.length; i++) lowerTokens.push(tokens[i].toLowerCase());
+  // A range or an estimate is no amount to send ("100 to 200", "about 500")
+  const ambiguousAmount = findAmbiguousAmount(tokens, amountStart, accounts, decimalSeparator);
+  if (ambiguousAmount !== null) {
+    result = {
+      ...baseResponse,
+      status_reason: `${PaymentMessages.AMBIGUOUS_AMOUNT}: "${ambiguousAmount.text}"`,
+      status_code: 'AM04',
+    };
+    timeLogger.end('parse-instruction');
+    return result;
+  }
+  const parsedAmount = parseAmount(tokens, amountStart, decimalSeparator, options.amountParsers);
+  const amountConsumed = parsedAmount ? parsedAmount.consumed : 1;
+  const amountRatio = parsedAmount && parsedAmount.ratio ? parsedAmount.ratio : null;
+  const isDirection = (word) => (withdrawal ? word === 'from' : word === 'to' || word === 'into');
+  // The currency may be left out ("withdraw 5000 from acc1")
+  const iAfterAmount = amountStart + amountConsumed;
+  const currencyToken = isDirection(lowerTokens[iAfterAmount]) ? null : tokens[iAfterAmount];
+  const iDirection = currencyToken === null ? iAfterAmount : iAfterAmount + 1;
+  const shownCurrency = currencyToken !== null ? String(currencyToken).toUpperCase() : null;
+
+  // A share of "the balance" is not a cash amount
+  if (
+    iAfterAmount >= tokens.length ||
+    amountRatio !== null ||
+    (parsedAmount !== null && parsedAmount.amount === null)
+  ) {
+    result = {
+      ...baseResponse,
+      status_reason: PaymentMessages.MALFORMED_INSTRUCTION,
+      status_code: 'SY03',
+    };
+    timeLogger.end('parse-instruction');
+    return result;
+  }
+  const negativeAmount =
+    parsedAmount === null ? parseNegativeAmount(tokens[amountStart], decimalSeparator) : null;
+  if (negativeAmount !== null || (parsedAmount !== null && parsedAmount.amount === 0)) {
+    result = {
+      ...baseResponse,
+      amount: negativeAmount !== null ? negativeAmount : 0,
+      currency: shownCurrency,
+      status_reason:
+        negativeAmount !== null
+          ? PaymentMessages.AMOUNT_MUST_NOT_BE_NEGATIVE
+          : PaymentMessages.AMOUNT_MUST_BE_POSITIVE,
+      status_code: negativeAmount !== null ? 'AM03' : 'AM01',
+    };
+    timeLogger.end('parse-instruction');
+    return result;
+  }
+  if (parsedAmount === null) {
+    result = {
+      ...baseResponse,
+      currency: shownCurrency,
+      status_reason: PaymentMessages.AMOUNT_MUST_BE_POSITIVE_NUMBER,
+      status_code: 'AM01',
+    };
+    timeLogger.end('parse-instruction');
+    return result;
+  }
+  const { amount } = parsedAmount;
+
+  const fuzzy = data.fuzzy_keywords === true || options.fuzzyKeywords === true;
+  const corrections = (options.keywordCorrections || []).slice();
+  let currencyCandidates = null;
+  if (currencyToken !== null) {
+    const heldCurrencies = accounts.map((a) => String(a.currency || '').toUpperCase());
+    currencyCandidates = resolveCurrency(currencyToken, heldCurrencies);
+    const correctedCurrency =
+      fuzzy && currencyCandidates.length === 0 ? correctCurrencyWord(currencyToken) : null;
+    if (correctedCurrency !== null) {
+      corrections.push({ from: currencyToken, to: correctedCurrency });
+      currencyCandidates = resolveCurrency(correctedCurrency, heldCurrencies);
+    }
+    if (currencyCandidates.length === 0) {
+      result = {
+        ...baseResponse,
+        amount,
+        currency: shownCurrency,
+        status_reason: isUnknownCurrencyCode(currencyToken)
+          ? `${PaymentMessages.UNKNOWN_CURRENCY_CODE}: ${shownCurrency}`
+          : PaymentMessages.UNSUPPORTED_CURRENCY,
+        status_code: isUnknownCurrencyCode(currencyToken) ? 'CU04' : 'CU02',
+      };
+      timeLogger.end('parse-instruction');
+      return result;
+    }
+    // A symbol several held currencies share ("Sh" with both KES and UGX accounts)
+    if (currencyCandidates.length > 1 && isCurrencySymbol(currencyToken)) {
+      result = {
+        ...baseResponse,
+        amount,
+        status_reason: `${PaymentMessages.AMBIGUOUS_CURRENCY_SYMBOL}: "${currencyToken}" could be ${currencyCandidates.join(' or ')}`,
+        status_code: 'CU06',
+      };
+      timeLogger.end('parse-instruction');
+      return result;
+    }
+  }
+  let currency =
+    currencyCandidates !== null && currencyCandidates.length === 1 ? currencyCandidates[0] : null;
+  // A configured default currency stands in for a missing one and must be the account's
+  if (currencyToken === null) currency = defaultCurrency;
+  const confidenceSignals = corrections.map(() => 'fuzzy');
+  const amountLead = String(tokens[amountStart])[0];
+  if (amountLead < '0' || amountLead > '9') confidenceSignals.push('word_amount');
+  // No currency and no default: the account's own is taken, as for a transfer
+  const currencyInferred = currencyToken === null && defaultCurrency === null;
+  if (currencyInferred || (currencyCandidates !== null && currencyCandidates.length > 1)) {
+    confidenceSignals.push('currency_inferred');
+  }
+
+  // FROM (withdrawals) or TO / INTO (deposits) [ACCOUNT] <acct>
+  const iAccountId = lowerTokens[iDirection + 1] === 'account' ? iDirection + 2 : iDirection + 1;
+  if (!isDirection(lowerTokens[iDirection]) || iAccountId >= tokens.length) {
+    const missing = lowerTokens[iDirection] === undefined || iAccountId >= tokens.length;
+    result = {
+      ...baseResponse,
+      amount,
+      currency,
+      status_reason: missing
+        ? PaymentMessages.MISSING_REQUIRED_KEYWORD
+        : PaymentMessages.INVALID_KEYWORD_ORDER,
+      status_code: missing ? 'SY01' : 'SY02',
+    };
+    timeLogger.end('parse-instruction');
+    return result;
+  }
+  const ref = parseAccountReference(tokens, iAccountId, accounts);
+  // A trailing "for <text>" / "ref: <text>" is the narration
+  const narration = parseNarration(tokens, iAccountId + ref.consumed);
+  baseResponse.narration = narration.text;
+  if (narration.start !== iAccountId + ref.consumed) {
+    result = {
+      ...baseResponse,
+      amount,
+      currency,
+      status_reason: PaymentMessages.MALFORMED_INSTRUCTION,
+      status_code: 'SY03',
+    };
+    timeLogger.end('parse-instruction');
+    return result;
+  }
+
+  // Alias names and trailing-digit references resolve exactly as for single instructions
+  const aliases =
+    data.aliases && typeof data.aliases === 'object' && !Array.isArray(data.aliases)
+      ? data.aliases
+      : null;
+  const ignoreCase = data.case_insensitive_ids === true || options.caseInsensitiveIds === true;
+  // Ids must also have the caller's shape when options.accountIdFormat gives one
+  const matchesIdFormat = createAccountIdMatcher(options.accountIdFormat);
+  let accountId = ref.token;
+  let failure = null;
+  const alias = aliases !== null ? resolveAccountAlias(ref.token, aliases) : null;
+  if (alias && alias.ambiguous) {
+    const candidates = `"${ref.token}" could be ${alias.ambiguous.join(' or ')}`;
+    failure = {
+      reason: `${PaymentMessages.AMBIGUOUS_ACCOUNT_ALIAS}: ${candidates}`,
+      code: 'AC05',
+      candidates: alias.ambiguous,
+    };
+  } else if (alias) {
+    accountId = alias.id;
+    confidenceSignals.push('alias');
+  } else if (ref.suffix !== null) {
+    const matches = findAccountsBySuffix(accounts, ref.suffix);
+    if (matches.length === 1) {
+      accountId = matches[0];
+      confidenceSignals.push('partial_account');
+    } else if (matches.length === 0) {
+      failure = {
+        reason: `${PaymentMessages.ACCOUNT_NOT_FOUND}: no account ending ${ref.suffix}`,
+        code: 'AC03',
+      };
+    } else {
+      const listed = `"${ref.token}" matches ${matches.join(', ')}`;
+      failure = {
+        reason: `${PaymentMessages.AMBIGUOUS_ACCOUNT_REFERENCE}: ${listed}`,
+        code: 'AC06',
+        candidates: matches,
+      };
+    }
+  }
+  const caseMatches = ignoreCase ? findAccountsIgnoringCase(accounts, accountId) : [];
+  if (failure === null && caseMatches.length > 1) {
+    const candidates = `"${accountId}" could be ${caseMatches.join(' or ')}`;
+    failure = {
+      reason: `${PaymentMessages.AMBIGUOUS_ACCOUNT_CASE}: ${candidates}`,
+      code: 'AC05',
+      candidates: caseMatches,
+    };
+  } else if (failure === null && caseMatches.length === 1 && caseMatches[0] !== accountId) {
+    accountId = caseMatches[0];
+    confidenceSignals.push('case_insensitive');
+  }
+  if (failure === null && strict && confidenceSignals.length > 0) {
+    const guesses = describeGuesses(confidenceSignals, PaymentMessages.STRICT_MODE_GUESSES);
+    failure = { reason: `${PaymentMessages.STRICT_MODE_GUESS}: ${guesses}`, code: 'SY06' };
+  }
+  if (failure === null && !isValidAccountId(accountId)) {
+    failure = { reason: PaymentMessages.INVALID_ACCOUNT_ID_FORMAT, code: 'AC04' };
+  }
+  if (failure === null && !matchesIdFormat(accountId)) {
+    const reason = `${PaymentMessages.MALFORMED_ACCOUNT_REFERENCE}: ${accountId}`;
+    failure = { reason, code: 'AC04' };
+  }
+  const entry = failure === null ? findAccount(accounts, accountId) : null;
+  if (failure === null && entry === null) {
+    failure = { reason: PaymentMessages.ACCOUNT_NOT_FOUND, code: 'AC03' };
+  }
+  if (failure !== null) {
+    result = {
+      ...baseResponse,
+      amount,
+      currency,
+      [accountField]: accountId,
+      status_reason: failure.reason,
+      status_code: failure.code,
+      ...(failure.candidates ? { candidates: failure.candidates } : {}),
+    };
+    timeLogger.end('parse-instruction');
+    return result;
+  }
+  baseResponse.confidence = scoreConfidence(confidenceSignals);
+  baseResponse[accountField] = accountId;
+  const correctionReason = corrections
+    .map((c) => `; ${PaymentMessages.KEYWORD_CORRECTED} "${c.from}" to ${c.to}`)
+    .join('');
+
+  const { account } = entry;
+  const accountCurr = String(account.currency || '').toUpperCase();
+  const balanceBefore = Number(account.balance);
+  const unchanged = [
+    {
+      id: account.id,
+      balance: account.balance,
+      balance_before: account.balance,
+      currency: accountCurr,
+    },
+  ];
+  if (currency === null) {
+    currency =
+      currencyCandidates === null || currencyCandidates.indexOf(accountCurr) !== -1
+        ? accountCurr
+        : currencyCandidates[0];
+  }
+  if (currency !== accountCurr) {
+    result = {
+      ...baseResponse,
+      amount,
+      currency,
+      status_reason: `${PaymentMessages.ACCOUNT_CURRENCY_MISMATCH}: ${describeCurrencyMismatch(currency, [account])}`,
+      status_code: 'CU01',
+      accounts: unchanged,
+    };
+    timeLogger.end('parse-instruction');
+    return result;
+  }
+  // Decimal places must fit the currency's minor units ("10.005 USD" does not)
+  if (!fitsMinorUnits(amount, currency)) {
+    result = {
+      ...baseResponse,
+      amount,
+      currency,
+      status_reason: PaymentMessages.AMOUNT_TOO_MANY_DECIMALS,
+      status_code: 'CU03',
+      accounts: unchanged,
+    };
+    timeLogger.end('parse-instruction');
+    return result;
+  }
+
+  const warnings = buildWarnings(confidenceSignals, PaymentMessages.WARNINGS);
+  const warningFields = warnings.length > 0 ? { warnings } : {};
+
+  // options.parseOnly (see parse-instruction.js) stops before the fee and the balance
+  if (options.parseOnly) {
+    result = { ...baseResponse, amount, currency, ...warningFields, status: 'parsed' };
+    timeLogger.end('parse-instruction');
+    return result;
+  }
+
+  // Per-transaction cap (LM02), for deposits as well as withdrawals
+  const maxAmountPolicy = options.maxAmountPolicy || MAX_AMOUNT_POLICY;
+  const cap = exceededAmountCap(amount, type, currency, maxAmountPolicy);
+  if (cap !== null) {
+    result = {
+      ...baseResponse,
+      amount,
+      currency,
+      status_reason: `${PaymentMessages.AMOUNT_EXCEEDS_TRANSACTION_LIMIT}: limit is ${cap} ${currency} for ${type}`,
+      status_code: 'LM02',
+      accounts: unchanged,
+    };
+    timeLogger.end('parse-instruction');
+    return result;
+  }
+
+  // A frozen (AC08) or closed (AC09) account takes no part, checked before any balance rule
+  const blocked = findBlockedAccount([account]);
+  if (blocked !== null) {
+    const blockedMessage =
+      blocked.status === 'frozen' ? PaymentMessages.ACCOUNT_FROZEN : PaymentMessages.ACCOUNT_CLOSED;
+    result = {
+      ...baseResponse,
+      amount,
+      currency,
+      status_reason: `${blockedMessage}: ${blocked.account.id}`,
+      status_code: blocked.code,
+      accounts: unchanged,
+    };
+    timeLogger.end('parse-instruction');
+    return result;
+  }
+
+  // Deposits carry no fee; a WITHDRAW (or PAY) fee is drawn with the amount
+  const feePolicy = options.feePolicy || FEE_POLICY;
+  const roundingPolicy = options.roundingPolicy || ROUNDING_POLICY;
+  const fee = withdrawal ? calculateFee(amount, type, feePolicy, currency, roundingPolicy) : null;
+  const feeFields = fee !== null ? { fee } : {};
+  const totalMinor =
+    toMinorUnits(amount, currency) + (fee !== null ? toMinorUnits(fee, currency) : 0);
+  const totalDebit = fromMinorUnits(totalMinor, currency);
+  const feeText =
+    fee !== null && fee > 0
+      ? ` (${amount} ${currency} + ${fee} ${currency} ${PaymentMessages.FEE})`
+      : '';
+  const afterMinor = withdrawal
+    ? toMinorUnits(balanceBefore, currency) - totalMinor
+    : toMinorUnits(balanceBefore, currency) + totalMinor;
+  const balanceAfter = fromMinorUnits(afterMinor, currency);
+
+  const dailyDebitStore = options.dailyDebitStore;
+  const debitDay = now.toISOString().slice(0, 10);
+  if (withdrawal) {
+    let declined = null;
+    const minimumBalance =
+      account.minimum_balance !== undefined ? Number(account.minimum_balance) : null;
+    const overdraftLimit = Math.max(Number(account.overdraft_limit) || 0, 0);
+    if (minimumBalance !== null && afterMinor < toMinorUnits(minimumBalance, currency)) {
+      declined = {
+        reason: `${PaymentMessages.MINIMUM_BALANCE_BREACH}: floor is ${minimumBalance} ${currency}, balance would be ${balanceAfter} ${currency}`,
+        code: 'BL02',
+      };
+    } else if (afterMinor < -toMinorUnits(overdraftLimit, currency)) {
+      const overdraftText =
+        overdraftLimit > 0
+          ? ` plus ${overdraftLimit} ${currency} ${PaymentMessages.OVERDRAFT}`
+          : '';
+      declined = {
+        reason: `${PaymentMessages.INSUFFICIENT_FUNDS}: has ${balanceBefore} ${currency}${overdraftText}, needs ${totalDebit} ${currency}${feeText}`,
+        code: 'AC01',
+      };
+    } else if (account.daily_limit !== undefined && dailyDebitStore) {
+      const dailyLimit = Number(account.daily_limit);
+      const debitedToday = await dailyDebitStore.getTotal(account.id, debitDay);
+      if (toMinorUnits(debitedToday, currency) + totalMinor > toMinorUnits(dailyLimit, currency)) {
+        declined = {
+          reason: `${PaymentMessages.DAILY_LIMIT_EXCEEDED}: limit is ${dailyLimit} ${currency}, already debited ${debitedToday} ${currency} today, needs ${totalDebit} ${currency}${feeText}`,
+          code: 'LM01',
+        };
+      }
+    }
+    if (declined !== null) {
+      result = {
+        ...baseResponse,
+        amount,
+        currency,
+        ...feeFields,
+        status_reason: declined.reason,
+        status_code: declined.code,
+        accounts: unchanged,
+      };
+      timeLogger.end('parse-instruction');
+      return result;
+    }
+  }
+
+  // Count the withdrawal towards today's total (previews move no money, so they are not counted)
+  if (withdrawal && !dryRun && dailyDebitStore) {
+    await dailyDebitStore.add(account.id, debitDay, totalDebit);
+  }
+
+  // Executed transactions are kept for reversal
+  const transactionStore = options.transactionStore || defaultTransactionStore;
+  const executedReason = dryRun
+    ? PaymentMessages.DRY_RUN_WOULD_EXECUTE
+    : PaymentMessages.TRANSACTION_EXECUTED;
+  const overdraftReason =
+    withdrawal && balanceAfter < 0
+      ? `; ${PaymentMessages.ACCOUNT_OVERDRAWN} by ${-balanceAfter} ${currency}`
+      : '';
+  result = {
+    ...baseResponse,
+    amount,
+    currency,
+    ...feeFields,
+    ...warningFields,
+    status: 'successful',
+    status_reason: `${executedReason}${overdraftReason}${correctionReason}`,
+    status_code: 'AP00',
+    accounts: [
+      {
+        id: account.id,
+        balance: dryRun ? balanceBefore : balanceAfter,
+        balance_before: balanceBefore,
+        ...(dryRun ? { projected_balance: balanceAfter } : {}),
+        currency: accountCurr,
+      },
+    ],
+  };
+  if (!dryRun) {
+    await transactionStore.set(result.transaction_id, { transaction: result, reversal: null });
+  }
+
+  timeLogger.end('parse-instruction');
+  return result;
+}
+
+module.exports = processCashInstruction;
@@ -0,0 +1,117 @@
+const validator = require('@app-core/validator');
+const { throwAppError, ERROR_CODE } = require('@app-core/errors');
+const { appLogger, TimeLogger } = require('@app-core/logger');
+const PaymentMessages = require('@app/messages/payment-instructions');
+const { tokenize, splitCompoundInstruction } = require('./helpers');
+const defaultIdGenerator = require('./id-generators/default-id-generator');
+const { parsedInstructionRequestSpec: parsedSpec } = require('./request-spec');
+
+/**
+ * Execute a compound instruction ("... TO acc2 and 200 NGN FROM acc1 ... TO acc3") clause
+ * by clause; see splitCompoundInstruction for where it splits.
+ *
+ * Clauses run in order against one working copy of the accounts, so each sees the balances
+ * left by the previous one. A clause that fails (unparseable, insufficient funds, ...) gets a
+ * failed sub-result and the others still run. Dry runs chain the projected balances.
+ *
+ * Called by the payment-instructions service, which passes itself in
+ * options.processInstruction to run each clause.
+ */
+async function processCompoundInstruction(serviceData, options = {}) {
+  let result;
+
+  const timeLogger = new TimeLogger('process-compound-instruction');
+  timeLogger.start('validate-input');
+
+  let data;
+  try {
+    data = validator.validate(serviceData, parsedSpec);
+  } catch (err) {
+    appLogger.errorX({ error: err, payload: serviceData }, 'process-compound.validation-failed');
+    throwAppError(PaymentMessages.MALFORMED_INSTRUCTION, ERROR_CODE.VALIDATIONERR);
+  }
+
+  timeLogger.end('validate-input');
+  timeLogger.start('process-clauses');
+
+  const dryRun = data.dry_run === true || options.dryRun === true;
+  const clauses = splitCompoundInstruction(tokenize(data.instruction));
+  // Working copy of the accounts; the caller's objects are never mutated
+  const working = data.accounts.map((a) => ({ ...a }));
+  const touched = [];
+
+  const subResults = [];
+  for (let c = 0; c < clauses.length; c++) {
+    const instruction = clauses[c].join(' ');
+    // Sequential on purpose: each clause must see the balances left by the previous one
+    // eslint-disable-next-line no-await-in-loop
+    const subResult = await options.processInstruction(
+      { ...serviceData, accounts: working, instruction },
+      options
+    );
+    if (subResult.status === 'successful') {
+      for (let j = 0; j < subResult.accounts.length; j++) {
+        const updated = subResult.accounts[j];
+        const after = dryRun ? updated.projected_balance : updated.balance;
+        for (let k = 0; k < working.length; k++) {
+          if (working[k].id === updated.id && after !== undefined) working[k].balance = after;
+        }
+      }
+    }
+    for (let j = 0; j < subResult.accounts.length; j++) {
+      if (touched.indexOf(subResult.accounts[j].id) === -1) {
+        touched.push(subResult.accounts[j].id);
+      }
+    }
+    subResults.push({ instruction, ...subResult });
+  }
+
+  // Every account a clause involved, in request order, from its opening balance to its last
+  const accountsOut = [];
+  for (let i = 0; i < data.accounts.length; i++) {
+    const a = data.accounts[i];
+    if (touched.indexOf(a.id) !== -1) {
+      accountsOut.push({
+        id: a.id,
+        balance: dryRun ? a.balance : working[i].balance,
+        balance_before: a.balance,
+        ...(dryRun ? { projected_balance: working[i].balance } : {}),
+        currency: String(a.currency || '').toUpperCase(),
+      });
+    }
+  }
+
+  // Clauses cancelled through options.signal count as failed; with options.parseOnly every
+  // clause is only parsed (see parse-instruction.js) and nothing is executed
+  const done = options.parseOnly ? 'parsed' : 'successful';
+  const failed = subResults.filter((r) => r.status !== done);
+  const idGenerator = options.idGenerator || defaultIdGenerator;
+  result = {
+    transaction_id: idGenerator.next(),
+    type: 'COMPOUND',
+    amount: null,
+    currency: null,
+    debit_account: null,
+    credit_account: null,
+    execute_by: null,
+    narration: '',
+    sub_results: subResults,
+    status: failed.length === 0 ? done : 'failed',
+    status_reason:
+      failed.length === 0
+        ? PaymentMessages.COMPOUND_EXECUTED
+        : `${PaymentMessages.COMPOUND_CLAUSES_FAILED}: ${failed.length} of ${subResults.length}`,
+    status_code: failed.length === 0 ? 'AP00' : failed[0].status_code,
+    accounts: accountsOut,
+  };
+  if (dryRun) result.dry_run = true;
+  if (options.parseOnly && failed.length === 0) {
+    result.status_reason = '';
+    result.status_code = '';
+  }
+
+  timeLogger.end('process-clauses');
+  return result;
+}
+
+module.exports = processCompoundInstruction;
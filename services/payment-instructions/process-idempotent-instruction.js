@@ -0,0 +1,160 @@
+const validator = require('@app-core/validator');
+const { throwAppError, ERROR_CODE } = require('@app-core/errors');
+const { appLogger } = require('@app-core/logger');
+const PaymentMessages = require('@app/messages/payment-instructions');
+const paymentInstructions = require('./payment-instructions');
+const createMemoryIdempotencyStore = require('./stores/create-memory-idempotency-store');
+const noopAuditSink = require('./audit-sinks/noop-audit-sink');
+const writeAuditRecord = require('./audit-sinks/write-audit-record');
+const { buildAuditRecord, currentTime } = require('./helpers');
+
+// -----------------------------
+// VSL Spec (only the idempotency fields; the instruction payload is validated downstream)
+// -----------------------------
+const spec = `root {
+  idempotency_key? string<trim|minLength:1|maxLength:255>
+  dry_run? boolean
+}`;
+
+const parsedSpec = validator.parse(spec);
+
+// Results are kept for 24 hours unless options.idempotencyTtlMs says otherwise
+const DEFAULT_IDEMPOTENCY_TTL_MS = 24 * 60 * 60 * 1000;
+
+// How long a key stays reserved for a request that is executing; should its process die
+// before storing the result, the key is free again after this
+const RESERVATION_TTL_MS = 60 * 1000;
+
+const defaultStore = createMemoryIdempotencyStore();
+
+// Executions under way in this process, per store and key: { fingerprint, promise }. A
+// same-key request arriving meanwhile waits for the promise instead of executing again
+const inFlightByStore = new WeakMap();
+
+function inFlightFor(store) {
+  if (!inFlightByStore.has(store)) inFlightByStore.set(store, new Map());
+  return inFlightByStore.get(store);
+}
+
+/**
+ * JSON with object keys sorted, so equal payloads always produce the same string.
+ */
+function stableStringify(value) {
+  let out;
+  if (Array.isArray(value)) {
+    out = `[${value.map((v) => stableStringify(v)).join(',')}]`;
+  } else if (value !== null && typeof value === 'object') {
+    const keys = Object.keys(value).sort();
+    out = `{${keys.map((k) => `${JSON.stringify(k)}:${stableStringify(value[k])}`).join(',')}}`;
+  } else {
+    out = JSON.stringify(value === undefined ? null : value);
+  }
+  return out;
+}
+
+/**
+ * Execute a payment instruction at most once per idempotency key.
+ *
+ * Without a key (or for dry runs) this is a plain call to the payment-instructions service.
+ * With a key, the first result is stored for the retention window and replayed for retries
+ * carrying the same key and the same payload; a different payload under a known key is
+ * rejected. The key is reserved in the store before the instruction executes, so a retry
+ * that arrives while the first request is still running never executes it again: in the
+ * same process it waits for the first result, and against a store shared with another
+ * process that holds the key it is refused with IDEMPOTENCY_KEY_IN_PROGRESS (409). Thrown
+ * errors are not stored, so a retry after one executes normally. Replays and rejected keys
+ * are audited to options.auditSink like executed instructions, a replay with replayed: true.
+ *
+ * @param {Object} serviceData - payment-instructions payload plus optional idempotency_key
+ * @param {{ idempotencyStore?: Object, idempotencyTtlMs?: number }} [options] - also passed
+ *   through to the payment-instructions service
+ */
+async function processIdempotentInstruction(serviceData, options = {}) {
+  let result;
+  // A payload refused here, a replay or a reused key never reaches the service: each is
+  // audited from here instead
+  const auditSink = options.auditSink || noopAuditSink;
+  let data;
+  try {
+    data = validator.validate(serviceData, parsedSpec);
+  } catch (err) {
+    const time = currentTime(options);
+    await writeAuditRecord(auditSink, buildAuditRecord({ serviceData, error: err, time }));
+    throw err;
+  }
+  const key = data.idempotency_key;
+
+  if (!key || data.dry_run === true || options.dryRun === true) {
+    result = await paymentInstructions(serviceData, options);
+  } else {
+    const store = options.idempotencyStore || defaultStore;
+    const ttlMs =
+      options.idempotencyTtlMs > 0 ? options.idempotencyTtlMs : DEFAULT_IDEMPOTENCY_TTL_MS;
+    const payload = { ...serviceData };
+    delete payload.idempotency_key;
+    const fingerprint = stableStringify(payload);
+
+    const audit = { serviceData: payload, idempotencyKey: key, time: currentTime(options) };
+
+    const rejectReused = async () => {
+      const error = {
+        message: PaymentMessages.IDEMPOTENCY_KEY_REUSED,
+        errorCode: ERROR_CODE.DUPLRCRD,
+      };
+      await writeAuditRecord(auditSink, buildAuditRecord({ ...audit, error }));
+      throwAppError(PaymentMessages.IDEMPOTENCY_KEY_REUSED, ERROR_CODE.DUPLRCRD);
+    };
+    const replay = async (replayed) => {
+      appLogger.info({ idempotency_key: key }, 'payment-instructions.idempotent-replay');
+      await writeAuditRecord(
+        auditSink,
+        buildAuditRecord({ ...audit, result: replayed, replayed: true })
+      );
+      return replayed;
+    };
+
+    // Reserve the key, then execute; a key held already is a replay, a reused key or a
+    // request still executing in another process sharing the store
+    const executeOnce = async () => {
+      if (await store.reserve(key, { fingerprint, pending: true }, RESERVATION_TTL_MS)) {
+        let executed;
+        try {
+          executed = await paymentInstructions(payload, { ...options, idempotencyKey: key });
+          await store.set(key, { fingerprint, result: executed }, ttlMs);
+        } catch (err) {
+          await store.delete(key);
+          throw err;
+        }
+        return executed;
+      }
+      const stored = await store.get(key);
+      if (stored && stored.fingerprint !== fingerprint) await rejectReused();
+      if (!stored || stored.pending) {
+        throwAppError(PaymentMessages.IDEMPOTENCY_KEY_IN_PROGRESS, ERROR_CODE.DUPLRCRD);
+      }
+      return replay(stored.result);
+    };
+
+    const inFlight = inFlightFor(store);
+    const running = inFlight.get(key);
+    if (running) {
+      // Same key while the first request is still running here: wait for its result
+      if (running.fingerprint !== fingerprint) await rejectReused();
+      result = await replay(await running.promise);
+    } else {
+      // Registered before the first store call, so a same-key request arriving during it
+      // already finds this one running
+      const promise = executeOnce();
+      inFlight.set(key, { fingerprint, promise });
+      try {
+        result = await promise;
+      } finally {
+        inFlight.delete(key);
+      }
+    }
+  }
+
+  return result;
+}
+
+module.exports = processIdempotentInstruction;
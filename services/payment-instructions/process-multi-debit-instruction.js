@@ -0,0 +1,663 @@
+const validator = require('@app-core/validator');
+const { throwAppError, ERROR_CODE } = require('@app-core/errors');
+const { appLogger, TimeLogger } = require('@app-core/logger');
+const PaymentMessages = require('@app/messages/payment-instructions');
+const {
+  parseAmount,
+  parseNegativeAmount,
+  findAmbiguousAmount,
+  resolveCurrency,
+  isCurrencySymbol,
+  omitsCurrency,
+  readDefaultCurrency,
+  listHeldCurrencies,
+  isUnknownCurrencyCode,
+  placeCurrencySymbol,
+  resolveAccountAlias,
+  parseAccountReference,
+  findAccountsBySuffix,
+  findAccountsIgnoringCase,
+  tokenize,
+  isValidAccountId,
+  createAccountIdMatcher,
+  findAccount,
+  findBlockedAccount,
+  toMinorUnits,
+  fromMinorUnits,
+  fitsMinorUnits,
+  calculateFee,
+  exceededAmountCap,
+  parseAccountList,
+  parseNarration,
+  scoreConfidence,
+  buildWarnings,
+  describeGuesses,
+  correctCurrencyWord,
+  FEE_POLICY,
+  ROUNDING_POLICY,
+  MAX_AMOUNT_POLICY,
+  currentTime,
+  describeCurrencyMismatch,
+  echoAccounts,
+} = require('./helpers');
+const defaultTransactionStore = require('./stores/default-transaction-store');
+const defaultIdGenerator = require('./id-generators/default-id-generator');
+const { parsedInstructionRequestSpec: parsedSpec } = require('./request-spec');
+
+/**
+ * Execute a MULTI_DEBIT instruction: several debits, one credit.
+ *
+ *   TRANSFER [OF] <amount> <currency> TO [ACCOUNT] <acct> FROM [ACCOUNT] <acct>, <acct> AND <acct>
+ *
+ * The debit accounts are drawn down in the order listed until the amount (plus any
+ * MULTI_DEBIT fee) is covered. Each account gives at most its balance above its minimum
+ * balance (0 when unset) and what is left of its daily limit; overdrafts are never used.
+ * When the accounts together cannot cover the total nothing is debited or credited (AC01).
+ * All accounts share the instruction currency.
+ *
+ * Called by the payment-instructions service, which passes its daily debit store in
+ * options.dailyDebitStore and any verb it corrected (fuzzy_keywords) in
+ * options.keywordCorrections.
+ */
+async function processMultiDebitInstruction(serviceData, options = {}) {
+  let result;
+
+  const timeLogger = new TimeLogger('process-multi-debit-instruction');
+  timeLogger.start('validate-input');
+
+  let data;
+  try {
+    data = validator.validate(serviceData, parsedSpec);
+  } catch (err) {
+    appLogger.errorX({ error: err, payload: serviceData }, 'process-multi-debit.validation-failed');
+    throwAppError(PaymentMessages.MALFORMED_INSTRUCTION, ERROR_CODE.VALIDATIONERR);
+  }
+
+  timeLogger.end('validate-input');
+  timeLogger.start('parse-instruction');
+
+  const now = currentTime(options);
+  const dryRun = data.dry_run === true || options.dryRun === true;
+  // "1,000.50" by default; "1.000,50" when the decimal separator is ','
+  const decimalSeparator =
+    data.decimal_separator === ',' || options.decimalSeparator === ',' ? ',' : '.';
+  const strict = data.strict === true || options.strict === true;
+  const defaultCurrency = strict ? null : readDefaultCurrency(data, options);
+
+  // Every response carries an id, failures included; options.idGenerator replaces the default
+  const idGenerator = options.idGenerator || defaultIdGenerator;
+  const baseResponse = {
+    transaction_id: idGenerator.next(),
+    type: 'MULTI_DEBIT',
+    amount: null,
+    currency: null,
+    debit_account: null,
+    credit_account: null,
+    execute_by: null,
+    narration: '',
+    debits: [],
+    status: 'failed',
+    status_reason: '',
+    status_code: '',
+    accounts: [],
+  };
+  if (dryRun) baseResponse.dry_run = true;
+  if (options.matchedVerb) baseResponse.matched_verb = options.matchedVerb;
+
+  const accounts = data.accounts;
+  const rawTokens = tokenize(data.instruction);
+
+  // "TRANSFER OF 10000 NGN ..." - the "of" is optional filler
+  const amountStart = String(rawTokens[1]).toLowerCase() === 'of' ? 2 : 1;
+  // "₦5000", "$ 20" or "20€": the symbol takes the place of the currency token
+  const tokens = placeCurrencySymbol(
+    rawTokens,
+    amountStart,
+    decimalSeparator,
+    options.amountParsers
+  );
+  const lowerTokens = [];
+  for (let i = 0; i < tokens.length; i++) lowerTokens.push(tokens[i].toLowerCase());
+  // A range or an estimate is no amount to send ("100 to 200", "about 500")
+  const ambiguousAmount = findAmbiguousAmount(tokens, amountStart, accounts, decimalSeparator);
+  if (ambiguousAmount !== null) {
+    result = {
+      ...baseResponse,
+      status_reason: `${PaymentMessages.AMBIGUOUS_AMOUNT}: "${ambiguousAmount.text}"`,
+      status_code: 'AM04',
+    };
+    timeLogger.end('parse-instruction');
+    return result;
+  }
+  const parsedAmount = parseAmount(tokens, amountStart, decimalSeparator, options.amountParsers);
+  const amountConsumed = parsedAmount ? parsedAmount.consumed : 1;
+  const amountRatio = parsedAmount && parsedAmount.ratio ? parsedAmount.ratio : null;
+  // Without a currency the configured default currency is read in its place, or else the one
+  // every account involved holds
+  const currencyOmitted = omitsCurrency(tokens[amountStart + amountConsumed], accounts);
+  const currencyInferred = currencyOmitted && defaultCurrency === null;
+  const currencyToken = currencyOmitted ? defaultCurrency : tokens[amountStart + amountConsumed];
+  const shownCurrency = currencyToken !== null ? String(currencyToken).toUpperCase() : null;
+  const clauseStart = amountStart + amountConsumed + (currencyOmitted ? 0 : 1);
+
+  // A share of "the balance" has no single account to take it from
+  if (
+    currencyToken === undefined ||
+    amountRatio !== null ||
+    (parsedAmount !== null && parsedAmount.amount === null)
+  ) {
+    result = {
+      ...baseResponse,
+      status_reason: PaymentMessages.MALFORMED_INSTRUCTION,
+      status_code: 'SY03',
+    };
+    timeLogger.end('parse-instruction');
+    return result;
+  }
+  const negativeAmount =
+    parsedAmount === null ? parseNegativeAmount(tokens[amountStart], decimalSeparator) : null;
+  if (negativeAmount !== null || (parsedAmount !== null && parsedAmount.amount === 0)) {
+    result = {
+      ...baseResponse,
+      amount: negativeAmount !== null ? negativeAmount : 0,
+      currency: shownCurrency,
+      status_reason:
+        negativeAmount !== null
+          ? PaymentMessages.AMOUNT_MUST_NOT_BE_NEGATIVE
+          : PaymentMessages.AMOUNT_MUST_BE_POSITIVE,
+      status_code: negativeAmount !== null ? 'AM03' : 'AM01',
+    };
+    timeLogger.end('parse-instruction');
+    return result;
+  }
+  if (parsedAmount === null) {
+    result = {
+      ...baseResponse,
+      currency: shownCurrency,
+      status_reason: PaymentMessages.AMOUNT_MUST_BE_POSITIVE_NUMBER,
+      status_code: 'AM01',
+    };
+    timeLogger.end('parse-instruction');
+    return result;
+  }
+  const { amount } = parsedAmount;
+
+  const heldCurrencies = accounts.map((a) => String(a.currency || '').toUpperCase());
+  let currencyCandidates = currencyInferred
+    ? listHeldCurrencies(accounts)
+    : resolveCurrency(currencyToken, heldCurrencies);
+  const fuzzy = data.fuzzy_keywords === true || options.fuzzyKeywords === true;
+  const corrections = (options.keywordCorrections || []).slice();
+  const correctedCurrency =
+    fuzzy && !currencyInferred && currencyCandidates.length === 0
+      ? correctCurrencyWord(currencyToken)
+      : null;
+  if (correctedCurrency !== null) {
+    corrections.push({ from: currencyToken, to: correctedCurrency });
+    currencyCandidates = resolveCurrency(correctedCurrency, heldCurrencies);
+  }
+  let currency = currencyCandidates.length === 1 ? currencyCandidates[0] : null;
+  const confidenceSignals = corrections.map(() => 'fuzzy');
+  const amountLead = String(tokens[amountStart])[0];
+  if (amountRatio !== null) confidenceSignals.push('balance_share');
+  else if (amountLead < '0' || amountLead > '9') confidenceSignals.push('word_amount');
+  if (currencyCandidates.length > 1 || currencyInferred) {
+    confidenceSignals.push('currency_inferred');
+  }
+  if (currencyCandidates.length === 0) {
+    result = {
+      ...baseResponse,
+      amount,
+      currency: shownCurrency,
+      status_reason: isUnknownCurrencyCode(currencyToken)
+        ? `${PaymentMessages.UNKNOWN_CURRENCY_CODE}: ${String(currencyToken).toUpperCase()}`
+        : PaymentMessages.UNSUPPORTED_CURRENCY,
+      status_code: isUnknownCurrencyCode(currencyToken) ? 'CU04' : 'CU02',
+    };
+    timeLogger.end('parse-instruction');
+    return result;
+  }
+  // A symbol several held currencies share ("Sh" with both KES and UGX accounts)
+  if (currencyCandidates.length > 1 && isCurrencySymbol(currencyToken)) {
+    result = {
+      ...baseResponse,
+      amount,
+      currency: null,
+      status_reason: `${PaymentMessages.AMBIGUOUS_CURRENCY_SYMBOL}: "${currencyToken}" could be ${currencyCandidates.join(' or ')}`,
+      status_code: 'CU06',
+    };
+    timeLogger.end('parse-instruction');
+    return result;
+  }
+  // Decimal places must fit the currency's minor units ("10.005 USD" does not)
+  // (an inferred currency is checked once it is known)
+  if (!currencyInferred && !currencyCandidates.every((c) => fitsMinorUnits(amount, c))) {
+    result = {
+      ...baseResponse,
+      amount,
+      currency,
+      status_reason: PaymentMessages.AMOUNT_TOO_MANY_DECIMALS,
+      status_code: 'CU03',
+    };
+    timeLogger.end('parse-instruction');
+    return result;
+  }
+
+  // TO [ACCOUNT] <acct>
+  const iTo = lowerTokens.indexOf('to', clauseStart);
+  const iCreditId = lowerTokens[iTo + 1] === 'account' ? iTo + 2 : iTo + 1;
+  if (iTo === -1 || iCreditId >= tokens.length) {
+    result = {
+      ...baseResponse,
+      amount,
+      currency,
+      status_reason: PaymentMessages.MISSING_REQUIRED_KEYWORD,
+      status_code: 'SY01',
+    };
+    timeLogger.end('parse-instruction');
+    return result;
+  }
+  const creditRef = parseAccountReference(tokens, iCreditId, accounts);
+  let creditAccountId = creditRef.token;
+
+  // FROM <debit accounts>
+  const iFrom = iCreditId + creditRef.consumed;
+  if (lowerTokens[iFrom] !== 'from') {
+    result = {
+      ...baseResponse,
+      amount,
+      currency,
+      credit_account: creditAccountId,
+      status_reason:
+        lowerTokens[iFrom] === undefined
+          ? PaymentMessages.MISSING_REQUIRED_KEYWORD
+          : PaymentMessages.INVALID_KEYWORD_ORDER,
+      status_code: lowerTokens[iFrom] === undefined ? 'SY01' : 'SY02',
+    };
+    timeLogger.end('parse-instruction');
+    return result;
+  }
+  // A trailing "for <text>" / "ref: <text>" is the narration, not a debit account
+  const narration = parseNarration(tokens, iFrom + 1);
+  baseResponse.narration = narration.text;
+  const parsedSources = parseAccountList(
+    tokens.slice(iFrom + 1, narration.start),
+    decimalSeparator,
+    accounts
+  );
+  if (parsedSources === null || parsedSources.explicit || parsedSources.shares !== null) {
+    result = {
+      ...baseResponse,
+      amount,
+      currency,
+      credit_account: creditAccountId,
+      status_reason: PaymentMessages.INVALID_DEBIT_SOURCES,
+      status_code: 'SY03',
+    };
+    timeLogger.end('parse-instruction');
+    return result;
+  }
+  const sources = parsedSources.entries;
+
+  // Alias names and trailing-digit references resolve exactly as for single instructions
+  const aliases =
+    data.aliases && typeof data.aliases === 'object' && !Array.isArray(data.aliases)
+      ? data.aliases
+      : null;
+  const ignoreCase = data.case_insensitive_ids === true || options.caseInsensitiveIds === true;
+  // Ids must also have the caller's shape when options.accountIdFormat gives one
+  const matchesIdFormat = createAccountIdMatcher(options.accountIdFormat);
+  const refs = [creditRef];
+  for (let s = 0; s < sources.length; s++) refs.push(sources[s].ref);
+  const resolvedIds = [];
+  for (let k = 0; k < refs.length; k++) resolvedIds.push(refs[k].token);
+  for (let k = 0; k < refs.length; k++) {
+    const ref = refs[k];
+    let failure = null;
+    const alias = aliases !== null ? resolveAccountAlias(ref.token, aliases) : null;
+    if (alias && alias.ambiguous) {
+      const candidates = `"${ref.token}" could be ${alias.ambiguous.join(' or ')}`;
+      failure = {
+        reason: `${PaymentMessages.AMBIGUOUS_ACCOUNT_ALIAS}: ${candidates}`,
+        code: 'AC05',
+        candidates: alias.ambiguous,
+      };
+    } else if (alias) {
+      resolvedIds[k] = alias.id;
+      confidenceSignals.push('alias');
+    } else if (ref.suffix !== null) {
+      const matches = findAccountsBySuffix(accounts, ref.suffix);
+      if (matches.length === 1) {
+        resolvedIds[k] = matches[0];
+        confidenceSignals.push('partial_account');
+      } else if (matches.length === 0) {
+        failure = {
+          reason: `${PaymentMessages.ACCOUNT_NOT_FOUND}: no account ending ${ref.suffix}`,
+          code: 'AC03',
+        };
+      } else {
+        const listed = `"${ref.token}" matches ${matches.join(', ')}`;
+        failure = {
+          reason: `${PaymentMessages.AMBIGUOUS_ACCOUNT_REFERENCE}: ${listed}`,
+          code: 'AC06',
+          candidates: matches,
+        };
+      }
+    }
+    const caseMatches = ignoreCase ? findAccountsIgnoringCase(accounts, resolvedIds[k]) : [];
+    if (failure === null && caseMatches.length > 1) {
+      const candidates = `"${resolvedIds[k]}" could be ${caseMatches.join(' or ')}`;
+      failure = {
+        reason: `${PaymentMessages.AMBIGUOUS_ACCOUNT_CASE}: ${candidates}`,
+        code: 'AC05',
+        candidates: caseMatches,
+      };
+    } else if (failure === null && caseMatches.length === 1 && caseMatches[0] !== resolvedIds[k]) {
+      resolvedIds[k] = caseMatches[0];
+      confidenceSignals.push('case_insensitive');
+    }
+    if (failure === null && !isValidAccountId(resolvedIds[k])) {
+      failure = { reason: PaymentMessages.INVALID_ACCOUNT_ID_FORMAT, code: 'AC04' };
+    }
+    if (failure === null && !matchesIdFormat(resolvedIds[k])) {
+      const reason = `${PaymentMessages.MALFORMED_ACCOUNT_REFERENCE}: ${resolvedIds[k]}`;
+      failure = { reason, code: 'AC04' };
+    }
+    if (failure !== null) {
+      result = {
+        ...baseResponse,
+        amount,
+        currency,
+        credit_account: resolvedIds[0],
+        status_reason: failure.reason,
+        status_code: failure.code,
+        ...(failure.candidates ? { candidates: failure.candidates } : {}),
+      };
+      timeLogger.end('parse-instruction');
+      return result;
+    }
+  }
+  baseResponse.confidence = scoreConfidence(confidenceSignals);
+  if (strict && confidenceSignals.length > 0) {
+    result = {
+      ...baseResponse,
+      amount,
+      currency,
+      credit_account: creditAccountId,
+      status_reason: `${PaymentMessages.STRICT_MODE_GUESS}: ${describeGuesses(
+        confidenceSignals,
+        PaymentMessages.STRICT_MODE_GUESSES
+      )}`,
+      status_code: 'SY06',
+    };
+    timeLogger.end('parse-instruction');
+    return result;
+  }
+  const correctionReason = corrections
+    .map((c) => `; ${PaymentMessages.KEYWORD_CORRECTED} "${c.from}" to ${c.to}`)
+    .join('');
+  creditAccountId = resolvedIds[0];
+  const debitIds = resolvedIds.slice(1);
+
+  const creditEntry = findAccount(accounts, creditAccountId);
+  let missing = creditEntry === null;
+  for (let s = 0; s < debitIds.length; s++) {
+    if (findAccount(accounts, debitIds[s]) === null) missing = true;
+  }
+  if (missing) {
+    result = {
+      ...baseResponse,
+      amount,
+      currency,
+      credit_account: creditAccountId,
+      status_reason: PaymentMessages.ACCOUNT_NOT_FOUND,
+      status_code: 'AC03',
+    };
+    timeLogger.end('parse-instruction');
+    return result;
+  }
+  const involvedIds = [...debitIds, creditAccountId];
+
+  // Every account must be in the instruction currency (no FX between legs)
+  const creditAccCurr = String(creditEntry.account.currency || '').toUpperCase();
+  if (currency === null) {
+    const matchesCredit = currencyCandidates.indexOf(creditAccCurr) !== -1;
+    currency = matchesCredit ? creditAccCurr : currencyCandidates[0];
+  }
+  let currencyMismatch = false;
+  for (let s = 0; s < debitIds.length; s++) {
+    const debit = findAccount(accounts, debitIds[s]).account;
+    if (String(debit.currency || '').toUpperCase() !== creditAccCurr) currencyMismatch = true;
+  }
+  if (currencyMismatch || creditAccCurr !== currency) {
+    // Without a currency written, accounts in different currencies leave it to the
+    // instruction to say which (CU02)
+    let message = currencyMismatch
+      ? PaymentMessages.ACCOUNT_CURRENCY_MISMATCH
+      : PaymentMessages.INSTRUCTION_CURRENCY_NOT_HELD;
+    if (currencyInferred) message = PaymentMessages.CURRENCY_NOT_GIVEN;
+    const held = involvedIds.map((id) => findAccount(accounts, id).account);
+    result = {
+      ...baseResponse,
+      amount,
+      currency: currencyInferred ? null : currency,
+      credit_account: creditAccountId,
+      status_reason: `${message}: ${describeCurrencyMismatch(currency, held)}`,
+      status_code: currencyMismatch && !currencyInferred ? 'CU01' : 'CU02',
+      accounts: echoAccounts(accounts, involvedIds),
+    };
+    timeLogger.end('parse-instruction');
+    return result;
+  }
+  // Decimal places must fit an inferred currency's minor units too
+  if (currencyInferred && !fitsMinorUnits(amount, currency)) {
+    result = {
+      ...baseResponse,
+      amount,
+      currency,
+      credit_account: creditAccountId,
+      status_reason: PaymentMessages.AMOUNT_TOO_MANY_DECIMALS,
+      status_code: 'CU03',
+      accounts: echoAccounts(accounts, involvedIds),
+    };
+    timeLogger.end('parse-instruction');
+    return result;
+  }
+
+  // The credit account and every debit account must be distinct
+  let duplicate = null;
+  for (let k = 0; k < involvedIds.length && duplicate === null; k++) {
+    if (involvedIds.indexOf(involvedIds[k]) !== k) duplicate = involvedIds[k];
+  }
+  if (duplicate !== null) {
+    result = {
+      ...baseResponse,
+      amount,
+      currency,
+      credit_account: creditAccountId,
+      status_reason:
+        duplicate === creditAccountId
+          ? PaymentMessages.DEBIT_CREDIT_SAME_ACCOUNT
+          : `${PaymentMessages.DUPLICATE_DEBIT_SOURCE}: ${duplicate}`,
+      status_code: 'AC02',
+      accounts: echoAccounts(accounts, involvedIds),
+    };
+    timeLogger.end('parse-instruction');
+    return result;
+  }
+
+  const warnings = buildWarnings(confidenceSignals, PaymentMessages.WARNINGS);
+  const warningFields = warnings.length > 0 ? { warnings } : {};
+
+  // options.parseOnly (see parse-instruction.js) stops here: how much each account gives
+  // depends on the balances, so the debits carry no amounts yet
+  if (options.parseOnly) {
+    result = {
+      ...baseResponse,
+      amount,
+      currency,
+      credit_account: creditAccountId,
+      debits: debitIds.map((id) => ({ account: id, amount: null })),
+      ...warningFields,
+      status: 'parsed',
+    };
+    timeLogger.end('parse-instruction');
+    return result;
+  }
+
+  // Per-transaction cap on the total drawn (LM02)
+  const maxAmountPolicy = options.maxAmountPolicy || MAX_AMOUNT_POLICY;
+  const cap = exceededAmountCap(amount, 'MULTI_DEBIT', currency, maxAmountPolicy);
+  if (cap !== null) {
+    result = {
+      ...baseResponse,
+      amount,
+      currency,
+      credit_account: creditAccountId,
+      status_reason: `${PaymentMessages.AMOUNT_EXCEEDS_TRANSACTION_LIMIT}: limit is ${cap} ${currency} for MULTI_DEBIT`,
+      status_code: 'LM02',
+      accounts: echoAccounts(accounts, involvedIds),
+    };
+    timeLogger.end('parse-instruction');
+    return result;
+  }
+
+  // A frozen (AC08) or closed (AC09) account takes no part, checked before any balance rule
+  const blocked = findBlockedAccount(involvedIds.map((id) => findAccount(accounts, id).account));
+  if (blocked !== null) {
+    const blockedMessage =
+      blocked.status === 'frozen' ? PaymentMessages.ACCOUNT_FROZEN : PaymentMessages.ACCOUNT_CLOSED;
+    result = {
+      ...baseResponse,
+      amount,
+      currency,
+      credit_account: creditAccountId,
+      status_reason: `${blockedMessage}: ${blocked.account.id}`,
+      status_code: blocked.code,
+      accounts: echoAccounts(accounts, involvedIds),
+    };
+    timeLogger.end('parse-instruction');
+    return result;
+  }
+
+  // Optional MULTI_DEBIT fee, drawn with the amount (the credit account receives the full amount)
+  const feePolicy = options.feePolicy || FEE_POLICY;
+  const roundingPolicy = options.roundingPolicy || ROUNDING_POLICY;
+  const fee = calculateFee(amount, 'MULTI_DEBIT', feePolicy, currency, roundingPolicy);
+  const feeFields = fee !== null ? { fee } : {};
+  const totalMinor =
+    toMinorUnits(amount, currency) + (fee !== null ? toMinorUnits(fee, currency) : 0);
+  const totalDebit = fromMinorUnits(totalMinor, currency);
+  const feeText =
+    fee !== null && fee > 0
+      ? ` (${amount} ${currency} + ${fee} ${currency} ${PaymentMessages.FEE})`
+      : '';
+
+  // Draw each debit account down in order, never past its floor or today's remaining limit
+  const dailyDebitStore = options.dailyDebitStore;
+  const debitDay = now.toISOString().slice(0, 10);
+  const debits = [];
+  let remainingMinor = totalMinor;
+  let availableMinor = 0;
+  for (let s = 0; s < debitIds.length; s++) {
+    const debit = findAccount(accounts, debitIds[s]).account;
+    const floor = debit.minimum_balance !== undefined ? Number(debit.minimum_balance) : 0;
+    let available = Math.max(
+      toMinorUnits(Number(debit.balance), currency) - toMinorUnits(Math.max(floor, 0), currency),
+      0
+    );
+    if (debit.daily_limit !== undefined && dailyDebitStore) {
+      // eslint-disable-next-line no-await-in-loop
+      const debitedToday = await dailyDebitStore.getTotal(debitIds[s], debitDay);
+      const leftToday =
+        toMinorUnits(Number(debit.daily_limit), currency) - toMinorUnits(debitedToday, currency);
+      available = Math.min(available, Math.max(leftToday, 0));
+    }
+    availableMinor += available;
+    const drawn = Math.min(available, remainingMinor);
+    if (drawn > 0) {
+      debits.push({ account: debitIds[s], amount: fromMinorUnits(drawn, currency) });
+      remainingMinor -= drawn;
+    }
+  }
+  if (remainingMinor > 0) {
+    const availableTotal = fromMinorUnits(availableMinor, currency);
+    result = {
+      ...baseResponse,
+      amount,
+      currency,
+      credit_account: creditAccountId,
+      ...feeFields,
+      status_reason: `${PaymentMessages.INSUFFICIENT_COMBINED_FUNDS}: has ${availableTotal} ${currency} across ${debitIds.join(', ')}, needs ${totalDebit} ${currency}${feeText}`,
+      status_code: 'AC01',
+      accounts: echoAccounts(accounts, involvedIds),
+    };
+    timeLogger.end('parse-instruction');
+    return result;
+  }
+
+  // Apply every debit and the credit together
+  const accountsOut = [];
+  for (let i = 0; i < accounts.length; i++) {
+    const a = accounts[i];
+    const before = Number(a.balance);
+    let after = null;
+    // Balance arithmetic runs in integer minor units, as for a single instruction
+    if (a.id === creditAccountId) {
+      after = fromMinorUnits(
+        toMinorUnits(before, currency) + toMinorUnits(amount, currency),
+        currency
+      );
+    } else if (debitIds.indexOf(a.id) !== -1) {
+      after = before;
+      for (let d = 0; d < debits.length; d++) {
+        if (debits[d].account === a.id) {
+          const drawnMinor = toMinorUnits(debits[d].amount, currency);
+          after = fromMinorUnits(toMinorUnits(before, currency) - drawnMinor, currency);
+        }
+      }
+    }
+    if (after !== null) {
+      accountsOut.push({
+        id: a.id,
+        balance: dryRun ? before : after,
+        balance_before: before,
+        ...(dryRun ? { projected_balance: after } : {}),
+        currency: String(a.currency || '').toUpperCase(),
+      });
+    }
+  }
+  if (!dryRun && dailyDebitStore) {
+    for (let d = 0; d < debits.length; d++) {
+      // eslint-disable-next-line no-await-in-loop
+      await dailyDebitStore.add(debits[d].account, debitDay, debits[d].amount);
+    }
+  }
+
+  // Executed transactions are kept for reversal
+  const transactionStore = options.transactionStore || defaultTransactionStore;
+  const executedReason = dryRun
+    ? PaymentMessages.DRY_RUN_WOULD_EXECUTE
+    : PaymentMessages.TRANSACTION_EXECUTED;
+  result = {
+    ...baseResponse,
+    amount,
+    currency,
+    credit_account: creditAccountId,
+    debits,
+    ...feeFields,
+    ...warningFields,
+    status: 'successful',
+    status_reason: `${executedReason}${correctionReason}`,
+    status_code: 'AP00',
+    accounts: accountsOut,
+  };
+  if (!dryRun) {
+    await transactionStore.set(result.transaction_id, { transaction: result, reversal: null });
+  }
+
+  timeLogger.end('parse-instruction');
+  return result;
+}
+
+module.exports = processMultiDebitInstruction;
@@ -0,0 +1,189 @@
+const validator = require('@app-core/validator');
+const { throwAppError, ERROR_CODE } = require('@app-core/errors');
+const { appLogger, TimeLogger } = require('@app-core/logger');
+const PaymentMessages = require('@app/messages/payment-instructions');
+const { getReasonCategory } = require('@app/messages/payment-instruction-status-codes');
+const {
+  findAccount,
+  toMinorUnits,
+  fromMinorUnits,
+  selectWalletPockets,
+  isCompleteAccount,
+} = require('./helpers');
+const defaultTransactionStore = require('./stores/default-transaction-store');
+const defaultIdGenerator = require('./id-generators/default-id-generator');
+const { ACCOUNT_FIELDS_SPEC } = require('./request-spec');
+
+// -----------------------------
+// VSL Spec (validate incoming payload)
+// -----------------------------
+const spec = `root {
+  accounts[] ${ACCOUNT_FIELDS_SPEC}
+  transaction_id string<trim|minLength:1>
+}`;
+
+const parsedSpec = validator.parse(spec);
+
+/**
+ * Undo an executed transaction, given the transaction_id its response carried and the
+ * current account set.
+ *
+ * Every balance the original transaction moved is moved back by the same amount (fee
+ * included), as a new REVERSAL transaction. It fails with AC01, touching nothing, when an
+ * account that received money no longer holds enough to give it back. Reversing twice
+ * replays the first reversal instead of moving money again.
+ *
+ * @param {Object} serviceData - { accounts, transaction_id }
+ * @param {{ transactionStore?: Object }} [options] - store the original transaction was
+ *   recorded in (defaults to the process-wide store)
+ */
+async function processReversal(serviceData, options = {}) {
+  let result;
+
+  const timeLogger = new TimeLogger('process-reversal');
+  timeLogger.start('validate-input');
+
+  let data;
+  try {
+    data = validator.validate(serviceData, parsedSpec);
+    if (!data.accounts.every(isCompleteAccount)) throw new Error('Account balance missing');
+  } catch (err) {
+    appLogger.errorX({ error: err, payload: serviceData }, 'process-reversal.validation-failed');
+    throwAppError(PaymentMessages.MALFORMED_REVERSAL, ERROR_CODE.VALIDATIONERR);
+  }
+
+  timeLogger.end('validate-input');
+  timeLogger.start('reverse-transaction');
+
+  const transactionStore = options.transactionStore || defaultTransactionStore;
+  const record = await transactionStore.get(data.transaction_id);
+
+  // Every response carries an id, failures included; options.idGenerator replaces the default
+  const idGenerator = options.idGenerator || defaultIdGenerator;
+  const baseResponse = {
+    transaction_id: idGenerator.next(),
+    type: 'REVERSAL',
+    amount: null,
+    currency: null,
+    debit_account: null,
+    credit_account: null,
+    execute_by: null,
+    narration: '',
+    reversed_transaction_id: data.transaction_id,
+    status: 'failed',
+    status_reason: '',
+    status_code: '',
+    accounts: [],
+  };
+
+  if (record === null) {
+    result = {
+      ...baseResponse,
+      status_reason: `${PaymentMessages.TRANSACTION_NOT_FOUND}: ${data.transaction_id}`,
+      status_code: 'RV01',
+      reason_category: getReasonCategory('RV01'),
+    };
+    timeLogger.end('reverse-transaction');
+    return result;
+  }
+  if (record.reversal) {
+    appLogger.info({ transaction_id: data.transaction_id }, 'process-reversal.replayed');
+    timeLogger.end('reverse-transaction');
+    return record.reversal;
+  }
+
+  // Money flows the other way: the original credit side is debited and vice versa
+  const original = record.transaction;
+  // A wallet takes part through its pocket in the currency the transaction moved for it
+  const accounts = data.accounts.map((a) => {
+    const moved = original.accounts.find((m) => m.id === a.id);
+    return selectWalletPockets([a], moved ? moved.currency : original.currency)[0];
+  });
+  baseResponse.amount = original.amount;
+  baseResponse.currency = original.currency;
+  baseResponse.debit_account = original.credit_account;
+  baseResponse.credit_account = original.debit_account;
+  baseResponse.narration = original.narration || '';
+
+  let failure = null;
+  const moves = [];
+  for (let i = 0; i < original.accounts.length; i++) {
+    const moved = original.accounts[i];
+    const entry = findAccount(accounts, moved.id);
+    if (entry === null) {
+      failure = { reason: `${PaymentMessages.ACCOUNT_NOT_FOUND}: ${moved.id}`, code: 'AC03' };
+      break;
+    }
+    const currency = String(entry.account.currency || '').toUpperCase();
+    if (currency !== moved.currency) {
+      failure = {
+        reason: `${PaymentMessages.ACCOUNT_CURRENCY_MISMATCH}: ${moved.id} holds ${currency}, the transaction moved ${moved.currency}`,
+        code: 'CU01',
+      };
+      break;
+    }
+    const deltaMinor =
+      toMinorUnits(moved.balance, currency) - toMinorUnits(moved.balance_before, currency);
+    const beforeMinor = toMinorUnits(entry.account.balance, currency);
+    const afterMinor = beforeMinor - deltaMinor;
+    if (deltaMinor > 0 && afterMinor < 0) {
+      const needs = fromMinorUnits(deltaMinor, currency);
+      failure = {
+        reason: `${PaymentMessages.INSUFFICIENT_FUNDS_FOR_REVERSAL}: ${moved.id} has ${entry.account.balance} ${currency}, needs ${needs} ${currency}`,
+        code: 'AC01',
+      };
+      break;
+    }
+    moves.push({ index: entry.index, after: fromMinorUnits(afterMinor, currency) });
+  }
+
+  if (failure !== null) {
+    const accountsOut = [];
+    for (let i = 0; i < accounts.length; i++) {
+      const a = accounts[i];
+      if (original.accounts.some((moved) => moved.id === a.id)) {
+        accountsOut.push({
+          id: a.id,
+          balance: a.balance,
+          balance_before: a.balance,
+          currency: String(a.currency || '').toUpperCase(),
+        });
+      }
+    }
+    result = {
+      ...baseResponse,
+      status_reason: failure.reason,
+      status_code: failure.code,
+      reason_category: getReasonCategory(failure.code),
+      accounts: accountsOut,
+    };
+    timeLogger.end('reverse-transaction');
+    return result;
+  }
+
+  // Involved accounts in request order
+  moves.sort((x, y) => x.index - y.index);
+  const accountsOut = moves.map((move) => {
+    const a = accounts[move.index];
+    return {
+      id: a.id,
+      balance: move.after,
+      balance_before: a.balance,
+      currency: String(a.currency || '').toUpperCase(),
+    };
+  });
+
+  result = {
+    ...baseResponse,
+    status: 'successful',
+    status_reason: PaymentMessages.TRANSACTION_REVERSED,
+    status_code: 'AP00',
+    accounts: accountsOut,
+  };
+  await transactionStore.set(data.transaction_id, { transaction: original, reversal: result });
+
+  timeLogger.end('reverse-transaction');
+  return result;
+}
+
+module.exports = processReversal;
@@ -0,0 +1,789 @@
+const validator = require('@app-core/validator');
+const { throwAppError, ERROR_CODE } = require('@app-core/errors');
+const { appLogger, TimeLogger } = require('@app-core/logger');
+const PaymentMessages = require('@app/messages/payment-instructions');
+const {
+  parseAmount,
+  parseNegativeAmount,
+  findAmbiguousAmount,
+  resolveRatioAmount,
+  isRatioRounded,
+  resolveCurrency,
+  isCurrencySymbol,
+  omitsCurrency,
+  readDefaultCurrency,
+  listHeldCurrencies,
+  isUnknownCurrencyCode,
+  placeCurrencySymbol,
+  resolveAccountAlias,
+  parseAccountReference,
+  findAccountsBySuffix,
+  findAccountsIgnoringCase,
+  tokenize,
+  isValidAccountId,
+  createAccountIdMatcher,
+  findAccount,
+  findBlockedAccount,
+  toMinorUnits,
+  fromMinorUnits,
+  fitsMinorUnits,
+  calculateFee,
+  exceededAmountCap,
+  splitAmount,
+  splitAmountByWeights,
+  parseAccountList,
+  parseNarration,
+  scoreConfidence,
+  buildWarnings,
+  describeGuesses,
+  correctCurrencyWord,
+  FEE_POLICY,
+  ROUNDING_POLICY,
+  MAX_AMOUNT_POLICY,
+  currentTime,
+  describeCurrencyMismatch,
+  echoAccounts,
+} = require('./helpers');
+const defaultTransactionStore = require('./stores/default-transaction-store');
+const defaultIdGenerator = require('./id-generators/default-id-generator');
+const { parsedInstructionRequestSpec: parsedSpec } = require('./request-spec');
+
+/**
+ * Execute a SPLIT instruction: one debit, several credits.
+ *
+ *   SPLIT [OF] <amount> <currency> FROM [ACCOUNT] <acct> EQUALLY BETWEEN <acct>, <acct> AND <acct>
+ *   SPLIT [OF] <amount> <currency> FROM [ACCOUNT] <acct> TO <acct> <amount>, <acct> <amount>
+ *   SPLIT [OF] <amount> <currency> FROM [ACCOUNT] <acct> TO <acct> 60% AND <acct> 40%
+ *   SPLIT [OF] <amount> <currency> FROM [ACCOUNT] <acct> 2 PARTS TO <acct> 1 PART TO <acct>
+ *
+ * BETWEEN, AMONG and TO are interchangeable. Equal splits hand leftover minor units to the
+ * first recipients; explicit amounts must add up to the total. Percentages must add up to
+ * 100 to within their two-decimal rounding (3 x 33.33% does) and parts be whole numbers;
+ * both divide the amount by weight (see splitAmountByWeights). All recipients share the
+ * debit account's currency. The debit side follows the same rules as a single instruction
+ * (overdraft, minimum balance, daily limit) and nothing is credited unless the whole amount
+ * can be debited. SPLIT has no schedule date.
+ *
+ * Called by the payment-instructions service, which passes its daily debit store in
+ * options.dailyDebitStore and any verb it corrected (fuzzy_keywords) in
+ * options.keywordCorrections.
+ */
+async function processSplitInstruction(serviceData, options = {}) {
+  let result;
+
+  const timeLogger = new TimeLogger('process-split-instruction');
+  timeLogger.start('validate-input');
+
+  let data;
+  try {
+    data = validator.validate(serviceData, parsedSpec);
+  } catch (err) {
+    appLogger.errorX({ error: err, payload: serviceData }, 'process-split.validation-failed');
+    throwAppError(PaymentMessages.MALFORMED_INSTRUCTION, ERROR_CODE.VALIDATIONERR);
+  }
+
+  timeLogger.end('validate-input');
+  timeLogger.start('parse-instruction');
+
+  const now = currentTime(options);
+  const dryRun = data.dry_run === true || options.dryRun === true;
+  // "1,000.50" by default; "1.000,50" when the decimal separator is ','
+  const decimalSeparator =
+    data.decimal_separator === ',' || options.decimalSeparator === ',' ? ',' : '.';
+  const strict = data.strict === true || options.strict === true;
+  const defaultCurrency = strict ? null : readDefaultCurrency(data, options);
+
+  // Every response carries an id, failures included; options.idGenerator replaces the default
+  const idGenerator = options.idGenerator || defaultIdGenerator;
+  const baseResponse = {
+    transaction_id: idGenerator.next(),
+    type: 'SPLIT',
+    amount: null,
+    currency: null,
+    debit_account: null,
+    credit_account: null,
+    execute_by: null,
+    narration: '',
+    splits: [],
+    status: 'failed',
+    status_reason: '',
+    status_code: '',
+    accounts: [],
+  };
+  if (dryRun) baseResponse.dry_run = true;
+  if (options.matchedVerb) baseResponse.matched_verb = options.matchedVerb;
+
+  const accounts = data.accounts;
+  const rawTokens = tokenize(data.instruction);
+
+  // "SPLIT OF 9000 NGN ..." - the "of" is optional filler
+  const amountStart = String(rawTokens[1]).toLowerCase() === 'of' ? 2 : 1;
+  // "₦5000", "$ 20" or "20€": the symbol takes the place of the currency token
+  const tokens = placeCurrencySymbol(
+    rawTokens,
+    amountStart,
+    decimalSeparator,
+    options.amountParsers
+  );
+  const lowerTokens = [];
+  for (let i = 0; i < tokens.length; i++) lowerTokens.push(tokens[i].toLowerCase());
+  // A range or an estimate is no amount to send ("100 to 200", "about 500")
+  const ambiguousAmount = findAmbiguousAmount(tokens, amountStart, accounts, decimalSeparator);
+  if (ambiguousAmount !== null) {
+    result = {
+      ...baseResponse,
+      status_reason: `${PaymentMessages.AMBIGUOUS_AMOUNT}: "${ambiguousAmount.text}"`,
+      status_code: 'AM04',
+    };
+    timeLogger.end('parse-instruction');
+    return result;
+  }
+  const parsedAmount = parseAmount(tokens, amountStart, decimalSeparator, options.amountParsers);
+  const amountConsumed = parsedAmount ? parsedAmount.consumed : 1;
+  const amountRatio = parsedAmount && parsedAmount.ratio ? parsedAmount.ratio : null;
+  // Without a currency the configured default currency is read in its place, or else the one
+  // every account involved holds
+  const currencyOmitted = omitsCurrency(tokens[amountStart + amountConsumed], accounts);
+  const currencyInferred = currencyOmitted && defaultCurrency === null;
+  const currencyToken = currencyOmitted ? defaultCurrency : tokens[amountStart + amountConsumed];
+  const shownCurrency = currencyToken !== null ? String(currencyToken).toUpperCase() : null;
+  const clauseStart = amountStart + amountConsumed + (currencyOmitted ? 0 : 1);
+
+  if (
+    currencyToken === undefined ||
+    (parsedAmount !== null && parsedAmount.amount === null && amountRatio === null)
+  ) {
+    result = {
+      ...baseResponse,
+      status_reason: PaymentMessages.MALFORMED_INSTRUCTION,
+      status_code: 'SY03',
+    };
+    timeLogger.end('parse-instruction');
+    return result;
+  }
+  const negativeAmount =
+    parsedAmount === null ? parseNegativeAmount(tokens[amountStart], decimalSeparator) : null;
+  if (negativeAmount !== null || (parsedAmount !== null && parsedAmount.amount === 0)) {
+    result = {
+      ...baseResponse,
+      amount: negativeAmount !== null ? negativeAmount : 0,
+      currency: shownCurrency,
+      status_reason:
+        negativeAmount !== null
+          ? PaymentMessages.AMOUNT_MUST_NOT_BE_NEGATIVE
+          : PaymentMessages.AMOUNT_MUST_BE_POSITIVE,
+      status_code: negativeAmount !== null ? 'AM03' : 'AM01',
+    };
+    timeLogger.end('parse-instruction');
+    return result;
+  }
+  if (parsedAmount === null) {
+    result = {
+      ...baseResponse,
+      currency: shownCurrency,
+      status_reason: PaymentMessages.AMOUNT_MUST_BE_POSITIVE_NUMBER,
+      status_code: 'AM01',
+    };
+    timeLogger.end('parse-instruction');
+    return result;
+  }
+  let { amount } = parsedAmount;
+
+  const heldCurrencies = accounts.map((a) => String(a.currency || '').toUpperCase());
+  let currencyCandidates = currencyInferred
+    ? listHeldCurrencies(accounts)
+    : resolveCurrency(currencyToken, heldCurrencies);
+  const fuzzy = data.fuzzy_keywords === true || options.fuzzyKeywords === true;
+  const corrections = (options.keywordCorrections || []).slice();
+  const correctedCurrency =
+    fuzzy && !currencyInferred && currencyCandidates.length === 0
+      ? correctCurrencyWord(currencyToken)
+      : null;
+  if (correctedCurrency !== null) {
+    corrections.push({ from: currencyToken, to: correctedCurrency });
+    currencyCandidates = resolveCurrency(correctedCurrency, heldCurrencies);
+  }
+  let currency = currencyCandidates.length === 1 ? currencyCandidates[0] : null;
+  const confidenceSignals = corrections.map(() => 'fuzzy');
+  const amountLead = String(tokens[amountStart])[0];
+  if (amountRatio !== null) confidenceSignals.push('balance_share');
+  else if (amountLead < '0' || amountLead > '9') confidenceSignals.push('word_amount');
+  if (currencyCandidates.length > 1 || currencyInferred) {
+    confidenceSignals.push('currency_inferred');
+  }
+  if (currencyCandidates.length === 0) {
+    result = {
+      ...baseResponse,
+      amount,
+      currency: shownCurrency,
+      status_reason: isUnknownCurrencyCode(currencyToken)
+        ? `${PaymentMessages.UNKNOWN_CURRENCY_CODE}: ${String(currencyToken).toUpperCase()}`
+        : PaymentMessages.UNSUPPORTED_CURRENCY,
+      status_code: isUnknownCurrencyCode(currencyToken) ? 'CU04' : 'CU02',
+    };
+    timeLogger.end('parse-instruction');
+    return result;
+  }
+  // A symbol several held currencies share ("Sh" with both KES and UGX accounts)
+  if (currencyCandidates.length > 1 && isCurrencySymbol(currencyToken)) {
+    result = {
+      ...baseResponse,
+      amount,
+      currency: null,
+      status_reason: `${PaymentMessages.AMBIGUOUS_CURRENCY_SYMBOL}: "${currencyToken}" could be ${currencyCandidates.join(' or ')}`,
+      status_code: 'CU06',
+    };
+    timeLogger.end('parse-instruction');
+    return result;
+  }
+  // Decimal places must fit the currency's minor units ("10.005 USD" does not)
+  // (an inferred currency is checked once it is known)
+  if (
+    amountRatio === null &&
+    !currencyInferred &&
+    !currencyCandidates.every((c) => fitsMinorUnits(amount, c))
+  ) {
+    result = {
+      ...baseResponse,
+      amount,
+      currency,
+      status_reason: PaymentMessages.AMOUNT_TOO_MANY_DECIMALS,
+      status_code: 'CU03',
+    };
+    timeLogger.end('parse-instruction');
+    return result;
+  }
+
+  // FROM [ACCOUNT] <acct>
+  const iFrom = lowerTokens.indexOf('from', clauseStart);
+  const iDebitId = lowerTokens[iFrom + 1] === 'account' ? iFrom + 2 : iFrom + 1;
+  if (iFrom === -1 || iDebitId >= tokens.length) {
+    result = {
+      ...baseResponse,
+      amount,
+      currency,
+      status_reason: PaymentMessages.MISSING_REQUIRED_KEYWORD,
+      status_code: 'SY01',
+    };
+    timeLogger.end('parse-instruction');
+    return result;
+  }
+  const debitRef = parseAccountReference(tokens, iDebitId, accounts);
+  let debitAccountId = debitRef.token;
+
+  // [EQUALLY] BETWEEN|AMONG|TO <recipients>
+  let iList = iDebitId + debitRef.consumed;
+  const equally = lowerTokens[iList] === 'equally';
+  if (equally) iList++;
+  const listKeyword = lowerTokens[iList];
+  // "2 parts to acc2 1 part to acc3": the list opens with a share and has a TO per recipient
+  const partWord = lowerTokens[iList + 1];
+  const leadingShares =
+    (partWord === 'part' || partWord === 'parts') && lowerTokens[iList + 2] === 'to';
+  if (
+    !leadingShares &&
+    listKeyword !== 'between' &&
+    listKeyword !== 'among' &&
+    listKeyword !== 'to'
+  ) {
+    result = {
+      ...baseResponse,
+      amount,
+      currency,
+      debit_account: debitAccountId,
+      status_reason:
+        listKeyword === undefined
+          ? PaymentMessages.MISSING_REQUIRED_KEYWORD
+          : PaymentMessages.INVALID_KEYWORD_ORDER,
+      status_code: listKeyword === undefined ? 'SY01' : 'SY02',
+    };
+    timeLogger.end('parse-instruction');
+    return result;
+  }
+  // A trailing "for <text>" / "ref: <text>" is the narration, not a recipient
+  const listStart = leadingShares ? iList : iList + 1;
+  const narration = parseNarration(tokens, listStart);
+  baseResponse.narration = narration.text;
+  const parsedRecipients = parseAccountList(
+    tokens.slice(listStart, narration.start),
+    decimalSeparator,
+    accounts
+  );
+  const weighted = parsedRecipients !== null && parsedRecipients.shares !== null;
+  if (parsedRecipients === null || (equally && (parsedRecipients.explicit || weighted))) {
+    result = {
+      ...baseResponse,
+      amount,
+      currency,
+      debit_account: debitAccountId,
+      status_reason: PaymentMessages.INVALID_SPLIT_RECIPIENTS,
+      status_code: 'SY03',
+    };
+    timeLogger.end('parse-instruction');
+    return result;
+  }
+  const { entries: recipients, explicit, shares: shareUnit } = parsedRecipients;
+  const recipientIds = [];
+  for (let r = 0; r < recipients.length; r++) recipientIds.push(recipients[r].ref.token);
+
+  // Alias names and trailing-digit references resolve exactly as for single instructions
+  const aliases =
+    data.aliases && typeof data.aliases === 'object' && !Array.isArray(data.aliases)
+      ? data.aliases
+      : null;
+  const ignoreCase = data.case_insensitive_ids === true || options.caseInsensitiveIds === true;
+  // Ids must also have the caller's shape when options.accountIdFormat gives one
+  const matchesIdFormat = createAccountIdMatcher(options.accountIdFormat);
+  const refs = [debitRef];
+  for (let r = 0; r < recipients.length; r++) refs.push(recipients[r].ref);
+  const resolvedIds = [debitAccountId, ...recipientIds];
+  for (let k = 0; k < refs.length; k++) {
+    const ref = refs[k];
+    let failure = null;
+    const alias = aliases !== null ? resolveAccountAlias(ref.token, aliases) : null;
+    if (alias && alias.ambiguous) {
+      const candidates = `"${ref.token}" could be ${alias.ambiguous.join(' or ')}`;
+      failure = {
+        reason: `${PaymentMessages.AMBIGUOUS_ACCOUNT_ALIAS}: ${candidates}`,
+        code: 'AC05',
+        candidates: alias.ambiguous,
+      };
+    } else if (alias) {
+      resolvedIds[k] = alias.id;
+      confidenceSignals.push('alias');
+    } else if (ref.suffix !== null) {
+      const matches = findAccountsBySuffix(accounts, ref.suffix);
+      if (matches.length === 1) {
+        resolvedIds[k] = matches[0];
+        confidenceSignals.push('partial_account');
+      } else if (matches.length === 0) {
+        failure = {
+          reason: `${PaymentMessages.ACCOUNT_NOT_FOUND}: no account ending ${ref.suffix}`,
+          code: 'AC03',
+        };
+      } else {
+        const listed = `"${ref.token}" matches ${matches.join(', ')}`;
+        failure = {
+          reason: `${PaymentMessages.AMBIGUOUS_ACCOUNT_REFERENCE}: ${listed}`,
+          code: 'AC06',
+          candidates: matches,
+        };
+      }
+    }
+    const caseMatches = ignoreCase ? findAccountsIgnoringCase(accounts, resolvedIds[k]) : [];
+    if (failure === null && caseMatches.length > 1) {
+      const candidates = `"${resolvedIds[k]}" could be ${caseMatches.join(' or ')}`;
+      failure = {
+        reason: `${PaymentMessages.AMBIGUOUS_ACCOUNT_CASE}: ${candidates}`,
+        code: 'AC05',
+        candidates: caseMatches,
+      };
+    } else if (failure === null && caseMatches.length === 1 && caseMatches[0] !== resolvedIds[k]) {
+      resolvedIds[k] = caseMatches[0];
+      confidenceSignals.push('case_insensitive');
+    }
+    if (failure === null && !isValidAccountId(resolvedIds[k])) {
+      failure = { reason: PaymentMessages.INVALID_ACCOUNT_ID_FORMAT, code: 'AC04' };
+    }
+    if (failure === null && !matchesIdFormat(resolvedIds[k])) {
+      const reason = `${PaymentMessages.MALFORMED_ACCOUNT_REFERENCE}: ${resolvedIds[k]}`;
+      failure = { reason, code: 'AC04' };
+    }
+    if (failure !== null) {
+      result = {
+        ...baseResponse,
+        amount,
+        currency,
+        debit_account: resolvedIds[0],
+        status_reason: failure.reason,
+        status_code: failure.code,
+        ...(failure.candidates ? { candidates: failure.candidates } : {}),
+      };
+      timeLogger.end('parse-instruction');
+      return result;
+    }
+  }
+  baseResponse.confidence = scoreConfidence(confidenceSignals);
+  if (strict && confidenceSignals.length > 0) {
+    result = {
+      ...baseResponse,
+      amount,
+      currency,
+      debit_account: debitAccountId,
+      status_reason: `${PaymentMessages.STRICT_MODE_GUESS}: ${describeGuesses(
+        confidenceSignals,
+        PaymentMessages.STRICT_MODE_GUESSES
+      )}`,
+      status_code: 'SY06',
+    };
+    timeLogger.end('parse-instruction');
+    return result;
+  }
+  const correctionReason = corrections
+    .map((c) => `; ${PaymentMessages.KEYWORD_CORRECTED} "${c.from}" to ${c.to}`)
+    .join('');
+  debitAccountId = resolvedIds[0];
+  const creditIds = resolvedIds.slice(1);
+
+  const debitEntry = findAccount(accounts, debitAccountId);
+  let missing = debitEntry === null;
+  for (let r = 0; r < creditIds.length; r++) {
+    if (findAccount(accounts, creditIds[r]) === null) missing = true;
+  }
+  if (missing) {
+    result = {
+      ...baseResponse,
+      amount,
+      currency,
+      debit_account: debitAccountId,
+      status_reason: PaymentMessages.ACCOUNT_NOT_FOUND,
+      status_code: 'AC03',
+    };
+    timeLogger.end('parse-instruction');
+    return result;
+  }
+  const involvedIds = [debitAccountId, ...creditIds];
+
+  if (amountRatio !== null) {
+    amount = resolveRatioAmount(
+      debitEntry.account.balance,
+      amountRatio,
+      debitEntry.account.currency,
+      options.roundingPolicy || ROUNDING_POLICY
+    );
+    if (isRatioRounded(debitEntry.account.balance, amountRatio, debitEntry.account.currency)) {
+      confidenceSignals.push('amount_rounded');
+    }
+  }
+
+  // Every account must be in the instruction currency (no FX between split legs)
+  const debitAccCurr = String(debitEntry.account.currency || '').toUpperCase();
+  if (currency === null) {
+    const matchesDebit = currencyCandidates.indexOf(debitAccCurr) !== -1;
+    currency = matchesDebit ? debitAccCurr : currencyCandidates[0];
+  }
+  let currencyMismatch = false;
+  for (let r = 0; r < creditIds.length; r++) {
+    const credit = findAccount(accounts, creditIds[r]).account;
+    if (String(credit.currency || '').toUpperCase() !== debitAccCurr) currencyMismatch = true;
+  }
+  if (currencyMismatch || debitAccCurr !== currency) {
+    // Without a currency written, accounts in different currencies leave it to the
+    // instruction to say which (CU02)
+    let message = currencyMismatch
+      ? PaymentMessages.ACCOUNT_CURRENCY_MISMATCH
+      : PaymentMessages.INSTRUCTION_CURRENCY_NOT_HELD;
+    if (currencyInferred) message = PaymentMessages.CURRENCY_NOT_GIVEN;
+    const held = involvedIds.map((id) => findAccount(accounts, id).account);
+    result = {
+      ...baseResponse,
+      amount,
+      currency: currencyInferred ? null : currency,
+      debit_account: debitAccountId,
+      status_reason: `${message}: ${describeCurrencyMismatch(currency, held)}`,
+      status_code: currencyMismatch && !currencyInferred ? 'CU01' : 'CU02',
+      accounts: echoAccounts(accounts, involvedIds),
+    };
+    timeLogger.end('parse-instruction');
+    return result;
+  }
+  // Decimal places must fit an inferred currency's minor units too
+  if (currencyInferred && !fitsMinorUnits(amount, currency)) {
+    result = {
+      ...baseResponse,
+      amount,
+      currency,
+      debit_account: debitAccountId,
+      status_reason: PaymentMessages.AMOUNT_TOO_MANY_DECIMALS,
+      status_code: 'CU03',
+      accounts: echoAccounts(accounts, involvedIds),
+    };
+    timeLogger.end('parse-instruction');
+    return result;
+  }
+
+  // The debit account and every recipient must be distinct
+  let duplicate = null;
+  for (let k = 0; k < involvedIds.length && duplicate === null; k++) {
+    if (involvedIds.indexOf(involvedIds[k]) !== k) duplicate = involvedIds[k];
+  }
+  if (duplicate !== null) {
+    result = {
+      ...baseResponse,
+      amount,
+      currency,
+      debit_account: debitAccountId,
+      status_reason:
+        duplicate === debitAccountId
+          ? PaymentMessages.DEBIT_CREDIT_SAME_ACCOUNT
+          : `${PaymentMessages.DUPLICATE_SPLIT_RECIPIENT}: ${duplicate}`,
+      status_code: 'AC02',
+      accounts: echoAccounts(accounts, involvedIds),
+    };
+    timeLogger.end('parse-instruction');
+    return result;
+  }
+
+  // Shares: explicit per-recipient amounts, or equal parts of the total
+  let shares;
+  let invalidShare = !(amount > 0);
+  let preciseShare = false;
+  if (explicit) {
+    shares = [];
+    let sharesMinor = 0;
+    for (let r = 0; r < recipients.length; r++) {
+      const share = recipients[r].amount;
+      if (!(share > 0)) invalidShare = true;
+      else if (!fitsMinorUnits(share, currency)) preciseShare = true;
+      shares.push(share);
+      sharesMinor += toMinorUnits(share, currency);
+    }
+    if (!invalidShare && !preciseShare && sharesMinor !== toMinorUnits(amount, currency)) {
+      const sharesTotal = sharesMinor / toMinorUnits(1, currency);
+      result = {
+        ...baseResponse,
+        amount,
+        currency,
+        debit_account: debitAccountId,
+        status_reason: `${PaymentMessages.SPLIT_AMOUNTS_MISMATCH}: shares total ${sharesTotal} ${currency}, amount is ${amount} ${currency}`,
+        status_code: 'AM02',
+        accounts: echoAccounts(accounts, involvedIds),
+      };
+      timeLogger.end('parse-instruction');
+      return result;
+    }
+  } else if (weighted) {
+    // Percentages (in hundredths) or parts weigh the recipients
+    const weights = recipients.map((r) => r.share.value);
+    if (!weights.every((w) => w > 0)) {
+      result = {
+        ...baseResponse,
+        amount,
+        currency,
+        debit_account: debitAccountId,
+        status_reason: PaymentMessages.INVALID_SPLIT_SHARE,
+        status_code: 'AM01',
+      };
+      timeLogger.end('parse-instruction');
+      return result;
+    }
+    // Each percentage may be off by half a hundredth from its exact share
+    const weightTotal = weights.reduce((sum, w) => sum + w, 0);
+    if (shareUnit === 'percent' && Math.abs(weightTotal - 10000) * 2 > weights.length) {
+      result = {
+        ...baseResponse,
+        amount,
+        currency,
+        debit_account: debitAccountId,
+        status_reason: `${PaymentMessages.SPLIT_PERCENTAGES_MISMATCH}: shares total ${weightTotal / 100}%`,
+        status_code: 'AM02',
+        accounts: echoAccounts(accounts, involvedIds),
+      };
+      timeLogger.end('parse-instruction');
+      return result;
+    }
+    shares = splitAmountByWeights(amount, weights, currency);
+  } else {
+    shares = splitAmount(amount, recipients.length, currency);
+  }
+  if (invalidShare || preciseShare) {
+    result = {
+      ...baseResponse,
+      amount,
+      currency,
+      debit_account: debitAccountId,
+      status_reason: invalidShare
+        ? PaymentMessages.AMOUNT_MUST_BE_POSITIVE_NUMBER
+        : PaymentMessages.AMOUNT_TOO_MANY_DECIMALS,
+      status_code: invalidShare ? 'AM01' : 'CU03',
+    };
+    timeLogger.end('parse-instruction');
+    return result;
+  }
+  const splits = [];
+  for (let r = 0; r < creditIds.length; r++) {
+    splits.push({ account: creditIds[r], amount: shares[r] });
+  }
+
+  const warnings = buildWarnings(confidenceSignals, PaymentMessages.WARNINGS);
+  const warningFields = warnings.length > 0 ? { warnings } : {};
+
+  // options.parseOnly (see parse-instruction.js) stops before the fee and the balances
+  if (options.parseOnly) {
+    result = {
+      ...baseResponse,
+      amount,
+      currency,
+      debit_account: debitAccountId,
+      splits,
+      ...warningFields,
+      status: 'parsed',
+    };
+    timeLogger.end('parse-instruction');
+    return result;
+  }
+
+  // Per-transaction cap on the total (LM02)
+  const maxAmountPolicy = options.maxAmountPolicy || MAX_AMOUNT_POLICY;
+  const cap = exceededAmountCap(amount, 'SPLIT', currency, maxAmountPolicy);
+  if (cap !== null) {
+    result = {
+      ...baseResponse,
+      amount,
+      currency,
+      debit_account: debitAccountId,
+      splits,
+      status_reason: `${PaymentMessages.AMOUNT_EXCEEDS_TRANSACTION_LIMIT}: limit is ${cap} ${currency} for SPLIT`,
+      status_code: 'LM02',
+      accounts: echoAccounts(accounts, involvedIds),
+    };
+    timeLogger.end('parse-instruction');
+    return result;
+  }
+
+  // A frozen (AC08) or closed (AC09) account takes no part, checked before any balance rule
+  const blocked = findBlockedAccount(involvedIds.map((id) => findAccount(accounts, id).account));
+  if (blocked !== null) {
+    const blockedMessage =
+      blocked.status === 'frozen' ? PaymentMessages.ACCOUNT_FROZEN : PaymentMessages.ACCOUNT_CLOSED;
+    result = {
+      ...baseResponse,
+      amount,
+      currency,
+      debit_account: debitAccountId,
+      splits,
+      status_reason: `${blockedMessage}: ${blocked.account.id}`,
+      status_code: blocked.code,
+      accounts: echoAccounts(accounts, involvedIds),
+    };
+    timeLogger.end('parse-instruction');
+    return result;
+  }
+
+  // Optional SPLIT fee, debited on top of the amount (recipients receive the full shares)
+  const feePolicy = options.feePolicy || FEE_POLICY;
+  const roundingPolicy = options.roundingPolicy || ROUNDING_POLICY;
+  const fee = calculateFee(amount, 'SPLIT', feePolicy, currency, roundingPolicy);
+  const feeFields = fee !== null ? { fee } : {};
+  const totalDebitMinor =
+    toMinorUnits(amount, currency) + (fee !== null ? toMinorUnits(fee, currency) : 0);
+  const totalDebit = fromMinorUnits(totalDebitMinor, currency);
+  const feeText =
+    fee !== null && fee > 0
+      ? ` (${amount} ${currency} + ${fee} ${currency} ${PaymentMessages.FEE})`
+      : '';
+
+  // Balance arithmetic runs in integer minor units, as for a single instruction
+  const debitBalanceBefore = Number(debitEntry.account.balance);
+  const balanceAfterMinor = toMinorUnits(debitBalanceBefore, currency) - totalDebitMinor;
+  const balanceAfter = fromMinorUnits(balanceAfterMinor, currency);
+
+  // Debit-side rules, in the same order as a single instruction
+  let failure = null;
+  const minimumBalance =
+    debitEntry.account.minimum_balance !== undefined
+      ? Number(debitEntry.account.minimum_balance)
+      : null;
+  const overdraftLimit = Math.max(Number(debitEntry.account.overdraft_limit) || 0, 0);
+  const dailyLimit =
+    debitEntry.account.daily_limit !== undefined ? Number(debitEntry.account.daily_limit) : null;
+  const dailyDebitStore = options.dailyDebitStore;
+  const debitDay = now.toISOString().slice(0, 10);
+  if (minimumBalance !== null && balanceAfterMinor < toMinorUnits(minimumBalance, currency)) {
+    failure = {
+      reason: `${PaymentMessages.MINIMUM_BALANCE_BREACH}: floor is ${minimumBalance} ${currency}, balance would be ${balanceAfter} ${currency}`,
+      code: 'BL02',
+    };
+  } else if (balanceAfterMinor < -toMinorUnits(overdraftLimit, currency)) {
+    const overdraftText =
+      overdraftLimit > 0
+        ? ` plus ${overdraftLimit} ${currency} ${PaymentMessages.OVERDRAFT}`
+        : '';
+    failure = {
+      reason: `${PaymentMessages.INSUFFICIENT_FUNDS}: has ${debitBalanceBefore} ${currency}${overdraftText}, needs ${totalDebit} ${currency}${feeText}`,
+      code: 'AC01',
+    };
+  } else if (dailyLimit !== null && dailyDebitStore) {
+    const debitedToday = await dailyDebitStore.getTotal(debitAccountId, debitDay);
+    const debitedTodayMinor = toMinorUnits(debitedToday, currency);
+    if (debitedTodayMinor + totalDebitMinor > toMinorUnits(dailyLimit, currency)) {
+      failure = {
+        reason: `${PaymentMessages.DAILY_LIMIT_EXCEEDED}: limit is ${dailyLimit} ${currency}, already debited ${debitedToday} ${currency} today, needs ${totalDebit} ${currency}${feeText}`,
+        code: 'LM01',
+      };
+    }
+  }
+  if (failure !== null) {
+    result = {
+      ...baseResponse,
+      amount,
+      currency,
+      debit_account: debitAccountId,
+      splits,
+      ...feeFields,
+      status_reason: failure.reason,
+      status_code: failure.code,
+      accounts: echoAccounts(accounts, involvedIds),
+    };
+    timeLogger.end('parse-instruction');
+    return result;
+  }
+
+  // Apply the debit and every credit together
+  const accountsOut = [];
+  for (let i = 0; i < accounts.length; i++) {
+    const a = accounts[i];
+    const before = Number(a.balance);
+    let after = null;
+    if (a.id === debitAccountId) {
+      after = balanceAfter;
+    } else if (creditIds.indexOf(a.id) !== -1) {
+      const share = shares[creditIds.indexOf(a.id)];
+      after = fromMinorUnits(
+        toMinorUnits(before, currency) + toMinorUnits(share, currency),
+        currency
+      );
+    }
+    if (after !== null) {
+      accountsOut.push({
+        id: a.id,
+        balance: dryRun ? before : after,
+        balance_before: before,
+        ...(dryRun ? { projected_balance: after } : {}),
+        currency: String(a.currency || '').toUpperCase(),
+      });
+    }
+  }
+  if (!dryRun && dailyDebitStore) await dailyDebitStore.add(debitAccountId, debitDay, totalDebit);
+
+  // Executed transactions are kept for reversal
+  const transactionStore = options.transactionStore || defaultTransactionStore;
+  const executedReason = dryRun
+    ? PaymentMessages.DRY_RUN_WOULD_EXECUTE
+    : PaymentMessages.TRANSACTION_EXECUTED;
+  const overdraftReason =
+    balanceAfter < 0
+      ? `; ${PaymentMessages.ACCOUNT_OVERDRAWN} by ${-balanceAfter} ${currency}`
+      : '';
+  result = {
+    ...baseResponse,
+    amount,
+    currency,
+    debit_account: debitAccountId,
+    splits,
+    ...feeFields,
+    ...warningFields,
+    status: 'successful',
+    status_reason: `${executedReason}${overdraftReason}${correctionReason}`,
+    status_code: 'AP00',
+    accounts: accountsOut,
+  };
+  if (!dryRun) {
+    await transactionStore.set(result.transaction_id, { transaction: result, reversal: null });
+  }
+
+  timeLogger.end('parse-instruction');
+  return result;
+}
+
+module.exports = processSplitInstruction;
@@ -0,0 +1,208 @@
+const validator = require('@app-core/validator');
+const { throwAppError, ERROR_CODE } = require('@app-core/errors');
+const { appLogger } = require('@app-core/logger');
+const PaymentMessages = require('@app/messages/payment-instructions');
+const paymentInstructions = require('./payment-instructions');
+const { tokenize, referencedAccountIds } = require('./helpers');
+const createMemoryAccountStore = require('./stores/create-memory-account-store');
+const defaultIdGenerator = require('./id-generators/default-id-generator');
+
+// -----------------------------
+// VSL Spec (only what is needed to load the accounts; the payload is validated downstream)
+// -----------------------------
+const spec = `root {
+  instruction string<trim>
+  aliases? object
+  dry_run? boolean
+}`;
+
+const parsedSpec = validator.parse(spec);
+
+// Put back the balances written before a failed write, newest first, for a store that can
+// neither roll back nor restore a snapshot; an account that cannot be put back is logged and
+// the rest are still tried
+async function restoreBalances(accountStore, written, storeOptions) {
+  for (let i = written.length - 1; i >= 0; i--) {
+    try {
+      // eslint-disable-next-line no-await-in-loop
+      await accountStore.updateBalance(written[i].id, written[i].balance_before, {
+        ...storeOptions,
+        currency: written[i].currency,
+      });
+    } catch (err) {
+      appLogger.errorX({ error: err, account: written[i].id }, 'process-stored.restore-failed');
+    }
+  }
+}
+
+// Put the accounts back as the snapshot has them; a restore that fails is logged so the
+// original write error is still the one reported
+async function restoreSnapshot(accountStore, snapshot, storeOptions) {
+  try {
+    await accountStore.restore(snapshot, storeOptions);
+  } catch (err) {
+    appLogger.errorX({ error: err }, 'process-stored.snapshot-restore-failed');
+  }
+}
+
+// Last queued run per store without transactions: each run waits for the one before it, so
+// that in this process no two read the same balances before the first has written them
+const pendingRuns = new WeakMap();
+
+function runInTurn(accountStore, run) {
+  const previous = pendingRuns.get(accountStore) || Promise.resolve();
+  const current = previous.then(run);
+  pendingRuns.set(accountStore, current.catch(() => {}));
+  return current;
+}
+
+// The referenced accounts the reader (the store, or its transaction) has, in id order; once
+// the signal aborts nothing more is loaded
+async function loadAccounts(reader, ids, storeOptions, aborted) {
+  const accounts = [];
+  for (let i = 0; i < ids.length && !aborted(); i++) {
+    // eslint-disable-next-line no-await-in-loop
+    const account = await reader.getAccount(ids[i], storeOptions);
+    if (account) accounts.push(account);
+  }
+  return accounts;
+}
+
+// Write each changed balance through the writer (the store, or its transaction)
+async function writeBalances(writer, changed, storeOptions, written) {
+  for (let i = 0; i < changed.length; i++) {
+    // eslint-disable-next-line no-await-in-loop
+    await writer.updateBalance(changed[i].id, changed[i].balance, {
+      ...storeOptions,
+      currency: changed[i].currency,
+    });
+    written.push(changed[i]);
+  }
+}
+
+/**
+ * Execute a payment instruction against an account store instead of an inline accounts
+ * array.
+ *
+ * Only the accounts the instruction refers to are loaded (see referencedAccountIds). When
+ * the store has transactions (beginTx), the accounts are read through the transaction and
+ * the balances the instruction changed are written in it, so a database store that reads
+ * with row locks (SELECT ... FOR UPDATE) keeps a concurrent instruction on the same accounts
+ * waiting until this one has committed: neither can overwrite the other's debit. Without
+ * transactions each balance is written with one updateBalance call, and stored runs on the
+ * same store take turns within this process (a store shared by several processes needs
+ * beginTx).
+ *
+ * Either way the write is all or nothing: SPLIT and MULTI_DEBIT work out every balance
+ * before any is written, and when a write fails without a transaction the balances already
+ * written are set back to what they were before the error is thrown: a store with snapshot
+ * and restore has the accounts it loaded snapshotted before the write and restored exactly,
+ * any other has the written balances put back one by one. Ids the store does not know fail
+ * downstream as any unknown account does (AC03). Without options.accountStore the request's
+ * own accounts array is the store. An executed instruction's balances are always written,
+ * even if options.signal aborts meanwhile.
+ *
+ * @param {Object} serviceData - payment-instructions payload; accounts only without a store
+ * @param {{ accountStore?: Object, signal?: AbortSignal }} [options] - see
+ *   createMemoryAccountStore for the store interface; also passed through to the
+ *   payment-instructions service
+ */
+async function processStoredInstruction(serviceData, options = {}) {
+  let data;
+  try {
+    data = validator.validate(serviceData, parsedSpec);
+  } catch (err) {
+    appLogger.errorX({ error: err, payload: serviceData }, 'process-stored.validation-failed');
+    throwAppError(PaymentMessages.MALFORMED_INSTRUCTION, ERROR_CODE.VALIDATIONERR);
+  }
+  const accountStore = options.accountStore || createMemoryAccountStore(serviceData.accounts || []);
+  const aliases =
+    data.aliases && typeof data.aliases === 'object' && !Array.isArray(data.aliases)
+      ? data.aliases
+      : null;
+  const ids = referencedAccountIds(tokenize(data.instruction), aliases);
+  const dryRun = data.dry_run === true || options.dryRun === true;
+
+  // The caller's AbortSignal reaches every store call; once it is aborted nothing more is
+  // loaded and the instruction comes back cancelled
+  const storeOptions = { signal: options.signal };
+  const aborted = () => !!options.signal && options.signal.aborted;
+
+  async function execute(accounts) {
+    if (accounts.length > 0) return paymentInstructions({ ...serviceData, accounts }, options);
+    // The service needs at least one account: with none found (or none loaded before the
+    // signal aborted) the instruction fails here, in the service's response shape
+    const idGenerator = options.idGenerator || defaultIdGenerator;
+    return {
+      transaction_id: idGenerator.next(),
+      type: null,
+      amount: null,
+      currency: null,
+      debit_account: null,
+      credit_account: null,
+      execute_by: null,
+      narration: '',
+      status: aborted() ? 'cancelled' : 'failed',
+      status_reason: aborted()
+        ? PaymentMessages.INSTRUCTION_CANCELLED
+        : PaymentMessages.ACCOUNT_NOT_FOUND,
+      status_code: aborted() ? 'CANCELLED' : 'AC03',
+      accounts: [],
+    };
+  }
+
+  // Every balance the response reports as moved, so the store ends up as the response says:
+  // a compound instruction whose later clause failed still keeps the clauses that executed
+  function changedAccounts(result) {
+    return dryRun ? [] : result.accounts.filter((a) => a.balance !== a.balance_before);
+  }
+
+  function failWrite(err) {
+    appLogger.errorX({ error: err }, 'process-stored.balance-update-failed');
+    throwAppError(PaymentMessages.INTERNAL_ERROR, ERROR_CODE.APPERR);
+  }
+
+  if (accountStore.beginTx) {
+    const tx = await accountStore.beginTx(storeOptions);
+    let result;
+    try {
+      result = await execute(await loadAccounts(tx, ids, storeOptions, aborted));
+    } catch (err) {
+      await tx.rollback();
+      throw err;
+    }
+    const changed = changedAccounts(result);
+    try {
+      await writeBalances(tx, changed, storeOptions, []);
+      if (changed.length > 0) await tx.commit();
+      else await tx.rollback();
+    } catch (err) {
+      await tx.rollback();
+      failWrite(err);
+    }
+    return result;
+  }
+
+  return runInTurn(accountStore, async () => {
+    const accounts = await loadAccounts(accountStore, ids, storeOptions, aborted);
+    const result = await execute(accounts);
+    const changed = changedAccounts(result);
+    if (changed.length > 0) {
+      const restorable = !!accountStore.snapshot && !!accountStore.restore;
+      const snapshot = restorable
+        ? await accountStore.snapshot(accounts.map((a) => a.id), storeOptions)
+        : null;
+      const written = [];
+      try {
+        await writeBalances(accountStore, changed, storeOptions, written);
+      } catch (err) {
+        if (snapshot) await restoreSnapshot(accountStore, snapshot, storeOptions);
+        else await restoreBalances(accountStore, written, storeOptions);
+        failWrite(err);
+      }
+    }
+    return result;
+  });
+}
+
+module.exports = processStoredInstruction;
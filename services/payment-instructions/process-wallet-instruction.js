@@ -0,0 +1,71 @@
+const PaymentMessages = require('@app/messages/payment-instructions');
+const { isWalletAccount, selectWalletPockets } = require('./helpers');
+
+// Ids of the accounts a (parsed) result names on either side or as the fee account
+function namedAccountIds(result) {
+  const ids = [result.debit_account, result.credit_account, result.fee_account];
+  (result.splits || []).forEach((s) => ids.push(s.account));
+  (result.debits || []).forEach((d) => ids.push(d.account));
+  return ids.filter((id) => typeof id === 'string');
+}
+
+// Whether the wallet has a pocket in the currency, however the request cased its code
+function holdsPocket(wallet, currency) {
+  return Object.keys(wallet.balances).some((code) => code.toUpperCase() === currency);
+}
+
+/**
+ * Run an instruction where some accounts are multi-currency wallets (`balances` keyed by
+ * currency instead of one balance and currency): each wallet takes part through its pocket
+ * in the instruction currency, so an NGN transfer moves the NGN pocket and leaves the others
+ * as they are.
+ *
+ * The instruction is first read with every wallet shown as its default-currency (else first)
+ * pocket, which gives the currency and the accounts it names; a named wallet with no pocket
+ * in that currency fails with AC11. It then runs as usual with the wallets shown as their
+ * pockets in that currency (see the payment-instructions service, passed in as
+ * options.processInstruction), so a wallet in the response reports that pocket: its balance,
+ * balance_before and currency.
+ *
+ * @param {Object} serviceData - payment-instructions payload
+ * @param {Object} [options] - payment-instructions options
+ * @returns {Promise<Object>}
+ */
+async function processWalletInstruction(serviceData, options = {}) {
+  const run = options.processInstruction;
+  const accounts = serviceData.accounts;
+  const defaultCurrency = serviceData.default_currency || options.defaultCurrency || null;
+  const firstRead = await run(
+    { ...serviceData, accounts: selectWalletPockets(accounts, defaultCurrency) },
+    { ...options, parseOnly: true }
+  );
+  const currency = firstRead.currency ? String(firstRead.currency).toUpperCase() : null;
+
+  let missing = null;
+  if (currency !== null) {
+    const named = namedAccountIds(firstRead);
+    for (let i = 0; i < accounts.length && missing === null; i++) {
+      const a = accounts[i];
+      const unheld = isWalletAccount(a) && !holdsPocket(a, currency);
+      if (unheld && named.indexOf(a.id) !== -1) missing = a;
+    }
+  }
+  if (missing !== null) {
+    const held = Object.keys(missing.balances).map((code) => code.toUpperCase());
+    const { confidence, warnings, parse_error: parseError, ...parsed } = firstRead;
+    return {
+      ...parsed,
+      status: 'failed',
+      status_reason: `${PaymentMessages.WALLET_POCKET_MISSING}: ${missing.id} has no ${currency} pocket (holds ${held.join(', ')})`,
+      status_code: 'AC11',
+      accounts: [],
+    };
+  }
+
+  return run(
+    { ...serviceData, accounts: selectWalletPockets(accounts, currency || defaultCurrency) },
+    options
+  );
+}
+
+module.exports = processWalletInstruction;
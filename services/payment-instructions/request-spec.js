@@ -0,0 +1,63 @@
+const validator = require('@app-core/validator');
+
+// -----------------------------
+// VSL fragments shared by every flow that reads a payment-instructions payload
+// -----------------------------
+
+// One account of a request, in accounts[] (and items[].accounts[] of a batch); a
+// multi-currency wallet gives balances instead of balance and currency (see
+// isCompleteAccount for the check the flows make)
+const ACCOUNT_FIELDS_SPEC = `{
+    id string
+    balance? number
+    currency? string
+    balances? object
+    overdraft_limit? number
+    minimum_balance? number
+    daily_limit? number
+    status? string
+  }`;
+
+// Optional fields that change how an instruction is read or run, the same for a single
+// instruction and for every item of a batch; dry_run is not one of them (a batch has none)
+const REQUEST_OPTION_FIELDS_SPEC = `
+  fx_rates? object
+  aliases? object
+  decimal_separator? string
+  default_currency? string
+  timezone? string
+  case_insensitive_ids? boolean
+  fuzzy_keywords? boolean
+  strict? boolean
+  collection_account? string<trim>
+  partial_execution? boolean
+  include_all_accounts? boolean
+  locale? string`;
+
+// Their names, for flows that pass them on field by field
+const REQUEST_OPTION_FIELDS = REQUEST_OPTION_FIELDS_SPEC.split('\n')
+  .map((line) => line.trim())
+  .filter((line) => line !== '')
+  .map((line) => line.split(' ')[0].split('?')[0]);
+
+// A single instruction: the payload of POST /payment-instructions
+const INSTRUCTION_REQUEST_SPEC = `root {
+  accounts[] ${ACCOUNT_FIELDS_SPEC}
+  instruction string<trim>
+  dry_run? boolean${REQUEST_OPTION_FIELDS_SPEC}
+}`;
+
+/**
+ * The payment-instructions request spec and its parts. Every flow that validates a payload
+ * (the service itself, the SPLIT, multi-debit, cash and compound flows it routes to, the
+ * debugger, batches, stored and reversal requests) builds its spec from these, so a body
+ * field added here reaches all of them; validator.validate drops any key a spec does not
+ * list.
+ */
+module.exports = {
+  ACCOUNT_FIELDS_SPEC,
+  REQUEST_OPTION_FIELDS_SPEC,
+  REQUEST_OPTION_FIELDS,
+  INSTRUCTION_REQUEST_SPEC,
+  parsedInstructionRequestSpec: validator.parse(INSTRUCTION_REQUEST_SPEC),
+};
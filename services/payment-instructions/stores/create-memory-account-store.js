@@ -0,0 +1,105 @@
+const { isWalletAccount } = require('../helpers');
+
+/**
+ * In-memory account store over an accounts array, the shape requests carry inline (default
+ * when no store is passed, and for tests). The array's objects are copied, never mutated.
+ *
+ * Any object with the same async interface can be passed in options.accountStore instead,
+ * e.g. one backed by an accounts table:
+ *   getAccount(id)               -> { id, balance, currency, ... } or null when unknown
+ *   updateBalance(id, balance)   -> sets the account's balance
+ *   beginTx()                    -> optional; { getAccount, updateBalance, commit,
+ *                                   rollback }: reads lock the account until commit or
+ *                                   rollback (SELECT ... FOR UPDATE), updates take effect
+ *                                   together on commit
+ *   snapshot(ids)                -> optional, with restore; the accounts with those ids
+ *                                   (every account when ids is omitted) as they are now
+ *   restore(snapshot)            -> puts each snapshot account back exactly as it was;
+ *                                   restoring the same snapshot again changes nothing
+ * Each call also gets { signal } as a last argument: the caller's AbortSignal, which a
+ * database-backed store can hand to its driver so a cancelled request stops waiting. For
+ * updateBalance it also has the balance's currency, which for a multi-currency wallet
+ * (`balances` by currency) names the pocket to set.
+ *
+ * @param {Object[]} [accounts]
+ */
+function createMemoryAccountStore(accounts = []) {
+  const byId = new Map();
+  accounts.forEach((account) => {
+    if (!byId.has(account.id)) byId.set(account.id, { ...account });
+  });
+
+  async function getAccount(id) {
+    const account = byId.get(id);
+    return account ? { ...account } : null;
+  }
+
+  function setBalance(id, balance, currency) {
+    const account = byId.get(id);
+    if (account && isWalletAccount(account)) {
+      const codes = Object.keys(account.balances);
+      const code = codes.find((c) => c.toUpperCase() === currency) || currency;
+      account.balances = { ...account.balances, [code]: balance };
+    } else if (account) {
+      account.balance = balance;
+    }
+  }
+
+  async function updateBalance(id, balance, { currency } = {}) {
+    setBalance(id, balance, currency);
+  }
+
+  // A transaction holds the store from beginTx until it commits or rolls back, as row locks
+  // taken by SELECT ... FOR UPDATE would: the next beginTx resolves only then
+  let released = Promise.resolve();
+
+  async function beginTx() {
+    const previous = released;
+    let release;
+    released = new Promise((resolve) => {
+      release = resolve;
+    });
+    await previous;
+    const pending = [];
+    return {
+      getAccount,
+      updateBalance: async (id, balance, { currency } = {}) => {
+        pending.push({ id, balance, currency });
+      },
+      commit: async () => {
+        pending.forEach((update) => setBalance(update.id, update.balance, update.currency));
+        pending.length = 0;
+        release();
+      },
+      rollback: async () => {
+        pending.length = 0;
+        release();
+      },
+    };
+  }
+
+  async function snapshot(ids) {
+    const taken = [];
+    const keys = Array.isArray(ids) ? ids : Array.from(byId.keys());
+    keys.forEach((id) => {
+      const account = byId.get(id);
+      if (account) taken.push({ ...account });
+    });
+    return taken;
+  }
+
+  async function restore(taken) {
+    taken.forEach((account) => byId.set(account.id, { ...account }));
+  }
+
+  return {
+    getAccount,
+    updateBalance,
+    beginTx,
+    snapshot,
+    restore,
+    size: () => byId.size,
+  };
+}
+
+module.exports = createMemoryAccountStore;
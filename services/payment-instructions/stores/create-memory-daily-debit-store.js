@@ -0,0 +1,34 @@
+/**
+ * In-memory store of how much each account has debited per calendar day (default for a
+ * single process and for tests).
+ *
+ * Any object with the same async interface can be passed to the service instead,
+ * e.g. a Redis-backed store using INCRBYFLOAT on a "<day>:<accountId>" key with a TTL:
+ *   getTotal(accountId, day)      -> amount already debited on day (0 when none)
+ *   add(accountId, day, amount)   -> records a debit of amount on day
+ *
+ * Days are "YYYY-MM-DD" strings; the caller decides where the day boundary falls.
+ */
+function createMemoryDailyDebitStore() {
+  const totals = new Map();
+
+  async function getTotal(accountId, day) {
+    const entry = totals.get(accountId);
+    return entry && entry.day === day ? entry.total : 0;
+  }
+
+  async function add(accountId, day, amount) {
+    // Only the latest day is kept per account; earlier days can no longer be limited
+    const entry = totals.get(accountId);
+    const total = entry && entry.day === day ? entry.total + amount : amount;
+    totals.set(accountId, { day, total });
+  }
+
+  return {
+    getTotal,
+    add,
+    size: () => totals.size,
+  };
+}
+
+module.exports = createMemoryDailyDebitStore;
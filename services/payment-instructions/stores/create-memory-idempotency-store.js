@@ -0,0 +1,76 @@
+const systemClock = require('../clocks/system-clock');
+
+/**
+ * In-memory idempotency store (default for a single process and for tests).
+ *
+ * Any object with the same async interface can be passed to the service instead,
+ * e.g. a Redis-backed store using SET ... PX <ttlMs> and GET:
+ *   get(key)                 -> { fingerprint, result } or null when missing/expired
+ *   set(key, record, ttlMs)  -> stores record for ttlMs milliseconds
+ *   reserve(key, record, ttlMs)
+ *                            -> stores record only when the key is free, atomically
+ *                               (SET ... NX PX <ttlMs>); true when it did
+ *   delete(key)              -> frees the key (DEL)
+ *
+ * @param {{ clock?: { now: () => number }, now?: () => number }} [config] - clock (see
+ *   clocks/system-clock.js) or clock function (epoch ms) to use instead of the system time
+ */
+function createMemoryIdempotencyStore(config = {}) {
+  const source = config.clock || systemClock;
+  const clock = typeof config.now === 'function' ? config.now : () => source.now();
+  const entries = new Map();
+
+  /**
+   * Drop every expired entry.
+   * @returns {number} how many entries were removed
+   */
+  function purgeExpired() {
+    const current = clock();
+    let removed = 0;
+    entries.forEach((entry, key) => {
+      if (entry.expiresAt <= current) {
+        entries.delete(key);
+        removed++;
+      }
+    });
+    return removed;
+  }
+
+  async function get(key) {
+    const entry = entries.get(key);
+    let record = null;
+    if (entry && entry.expiresAt > clock()) {
+      record = entry.record;
+    } else if (entry) {
+      entries.delete(key);
+    }
+    return record;
+  }
+
+  async function set(key, record, ttlMs) {
+    purgeExpired();
+    entries.set(key, { record, expiresAt: clock() + ttlMs });
+  }
+
+  async function reserve(key, record, ttlMs) {
+    purgeExpired();
+    const free = !entries.has(key);
+    if (free) entries.set(key, { record, expiresAt: clock() + ttlMs });
+    return free;
+  }
+
+  async function remove(key) {
+    entries.delete(key);
+  }
+
+  return {
+    get,
+    set,
+    reserve,
+    delete: remove,
+    purgeExpired,
+    size: () => entries.size,
+  };
+}
+
+module.exports = createMemoryIdempotencyStore;
@@ -0,0 +1,31 @@
+/**
+ * In-memory store of executed transactions, kept so they can be reversed (default for a
+ * single process and for tests).
+ *
+ * Any object with the same async interface can be passed to the services instead,
+ * e.g. a table keyed by transaction id:
+ *   get(transactionId)          -> { transaction, reversal } or null when unknown
+ *   set(transactionId, record)  -> stores record, replacing any earlier one
+ *
+ * transaction is the successful response of the original instruction; reversal is the
+ * REVERSAL response once it has been reversed, else null.
+ */
+function createMemoryTransactionStore() {
+  const records = new Map();
+
+  async function get(transactionId) {
+    return records.get(transactionId) || null;
+  }
+
+  async function set(transactionId, record) {
+    records.set(transactionId, record);
+  }
+
+  return {
+    get,
+    set,
+    size: () => records.size,
+  };
+}
+
+module.exports = createMemoryTransactionStore;
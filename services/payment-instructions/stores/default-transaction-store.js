@@ -0,0 +1,5 @@
+const createMemoryTransactionStore = require('./create-memory-transaction-store');
+
+// One store per process, shared by the payment and reversal services so a transaction
+// executed by one can be found by the other; options.transactionStore replaces it
+module.exports = createMemoryTransactionStore();
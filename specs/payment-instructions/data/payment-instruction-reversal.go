@@ -0,0 +1,18 @@
+import ../../examples/commons.go
+
+PaymentInstructionReversalData {
+
+  // Current balances of the accounts the original transaction moved
+  accounts[] {
+    id string                         // Account identifier (case-sensitive)
+    balance? number                   // Current account balance (required unless balances is given)
+    currency? string                  // Currency code
+    balances? object                  // Multi-currency wallet: the pocket in the currency moved is reversed
+    overdraft_limit? number           // Accepted for symmetry; reversals never use overdraft
+    minimum_balance? number
+    daily_limit? number               // Reversals do not count towards it
+  }
+
+  // transaction_id from the successful response of the transaction to undo
+  transaction_id string<trim|minLength:1>
+}
@@ -0,0 +1,65 @@
+import ../../examples/commons.go
+
+PaymentInstructionsBatchData {
+
+  // Shared mode: one account set, instructions applied in order (balances carry over)
+  accounts[]? {
+    id string                         // Account identifier (case-sensitive)
+    balance? number                   // Current account balance (required unless balances is given)
+    currency? string                  // Currency code
+    balances? object                  // Multi-currency wallet: balance per currency code, in place of balance/currency
+    overdraft_limit? number           // Optional overdraft allowance (default 0)
+    minimum_balance? number           // Optional balance floor (BL02)
+    daily_limit? number               // Optional daily debit limit (LM01)
+    status? string                    // active (default) | frozen (AC08) | closed (AC09)
+  }
+  instructions[]? string              // Instructions run sequentially against accounts
+
+  // Items mode: independent instructions, each with its own accounts
+  items[]? {
+    accounts[] {
+      id string
+      balance? number
+      currency? string
+      balances? object
+      overdraft_limit? number
+      minimum_balance? number
+      daily_limit? number
+      status? string
+    }
+    instruction string<trim>
+  }
+
+  // Optional FX rate table and account aliases shared by every instruction
+  fx_rates? object
+  aliases? object
+
+  // Optional: match account ids ignoring case in every instruction
+  case_insensitive_ids? boolean
+
+  // Optional: correct typos in verbs and currency words in every instruction
+  fuzzy_keywords? boolean
+  strict? boolean
+  collection_account? string<trim>
+
+  // Optional: partial execution (BL03) in every instruction
+  partial_execution? boolean
+
+  // Optional: "," for European-style amounts in every instruction
+  decimal_separator? string
+
+  // Optional: currency of every instruction that names none
+  default_currency? string
+
+  // Optional: IANA timezone every schedule is read in
+  timezone? string
+
+  // Optional: language for every status_reason when a message resolver is configured
+  locale? string
+
+  // Optional: fail repeats of an earlier instruction with DUP01 instead of executing them
+  reject_duplicates? boolean
+
+  // Optional: return balance_trails, every balance step of each account in execution order
+  balance_trail? boolean
+}
@@ -5,11 +5,49 @@ PaymentInstructionsData {
   // The list of accounts involved in potential transactions
   accounts[] {
     id string                         // Account identifier (case-sensitive)
-    balance number                    // Current account balance
-    currency string                   // Currency code (NGN, USD, GBP, GHS)
+    balance? number                   // Current account balance (required unless balances is given)
+    currency? string                  // Currency code (NGN, USD, GBP, GHS, KES, ZAR, EUR, UGX, JPY, KWD)
+    balances? object                  // Multi-currency wallet: balance per currency code, in place of balance/currency
+    overdraft_limit? number           // How far below zero a debit may take the balance (default 0)
+    minimum_balance? number           // Floor a debit may not cross (BL02); takes precedence over overdraft
+    daily_limit? number               // Max total debited per UTC calendar day (LM01)
+    status? string                    // active (default) | frozen (AC08) | closed (AC09)
   }
 
   // Raw instruction string to parse and process
   instruction string<trim>                 // Must be a non-empty instruction string
+
+  // Optional FX rate table keyed "FROM/TO" (1 FROM = rate TO), enables cross-currency transfers
+  fx_rates? object
+
+  // Optional account names (alias -> account id), matched case-insensitively before exact ids
+  aliases? object
+
+  // Optional preview mode: validate and project balances without changing them
+  dry_run? boolean
+
+  // Optional: match account ids ignoring case (responses keep the ids as given in accounts)
+  case_insensitive_ids? boolean
+
+  // Optional: correct typos in verbs and currency words ("trasnfer", "niara")
+  fuzzy_keywords? boolean
+  strict? boolean
+  collection_account? string<trim>
+
+  // Optional: when the debit account cannot cover the amount, send as much as it can (BL03)
+  partial_execution? boolean
+
+  // Optional: "," for European-style amounts ("1.000,50"); default "." ("1,000.50")
+  decimal_separator? string
+
+  // Optional: currency of an instruction that names none ("NGN")
+  default_currency? string
+
+  // Optional: IANA timezone schedule dates and times are read in ("Africa/Lagos"); DT04 if unknown
+  timezone? string
+
+  // Optional: language for status_reason ("fr", "sw-KE") when a message resolver is configured;
+  // an Indian locale ("en-IN") also reads lakh/crore amounts ("1,00,000", "2 lakh")
+  locale? string
 }
 
@@ -0,0 +1,74 @@
+PaymentInstructionReversalRequest {
+  path /payment-instructions/reversal
+  method POST
+
+  // Input body (validated by data spec)
+  body {
+    accounts[] {
+      id string
+      balance? number
+      currency? string
+      balances? object                     // Multi-currency wallet, in place of balance/currency
+    }
+    transaction_id string                  // As returned by /payment-instructions
+  }
+
+  // -------------------------
+  // SUCCESSFUL RESPONSE
+  // -------------------------
+  // Reversing an already reversed transaction replays the first reversal (nothing moves twice)
+  response.ok {
+    http.code 200
+    status successful
+    message "Transaction reversed successfully"
+
+    data {
+      type string                          // REVERSAL
+      amount number                        // Amount of the original transaction
+      currency string
+      debit_account string|null            // Original credit account
+      credit_account string|null           // Original debit account
+      execute_by null
+      narration string                     // Narration of the original transaction
+      reversed_transaction_id string
+      transaction_id string                // Id of the reversal itself
+
+      status string                        // "successful"
+      status_reason string
+      status_code string                   // AP00
+
+      accounts[] {                         // Every account the original moved, in request order
+        id string
+        balance number
+        balance_before number
+        currency string
+      }
+    }
+  }
+
+  // -------------------------
+  // ERROR / FAILED RESPONSE
+  // -------------------------
+  // Malformed bodies are rejected with HTTP 400 before any lookup; a reversal that is refused
+  // (RV01, AC01, AC03, CU01) is HTTP 422
+  response.error {
+    http.code 422
+    status failed
+
+    data {
+      transaction_id string
+      type string                          // REVERSAL
+      reversed_transaction_id string
+      status string                        // "failed"
+      status_reason string
+      status_code string                   // RV01 unknown id, AC01 funds no longer there, AC03, CU01
+      reason_category string               // parse_error | validation | funds | limits | account
+      accounts[] {                         // Unchanged balances (balance_before = balance)
+        id string
+        balance number
+        balance_before number
+        currency string
+      }
+    }
+  }
+}
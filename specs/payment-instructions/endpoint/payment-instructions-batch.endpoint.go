@@ -0,0 +1,82 @@
+PaymentInstructionsBatchRequest {
+  path /payment-instructions/batch
+  method POST
+
+  // Input body (validated by data spec): either accounts + instructions, or items
+  body {
+    accounts[]? {
+      id string
+      balance? number
+      currency? string
+      balances? object                     // Multi-currency wallet, in place of balance/currency
+    }
+    instructions[]? string
+    items[]? {
+      accounts[] {
+        id string
+        balance? number
+        currency? string
+        balances? object
+      }
+      instruction string<trim>
+    }
+    fx_rates? object
+    aliases? object
+    reject_duplicates? boolean           // Repeats of an earlier instruction fail with DUP01, unexecuted
+    balance_trail? boolean               // Return balance_trails (opt-in: grows with the batch)
+  }
+
+  // -------------------------
+  // SUCCESSFUL RESPONSE
+  // -------------------------
+  // Failed items do not abort the batch; each result has its own status and status_code.
+  response.ok {
+    http.code 200
+
+    data {
+      results[] {                          // Same order as the input, same shape as /payment-instructions data
+        transaction_id string
+        type string|null
+        amount number|null
+        currency string|null
+        debit_account string|null
+        credit_account string|null
+        execute_by number|null
+        status string                      // successful | pending | failed
+        status_reason string
+        status_code string
+        reason_category? string            // Failed items only (parse_error, validation, funds, ...)
+        duplicate_of? number               // DUP01: index of the item this one repeats
+
+        accounts[] {
+          id string
+          balance number
+          balance_before number
+          currency string
+        }
+      }
+
+      accounts[]? {                        // Shared mode only: balances after the whole batch
+        id string
+        balance number
+        currency string
+      }
+
+      balance_trails? {                    // balance_trail only: per account id, in execution order
+        <account_id>[] {                   //   one step per executed item that moved the account
+          index number                     // Index of the item in results
+          balance_before number
+          balance number
+        }
+      }
+    }
+  }
+
+  // -------------------------
+  // ERROR RESPONSE
+  // -------------------------
+  // Neither (or both) of items / instructions supplied, or instructions without accounts
+  response.error {
+    http.code 400
+  }
+}
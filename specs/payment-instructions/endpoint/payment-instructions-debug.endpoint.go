@@ -0,0 +1,87 @@
+PaymentInstructionsDebugRequest {
+  path /payment-instructions/debug
+  method POST
+
+  // Input body (validated by data spec): the /payment-instructions payload
+  body {
+    accounts[] {
+      id string
+      balance? number
+      currency? string
+      balances? object
+      overdraft_limit? number
+      minimum_balance? number
+      daily_limit? number
+      status? string
+    }
+    instruction string<trim>
+    fx_rates? object
+    aliases? object
+    decimal_separator? string
+    default_currency? string
+    timezone? string
+    case_insensitive_ids? boolean
+    fuzzy_keywords? boolean
+    strict? boolean
+    collection_account? string
+  }
+
+  // -------------------------
+  // SUCCESSFUL RESPONSE
+  // -------------------------
+  // Nothing is executed; an instruction that does not parse is still 200 with the failure in outcome.
+  response.ok {
+    http.code 200
+
+    data {
+      instruction string                   // As sent, before trimming
+      tokens[] {
+        index number
+        token string
+        offset number                      // Position in instruction as sent
+        length number
+      }
+
+      candidates {
+        verb? {                            // null for an empty instruction
+          index number
+          token string
+          reads_as string|null             // e.g. "transfer" for "send"
+          suggestion string|null           // Closest verb when reads_as is null
+        }
+        amounts[] {
+          index number
+          text string
+          amount number
+          ratio number|null
+        }
+        currencies[] {
+          index number
+          token string
+          codes string[]                   // Held codes the token could be
+        }
+        accounts[] {
+          index number
+          text string
+          via string                       // id | alias | suffix | case_insensitive
+          ids string[]
+        }
+      }
+
+      outcome {
+        status string                      // parsed | failed
+        status_code string|null
+        status_reason string|null
+        parse_error object|null            // { segment, token, offset, length } as on /payment-instructions
+      }
+    }
+  }
+
+  // -------------------------
+  // ERROR RESPONSE
+  // -------------------------
+  // Malformed payload
+  response.error {
+    http.code 400
+  }
+}
@@ -6,10 +6,28 @@ PaymentInstructionsRequest {
   body {
     accounts[] {
       id string
-      balance number
-      currency string
+      balance? number                      // Or, for a multi-currency wallet, balances instead
+      currency? string
+      balances? object                     // Wallet: { "NGN": 1000, "USD": 50 }; moves the instruction currency's pocket (AC11 if none)
+      overdraft_limit? number              // Debits may go down to -overdraft_limit (default 0)
+      minimum_balance? number              // Debits below this floor fail with BL02
+      daily_limit? number                  // Max total debited per UTC day; over it fails with LM01
+      status? string                       // active (default) | frozen (AC08) | closed (AC09)
     }
     instruction string<trim>
+    fx_rates? object                       // e.g. { "NGN/USD": 0.00065 }
+    aliases? object                        // e.g. { "salary": "acc-001" }
+    dry_run? boolean                       // Preview only; also accepted as ?dry_run=true
+    case_insensitive_ids? boolean          // Match account ids ignoring case (default false)
+    fuzzy_keywords? boolean                // Correct typos in verbs and currency words (default false)
+    strict? boolean                        // Refuse guesses: aliases, inferred currency, typos (SY06)
+    collection_account? string             // Payee of a collect/charge that names none
+    partial_execution? boolean             // Send what the debit account can cover instead of failing (BL03)
+    decimal_separator? string              // "," for "1.000,50"; default "." for "1,000.50"
+    default_currency? string               // Currency of an instruction that names none (e.g. "NGN")
+    timezone? string                       // IANA timezone schedules are read in (e.g. "Africa/Lagos"); DT04 if unknown
+    locale? string                         // status_reason language when messages are configured (default English); "en-IN" reads "1,00,000" and "2 lakh"
+    idempotency_key? string                // Or the Idempotency-Key header; retries replay the first result
   }
 
   // -------------------------
@@ -21,21 +39,63 @@ PaymentInstructionsRequest {
     message "Transaction executed successfully"
 
     data {
-      type string                          // DEBIT | TRANSFER | PAY | SCHEDULE | etc.
-      amount number                        // Parsed numeric amount
+      transaction_id string                // Sortable unique id (options.idGenerator); reversible once executed
+      type string                          // DEBIT | CREDIT | TRANSFER | COLLECT | SCHEDULE | STANDING_ORDER | SPLIT | MULTI_DEBIT | COMPOUND | WITHDRAW | DEPOSIT | PAY
+      amount number                        // Parsed numeric amount (decimals up to the currency's minor units)
       currency string                      // Currency extracted from instruction
-      debit_account string                 // Account losing money
-      credit_account string                // Account receiving money
+      debit_account string|null            // Account losing money (null for MULTI_DEBIT and DEPOSIT)
+      credit_account string|null           // Account receiving money (null for SPLIT, WITHDRAW and PAY)
       execute_by number|null               // null or timestamp for SCHEDULE instructions
+      narration string                     // Trailing "for <text>" / "ref: <text>" (max 140), else ""
+      converted_amount? number             // FX only: amount credited in converted_currency
+      converted_currency? string           // FX only: credit account currency
+      fx_rate? number                      // FX only: rate applied (1 currency = fx_rate converted_currency)
+      fee? number                          // Fee debited on top of amount (omitted when fees are off)
+      fee_account? string                  // "..., fee from acc3": the account the fee is debited from instead
+      condition? {                         // "only if balance above 1000": checked against the debit balance
+        comparison string                  //   when the transfer runs (above | below | at_least), else CD01
+        threshold number
+        currency string|null               // As written after the amount, if at all
+      }
+      requested_amount? number             // BL03 only: amount asked for; amount is what was sent
+      confidence? number                   // 0-1: lower when aliases, partial ids or inferred amounts were used
+      matched_verb? string                 // Verb as written, lowercased ("wire" for a TRANSFER); none for STANDING_ORDER
+      canonical_instruction? string        // "TRANSFER 5000.00 NGN FROM acc1 TO acc2", the same however it was phrased
+      warnings[]? {                        // Guesses worth a second look (omitted when none were made)
+        code string                        // currency_inferred | amount_rounded | alias_used | keyword_corrected | account_by_digits | account_case_corrected
+        message string
+      }
+      splits[]? {                          // SPLIT only: one entry per credited account
+        account string
+        amount number
+      }
+      debits[]? {                          // MULTI_DEBIT only: amount drawn from each debit account, in order
+        account string
+        amount number
+      }
+      sub_results[]? {                     // COMPOUND only: one result per clause, in order, each
+        instruction string                 //   with the clause as run (leading verb filled in)
+      }                                    //   and the fields of a single-instruction result
+      recurrence? {                        // STANDING_ORDER only
+        unit string                        // day | week | month
+        count number                       // every <count> units
+        weekday string|null                // set for "every friday"
+        anchor? {                          // set for a named day ("every payday"): its name
+          name string                      //   and the rule it resolves with, e.g.
+          rule object                      //   { day: 25 } or { day: -1, roll: "previous" }
+        }
+      }
 
       status string                        // "successful"
-      status_reason string                 // Human-readable status message
-      status_code string                   // AP00, BL01, AC01, etc.
+      status_reason string                 // Human-readable status message; notes any overdraft used
+      status_code string                   // AP00, BL03 (partially executed), AP02, etc.
+      dry_run? boolean                     // Present (true) for previews
 
       accounts[] {
         id string
-        balance number
+        balance number                     // Unchanged for dry runs
         balance_before number
+        projected_balance? number          // Dry run only: balance the instruction would leave
         currency string
       }
     }
@@ -44,22 +104,38 @@ PaymentInstructionsRequest {
   // -------------------------
   // ERROR / FAILED RESPONSE
   // -------------------------
+  // HTTP 400 when the instruction cannot be read (SY, AM01/AM03/AM04, CU04, CU06, AC04, DT01,
+  // DT03, DT04) and 422 when it reads but the accounts or rules refuse it (AC01, LM01, AC08,
+  // ...; StatusHttpStatuses has the code table). HTTP 409 when an idempotency key is reused
+  // with a different body, or while another process is still executing the request holding it
   response.error {
-    http.code 400
+    http.code 400|422
     status failed
     message "Transaction failed"
 
     data {
+      transaction_id string                // Present on failures too; replays keep the first id
       type string|null                     // Parsed or null
       amount number|null                   // Parsed or null
       currency string|null                 // Parsed or null
       debit_account string|null            // Parsed or null
       credit_account string|null           // Parsed or null
       execute_by number|null               // Parsed or null
+      narration string                     // Parsed or ""
+      confidence? number                   // Set once the accounts are resolved
+      matched_verb? string                 // Set once the verb is recognised
+      canonical_instruction? string        // Set once amount, currency and both accounts are resolved
 
       status string                        // "failed"
       status_reason string                 // Detailed reason for failure
-      status_code string                   // Error code: SY03, CU02, BL01…
+      status_code string                   // Error code: SY03, CU02, AC01, BL02…
+      reason_category string               // parse_error | validation | funds | limits | account
+      parse_error? {                       // Parse failures (SY, AM, CU02/CU06, AC04): what could not be read
+        segment string                     // verb | amount | currency | debit | credit
+        token string|null                  // Text at fault as written; null when the segment is missing
+        offset number                      // Character offset in instruction (its end when missing)
+        length number                      // Characters covered (0 when missing)
+      }
 
       // For parseable but failed transactions: return accounts with balances_before = balance
       // For unparseable instruction (SY03): return [] (empty array)
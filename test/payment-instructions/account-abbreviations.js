@@ -0,0 +1,70 @@
+const assert = require('assert');
+const paymentInstructions = require('@app/services/payment-instructions/payment-instructions');
+const { parseAccountReference } = require('@app/services/payment-instructions/helpers');
+
+describe('payment-instructions: abbreviated account references', () => {
+  function makeAccounts() {
+    return [
+      { id: '1', balance: 1000, currency: 'NGN' },
+      { id: 'acc2', balance: 0, currency: 'NGN' },
+    ];
+  }
+
+  const forms = [
+    'a/c1',
+    'a/c 1',
+    'A/C no. 1',
+    'acct1',
+    'acct 1',
+    'acct. 1',
+    'number 1',
+    'account number 1',
+    '#1',
+    '# 1',
+    'no.1',
+  ];
+  forms.forEach((form) => {
+    it(`reads "${form}" as account 1`, async () => {
+      const result = await paymentInstructions({
+        accounts: makeAccounts(),
+        instruction: `transfer 100 NGN from ${form} to acc2`,
+      });
+      assert.strictEqual(result.status_code, 'AP00');
+      assert.strictEqual(result.debit_account, '1');
+      assert.deepStrictEqual(result.accounts.map((a) => a.balance), [900, 100]);
+    });
+  });
+
+  it('reads abbreviations after the ACCOUNT keyword and in lists', async () => {
+    const debit = await paymentInstructions({
+      accounts: makeAccounts(),
+      instruction: 'DEBIT 100 NGN FROM ACCOUNT #1 FOR CREDIT TO ACCOUNT acc2',
+    });
+    assert.strictEqual(debit.status_code, 'AP00');
+    const split = await paymentInstructions({
+      accounts: [...makeAccounts(), { id: '3', balance: 0, currency: 'NGN' }],
+      instruction: 'split 100 NGN from acct 1 to acc2 and a/c 3',
+    });
+    assert.strictEqual(split.status_code, 'AP00');
+    assert.deepStrictEqual(split.splits.map((s) => s.account), ['acc2', '3']);
+  });
+
+  it('keeps ids that only look abbreviated', () => {
+    const accounts = [
+      { id: 'acct1', balance: 0, currency: 'NGN' },
+      { id: '1', balance: 0, currency: 'NGN' },
+      { id: '#7', balance: 0, currency: 'NGN' },
+    ];
+    // "acct1" is an account of its own, so it is not cut down to "1"
+    assert.strictEqual(parseAccountReference(['acct1'], 0, accounts).token, 'acct1');
+    assert.strictEqual(parseAccountReference(['#7'], 0, accounts).token, '#7');
+    // Nothing is stripped when the id after the marker is no account either
+    assert.deepStrictEqual(parseAccountReference(['acct99', 'to'], 0, accounts), {
+      token: 'acct99',
+      suffix: null,
+      consumed: 1,
+    });
+    // Without the accounts no abbreviation is read at all
+    assert.strictEqual(parseAccountReference(['a/c', '1'], 0).token, 'a/c');
+  });
+});
@@ -0,0 +1,77 @@
+const assert = require('assert');
+const paymentInstructions = require('@app/services/payment-instructions/payment-instructions');
+const { createAccountIdMatcher } = require('@app/services/payment-instructions/helpers');
+
+describe('payment-instructions: account id format', () => {
+  const accountIdFormat = { length: 10, characters: 'digits' };
+  function makeAccounts() {
+    return [
+      { id: '1234567890', balance: 5000, currency: 'NGN' },
+      { id: '0987654321', balance: 0, currency: 'NGN' },
+      { id: '12345', balance: 0, currency: 'NGN' },
+    ];
+  }
+  function run(instruction, options = { accountIdFormat }) {
+    return paymentInstructions({ accounts: makeAccounts(), instruction }, options);
+  }
+  const reason = (id) => `Malformed account reference, not in the account id format: ${id}`;
+
+  it('runs instructions whose ids have the shape', async () => {
+    const result = await run('transfer 100 NGN from 1234567890 to 0987654321');
+    assert.strictEqual(result.status_code, 'AP00');
+  });
+
+  it('rejects a short id with AC04 before looking it up', async () => {
+    const unknown = await run('transfer 100 NGN from 1234567890 to 999');
+    assert.strictEqual(unknown.status_code, 'AC04');
+    assert.strictEqual(unknown.status_reason, reason('999'));
+    assert.deepStrictEqual(unknown.parse_error, {
+      segment: 'credit',
+      token: '999',
+      offset: 36,
+      length: 3,
+    });
+    // Even an id the request holds, once it is not in the format
+    const held = await run('transfer 100 NGN from 1234567890 to 12345');
+    assert.strictEqual(held.status_code, 'AC04');
+    assert.strictEqual(held.status_reason, reason('12345'));
+    const unchecked = await run('transfer 100 NGN from 1234567890 to 12345', {});
+    assert.strictEqual(unchecked.status_code, 'AP00');
+  });
+
+  it('checks every account of a split and a cash instruction', async () => {
+    const split = await run('split 100 NGN from 1234567890 equally between 0987654321 and 12345');
+    assert.strictEqual(split.status_code, 'AC04');
+    assert.strictEqual(split.status_reason, reason('12345'));
+    const cash = await run('withdraw 100 NGN from 12345');
+    assert.strictEqual(cash.status_code, 'AC04');
+  });
+
+  it('takes a predicate or a declarative format and refuses anything else', async () => {
+    const tenDigits = createAccountIdMatcher({ length: 10, characters: 'digits' });
+    assert.deepStrictEqual(['1234567890', '123456789a', '12345'].map(tenDigits), [
+      true,
+      false,
+      false,
+    ]);
+    const prefixed = createAccountIdMatcher({ prefix: 'NG-', minLength: 5, characters: 'digits' });
+    assert.deepStrictEqual(['NG-12', 'NG-1', 'US-12', 'NG-1a'].map(prefixed), [
+      true,
+      false,
+      false,
+      false,
+    ]);
+    const byPredicate = await run('transfer 100 NGN from 1234567890 to 12345', {
+      accountIdFormat: (id) => id.length === 10,
+    });
+    assert.strictEqual(byPredicate.status_code, 'AC04');
+    assert.strictEqual(createAccountIdMatcher(undefined)('anything'), true);
+    const invalid = [{ accountIdFormat: '[0-9]{10}' }, { accountIdFormat: { characters: 'hex' } }];
+    for (let i = 0; i < invalid.length; i++) {
+      // eslint-disable-next-line no-await-in-loop
+      await assert.rejects(run('transfer 100 NGN from 1234567890 to 0987654321', invalid[i]), {
+        message: 'Account id format must be a predicate or a format object',
+      });
+    }
+  });
+});
@@ -0,0 +1,74 @@
+const assert = require('assert');
+const paymentInstructions = require('@app/services/payment-instructions/payment-instructions');
+
+describe('payment-instructions: frozen and closed accounts', () => {
+  function makeAccounts(statuses = {}) {
+    const accounts = [
+      { id: 'acc1', balance: 1000, currency: 'NGN' },
+      { id: 'acc2', balance: 500, currency: 'NGN' },
+      { id: 'acc3', balance: 0, currency: 'NGN' },
+    ];
+    accounts.forEach((a) => {
+      if (statuses[a.id]) a.status = statuses[a.id];
+    });
+    return accounts;
+  }
+  function run(instruction, statuses) {
+    return paymentInstructions({ accounts: makeAccounts(statuses), instruction });
+  }
+
+  it('fails a debit from a frozen account with AC08, balances unchanged', async () => {
+    const result = await run('DEBIT 100 NGN FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc2', {
+      acc1: 'frozen',
+    });
+    assert.strictEqual(result.status, 'failed');
+    assert.strictEqual(result.status_code, 'AC08');
+    assert.strictEqual(result.status_reason, 'Account is frozen: acc1');
+    assert.deepStrictEqual(
+      result.accounts.map((a) => [a.id, a.balance, a.balance_before]),
+      [
+        ['acc1', 1000, 1000],
+        ['acc2', 500, 500],
+      ]
+    );
+  });
+
+  it('fails a credit to a closed account with AC09, balances unchanged', async () => {
+    const result = await run('CREDIT 100 NGN TO ACCOUNT acc2 FOR DEBIT FROM ACCOUNT acc1', {
+      acc2: 'closed',
+    });
+    assert.strictEqual(result.status_code, 'AC09');
+    assert.strictEqual(result.status_reason, 'Account is closed: acc2');
+    assert.ok(result.accounts.every((a) => a.balance === a.balance_before));
+  });
+
+  it('checks the status before the balance rules', async () => {
+    const result = await run('DEBIT 5000 NGN FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc2', {
+      acc2: 'Frozen',
+    });
+    assert.strictEqual(result.status_code, 'AC08');
+  });
+
+  it('blocks splits, multi-debits and cash instructions too', async () => {
+    const split = await run('SPLIT 100 NGN FROM acc1 EQUALLY BETWEEN acc2 AND acc3', {
+      acc3: 'closed',
+    });
+    assert.strictEqual(split.status_code, 'AC09');
+    assert.deepStrictEqual(split.accounts.map((a) => a.balance), [1000, 500, 0]);
+    const multi = await run('TRANSFER 1200 NGN TO acc3 FROM acc1 AND acc2', { acc2: 'frozen' });
+    assert.strictEqual(multi.status_code, 'AC08');
+    assert.strictEqual(multi.status_reason, 'Account is frozen: acc2');
+    const withdrawal = await run('WITHDRAW 100 NGN FROM ACCOUNT acc1', { acc1: 'frozen' });
+    assert.strictEqual(withdrawal.status_code, 'AC08');
+    assert.strictEqual(withdrawal.accounts[0].balance, 1000);
+  });
+
+  it('moves money as usual for active accounts and accounts without a status', async () => {
+    const result = await run('DEBIT 100 NGN FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc2', {
+      acc1: 'active',
+      acc3: 'closed',
+    });
+    assert.strictEqual(result.status_code, 'AP00');
+    assert.deepStrictEqual(result.accounts.map((a) => a.balance), [900, 600]);
+  });
+});
@@ -0,0 +1,291 @@
+const assert = require('assert');
+const processStoredInstruction = require('@app/services/payment-instructions/process-stored-instruction');
+const createMemoryAccountStore = require('@app/services/payment-instructions/stores/create-memory-account-store');
+
+describe('payment-instructions: account store', () => {
+  // Records every call so the tests can see what was loaded and written
+  function mockStore(accounts) {
+    const rows = {};
+    accounts.forEach((a) => {
+      rows[a.id] = { ...a };
+    });
+    const calls = [];
+    return {
+      calls,
+      rows,
+      getAccount: async (id) => {
+        calls.push(['getAccount', id]);
+        return rows[id] ? { ...rows[id] } : null;
+      },
+      updateBalance: async (id, balance) => {
+        calls.push(['updateBalance', id, balance]);
+        rows[id].balance = balance;
+      },
+    };
+  }
+  const store = () =>
+    mockStore([
+      { id: 'acc1', balance: 500, currency: 'NGN' },
+      { id: 'acc2', balance: 0, currency: 'NGN' },
+      { id: 'acc3', balance: 900, currency: 'NGN' },
+    ]);
+
+  it('loads only the referenced accounts and writes the new balances back', async () => {
+    const accountStore = store();
+    const result = await processStoredInstruction(
+      { instruction: 'DEBIT 100 NGN FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc2' },
+      { accountStore }
+    );
+    assert.strictEqual(result.status_code, 'AP00');
+    const loaded = accountStore.calls.filter((c) => c[0] === 'getAccount').map((c) => c[1]);
+    assert.ok(loaded.indexOf('acc1') !== -1 && loaded.indexOf('acc2') !== -1);
+    assert.strictEqual(loaded.indexOf('acc3'), -1);
+    assert.deepStrictEqual(
+      accountStore.calls.filter((c) => c[0] === 'updateBalance'),
+      [
+        ['updateBalance', 'acc1', 400],
+        ['updateBalance', 'acc2', 100],
+      ]
+    );
+  });
+
+  it('fails with AC03 for an account the store does not have', async () => {
+    const accountStore = store();
+    const result = await processStoredInstruction(
+      { instruction: 'DEBIT 100 NGN FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc9' },
+      { accountStore }
+    );
+    assert.strictEqual(result.status, 'failed');
+    assert.strictEqual(result.status_code, 'AC03');
+    assert.strictEqual(accountStore.calls.filter((c) => c[0] === 'updateBalance').length, 0);
+    assert.strictEqual(accountStore.rows.acc1.balance, 500);
+    const none = await processStoredInstruction(
+      { instruction: 'DEBIT 100 NGN FROM ACCOUNT acc8 FOR CREDIT TO ACCOUNT acc9' },
+      { accountStore }
+    );
+    assert.strictEqual(none.status_code, 'AC03');
+  });
+
+  it('writes nothing for a declined instruction or a dry run', async () => {
+    const accountStore = store();
+    const declined = await processStoredInstruction(
+      { instruction: 'DEBIT 600 NGN FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc2' },
+      { accountStore }
+    );
+    assert.strictEqual(declined.status_code, 'AC01');
+    const preview = await processStoredInstruction(
+      { instruction: 'SPLIT 100 NGN FROM acc1 BETWEEN acc2, acc3', dry_run: true },
+      { accountStore }
+    );
+    assert.strictEqual(preview.status_code, 'AP00');
+    assert.strictEqual(accountStore.calls.filter((c) => c[0] === 'updateBalance').length, 0);
+  });
+
+  it('resolves aliases and commits through the in-memory store transaction', async () => {
+    const accountStore = createMemoryAccountStore([
+      { id: 'acc1', balance: 500, currency: 'NGN' },
+      { id: 'landlord-01', balance: 0, currency: 'NGN' },
+    ]);
+    const result = await processStoredInstruction(
+      {
+        instruction: 'DEBIT 100 NGN FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT landlord',
+        aliases: { landlord: 'landlord-01' },
+      },
+      { accountStore }
+    );
+    assert.strictEqual(result.credit_account, 'landlord-01');
+    assert.strictEqual((await accountStore.getAccount('acc1')).balance, 400);
+    assert.strictEqual((await accountStore.getAccount('landlord-01')).balance, 100);
+  });
+
+  it('leaves every balance as it was when the third of four credits is unknown', async () => {
+    const accountStore = mockStore([
+      { id: 'acc1', balance: 1000, currency: 'NGN' },
+      { id: 'acc2', balance: 10, currency: 'NGN' },
+      { id: 'acc3', balance: 20, currency: 'NGN' },
+      { id: 'acc5', balance: 50, currency: 'NGN' },
+    ]);
+    const split = await processStoredInstruction(
+      { instruction: 'SPLIT 400 NGN FROM acc1 BETWEEN acc2, acc3, acc4 AND acc5' },
+      { accountStore }
+    );
+    assert.strictEqual(split.status_code, 'AC03');
+    const multi = await processStoredInstruction(
+      { instruction: 'TRANSFER 1200 NGN TO acc5 FROM acc2, acc3, acc4 AND acc1' },
+      { accountStore }
+    );
+    assert.strictEqual(multi.status_code, 'AC03');
+    assert.strictEqual(accountStore.calls.filter((c) => c[0] === 'updateBalance').length, 0);
+    assert.deepStrictEqual(
+      Object.keys(accountStore.rows).map((id) => accountStore.rows[id].balance),
+      [1000, 10, 20, 50]
+    );
+  });
+
+  it('puts the written balances back when a later write fails', async () => {
+    const accountStore = mockStore([
+      { id: 'acc1', balance: 1000, currency: 'NGN' },
+      { id: 'acc2', balance: 10, currency: 'NGN' },
+      { id: 'acc3', balance: 20, currency: 'NGN' },
+      { id: 'acc4', balance: 30, currency: 'NGN' },
+    ]);
+    const write = accountStore.updateBalance;
+    let writes = 0;
+    accountStore.updateBalance = async (id, balance) => {
+      writes++;
+      if (writes === 3) throw new Error('connection lost');
+      return write(id, balance);
+    };
+    await assert.rejects(
+      processStoredInstruction(
+        { instruction: 'SPLIT 300 NGN FROM acc1 BETWEEN acc2, acc3 AND acc4' },
+        { accountStore }
+      ),
+      (err) => err.errorCode === 'APPLICATION_ERROR'
+    );
+    assert.deepStrictEqual(
+      Object.keys(accountStore.rows).map((id) => accountStore.rows[id].balance),
+      [1000, 10, 20, 30]
+    );
+  });
+
+  it('restores the exact snapshot after a failed write, untouched accounts included', async () => {
+    const memory = createMemoryAccountStore([
+      { id: 'acc1', balance: 1000, currency: 'NGN', status: 'active' },
+      { id: 'acc2', balance: 10, currency: 'NGN' },
+      { id: 'acc3', balance: 20, currency: 'NGN' },
+      { id: 'acc4', balance: 30, currency: 'NGN', daily_limit: 500 },
+      { id: 'acc5', balance: 40, currency: 'NGN' },
+    ]);
+    const before = await memory.snapshot();
+    let writes = 0;
+    const restored = [];
+    // No transaction, so the executor falls back to the snapshot
+    const accountStore = {
+      getAccount: memory.getAccount,
+      snapshot: memory.snapshot,
+      restore: async (snapshot) => {
+        restored.push(snapshot.map((a) => a.id));
+        return memory.restore(snapshot);
+      },
+      updateBalance: async (id, balance) => {
+        writes++;
+        if (writes === 3) throw new Error('connection lost');
+        return memory.updateBalance(id, balance);
+      },
+    };
+    await assert.rejects(
+      processStoredInstruction(
+        { instruction: 'SPLIT 300 NGN FROM acc1 BETWEEN acc2, acc3 AND acc4' },
+        { accountStore }
+      ),
+      (err) => err.errorCode === 'APPLICATION_ERROR'
+    );
+    assert.strictEqual(writes, 3);
+    assert.deepStrictEqual(restored, [['acc1', 'acc2', 'acc3', 'acc4']]);
+    assert.deepStrictEqual(await memory.snapshot(), before);
+  });
+
+  it('snapshots and restores the in-memory store idempotently', async () => {
+    const memory = createMemoryAccountStore([
+      { id: 'acc1', balance: 500, currency: 'NGN' },
+      { id: 'acc2', balance: 0, currency: 'NGN' },
+    ]);
+    const all = await memory.snapshot();
+    const some = await memory.snapshot(['acc2', 'acc9']);
+    assert.deepStrictEqual(some, [{ id: 'acc2', balance: 0, currency: 'NGN' }]);
+    await memory.updateBalance('acc1', 100);
+    await memory.updateBalance('acc2', 400);
+    await memory.restore(some);
+    assert.deepStrictEqual(await memory.snapshot(), [
+      { id: 'acc1', balance: 100, currency: 'NGN' },
+      { id: 'acc2', balance: 0, currency: 'NGN' },
+    ]);
+    await memory.restore(all);
+    await memory.restore(all);
+    assert.deepStrictEqual(await memory.snapshot(), all);
+    all[0].balance = 1;
+    assert.strictEqual((await memory.getAccount('acc1')).balance, 500);
+  });
+
+  it('writes the clauses of a compound that executed when a later one fails', async () => {
+    const accountStore = createMemoryAccountStore([
+      { id: 'acc1', balance: 1000, currency: 'NGN' },
+      { id: 'acc2', balance: 0, currency: 'NGN' },
+      { id: 'acc3', balance: 0, currency: 'NGN' },
+    ]);
+    const result = await processStoredInstruction(
+      { instruction: 'transfer 100 NGN from acc1 to acc2 and 5000 NGN from acc1 to acc3' },
+      { accountStore }
+    );
+    assert.strictEqual(result.status, 'failed');
+    assert.deepStrictEqual(result.sub_results.map((r) => r.status_code), ['AP00', 'AC01']);
+    assert.deepStrictEqual(
+      result.accounts.map((a) => [a.id, a.balance]),
+      [
+        ['acc1', 900],
+        ['acc2', 100],
+        ['acc3', 0],
+      ]
+    );
+    assert.deepStrictEqual(await accountStore.snapshot(), [
+      { id: 'acc1', balance: 900, currency: 'NGN' },
+      { id: 'acc2', balance: 100, currency: 'NGN' },
+      { id: 'acc3', balance: 0, currency: 'NGN' },
+    ]);
+  });
+
+  it('keeps both debits when two instructions run on one account at the same time', async () => {
+    const debit = (amount, to) => ({
+      instruction: `DEBIT ${amount} NGN FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT ${to}`,
+    });
+    const memory = () =>
+      createMemoryAccountStore([
+        { id: 'acc1', balance: 1000, currency: 'NGN' },
+        { id: 'acc2', balance: 0, currency: 'NGN' },
+        { id: 'acc3', balance: 0, currency: 'NGN' },
+      ]);
+    // Through the store transaction, which locks what it reads
+    const withTx = memory();
+    const results = await Promise.all([
+      processStoredInstruction(debit(100, 'acc2'), { accountStore: withTx }),
+      processStoredInstruction(debit(300, 'acc3'), { accountStore: withTx }),
+    ]);
+    assert.deepStrictEqual(results.map((r) => r.accounts[0].balance_before), [1000, 900]);
+    assert.strictEqual((await withTx.getAccount('acc1')).balance, 600);
+    // Without transactions, and with every store call taking a moment
+    const slow = memory();
+    const pause = () => new Promise((resolve) => setTimeout(resolve, 1));
+    const withoutTx = {
+      getAccount: async (id) => {
+        await pause();
+        return slow.getAccount(id);
+      },
+      updateBalance: async (id, balance) => {
+        await pause();
+        return slow.updateBalance(id, balance);
+      },
+    };
+    await Promise.all([
+      processStoredInstruction(debit(100, 'acc2'), { accountStore: withoutTx }),
+      processStoredInstruction(debit(300, 'acc3'), { accountStore: withoutTx }),
+    ]);
+    assert.deepStrictEqual(
+      [(await slow.getAccount('acc1')).balance, (await slow.getAccount('acc3')).balance],
+      [600, 300]
+    );
+  });
+
+  it('uses the inline accounts when no store is passed', async () => {
+    const accounts = [
+      { id: 'acc1', balance: 500, currency: 'NGN' },
+      { id: 'acc2', balance: 0, currency: 'NGN' },
+    ];
+    const result = await processStoredInstruction({
+      accounts,
+      instruction: 'DEBIT 100 NGN FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc2',
+    });
+    assert.strictEqual(result.accounts[0].balance, 400);
+    assert.strictEqual(accounts[0].balance, 500);
+  });
+});
@@ -0,0 +1,65 @@
+const assert = require('assert');
+const { parseAccountReference } = require('@app/services/payment-instructions/helpers');
+const paymentInstructions = require('@app/services/payment-instructions/payment-instructions');
+
+describe('payment-instructions: trailing-digit account references', () => {
+  const accounts = [
+    { id: 'acc-00014821', balance: 1000, currency: 'NGN' },
+    { id: 'acc-00027777', balance: 0, currency: 'NGN' },
+    { id: 'sav-99997777', balance: 0, currency: 'NGN' },
+  ];
+
+  it('recognises only explicit trailing-digit markers', () => {
+    assert.deepStrictEqual(parseAccountReference(['***4821'], 0), {
+      token: '***4821',
+      suffix: '4821',
+      consumed: 1,
+    });
+    assert.deepStrictEqual(parseAccountReference(['ending', 'in', '4821', 'FOR'], 0), {
+      token: 'ending in 4821',
+      suffix: '4821',
+      consumed: 3,
+    });
+    assert.strictEqual(parseAccountReference(['ending', '4821'], 0).consumed, 2);
+    assert.strictEqual(parseAccountReference(['4821'], 0).suffix, null);
+    assert.strictEqual(parseAccountReference(['acc4821'], 0).suffix, null);
+    assert.strictEqual(parseAccountReference(['***48'], 0).suffix, null);
+  });
+
+  it('resolves a unique match to the canonical id', async () => {
+    const result = await paymentInstructions({
+      accounts,
+      instruction: 'DEBIT 100 NGN FROM ACCOUNT ***4821 FOR CREDIT TO ACCOUNT acc-00027777',
+    });
+    assert.strictEqual(result.status_code, 'AP00');
+    assert.strictEqual(result.debit_account, 'acc-00014821');
+  });
+
+  it('matches "ending in" on the credit side', async () => {
+    const result = await paymentInstructions({
+      accounts,
+      instruction: 'CREDIT 100 NGN TO ACCOUNT ending in 4821 FOR DEBIT FROM ACCOUNT acc-00014821',
+    });
+    // Resolves to the same account as the debit side
+    assert.strictEqual(result.status_code, 'AC02');
+    assert.strictEqual(result.credit_account, 'acc-00014821');
+  });
+
+  it('returns AC06 when several accounts share the digits', async () => {
+    const result = await paymentInstructions({
+      accounts,
+      instruction: 'DEBIT 100 NGN FROM ACCOUNT acc-00014821 FOR CREDIT TO ACCOUNT ending 7777',
+    });
+    assert.strictEqual(result.status_code, 'AC06');
+    assert.ok(result.status_reason.indexOf('acc-00027777, sav-99997777') !== -1);
+  });
+
+  it('returns AC03 when no account has the digits', async () => {
+    const result = await paymentInstructions({
+      accounts,
+      instruction: 'DEBIT 100 NGN FROM ACCOUNT ***1234 FOR CREDIT TO ACCOUNT acc-00027777',
+    });
+    assert.strictEqual(result.status_code, 'AC03');
+    assert.strictEqual(result.debit_account, '***1234');
+  });
+});
@@ -0,0 +1,87 @@
+const assert = require('assert');
+const parseAccountsCsv = require('@app/services/payment-instructions/importers/parse-accounts-csv');
+const processBatch = require('@app/services/payment-instructions/process-batch');
+
+describe('payment-instructions: accounts CSV import', () => {
+  function errorOf(csv) {
+    let error = null;
+    try {
+      parseAccountsCsv(csv);
+    } catch (err) {
+      error = err;
+    }
+    assert.ok(error, 'expected the CSV to be rejected');
+    assert.strictEqual(error.errorCode, 'VALIDATION_ERROR');
+    return error;
+  }
+
+  it('reads a clean file into accounts and aliases for a batch run', async () => {
+    const csv = [
+      'id,balance,currency,alias',
+      'acc-001,1500.50,NGN,Salary',
+      'acc-002,0,ngn,',
+      '"acc-003", 250 ,NGN,"Rent, Lagos"',
+      '',
+    ].join('\r\n');
+    const { accounts, aliases, warnings } = parseAccountsCsv(csv);
+    assert.deepStrictEqual(accounts, [
+      { id: 'acc-001', balance: 1500.5, currency: 'NGN' },
+      { id: 'acc-002', balance: 0, currency: 'NGN' },
+      { id: 'acc-003', balance: 250, currency: 'NGN' },
+    ]);
+    assert.deepStrictEqual(aliases, { Salary: 'acc-001', 'Rent, Lagos': 'acc-003' });
+    assert.deepStrictEqual(warnings, []);
+
+    const batch = await processBatch({
+      accounts,
+      aliases,
+      instructions: ['DEBIT 500 NGN FROM ACCOUNT salary FOR CREDIT TO ACCOUNT acc-002'],
+    });
+    assert.strictEqual(batch.results[0].status_code, 'AP00');
+    assert.deepStrictEqual(batch.accounts.map((a) => a.balance), [1000.5, 500, 250]);
+  });
+
+  it('takes columns in order without a header and flags unknown currencies', () => {
+    const { accounts, warnings } = parseAccountsCsv('a1,10,USD\na2,-5,CNY\na3,7,XYZ\n');
+    assert.deepStrictEqual(accounts.map((a) => a.id), ['a1', 'a2', 'a3']);
+    assert.deepStrictEqual(warnings, [
+      { row: 2, message: 'Currency not supported by the parser: CNY' },
+      { row: 3, message: 'Unknown currency code: not an ISO 4217 code: XYZ' },
+    ]);
+  });
+
+  it('rejects non-numeric balances with their row numbers', () => {
+    const error = errorOf(
+      'id,currency,balance\nacc1,NGN,100\nacc2,NGN,1e3\nacc3,NGN,\nacc4,NGN,12a'
+    );
+    assert.deepStrictEqual(error.details.map((d) => d.row), [3, 4, 5]);
+    assert.strictEqual(
+      error.message,
+      'Invalid accounts CSV: row 3: balance is not a number: "1e3"; ' +
+        'row 4: balance is not a number: ""; row 5: balance is not a number: "12a"'
+    );
+  });
+
+  it('rejects duplicate ids and aliases', () => {
+    const error = errorOf(
+      'id,balance,currency,alias\nacc1,1,NGN,Rent\nacc2,1,NGN,rent\nacc1,1,NGN,\nbad id,1,NGN,'
+    );
+    assert.deepStrictEqual(error.details, [
+      { row: 3, message: 'duplicate alias "rent" (first on row 2)' },
+      { row: 4, message: 'duplicate account id "acc1" (first on row 2)' },
+      {
+        row: 5,
+        message: 'account id may only use letters, numbers, hyphen, dot and at: "bad id"',
+      },
+    ]);
+  });
+
+  it('rejects a header without the required columns and unclosed quotes', () => {
+    assert.strictEqual(errorOf('id,amount,currency\nacc1,1,NGN').details[0].row, 1);
+    const unclosed = errorOf('acc1,1,NGN\n"acc2,1,NGN\n');
+    assert.strictEqual(
+      unclosed.message,
+      'Invalid accounts CSV: row 2: has a quote that is never closed'
+    );
+  });
+});
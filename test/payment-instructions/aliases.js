@@ -0,0 +1,65 @@
+const assert = require('assert');
+const { resolveAccountAlias } = require('@app/services/payment-instructions/helpers');
+const paymentInstructions = require('@app/services/payment-instructions/payment-instructions');
+
+describe('payment-instructions: account aliases', () => {
+  const accounts = [
+    { id: 'acc-001', balance: 1000, currency: 'NGN' },
+    { id: 'acc-002', balance: 0, currency: 'NGN' },
+  ];
+  const aliases = { salary: 'acc-001', Rent: 'acc-002' };
+
+  it('matches aliases case-insensitively', () => {
+    assert.deepStrictEqual(resolveAccountAlias('SALARY', aliases), { id: 'acc-001' });
+    assert.deepStrictEqual(resolveAccountAlias('rent', aliases), { id: 'acc-002' });
+    assert.strictEqual(resolveAccountAlias('groceries', aliases), null);
+  });
+
+  it('reports differently-cased aliases for different accounts as ambiguous', () => {
+    assert.deepStrictEqual(resolveAccountAlias('rent', { rent: 'a', RENT: 'b' }), {
+      ambiguous: ['a', 'b'],
+    });
+    assert.deepStrictEqual(resolveAccountAlias('rent', { rent: 'a', RENT: 'a' }), { id: 'a' });
+  });
+
+  it('resolves alias tokens to canonical ids in the response', async () => {
+    const result = await paymentInstructions({
+      accounts,
+      aliases,
+      instruction: 'DEBIT 100 NGN FROM ACCOUNT Salary FOR CREDIT TO ACCOUNT rent',
+    });
+    assert.strictEqual(result.status_code, 'AP00');
+    assert.strictEqual(result.debit_account, 'acc-001');
+    assert.strictEqual(result.credit_account, 'acc-002');
+    assert.strictEqual(result.accounts[1].balance, 100);
+  });
+
+  it('still accepts exact ids when an alias map is given', async () => {
+    const result = await paymentInstructions({
+      accounts,
+      aliases,
+      instruction: 'DEBIT 100 NGN FROM ACCOUNT acc-001 FOR CREDIT TO ACCOUNT rent',
+    });
+    assert.strictEqual(result.status_code, 'AP00');
+  });
+
+  it('lists the valid names for an unknown alias', async () => {
+    const result = await paymentInstructions({
+      accounts,
+      aliases,
+      instruction: 'DEBIT 100 NGN FROM ACCOUNT savings FOR CREDIT TO ACCOUNT rent',
+    });
+    assert.strictEqual(result.status_code, 'AC03');
+    assert.strictEqual(result.status_reason, 'Account not found. Known account names: salary, Rent');
+  });
+
+  it('returns AC05 for an ambiguous alias', async () => {
+    const result = await paymentInstructions({
+      accounts,
+      aliases: { rent: 'acc-002', RENT: 'acc-001' },
+      instruction: 'CREDIT 100 NGN TO ACCOUNT Rent FOR DEBIT FROM ACCOUNT acc-001',
+    });
+    assert.strictEqual(result.status_code, 'AC05');
+    assert.ok(result.status_reason.indexOf('acc-002 or acc-001') !== -1);
+  });
+});
@@ -0,0 +1,56 @@
+const assert = require('assert');
+const paymentInstructions = require('@app/services/payment-instructions/payment-instructions');
+
+describe('payment-instructions: reporting every input account', () => {
+  function makeAccounts() {
+    return [
+      { id: 'acc1', balance: 1000, currency: 'NGN' },
+      { id: 'acc2', balance: 300, currency: 'ngn' },
+      { id: 'acc3', balance: 50, currency: 'NGN' },
+    ];
+  }
+  function run(instruction, extra = {}, options = {}) {
+    return paymentInstructions({ accounts: makeAccounts(), instruction, ...extra }, options);
+  }
+
+  it('returns all three accounts in input order with before and after balances', async () => {
+    const result = await run('DEBIT 20 NGN FROM ACCOUNT acc3 FOR CREDIT TO ACCOUNT acc1', {
+      include_all_accounts: true,
+    });
+    assert.strictEqual(result.status_code, 'AP00');
+    assert.strictEqual(result.accounts.length, 3);
+    assert.deepStrictEqual(result.accounts, [
+      { id: 'acc1', balance: 1020, balance_before: 1000, currency: 'NGN' },
+      { id: 'acc2', balance: 300, balance_before: 300, currency: 'NGN' },
+      { id: 'acc3', balance: 30, balance_before: 50, currency: 'NGN' },
+    ]);
+  });
+
+  it('reports only the moved accounts by default', async () => {
+    const result = await run('DEBIT 200 NGN FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc2');
+    assert.deepStrictEqual(result.accounts.map((a) => a.id), ['acc1', 'acc2']);
+  });
+
+  it('reports every account for failures and dry runs too', async () => {
+    const options = { includeAllAccounts: true };
+    const instruction = 'DEBIT 5 USD FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc2';
+    const failed = await run(instruction, {}, options);
+    assert.strictEqual(failed.status, 'failed');
+    assert.ok(failed.accounts.every((a) => a.balance === a.balance_before));
+    assert.deepStrictEqual(failed.accounts.map((a) => a.id), ['acc1', 'acc2', 'acc3']);
+
+    const preview = await run(
+      'DEBIT 100 NGN FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc2',
+      { dry_run: true },
+      options
+    );
+    assert.deepStrictEqual(
+      preview.accounts.map((a) => [a.id, a.balance, a.projected_balance]),
+      [
+        ['acc1', 1000, 900],
+        ['acc2', 300, 400],
+        ['acc3', 50, 50],
+      ]
+    );
+  });
+});
@@ -0,0 +1,73 @@
+const assert = require('assert');
+const paymentInstructions = require('@app/services/payment-instructions/payment-instructions');
+const parseInstruction = require('@app/services/payment-instructions/parse-instruction');
+
+describe('payment-instructions: candidates for ambiguous references', () => {
+  function makeAccounts() {
+    return [
+      { id: 'acc-00014821', balance: 1000, currency: 'NGN' },
+      { id: 'sav-99997777', balance: 0, currency: 'NGN' },
+      { id: 'acc-00027777', balance: 0, currency: 'NGN' },
+    ];
+  }
+
+  it('lists both accounts an alias could mean, in alias order', async () => {
+    const result = await paymentInstructions({
+      accounts: makeAccounts(),
+      aliases: { rent: 'acc-00027777', RENT: 'sav-99997777' },
+      instruction: 'DEBIT 100 NGN FROM ACCOUNT acc-00014821 FOR CREDIT TO ACCOUNT Rent',
+    });
+    assert.strictEqual(result.status_code, 'AC05');
+    assert.deepStrictEqual(result.candidates, ['acc-00027777', 'sav-99997777']);
+  });
+
+  it('lists both accounts sharing the last digits, in request order', async () => {
+    const result = await paymentInstructions({
+      accounts: makeAccounts(),
+      instruction: 'DEBIT 100 NGN FROM ACCOUNT acc-00014821 FOR CREDIT TO ACCOUNT ending 7777',
+    });
+    assert.strictEqual(result.status_code, 'AC06');
+    assert.deepStrictEqual(result.candidates, ['sav-99997777', 'acc-00027777']);
+  });
+
+  it('carries the candidates from split, withdrawal and parse-only errors', async () => {
+    const split = await paymentInstructions({
+      accounts: makeAccounts(),
+      instruction: 'SPLIT 100 NGN FROM ACCOUNT acc-00014821 TO ***7777',
+    });
+    assert.strictEqual(split.status_code, 'AC06');
+    assert.deepStrictEqual(split.candidates, ['sav-99997777', 'acc-00027777']);
+
+    const withdrawal = await paymentInstructions({
+      accounts: makeAccounts(),
+      instruction: 'WITHDRAW 100 NGN FROM ACCOUNT ***7777',
+    });
+    assert.strictEqual(withdrawal.status_code, 'AC06');
+    assert.deepStrictEqual(withdrawal.candidates, ['sav-99997777', 'acc-00027777']);
+
+    await assert.rejects(
+      parseInstruction('DEBIT 100 NGN FROM ACCOUNT acc-00014821 FOR CREDIT TO ACCOUNT ***7777', {
+        accounts: makeAccounts(),
+      }),
+      (error) => {
+        assert.deepStrictEqual(error.details.candidates, ['sav-99997777', 'acc-00027777']);
+        return true;
+      }
+    );
+  });
+
+  it('leaves the field off every other result', async () => {
+    const missing = await paymentInstructions({
+      accounts: makeAccounts(),
+      instruction: 'DEBIT 100 NGN FROM ACCOUNT acc-00014821 FOR CREDIT TO ACCOUNT ***1234',
+    });
+    assert.strictEqual(missing.status_code, 'AC03');
+    assert.strictEqual('candidates' in missing, false);
+    const done = await paymentInstructions({
+      accounts: makeAccounts(),
+      instruction: 'DEBIT 100 NGN FROM ACCOUNT acc-00014821 FOR CREDIT TO ACCOUNT acc-00027777',
+    });
+    assert.strictEqual(done.status_code, 'AP00');
+    assert.strictEqual('candidates' in done, false);
+  });
+});
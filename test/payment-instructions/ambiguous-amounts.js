@@ -0,0 +1,60 @@
+const assert = require('assert');
+const paymentInstructions = require('@app/services/payment-instructions/payment-instructions');
+const { findAmbiguousAmount } = require('@app/services/payment-instructions/helpers');
+
+describe('payment-instructions: ranged and approximate amounts', () => {
+  function makeAccounts() {
+    return [
+      { id: 'acc1', balance: 1000, currency: 'NGN' },
+      { id: 'acc2', balance: 0, currency: 'NGN' },
+      { id: 'acc200', balance: 0, currency: 'NGN' },
+    ];
+  }
+  function run(instruction, accounts = makeAccounts()) {
+    return paymentInstructions({ accounts, instruction });
+  }
+
+  it('fails a range with AM04 instead of a syntax error', async () => {
+    const result = await run('transfer 100 to 200 NGN from acc1 to acc2');
+    assert.strictEqual(result.status, 'failed');
+    assert.strictEqual(result.status_code, 'AM04');
+    const reason = 'Ambiguous amount; give one exact figure: "100 to 200"';
+    assert.strictEqual(result.status_reason, reason);
+    assert.deepStrictEqual(result.accounts, []);
+    const between = await run('transfer between 100 and 200 NGN from acc1 to acc2');
+    assert.strictEqual(between.status_code, 'AM04');
+    assert.deepStrictEqual(between.parse_error, {
+      segment: 'amount',
+      token: 'between 100 and 200',
+      offset: 9,
+      length: 19,
+    });
+  });
+
+  it('fails an approximate amount with AM04, in every flow', async () => {
+    const cases = [
+      ['transfer about 500 NGN from acc1 to acc2', 'about 500'],
+      ['send ~500 from acc1 to acc2', '~500'],
+      ['withdraw roughly five hundred NGN from acc1', 'roughly five hundred'],
+      ['split 100-200 NGN from acc1 equally between acc2 and acc200', '100-200'],
+    ];
+    for (let i = 0; i < cases.length; i++) {
+      // eslint-disable-next-line no-await-in-loop
+      const result = await run(cases[i][0]);
+      assert.strictEqual(result.status_code, 'AM04', cases[i][0]);
+      assert.ok(result.status_reason.endsWith(`"${cases[i][1]}"`), result.status_reason);
+    }
+  });
+
+  it('does not read an account id after "to" as the end of a range', async () => {
+    const named = await run('transfer 100 to acc200 from acc1');
+    assert.strictEqual(named.status_code, 'AP00');
+    assert.strictEqual(named.credit_account, 'acc200');
+    const accounts = [...makeAccounts(), { id: '200', balance: 0, currency: 'NGN' }];
+    const numeric = await run('transfer 100 to 200 from acc1', accounts);
+    assert.strictEqual(numeric.status_code, 'AP00');
+    assert.strictEqual(numeric.credit_account, '200');
+    assert.strictEqual(findAmbiguousAmount(['100', 'NGN', 'to', '200'], 0), null);
+    assert.strictEqual(findAmbiguousAmount(['about'], 0), null);
+  });
+});
@@ -0,0 +1,64 @@
+const assert = require('assert');
+const paymentInstructions = require('@app/services/payment-instructions/payment-instructions');
+const { parseAmount } = require('@app/services/payment-instructions/helpers');
+const DEFAULT_AMOUNT_PARSERS = require('@app/services/payment-instructions/amount-parsers/default-amount-parsers');
+
+describe('payment-instructions: pluggable amount parsers', () => {
+  // "2 lakh" = 200000, "1.5 crore" = 15000000
+  const lakhParser = {
+    name: 'lakh_crore',
+    parse(tokens, start) {
+      const scales = { lakh: 100000, lakhs: 100000, crore: 10000000, crores: 10000000 };
+      const scale = scales[String(tokens[start + 1]).toLowerCase()];
+      const value = Number(tokens[start]);
+      if (scale === undefined || !(value > 0)) return null;
+      return { amount: Math.round(value * scale), consumed: 2 };
+    },
+  };
+  const parsers = [lakhParser, ...DEFAULT_AMOUNT_PARSERS];
+
+  it('tries the default chain in order', () => {
+    assert.deepStrictEqual(
+      DEFAULT_AMOUNT_PARSERS.map((p) => p.name),
+      ['balance_share', 'number_words', 'digits']
+    );
+    assert.deepStrictEqual(parseAmount(['1,000.50'], 0), { amount: 1000.5, consumed: 1 });
+    assert.deepStrictEqual(parseAmount(['5', 'k', 'NGN'], 0), { amount: 5000, consumed: 2 });
+    assert.deepStrictEqual(parseAmount(['five', 'hundred'], 0), { amount: 500, consumed: 2 });
+    assert.strictEqual(parseAmount(['half'], 0).ratio.denominator, 2);
+    assert.strictEqual(parseAmount(['5km'], 0), null);
+  });
+
+  it('reads "2 lakh" with a registered strategy', () => {
+    assert.deepStrictEqual(parseAmount(['2', 'lakh', 'INR'], 0, '.', parsers), {
+      amount: 200000,
+      consumed: 2,
+    });
+    assert.deepStrictEqual(parseAmount(['2', 'lakh', 'INR'], 0), { amount: 2, consumed: 1 });
+    assert.deepStrictEqual(parseAmount(['750'], 0, '.', parsers), { amount: 750, consumed: 1 });
+  });
+
+  it('runs an instruction through options.amountParsers', async () => {
+    const accounts = [
+      { id: 'acc1', balance: 500000, currency: 'NGN' },
+      { id: 'acc2', balance: 0, currency: 'NGN' },
+    ];
+    const instruction = 'DEBIT 2 lakh NGN FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc2';
+    const result = await paymentInstructions(
+      { accounts, instruction },
+      { amountParsers: parsers }
+    );
+    assert.strictEqual(result.status_code, 'AP00');
+    assert.strictEqual(result.amount, 200000);
+    assert.deepStrictEqual(result.accounts.map((a) => a.balance), [300000, 200000]);
+
+    const split = await paymentInstructions(
+      { accounts, instruction: 'SPLIT 1 lakh NGN FROM acc1 EQUALLY BETWEEN acc2' },
+      { amountParsers: parsers }
+    );
+    assert.strictEqual(split.amount, 100000);
+
+    const unregistered = await paymentInstructions({ accounts, instruction });
+    assert.notStrictEqual(unregistered.status_code, 'AP00');
+  });
+});
@@ -0,0 +1,84 @@
+const assert = require('assert');
+const { parseAmount } = require('@app/services/payment-instructions/helpers');
+const paymentInstructions = require('@app/services/payment-instructions/payment-instructions');
+
+describe('payment-instructions: thousands separators and decimals', () => {
+  function amountOf(token, decimalSeparator) {
+    const parsed = parseAmount(['DEBIT', token, 'NGN'], 1, decimalSeparator);
+    return parsed === null ? null : parsed.amount;
+  }
+  function run(instruction, extra = {}) {
+    return paymentInstructions({
+      accounts: [
+        { id: 'a', balance: 5000, currency: 'NGN' },
+        { id: 'b', balance: 0, currency: 'NGN' },
+        { id: 'u', balance: 5000, currency: 'UGX' },
+        { id: 'v', balance: 0, currency: 'UGX' },
+      ],
+      instruction,
+      ...extra,
+    });
+  }
+
+  it('strips comma grouping and keeps the decimal part', () => {
+    assert.strictEqual(amountOf('1,000'), 1000);
+    assert.strictEqual(amountOf('1,000.50'), 1000.5);
+    assert.strictEqual(amountOf('12,345,678'), 12345678);
+    assert.strictEqual(amountOf('1,000.5k'), 1000500);
+    assert.strictEqual(amountOf('0.05'), 0.05);
+  });
+
+  it('rejects malformed groupings and repeated decimal points', () => {
+    assert.strictEqual(amountOf('1,00,0'), null);
+    assert.strictEqual(amountOf('1000,000'), null);
+    assert.strictEqual(amountOf(',100'), null);
+    assert.strictEqual(amountOf('1.000.50'), null);
+    assert.strictEqual(amountOf('1,000.5,0'), null);
+  });
+
+  it('swaps the separators for European-style input', () => {
+    assert.strictEqual(amountOf('1.000', ','), 1000);
+    assert.strictEqual(amountOf('1.000,50', ','), 1000.5);
+    assert.strictEqual(amountOf('1,5k', ','), 1500);
+    assert.strictEqual(amountOf('1.00.0', ','), null);
+    assert.strictEqual(amountOf('1,000.50', ','), null);
+  });
+
+  it('executes a grouped decimal amount', async () => {
+    const result = await run('DEBIT 1,000.50 NGN FROM ACCOUNT a FOR CREDIT TO ACCOUNT b');
+    assert.strictEqual(result.status_code, 'AP00');
+    assert.strictEqual(result.amount, 1000.5);
+    assert.strictEqual(result.accounts[0].balance, 3999.5);
+    assert.strictEqual(result.accounts[1].balance, 1000.5);
+  });
+
+  it('reads "1,000" as one thousand, not 1 followed by junk', async () => {
+    const result = await run('DEBIT 1,000 NGN FROM ACCOUNT a FOR CREDIT TO ACCOUNT b');
+    assert.strictEqual(result.status_code, 'AP00');
+    assert.strictEqual(result.amount, 1000);
+  });
+
+  it('honours decimal_separator in the request', async () => {
+    const result = await run('DEBIT 1.000,50 NGN FROM ACCOUNT a FOR CREDIT TO ACCOUNT b', {
+      decimal_separator: ',',
+    });
+    assert.strictEqual(result.status_code, 'AP00');
+    assert.strictEqual(result.amount, 1000.5);
+  });
+
+  it('fails with AM01 on a malformed grouping', async () => {
+    const result = await run('DEBIT 1,00,0 NGN FROM ACCOUNT a FOR CREDIT TO ACCOUNT b');
+    assert.strictEqual(result.status_code, 'AM01');
+  });
+
+  it('fails with CU03 when decimals exceed the currency minor units', async () => {
+    const ngn = await run('DEBIT 10.005 NGN FROM ACCOUNT a FOR CREDIT TO ACCOUNT b');
+    assert.strictEqual(ngn.status_code, 'CU03');
+    assert.strictEqual(
+      ngn.status_reason,
+      'Amount has more decimal places than the currency allows'
+    );
+    const ugx = await run('DEBIT 10.5 UGX FROM ACCOUNT u FOR CREDIT TO ACCOUNT v');
+    assert.strictEqual(ugx.status_code, 'CU03');
+  });
+});
@@ -0,0 +1,65 @@
+const assert = require('assert');
+const paymentInstructions = require('@app/services/payment-instructions/payment-instructions');
+
+describe('payment-instructions: zero and negative amounts', () => {
+  function makeAccounts() {
+    return [
+      { id: 'acc1', balance: 500, currency: 'NGN' },
+      { id: 'acc2', balance: 100, currency: 'NGN' },
+    ];
+  }
+  function run(amountText) {
+    return paymentInstructions({
+      accounts: makeAccounts(),
+      instruction: `DEBIT ${amountText} NGN FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc2`,
+    });
+  }
+
+  it('rejects a zero amount with AM01', async () => {
+    const result = await run('0');
+    assert.strictEqual(result.status, 'failed');
+    assert.strictEqual(result.status_code, 'AM01');
+    assert.strictEqual(result.status_reason, 'Amount must be greater than zero');
+    assert.strictEqual(result.amount, 0);
+    assert.deepStrictEqual(result.accounts, []);
+  });
+
+  it('treats the word "zero" the same way', async () => {
+    const result = await run('zero');
+    assert.strictEqual(result.status_code, 'AM01');
+    assert.strictEqual(result.status_reason, 'Amount must be greater than zero');
+  });
+
+  it('rejects a negative amount with AM03', async () => {
+    const result = await run('-50');
+    assert.strictEqual(result.status_code, 'AM03');
+    assert.strictEqual(result.status_reason, 'Amount cannot be negative');
+    assert.strictEqual(result.amount, -50);
+    assert.strictEqual(result.currency, 'NGN');
+    assert.deepStrictEqual(result.accounts, []);
+  });
+
+  it('executes a very small positive amount', async () => {
+    const result = await run('0.001k');
+    assert.strictEqual(result.status_code, 'AP00');
+    assert.strictEqual(result.amount, 1);
+    assert.deepStrictEqual(result.accounts, [
+      { id: 'acc1', balance: 499, balance_before: 500, currency: 'NGN' },
+      { id: 'acc2', balance: 101, balance_before: 100, currency: 'NGN' },
+    ]);
+  });
+
+  it('applies the same rules to SPLIT', async () => {
+    const accounts = [...makeAccounts(), { id: 'acc3', balance: 0, currency: 'NGN' }];
+    const zero = await paymentInstructions({
+      accounts,
+      instruction: 'SPLIT 0 NGN FROM acc1 BETWEEN acc2 AND acc3',
+    });
+    assert.strictEqual(zero.status_code, 'AM01');
+    const negative = await paymentInstructions({
+      accounts,
+      instruction: 'SPLIT -10 NGN FROM acc1 BETWEEN acc2 AND acc3',
+    });
+    assert.strictEqual(negative.status_code, 'AM03');
+  });
+});
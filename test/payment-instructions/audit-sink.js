@@ -0,0 +1,126 @@
+const assert = require('assert');
+const paymentInstructions = require('@app/services/payment-instructions/payment-instructions');
+const processIdempotentInstruction = require('@app/services/payment-instructions/process-idempotent-instruction');
+const createMemoryIdempotencyStore = require('@app/services/payment-instructions/stores/create-memory-idempotency-store');
+const PaymentMessages = require('@app/messages/payment-instructions');
+
+describe('payment-instructions: audit sink', () => {
+  const NOW = new Date(Date.UTC(2025, 4, 28, 9, 0, 0));
+  function createRecordingSink() {
+    const records = [];
+    return { records, write: (record) => records.push(record) };
+  }
+  const payload = (instruction, extra = {}) => ({
+    accounts: [
+      { id: 'acc1', balance: 500, currency: 'NGN' },
+      { id: 'acc2', balance: 0, currency: 'NGN' },
+    ],
+    instruction,
+    ...extra,
+  });
+
+  it('writes one record per request, failures included', async () => {
+    const auditSink = createRecordingSink();
+    const options = { auditSink, now: NOW };
+    const requests = [
+      payload('transfer 100 NGN from acc1 to acc2 for rent'),
+      payload('transfer 900 NGN from acc1 to acc2'),
+      payload('transfer abc'),
+      payload('transfer 100 NGN from acc1 to acc2', { dry_run: true }),
+      payload('transfer 100 NGN from acc1 to acc2 and transfer 50 NGN from acc1 to acc2'),
+    ];
+    const results = [];
+    for (let i = 0; i < requests.length; i++) {
+      // eslint-disable-next-line no-await-in-loop
+      results.push(await paymentInstructions(requests[i], options));
+    }
+    const rejected = await paymentInstructions({ accounts: [] }, options).catch((err) => err);
+    assert.strictEqual(rejected.isApplicationError, true);
+
+    const { records } = auditSink;
+    assert.strictEqual(records.length, requests.length + 1);
+    assert.deepStrictEqual(
+      records.map((r) => [r.transaction_id, r.status, r.status_code]),
+      [
+        ...results.map((r) => [r.transaction_id, r.status, r.status_code]),
+        [null, 'rejected', null],
+      ]
+    );
+    assert.deepStrictEqual(records[0], {
+      transaction_id: results[0].transaction_id,
+      timestamp: '2025-05-28T09:00:00.000Z',
+      instruction: 'transfer 100 NGN from acc1 to acc2 for rent',
+      idempotency_key: null,
+      parsed: {
+        type: 'TRANSFER',
+        amount: 100,
+        currency: 'NGN',
+        debit_account: 'acc1',
+        credit_account: 'acc2',
+        execute_by: null,
+        narration: 'rent',
+      },
+      status: 'successful',
+      status_code: 'AP00',
+      status_reason: 'Transaction executed successfully',
+      dry_run: false,
+      replayed: false,
+      accounts: [
+        { id: 'acc1', currency: 'NGN', balance_before: 500, balance: 400 },
+        { id: 'acc2', currency: 'NGN', balance_before: 0, balance: 100 },
+      ],
+    });
+    assert.strictEqual(records[1].status_code, 'AC01');
+    assert.deepStrictEqual(records[1].accounts.map((a) => a.balance), [500, 0]);
+    assert.strictEqual(records[2].status_code, 'SY03');
+    assert.strictEqual(records[2].parsed.type, null);
+    assert.strictEqual(records[3].dry_run, true);
+    assert.deepStrictEqual(records[4].accounts.map((a) => a.balance), [350, 150]);
+    assert.strictEqual(records[5].status_reason, rejected.message);
+    assert.strictEqual(records[5].error_code, rejected.errorCode);
+    assert.strictEqual(records[5].instruction, null);
+  });
+
+  it('hands sinks frozen records and leaves parse-only runs out', async () => {
+    const auditSink = createRecordingSink();
+    await paymentInstructions(payload('transfer 100 NGN from acc1 to acc2'), { auditSink });
+    await paymentInstructions(payload('transfer 100 NGN from acc1 to acc2'), {
+      auditSink,
+      parseOnly: true,
+    });
+    assert.strictEqual(auditSink.records.length, 1);
+    const [record] = auditSink.records;
+    assert.ok(Object.isFrozen(record));
+    assert.ok(Object.isFrozen(record.parsed) && Object.isFrozen(record.accounts[0]));
+  });
+
+  it('audits idempotent replays and reused keys under their key', async () => {
+    const auditSink = createRecordingSink();
+    const options = { auditSink, idempotencyStore: createMemoryIdempotencyStore(), now: NOW };
+    const run = (instruction) => {
+      const keyed = payload(instruction, { idempotency_key: 'audit-key-1' });
+      return processIdempotentInstruction(keyed, options);
+    };
+    const first = await run('transfer 100 NGN from acc1 to acc2');
+    await run('transfer 100 NGN from acc1 to acc2');
+    await run('transfer 200 NGN from acc1 to acc2').catch(() => null);
+    assert.deepStrictEqual(
+      auditSink.records.map((r) => [r.idempotency_key, r.transaction_id, r.status, r.replayed]),
+      [
+        ['audit-key-1', first.transaction_id, 'successful', false],
+        ['audit-key-1', first.transaction_id, 'successful', true],
+        ['audit-key-1', null, 'rejected', false],
+      ]
+    );
+    assert.strictEqual(auditSink.records[2].status_reason, PaymentMessages.IDEMPOTENCY_KEY_REUSED);
+  });
+
+  it('fails the request when the sink cannot take the record', async () => {
+    const auditSink = { write: () => Promise.reject(new Error('broker unavailable')) };
+    const err = await paymentInstructions(payload('transfer 100 NGN from acc1 to acc2'), {
+      auditSink,
+    }).catch((e) => e);
+    assert.strictEqual(err.isApplicationError, true);
+    assert.strictEqual(err.message, PaymentMessages.AUDIT_WRITE_FAILED);
+  });
+});
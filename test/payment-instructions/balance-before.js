@@ -0,0 +1,74 @@
+const assert = require('assert');
+const paymentInstructions = require('@app/services/payment-instructions/payment-instructions');
+
+describe('payment-instructions: balance_before snapshot', () => {
+  function makeAccounts() {
+    return [
+      { id: 'acc1', balance: 1000, currency: 'NGN', daily_limit: 5000 },
+      { id: 'acc2', balance: 200, currency: 'NGN', daily_limit: 5000 },
+      { id: 'acc3', balance: 300, currency: 'NGN' },
+    ];
+  }
+  // A daily debit store that, while it is awaited, credits the caller's account objects the
+  // way a concurrent request writing to the same objects would
+  function interferingStore(accounts) {
+    return {
+      async getTotal() {
+        accounts.forEach((a) => {
+          a.balance += 10000;
+        });
+        return 0;
+      },
+      async add() {},
+    };
+  }
+  async function run(instruction) {
+    const accounts = makeAccounts();
+    const result = await paymentInstructions(
+      { accounts, instruction },
+      { dailyDebitStore: interferingStore(accounts) }
+    );
+    assert.strictEqual(result.status_code, 'AP00', instruction);
+    return result.accounts.map((a) => [a.id, a.balance_before, a.balance]);
+  }
+
+  it('reports the credit account as it was before a DEBIT or CREDIT', async () => {
+    assert.deepStrictEqual(await run('DEBIT 100 NGN FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc2'), [
+      ['acc1', 1000, 900],
+      ['acc2', 200, 300],
+    ]);
+    assert.deepStrictEqual(await run('CREDIT 100 NGN TO ACCOUNT acc3 FOR DEBIT FROM ACCOUNT acc2'), [
+      ['acc2', 200, 100],
+      ['acc3', 300, 400],
+    ]);
+  });
+
+  it('reports every credited account as it was before a SPLIT', async () => {
+    assert.deepStrictEqual(await run('SPLIT 100 NGN FROM acc1 EQUALLY BETWEEN acc2 AND acc3'), [
+      ['acc1', 1000, 900],
+      ['acc2', 200, 250],
+      ['acc3', 300, 350],
+    ]);
+  });
+
+  it('reports the credit account as it was before a multi-debit TRANSFER', async () => {
+    assert.deepStrictEqual(await run('TRANSFER 1100 NGN TO acc3 FROM acc1 AND acc2'), [
+      ['acc1', 1000, 0],
+      ['acc2', 200, 100],
+      ['acc3', 300, 1400],
+    ]);
+  });
+
+  it('reports the account as it was before a WITHDRAW', async () => {
+    assert.deepStrictEqual(await run('WITHDRAW 100 NGN FROM acc1'), [['acc1', 1000, 900]]);
+  });
+
+  it('leaves the caller account objects to the caller', async () => {
+    const accounts = makeAccounts();
+    await paymentInstructions({
+      accounts,
+      instruction: 'DEBIT 100 NGN FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc2',
+    });
+    assert.deepStrictEqual(accounts, makeAccounts());
+  });
+});
@@ -0,0 +1,63 @@
+const assert = require('assert');
+const paymentInstructions = require('@app/services/payment-instructions/payment-instructions');
+const { parseBalanceCondition } = require('@app/services/payment-instructions/helpers');
+
+describe('payment-instructions: balance guards', () => {
+  function makeAccounts(balance = 1200) {
+    return [
+      { id: 'acc1', balance, currency: 'NGN' },
+      { id: 'acc2', balance: 0, currency: 'NGN' },
+    ];
+  }
+  function run(instruction, balance) {
+    return paymentInstructions({ accounts: makeAccounts(balance), instruction });
+  }
+
+  it('executes a transfer whose guard is met', async () => {
+    const result = await run('transfer 500 from acc1 to acc2 only if balance above 1000');
+    assert.strictEqual(result.status_code, 'AP00');
+    assert.deepStrictEqual(result.condition, {
+      comparison: 'above',
+      threshold: 1000,
+      currency: null,
+    });
+    assert.deepStrictEqual(result.accounts.map((a) => a.balance), [700, 500]);
+    const atLeast = await run(
+      'transfer 500 NGN from acc1 to acc2, only if the balance is at least 1,200 NGN for rent'
+    );
+    assert.strictEqual(atLeast.status_code, 'AP00');
+    assert.strictEqual(atLeast.narration, 'rent');
+  });
+
+  it('fails an unmet guard with CD01 and moves nothing', async () => {
+    const result = await run('transfer 500 from acc1 to acc2 only if balance above 1000', 800);
+    assert.strictEqual(result.status, 'failed');
+    assert.strictEqual(result.status_code, 'CD01');
+    assert.strictEqual(
+      result.status_reason,
+      'Condition not met: acc1 balance is 800 NGN, not above 1000 NGN'
+    );
+    assert.deepStrictEqual(result.accounts, [
+      { id: 'acc1', balance: 800, balance_before: 800, currency: 'NGN' },
+      { id: 'acc2', balance: 0, balance_before: 0, currency: 'NGN' },
+    ]);
+    const below = await run(
+      'DEBIT 500 NGN FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc2 IF BALANCE UNDER 1k'
+    );
+    assert.strictEqual(
+      below.status_reason,
+      'Condition not met: acc1 balance is 1200 NGN, not below 1000 NGN'
+    );
+  });
+
+  it('rejects a guard that does not read and one in another currency', async () => {
+    const malformed = await run('transfer 500 NGN from acc1 to acc2 only if balance nearly 1000');
+    assert.strictEqual(malformed.status_code, 'SY03');
+    const foreign = await run('transfer 500 NGN from acc1 to acc2 only if balance above 10 USD');
+    assert.strictEqual(foreign.status_code, 'CU01');
+    assert.strictEqual(
+      parseBalanceCondition('send 5 NGN to acc2 for gifts only if balance above 5'.split(' ')),
+      null
+    );
+  });
+});
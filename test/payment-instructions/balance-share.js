@@ -0,0 +1,158 @@
+const assert = require('assert');
+const { parseAmount, resolveRatioAmount } = require('@app/services/payment-instructions/helpers');
+const paymentInstructions = require('@app/services/payment-instructions/payment-instructions');
+
+describe('payment-instructions: percentage and fraction amounts', () => {
+  it('parses percentages and fractions into ratios', () => {
+    assert.deepStrictEqual(parseAmount(['DEBIT', '10%', 'NGN'], 1).ratio, {
+      numerator: 10,
+      denominator: 100,
+    });
+    assert.deepStrictEqual(parseAmount(['DEBIT', '12.5', 'percent', 'NGN'], 1).ratio, {
+      numerator: 125,
+      denominator: 1000,
+    });
+    assert.strictEqual(parseAmount(['DEBIT', 'a', 'quarter', 'NGN'], 1).consumed, 2);
+    assert.deepStrictEqual(parseAmount(['DEBIT', 'half', 'NGN'], 1).ratio, {
+      numerator: 1,
+      denominator: 2,
+    });
+  });
+
+  it('rounds a share with fractional minor units to the currency decimals', () => {
+    // 10% of 1000.05 = 100.005 -> 100.00 (half-even), 100.01 half-up
+    assert.strictEqual(
+      resolveRatioAmount(1000.05, { numerator: 10, denominator: 100 }, 'NGN'),
+      100
+    );
+    assert.strictEqual(
+      resolveRatioAmount(1000.05, { numerator: 10, denominator: 100 }, 'NGN', 'half_up'),
+      100.01
+    );
+    // a third of 500 = 166.666... -> 166.67
+    assert.strictEqual(resolveRatioAmount(500, { numerator: 1, denominator: 3 }, 'USD'), 166.67);
+  });
+
+  it('resolves the share against the debit account balance', async () => {
+    const result = await paymentInstructions({
+      accounts: [
+        { id: 'acc1', balance: 800, currency: 'USD' },
+        { id: 'acc2', balance: 0, currency: 'USD' },
+      ],
+      instruction: 'DEBIT a quarter USD FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc2',
+    });
+    assert.strictEqual(result.status_code, 'AP00');
+    assert.strictEqual(result.amount, 200);
+    assert.strictEqual(result.accounts[0].balance, 600);
+  });
+
+  it('treats 100% as the full balance', async () => {
+    const result = await paymentInstructions({
+      accounts: [
+        { id: 'acc1', balance: 750, currency: 'NGN' },
+        { id: 'acc2', balance: 10, currency: 'NGN' },
+      ],
+      instruction: 'CREDIT 100% NGN TO ACCOUNT acc2 FOR DEBIT FROM ACCOUNT acc1',
+    });
+    assert.strictEqual(result.status_code, 'AP00');
+    assert.strictEqual(result.amount, 750);
+    assert.strictEqual(result.accounts[0].balance, 0);
+  });
+
+  it('returns SY03 when the debit account cannot be resolved', async () => {
+    const result = await paymentInstructions({
+      accounts: [{ id: 'acc2', balance: 10, currency: 'NGN' }],
+      instruction: 'DEBIT 10% NGN FROM ACCOUNT missing FOR CREDIT TO ACCOUNT acc2',
+    });
+    assert.strictEqual(result.status_code, 'SY03');
+    assert.strictEqual(result.amount, null);
+  });
+
+  it('recognises full-sweep keywords', () => {
+    const phrases = [
+      ['all'],
+      ['everything'],
+      ['entire', 'balance'],
+      ['full', 'balance'],
+      ['all', 'of', 'my', 'balance'],
+      ['my', 'whole', 'balance'],
+    ];
+    phrases.forEach((words) => {
+      const parsed = parseAmount(['DEBIT', ...words, 'NGN'], 1);
+      assert.strictEqual(parsed.sweep, true, words.join(' '));
+      assert.strictEqual(parsed.consumed, words.length, words.join(' '));
+    });
+    assert.strictEqual(parseAmount(['DEBIT', '100%', 'NGN'], 1).sweep, true);
+    assert.strictEqual(parseAmount(['DEBIT', '50%', 'NGN'], 1).sweep, false);
+  });
+
+  it('sweeps the debit account to exactly zero', async () => {
+    const result = await paymentInstructions({
+      accounts: [
+        { id: 'savings', balance: 1000.05, currency: 'NGN' },
+        { id: 'current', balance: 20, currency: 'NGN' },
+      ],
+      instruction: 'DEBIT all of my balance NGN FROM ACCOUNT savings FOR CREDIT TO ACCOUNT current',
+    });
+    assert.strictEqual(result.status_code, 'AP00');
+    assert.strictEqual(result.amount, 1000.05);
+    assert.strictEqual(result.accounts[0].balance, 0);
+  });
+
+  it('sweeps the balance less the fee so the account still ends at zero', async () => {
+    const accounts = [
+      { id: 'acc1', balance: 10000, currency: 'NGN' },
+      { id: 'acc2', balance: 0, currency: 'NGN' },
+    ];
+    const feePolicy = { TRANSFER: { percent: 1.25 } };
+    const result = await paymentInstructions(
+      { accounts, instruction: 'transfer all NGN from acc1 to acc2' },
+      { feePolicy }
+    );
+    assert.strictEqual(result.status_code, 'AP00');
+    assert.strictEqual(result.amount + result.fee, 10000);
+    assert.strictEqual(result.accounts[0].balance, 0);
+    assert.strictEqual(result.accounts[1].balance, result.amount);
+    const withFeeAccount = await paymentInstructions(
+      {
+        accounts: [...accounts, { id: 'acc3', balance: 500, currency: 'NGN' }],
+        instruction: 'transfer all NGN from acc1 to acc2, fee from acc3',
+      },
+      { feePolicy }
+    );
+    assert.strictEqual(withFeeAccount.amount, 10000);
+    assert.strictEqual(withFeeAccount.accounts[0].balance, 0);
+  });
+
+  it('reads "<share> of <account>" as a share of that debit account', async () => {
+    const accounts = [
+      { id: 'acc1', balance: 1000, currency: 'NGN' },
+      { id: 'acc2', balance: 0, currency: 'NGN' },
+    ];
+    const aliases = { savings: 'acc1', current: 'acc2' };
+    const results = await Promise.all(
+      ['move half of savings to current', 'transfer 10% of acc1 to acc2'].map((instruction) =>
+        paymentInstructions({ accounts, aliases, instruction })
+      )
+    );
+    assert.deepStrictEqual(
+      results.map((r) => [r.status_code, r.amount, r.currency, r.debit_account]),
+      [
+        ['AP00', 500, 'NGN', 'acc1'],
+        ['AP00', 100, 'NGN', 'acc1'],
+      ]
+    );
+    assert.strictEqual(parseAmount(['half', 'of', 'my', 'balance', 'NGN'], 0).consumed, 4);
+  });
+
+  it('fails a sweep of an empty account with AM01', async () => {
+    const result = await paymentInstructions({
+      accounts: [
+        { id: 'savings', balance: 0, currency: 'NGN' },
+        { id: 'current', balance: 20, currency: 'NGN' },
+      ],
+      instruction: 'DEBIT everything NGN FROM ACCOUNT savings FOR CREDIT TO ACCOUNT current',
+    });
+    assert.strictEqual(result.status_code, 'AM01');
+  });
+});
@@ -0,0 +1,103 @@
+const assert = require('assert');
+const processBatch = require('@app/services/payment-instructions/process-batch');
+
+describe('payment-instructions: batch processing', () => {
+  const accounts = [
+    { id: 'acc1', balance: 1000, currency: 'NGN' },
+    { id: 'acc2', balance: 0, currency: 'NGN' },
+    { id: 'acc3', balance: 50, currency: 'NGN' },
+  ];
+
+  it('applies shared-account instructions sequentially', async () => {
+    const result = await processBatch({
+      accounts,
+      instructions: [
+        'DEBIT 700 NGN FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc2',
+        'DEBIT 500 NGN FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc3',
+        'DEBIT 600 NGN FROM ACCOUNT acc2 FOR CREDIT TO ACCOUNT acc3',
+      ],
+    });
+    const codes = result.results.map((r) => r.status_code);
+    // The second debit only sees the 300 left by the first one
+    assert.deepStrictEqual(codes, ['AP00', 'AC01', 'AP00']);
+    assert.strictEqual(result.results[2].accounts[0].balance_before, 700);
+    assert.deepStrictEqual(result.accounts.map((a) => a.balance), [300, 100, 650]);
+    // Caller's accounts are untouched
+    assert.strictEqual(accounts[0].balance, 1000);
+  });
+
+  it('continues past failed and rejected items', async () => {
+    const result = await processBatch({
+      accounts,
+      instructions: [
+        'PAY 10 NGN TO acc2',
+        '   ',
+        'CREDIT 100 NGN TO ACCOUNT acc2 FOR DEBIT FROM ACCOUNT acc1',
+      ],
+    });
+    assert.deepStrictEqual(result.results.map((r) => r.status_code), ['SY01', 'SY05', 'AP00']);
+    assert.strictEqual(result.results[1].status, 'failed');
+  });
+
+  it('runs independent items on their own accounts', async () => {
+    const result = await processBatch({
+      items: [
+        { accounts, instruction: 'DEBIT 1000 NGN FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc2' },
+        { accounts, instruction: 'DEBIT 1000 NGN FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc3' },
+      ],
+    });
+    assert.deepStrictEqual(result.results.map((r) => r.status_code), ['AP00', 'AP00']);
+    assert.strictEqual(result.accounts, undefined);
+  });
+
+  it('rejects a batch without items or instructions', async () => {
+    await assert.rejects(() => processBatch({ accounts }), /Batch must contain/);
+    await assert.rejects(() => processBatch({ instructions: ['DEBIT 1 NGN'] }), /Batch/);
+  });
+
+  it('executes a duplicated instruction once with reject_duplicates', async () => {
+    const repeated = 'DEBIT 100 NGN FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc2';
+    const instructions = [
+      repeated,
+      'DEBIT 20 NGN FROM ACCOUNT acc3 FOR CREDIT TO ACCOUNT acc2',
+      '  DEBIT  100 NGN FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc2 ',
+    ];
+    const result = await processBatch({ accounts, instructions, reject_duplicates: true });
+    assert.deepStrictEqual(result.results.map((r) => r.status_code), ['AP00', 'AP00', 'DUP01']);
+    const duplicate = result.results[2];
+    assert.strictEqual(duplicate.status, 'failed');
+    assert.strictEqual(duplicate.duplicate_of, 0);
+    assert.strictEqual(duplicate.status_reason, 'Instruction repeats an earlier one in the batch');
+    assert.deepStrictEqual(duplicate.accounts, []);
+    assert.deepStrictEqual(result.accounts.map((a) => a.balance), [900, 120, 30]);
+  });
+
+  it('runs repeats when reject_duplicates is off', async () => {
+    const repeated = 'DEBIT 100 NGN FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc2';
+    const result = await processBatch({ accounts, instructions: [repeated, repeated] });
+    assert.deepStrictEqual(result.results.map((r) => r.status_code), ['AP00', 'AP00']);
+    assert.strictEqual(result.accounts[0].balance, 800);
+  });
+
+  it('returns a balance trail per account only when asked', async () => {
+    const instructions = [
+      'DEBIT 100 NGN FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc2',
+      'DEBIT 5000 NGN FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc2',
+      'DEBIT 200 NGN FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc3',
+      'DEBIT 300 NGN FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc2',
+    ];
+    const result = await processBatch({ accounts, instructions, balance_trail: true });
+    const codes = result.results.map((r) => r.status_code);
+    assert.deepStrictEqual(codes, ['AP00', 'AC01', 'AP00', 'AP00']);
+    // The failed second debit is no step
+    assert.deepStrictEqual(result.balance_trails.acc1, [
+      { index: 0, balance_before: 1000, balance: 900 },
+      { index: 2, balance_before: 900, balance: 700 },
+      { index: 3, balance_before: 700, balance: 400 },
+    ]);
+    assert.deepStrictEqual(result.balance_trails.acc2.map((step) => step.balance), [100, 400]);
+    assert.deepStrictEqual(Object.keys(result.balance_trails), ['acc1', 'acc2', 'acc3']);
+    const plain = await processBatch({ accounts, instructions });
+    assert.strictEqual('balance_trails' in plain, false);
+  });
+});
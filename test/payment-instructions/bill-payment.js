@@ -0,0 +1,79 @@
+const assert = require('assert');
+const paymentInstructions = require('@app/services/payment-instructions/payment-instructions');
+const { parseBillPayment } = require('@app/services/payment-instructions/helpers');
+
+describe('payment-instructions: bill payments', () => {
+  function makeAccounts() {
+    return [
+      { id: 'acc1', balance: 10000, currency: 'NGN', minimum_balance: 1000 },
+      { id: 'acc2', balance: 0, currency: 'NGN' },
+    ];
+  }
+  function run(instruction, extra = {}) {
+    return paymentInstructions({ accounts: makeAccounts(), instruction, ...extra });
+  }
+
+  it('pays a biller from the debit account with a null credit account', async () => {
+    const result = await run('pay 5000 NGN to DSTV from acc1 for March');
+    assert.strictEqual(result.status_code, 'AP00');
+    assert.strictEqual(result.type, 'PAY');
+    assert.strictEqual(result.biller, 'DSTV');
+    assert.strictEqual(result.debit_account, 'acc1');
+    assert.strictEqual(result.credit_account, null);
+    assert.strictEqual(result.narration, 'March');
+    assert.deepStrictEqual(result.accounts, [
+      { id: 'acc1', balance: 5000, balance_before: 10000, currency: 'NGN' },
+    ]);
+  });
+
+  it('reads the biller before the amount or after TO, in several words', async () => {
+    const leading = await run('pay electricity 3000 from acc1');
+    assert.strictEqual(leading.biller, 'electricity');
+    assert.strictEqual(leading.amount, 3000);
+    const words = await run('pay ₦3000 from account acc1 to Ikeja Electric');
+    assert.strictEqual(words.biller, 'Ikeja Electric');
+    assert.strictEqual(words.currency, 'NGN');
+    assert.deepStrictEqual(parseBillPayment(['pay', '5000', 'to', 'DSTV'], '.', 'acc1'), {
+      biller: 'DSTV',
+      tokens: ['pay', '5000', 'FROM', 'acc1'],
+    });
+  });
+
+  it('pays from the only account when FROM is left out', async () => {
+    const result = await paymentInstructions({
+      accounts: [{ id: 'acc1', balance: 8000, currency: 'NGN' }],
+      instruction: 'pay 5000 to DSTV',
+    });
+    assert.strictEqual(result.status_code, 'AP00');
+    assert.strictEqual(result.debit_account, 'acc1');
+    assert.strictEqual(result.accounts[0].balance, 3000);
+  });
+
+  it('holds the debit account to its balance rules', async () => {
+    const floor = await run('pay 9500 NGN to DSTV from acc1');
+    assert.strictEqual(floor.status_code, 'BL02');
+    assert.strictEqual(floor.biller, 'DSTV');
+    assert.strictEqual(floor.accounts[0].balance, 10000);
+    const broke = await run('pay 100 NGN to DSTV from acc2');
+    assert.strictEqual(broke.status_code, 'AC01');
+    const missing = await run('pay 100 NGN to DSTV from nobody');
+    assert.strictEqual(missing.status_code, 'AC03');
+  });
+
+  it('falls back to a transfer when the payee is one of the accounts', async () => {
+    const byId = await run('pay 500 NGN to acc2 from acc1');
+    assert.strictEqual(byId.type, 'CREDIT');
+    assert.strictEqual(byId.credit_account, 'acc2');
+    assert.strictEqual(byId.biller, undefined);
+    assert.deepStrictEqual(byId.accounts.map((a) => a.balance), [9500, 500]);
+    const byAlias = await run('pay landlord 500 NGN from acc1', { aliases: { landlord: 'acc2' } });
+    assert.strictEqual(byAlias.type, 'CREDIT');
+    assert.strictEqual(byAlias.credit_account, 'acc2');
+  });
+
+  it('leaves what names no biller to the other forms', () => {
+    assert.strictEqual(parseBillPayment(['pay', '500', 'from', 'acc1']), null);
+    assert.strictEqual(parseBillPayment(['pay', 'DSTV']), null);
+    assert.strictEqual(parseBillPayment(['pay', 'DSTV', '500', 'to', 'GOtv']), null);
+  });
+});
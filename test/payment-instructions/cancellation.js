@@ -0,0 +1,69 @@
+const assert = require('assert');
+const paymentInstructions = require('@app/services/payment-instructions/payment-instructions');
+const processBatch = require('@app/services/payment-instructions/process-batch');
+const processStoredInstruction = require('@app/services/payment-instructions/process-stored-instruction');
+const createMemoryTransactionStore = require('@app/services/payment-instructions/stores/create-memory-transaction-store');
+
+describe('payment-instructions: cancellation', () => {
+  const accounts = () => [
+    { id: 'acc1', balance: 1000, currency: 'NGN' },
+    { id: 'acc2', balance: 0, currency: 'NGN' },
+  ];
+  const debit = (amount) => `DEBIT ${amount} NGN FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc2`;
+
+  it('returns the items already run and marks the rest cancelled', async () => {
+    // Aborts as soon as the second instruction has executed
+    const controller = new AbortController();
+    const transactionStore = createMemoryTransactionStore();
+    const recording = {
+      get: transactionStore.get,
+      set: async (id, record) => {
+        await transactionStore.set(id, record);
+        if (transactionStore.size() === 2) controller.abort();
+      },
+    };
+    const result = await processBatch(
+      { accounts: accounts(), instructions: [debit(100), debit(200), debit(300), debit(400)] },
+      { signal: controller.signal, transactionStore: recording }
+    );
+    assert.deepStrictEqual(
+      result.results.map((r) => r.status),
+      ['successful', 'successful', 'cancelled', 'cancelled']
+    );
+    assert.strictEqual(result.results[2].status_code, 'CANCELLED');
+    assert.strictEqual(result.results[3].status_reason, 'Cancelled before the instruction ran');
+    assert.deepStrictEqual(result.accounts.map((a) => a.balance), [700, 300]);
+  });
+
+  it('runs nothing once a deadline has passed', async () => {
+    const signal = AbortSignal.abort();
+    const result = await paymentInstructions(
+      { accounts: accounts(), instruction: debit(100) },
+      { signal }
+    );
+    assert.strictEqual(result.status, 'cancelled');
+    assert.strictEqual(typeof result.transaction_id, 'string');
+    assert.deepStrictEqual(result.accounts, []);
+  });
+
+  it('passes the signal to the account store', async () => {
+    const controller = new AbortController();
+    const seen = [];
+    const accountStore = {
+      getAccount: async (id, storeOptions) => {
+        seen.push(storeOptions.signal);
+        controller.abort();
+        return accounts().find((a) => a.id === id) || null;
+      },
+      updateBalance: async () => {
+        throw new Error('nothing should be written');
+      },
+    };
+    const result = await processStoredInstruction(
+      { instruction: debit(100) },
+      { accountStore, signal: controller.signal }
+    );
+    assert.strictEqual(result.status_code, 'CANCELLED');
+    assert.deepStrictEqual(seen, [controller.signal]);
+  });
+});
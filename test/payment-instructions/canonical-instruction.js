@@ -0,0 +1,80 @@
+const assert = require('assert');
+const paymentInstructions = require('@app/services/payment-instructions/payment-instructions');
+const parseInstruction = require('@app/services/payment-instructions/parse-instruction');
+const { canonicalInstruction } = require('@app/services/payment-instructions/helpers');
+
+// Wednesday 2025-05-28, morning UTC
+const NOW = new Date(Date.UTC(2025, 4, 28, 9, 0, 0));
+
+describe('payment-instructions: canonical instruction', () => {
+  function makeAccounts() {
+    return [
+      { id: 'acc1', balance: 20000, currency: 'NGN' },
+      { id: 'acc2', balance: 0, currency: 'NGN' },
+    ];
+  }
+  function run(instruction) {
+    return paymentInstructions(
+      { accounts: makeAccounts(), instruction, aliases: { rent: 'acc2' } },
+      { now: NOW }
+    );
+  }
+
+  it('reads equivalent instructions as the same string', async () => {
+    const results = await Promise.all(
+      [
+        'DEBIT 5k NGN FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc2',
+        '  transfer   ₦5,000 from acc1   to rent  ',
+        'CREDIT ACCOUNT acc2 WITH 5000 naira FROM ACCOUNT acc1 for March',
+      ].map(run)
+    );
+    assert.deepStrictEqual(
+      results.map((r) => [r.status_code, r.canonical_instruction]),
+      [
+        ['AP00', 'TRANSFER 5000.00 NGN FROM acc1 TO acc2'],
+        ['AP00', 'TRANSFER 5000.00 NGN FROM acc1 TO acc2'],
+        ['AP00', 'TRANSFER 5000.00 NGN FROM acc1 TO acc2'],
+      ]
+    );
+    const parsed = await parseInstruction('send 5000 NGN from acc1 to acc2', {
+      accounts: makeAccounts(),
+    });
+    assert.strictEqual(parsed.canonical_instruction, 'TRANSFER 5000.00 NGN FROM acc1 TO acc2');
+  });
+
+  it('keeps the date and recurrence apart from an immediate transfer', async () => {
+    const dated = await run('transfer 5000 NGN from acc1 to acc2 on 2025-06-01');
+    assert.strictEqual(
+      dated.canonical_instruction,
+      'TRANSFER 5000.00 NGN FROM acc1 TO acc2 ON 2025-06-01'
+    );
+    const scheduled = await run(
+      'schedule transfer 5000 NGN from acc1 to acc2 on 2025-06-01 at 9am'
+    );
+    assert.strictEqual(
+      scheduled.canonical_instruction,
+      'TRANSFER 5000.00 NGN FROM acc1 TO acc2 ON 2025-06-01T09:00:00Z'
+    );
+    assert.strictEqual(
+      canonicalInstruction({
+        amount: 1500,
+        currency: 'JPY',
+        debit_account: 'a',
+        credit_account: 'b',
+        execute_by: Date.UTC(2025, 5, 2, 9) / 1000,
+        recurrence: { unit: 'week', count: 2, weekday: 'monday', anchor: null },
+      }),
+      'TRANSFER 1500 JPY FROM a TO b ON 2025-06-02T09:00:00Z EVERY 2 WEEK ON MONDAY'
+    );
+  });
+
+  it('is left out until both accounts are known', async () => {
+    const result = await run('transfer 5000 NGN from acc1 to acc9');
+    assert.strictEqual(result.status_code, 'AC03');
+    assert.strictEqual(result.canonical_instruction, undefined);
+    assert.strictEqual(
+      canonicalInstruction({ amount: 10, currency: 'NGN', debit_account: 'acc1' }),
+      null
+    );
+  });
+});
@@ -0,0 +1,60 @@
+const assert = require('assert');
+const paymentInstructions = require('@app/services/payment-instructions/payment-instructions');
+
+describe('payment-instructions: case-insensitive account ids', () => {
+  function makeAccounts() {
+    return [
+      { id: 'acc1', balance: 500, currency: 'NGN' },
+      { id: 'Acc2', balance: 100, currency: 'NGN' },
+    ];
+  }
+  const instruction = 'DEBIT 100 NGN FROM ACCOUNT ACC1 FOR CREDIT TO ACCOUNT acc2';
+
+  it('stays case-sensitive by default', async () => {
+    const result = await paymentInstructions({ accounts: makeAccounts(), instruction });
+    assert.strictEqual(result.status_code, 'AC03');
+    assert.strictEqual(result.debit_account, 'ACC1');
+  });
+
+  it('matches ids ignoring case when enabled and reports the canonical ids', async () => {
+    const result = await paymentInstructions({
+      accounts: makeAccounts(),
+      instruction,
+      case_insensitive_ids: true,
+    });
+    assert.strictEqual(result.status_code, 'AP00');
+    assert.strictEqual(result.debit_account, 'acc1');
+    assert.strictEqual(result.credit_account, 'Acc2');
+    assert.deepStrictEqual(result.accounts, [
+      { id: 'acc1', balance: 400, balance_before: 500, currency: 'NGN' },
+      { id: 'Acc2', balance: 200, balance_before: 100, currency: 'NGN' },
+    ]);
+  });
+
+  it('accepts the flag through options', async () => {
+    const result = await paymentInstructions(
+      { accounts: makeAccounts(), instruction },
+      { caseInsensitiveIds: true }
+    );
+    assert.strictEqual(result.status_code, 'AP00');
+  });
+
+  it('fails as duplicate ids when accounts differ only by case', async () => {
+    const accounts = [...makeAccounts(), { id: 'ACC1', balance: 0, currency: 'NGN' }];
+    const result = await paymentInstructions({ accounts, instruction, case_insensitive_ids: true });
+    assert.strictEqual(result.status_code, 'AC07');
+    assert.strictEqual(result.status_reason, 'More than one account has this id: acc1, ACC1');
+    assert.deepStrictEqual(result.accounts, []);
+  });
+
+  it('applies to SPLIT recipients', async () => {
+    const accounts = [...makeAccounts(), { id: 'acc3', balance: 0, currency: 'NGN' }];
+    const result = await paymentInstructions({
+      accounts,
+      instruction: 'SPLIT 100 NGN FROM ACC1 BETWEEN ACC2 AND ACC3',
+      case_insensitive_ids: true,
+    });
+    assert.strictEqual(result.status_code, 'AP00');
+    assert.deepStrictEqual(result.splits.map((s) => s.account), ['Acc2', 'acc3']);
+  });
+});
@@ -0,0 +1,107 @@
+const assert = require('assert');
+const paymentInstructions = require('@app/services/payment-instructions/payment-instructions');
+const processReversal = require('@app/services/payment-instructions/process-reversal');
+const createMemoryTransactionStore = require('@app/services/payment-instructions/stores/create-memory-transaction-store');
+
+describe('payment-instructions: WITHDRAW and DEPOSIT', () => {
+  function makeAccounts(extra = {}) {
+    return [
+      { id: 'acc1', balance: 6000, currency: 'NGN', ...extra },
+      { id: 'acc2', balance: 0, currency: 'NGN' },
+    ];
+  }
+  function run(instruction, accounts = makeAccounts(), options = {}) {
+    return paymentInstructions({ accounts, instruction }, options);
+  }
+
+  it('withdraws from one account with no credit account', async () => {
+    const result = await run('withdraw 5000 from acc1');
+    assert.strictEqual(result.type, 'WITHDRAW');
+    assert.strictEqual(result.status_code, 'AP00');
+    assert.strictEqual(result.amount, 5000);
+    assert.strictEqual(result.currency, 'NGN');
+    assert.strictEqual(result.debit_account, 'acc1');
+    assert.strictEqual(result.credit_account, null);
+    assert.deepStrictEqual(result.accounts, [
+      { id: 'acc1', balance: 1000, balance_before: 6000, currency: 'NGN' },
+    ]);
+  });
+
+  it('deposits into one account with no debit account', async () => {
+    const result = await run('DEPOSIT 2000 NGN TO ACCOUNT acc1 for cash lodgement');
+    assert.strictEqual(result.type, 'DEPOSIT');
+    assert.strictEqual(result.status_code, 'AP00');
+    assert.strictEqual(result.debit_account, null);
+    assert.strictEqual(result.credit_account, 'acc1');
+    assert.strictEqual(result.narration, 'cash lodgement');
+    assert.deepStrictEqual(result.accounts, [
+      { id: 'acc1', balance: 8000, balance_before: 6000, currency: 'NGN' },
+    ]);
+    const into = await run('deposit 2000 into acc2');
+    assert.strictEqual(into.accounts[0].balance, 2000);
+  });
+
+  it('fails a withdrawal that overdraws with AC01 and leaves the balance', async () => {
+    const result = await run('WITHDRAW 7000 NGN FROM acc1');
+    assert.strictEqual(result.status, 'failed');
+    assert.strictEqual(result.status_code, 'AC01');
+    assert.strictEqual(
+      result.status_reason,
+      'Insufficient funds in debit account: has 6000 NGN, needs 7000 NGN'
+    );
+    assert.deepStrictEqual(result.accounts, [
+      { id: 'acc1', balance: 6000, balance_before: 6000, currency: 'NGN' },
+    ]);
+  });
+
+  it('lets a withdrawal overdraw within the overdraft limit', async () => {
+    const accounts = makeAccounts({ overdraft_limit: 1000 });
+    const result = await run('WITHDRAW 7000 NGN FROM acc1', accounts);
+    assert.strictEqual(result.status_code, 'AP00');
+    assert.strictEqual(
+      result.status_reason,
+      'Transaction executed successfully; debit account overdrawn by 1000 NGN'
+    );
+    assert.strictEqual(result.accounts[0].balance, -1000);
+  });
+
+  it('never declines a deposit for funds', async () => {
+    const accounts = makeAccounts({ balance: -200, overdraft_limit: 200 });
+    const result = await run('deposit 500 into acc1', accounts);
+    assert.strictEqual(result.status_code, 'AP00');
+    assert.strictEqual(result.accounts[0].balance, 300);
+  });
+
+  it('checks the currency and the account', async () => {
+    const mismatch = await run('withdraw 100 USD from acc1');
+    assert.strictEqual(mismatch.status_code, 'CU01');
+    const missing = await run('deposit 100 NGN to acc9');
+    assert.strictEqual(missing.status_code, 'AC03');
+    const noAccount = await run('withdraw 100 NGN');
+    assert.strictEqual(noAccount.status_code, 'SY01');
+    const wrongWay = await run('withdraw 100 NGN to acc1');
+    assert.strictEqual(wrongWay.status_code, 'SY02');
+  });
+
+  it('records withdrawals for reversal and previews dry runs', async () => {
+    const transactionStore = createMemoryTransactionStore();
+    const preview = await paymentInstructions(
+      { accounts: makeAccounts(), instruction: 'withdraw 5000 from acc1', dry_run: true },
+      { transactionStore }
+    );
+    assert.strictEqual(preview.accounts[0].projected_balance, 1000);
+    assert.strictEqual(transactionStore.size(), 0);
+
+    const withdrawn = await run('withdraw 5000 from acc1', makeAccounts(), { transactionStore });
+    const reversal = await processReversal(
+      {
+        accounts: [{ id: 'acc1', balance: 1000, currency: 'NGN' }],
+        transaction_id: withdrawn.transaction_id,
+      },
+      { transactionStore }
+    );
+    assert.strictEqual(reversal.status_code, 'AP00');
+    assert.strictEqual(reversal.credit_account, 'acc1');
+    assert.strictEqual(reversal.accounts[0].balance, 6000);
+  });
+});
@@ -0,0 +1,93 @@
+const assert = require('assert');
+const paymentInstructions = require('@app/services/payment-instructions/payment-instructions');
+const processIdempotentInstruction = require('@app/services/payment-instructions/process-idempotent-instruction');
+const createManualClock = require('@app/services/payment-instructions/clocks/create-manual-clock');
+const createMemoryDailyDebitStore = require('@app/services/payment-instructions/stores/create-memory-daily-debit-store');
+const createMemoryIdempotencyStore = require('@app/services/payment-instructions/stores/create-memory-idempotency-store');
+
+describe('payment-instructions: injected clock', () => {
+  const HOUR = 60 * 60 * 1000;
+
+  function makeAccounts() {
+    return [
+      { id: 'acc1', balance: 10000, currency: 'NGN', daily_limit: 1000 },
+      { id: 'acc2', balance: 0, currency: 'NGN' },
+    ];
+  }
+
+  it('schedules against the clock as it advances', async () => {
+    const clock = createManualClock(Date.UTC(2025, 2, 30, 12));
+    const instruction =
+      'SCHEDULE DEBIT 100 NGN FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc2 ON 2025-04-01';
+    const run = () =>
+      paymentInstructions(
+        { accounts: makeAccounts(), instruction },
+        { clock, dailyDebitStore: createMemoryDailyDebitStore() }
+      );
+
+    assert.strictEqual((await run()).status_code, 'AP02');
+    clock.advance(36 * HOUR); // 2025-04-01 00:00 UTC: due today
+    assert.strictEqual((await run()).status_code, 'AP00');
+    clock.advance(24 * HOUR);
+    assert.strictEqual((await run()).status_code, 'DT02');
+
+    const tomorrow = await paymentInstructions(
+      {
+        accounts: makeAccounts(),
+        instruction: 'SCHEDULE DEBIT 100 NGN FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc2 TOMORROW',
+      },
+      { clock }
+    );
+    assert.strictEqual(tomorrow.execute_by, Date.UTC(2025, 3, 3) / 1000);
+  });
+
+  it('counts the daily limit per day of the clock', async () => {
+    const clock = createManualClock(Date.UTC(2025, 2, 12, 23));
+    const options = { clock, dailyDebitStore: createMemoryDailyDebitStore() };
+    const instruction = 'DEBIT 600 NGN FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc2';
+    const run = () => paymentInstructions({ accounts: makeAccounts(), instruction }, options);
+
+    assert.strictEqual((await run()).status_code, 'AP00');
+    clock.advance(HOUR / 2);
+    assert.strictEqual((await run()).status_code, 'LM01');
+    clock.advance(HOUR); // past midnight: a new day's limit
+    assert.strictEqual((await run()).status_code, 'AP00');
+  });
+
+  it('expires idempotent results when the store clock passes the ttl', async () => {
+    const clock = createManualClock(Date.UTC(2025, 2, 12));
+    const options = {
+      clock,
+      idempotencyStore: createMemoryIdempotencyStore({ clock }),
+      idempotencyTtlMs: HOUR,
+    };
+    const payload = {
+      accounts: makeAccounts(),
+      instruction: 'DEBIT 10 NGN FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc2',
+      idempotency_key: 'clock-1',
+    };
+    const first = await processIdempotentInstruction(payload, options);
+    clock.advance(HOUR - 1);
+    assert.strictEqual(
+      (await processIdempotentInstruction(payload, options)).transaction_id,
+      first.transaction_id
+    );
+    clock.advance(1);
+    assert.notStrictEqual(
+      (await processIdempotentInstruction(payload, options)).transaction_id,
+      first.transaction_id
+    );
+  });
+
+  it('still lets options.now pin the time', async () => {
+    const clock = { now: () => assert.fail('a pinned time must not read the clock') };
+    const result = await paymentInstructions(
+      {
+        accounts: makeAccounts(),
+        instruction: 'DEBIT 10 NGN FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc2 ON 2025-04-01',
+      },
+      { clock, now: Date.UTC(2025, 2, 12), dailyDebitStore: createMemoryDailyDebitStore() }
+    );
+    assert.strictEqual(result.status_code, 'AP02');
+  });
+});
@@ -0,0 +1,73 @@
+const assert = require('assert');
+const paymentInstructions = require('@app/services/payment-instructions/payment-instructions');
+const toPain001Xml = require('@app/services/payment-instructions/exporters/to-pain001-xml');
+
+describe('payment-instructions: COLLECT pull payments', () => {
+  function makeAccounts() {
+    return [
+      { id: 'acc1', balance: 5000, currency: 'NGN' },
+      { id: 'acc2', balance: 0, currency: 'NGN' },
+    ];
+  }
+  function run(instruction, extra = {}, options = {}) {
+    return paymentInstructions({ accounts: makeAccounts(), instruction, ...extra }, options);
+  }
+
+  it('debits the customer and credits the merchant under the COLLECT type', async () => {
+    const collect = await run('collect 2000 NGN from acc1 to acc2');
+    assert.strictEqual(collect.status_code, 'AP00');
+    assert.strictEqual(collect.type, 'COLLECT');
+    assert.strictEqual(collect.matched_verb, 'collect');
+    assert.strictEqual(collect.debit_account, 'acc1');
+    assert.strictEqual(collect.credit_account, 'acc2');
+    assert.deepStrictEqual(collect.accounts.map((a) => a.balance), [3000, 2000]);
+
+    const charge = await run('charge acc1 3000 NGN to acc2');
+    assert.strictEqual(charge.status_code, 'AP00');
+    assert.strictEqual(charge.type, 'COLLECT');
+    assert.strictEqual(charge.matched_verb, 'charge');
+    assert.strictEqual(charge.debit_account, 'acc1');
+    assert.strictEqual(charge.credit_account, 'acc2');
+    assert.deepStrictEqual(charge.accounts.map((a) => a.balance), [2000, 3000]);
+  });
+
+  it('credits the collection account when the instruction names no payee', async () => {
+    const charge = await run('charge acc1 3000', { collection_account: 'acc2' });
+    assert.strictEqual(charge.status_code, 'AP00');
+    assert.strictEqual(charge.type, 'COLLECT');
+    assert.strictEqual(charge.currency, 'NGN');
+    assert.strictEqual(charge.credit_account, 'acc2');
+    const collect = await run(
+      'collect 2000 NGN from acc1 for invoice 17',
+      {},
+      { collectionAccount: 'acc2' }
+    );
+    assert.strictEqual(collect.credit_account, 'acc2');
+    assert.strictEqual(collect.narration, 'invoice 17');
+  });
+
+  it('fails SY01 on the missing payee without a collection account', async () => {
+    const charge = await run('charge acc1 3000 NGN');
+    assert.strictEqual(charge.status, 'failed');
+    assert.strictEqual(charge.type, 'COLLECT');
+    assert.strictEqual(charge.status_code, 'SY01');
+    assert.deepStrictEqual(charge.parse_error, {
+      segment: 'credit',
+      token: null,
+      offset: 20,
+      length: 0,
+    });
+    assert.deepStrictEqual(charge.accounts, []);
+    const collect = await run('collect 2000 NGN from acc1');
+    assert.strictEqual(collect.status_code, 'SY01');
+    assert.strictEqual(collect.parse_error.segment, 'credit');
+  });
+
+  it('prices COLLECT under its own fee rule and exports it as a transfer', async () => {
+    const feePolicy = { TRANSFER: { flat: 10 }, COLLECT: { percent: 1 } };
+    const result = await run('collect 2000 NGN from acc1 to acc2', {}, { feePolicy });
+    assert.strictEqual(result.fee, 20);
+    assert.strictEqual(result.accounts[0].balance, 2980);
+    assert.ok(toPain001Xml(result).indexOf('<InstdAmt Ccy="NGN">2000.00</InstdAmt>') !== -1);
+  });
+});
@@ -0,0 +1,109 @@
+const assert = require('assert');
+const {
+  splitCompoundInstruction,
+  tokenize,
+} = require('@app/services/payment-instructions/helpers');
+const paymentInstructions = require('@app/services/payment-instructions/payment-instructions');
+
+describe('payment-instructions: compound instructions', () => {
+  function makeAccounts() {
+    return [
+      { id: 'acc1', balance: 1000, currency: 'NGN' },
+      { id: 'acc2', balance: 0, currency: 'NGN' },
+      { id: 'acc3', balance: 0, currency: 'NGN' },
+    ];
+  }
+  function run(instruction, extra = {}) {
+    return paymentInstructions({ accounts: makeAccounts(), instruction, ...extra });
+  }
+  function clauses(instruction) {
+    return splitCompoundInstruction(tokenize(instruction)).map((c) => c.join(' '));
+  }
+
+  it('splits only on an "and" between two complete clauses', () => {
+    assert.deepStrictEqual(
+      clauses('DEBIT 100 NGN FROM ACCOUNT a FOR CREDIT TO ACCOUNT b and 200 NGN FROM a TO c'),
+      ['DEBIT 100 NGN FROM ACCOUNT a FOR CREDIT TO ACCOUNT b', 'DEBIT 200 NGN FROM a TO c']
+    );
+    assert.deepStrictEqual(
+      clauses('SCHEDULE TRANSFER 5 NGN FROM a TO b ON 2099-01-01 and CREDIT 7 NGN TO b FROM c'),
+      ['SCHEDULE TRANSFER 5 NGN FROM a TO b ON 2099-01-01', 'CREDIT 7 NGN TO b FROM c']
+    );
+    const single = [
+      'DEBIT one thousand and fifty NGN FROM ACCOUNT a FOR CREDIT TO ACCOUNT b',
+      'SPLIT 900 NGN FROM a BETWEEN b AND c',
+      'TRANSFER 100 NGN TO c FROM a AND b',
+    ];
+    single.forEach((instruction) => assert.strictEqual(clauses(instruction).length, 1));
+  });
+
+  it('runs two clauses in order on the shared accounts', async () => {
+    const result = await run(
+      'DEBIT 600 NGN FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc2 and 300 NGN FROM ACCOUNT acc2 FOR CREDIT TO ACCOUNT acc3'
+    );
+    assert.strictEqual(result.type, 'COMPOUND');
+    assert.strictEqual(result.status_code, 'AP00');
+    assert.strictEqual(result.sub_results.length, 2);
+    assert.strictEqual(result.sub_results[1].instruction.split(' ')[0], 'DEBIT');
+    assert.deepStrictEqual(result.sub_results[1].accounts[0], {
+      id: 'acc2',
+      balance: 300,
+      balance_before: 600,
+      currency: 'NGN',
+    });
+    assert.deepStrictEqual(result.accounts, [
+      { id: 'acc1', balance: 400, balance_before: 1000, currency: 'NGN' },
+      { id: 'acc2', balance: 300, balance_before: 0, currency: 'NGN' },
+      { id: 'acc3', balance: 300, balance_before: 0, currency: 'NGN' },
+    ]);
+  });
+
+  it('keeps going past a clause that fails', async () => {
+    const result = await run(
+      'DEBIT 100 NGN FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc2 and fifty fifty NGN FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc3 and 2000 NGN FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc3'
+    );
+    const codes = result.sub_results.map((r) => r.status_code);
+    assert.deepStrictEqual(codes, ['AP00', 'SY03', 'AC01']);
+    assert.strictEqual(result.status, 'failed');
+    assert.strictEqual(result.status_code, 'SY03');
+    assert.strictEqual(
+      result.status_reason,
+      'Some clauses of the compound instruction failed: 2 of 3'
+    );
+    assert.strictEqual(result.accounts[0].balance, 900);
+    assert.strictEqual(result.accounts[1].balance, 100);
+  });
+
+  it('runs three clauses and chains projected balances on a dry run', async () => {
+    const result = await run(
+      'DEBIT 500 NGN FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc2 and 400 NGN FROM ACCOUNT acc2 FOR CREDIT TO ACCOUNT acc3 and 300 NGN FROM ACCOUNT acc3 FOR CREDIT TO ACCOUNT acc1',
+      { dry_run: true }
+    );
+    assert.strictEqual(result.status_code, 'AP00');
+    assert.strictEqual(result.dry_run, true);
+    const balances = result.accounts.map((a) => [a.balance, a.projected_balance]);
+    assert.deepStrictEqual(balances, [
+      [1000, 800],
+      [0, 100],
+      [0, 100],
+    ]);
+  });
+
+  it('fails the whole instruction on an "and" clause it cannot read', async () => {
+    const unread = [
+      'transfer 100 NGN from acc1 to acc2 and blah blah',
+      'DEBIT 100 NGN FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc2 and 200 NGN to acc3',
+    ];
+    const results = await Promise.all(unread.map((instruction) => run(instruction)));
+    assert.deepStrictEqual(
+      results.map((r) => [r.status_code, r.status_reason, r.accounts]),
+      [
+        ['SY03', 'Could not read the clause after the transfer: and blah blah', []],
+        ['SY03', 'Could not read the clause after the transfer: and 200 NGN to acc3', []],
+      ]
+    );
+    const narrated = await run('transfer 100 NGN from acc1 to acc2 for bread and butter');
+    assert.strictEqual(narrated.status_code, 'AP00');
+    assert.strictEqual(narrated.narration, 'bread and butter');
+  });
+});
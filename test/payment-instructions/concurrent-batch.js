@@ -0,0 +1,91 @@
+const assert = require('assert');
+const processBatch = require('@app/services/payment-instructions/process-batch');
+const createMemoryDailyDebitStore = require('@app/services/payment-instructions/stores/create-memory-daily-debit-store');
+
+describe('payment-instructions: concurrent batch', () => {
+  const NOW = Date.UTC(2025, 2, 12, 15, 30);
+
+  // A daily debit store that answers after a random delay, so concurrent items interleave
+  function createSlowDailyDebitStore() {
+    const store = createMemoryDailyDebitStore();
+    const pause = () => new Promise((resolve) => setTimeout(resolve, Math.random() * 3));
+    return {
+      async getTotal(accountId, day) {
+        await pause();
+        return store.getTotal(accountId, day);
+      },
+      async add(accountId, day, amount) {
+        await pause();
+        return store.add(accountId, day, amount);
+      },
+    };
+  }
+
+  // Some items share a daily-limited account, so their order decides which ones hit LM01
+  function makeItems() {
+    const items = [];
+    for (let i = 0; i < 40; i++) {
+      const debit = `acc${i % 5}`;
+      items.push({
+        accounts: [
+          { id: debit, balance: 1000, currency: 'NGN', daily_limit: 1500 },
+          { id: `dest${i}`, balance: 0, currency: 'NGN' },
+        ],
+        instruction:
+          i % 7 === 3
+            ? `PAY ${i} NGN TO dest${i}`
+            : `DEBIT ${100 + i * 10} NGN FROM ACCOUNT ${debit} FOR CREDIT TO ACCOUNT dest${i}`,
+      });
+    }
+    return items;
+  }
+
+  async function run(concurrency) {
+    const { results } = await processBatch(
+      { items: makeItems() },
+      { now: NOW, concurrency, dailyDebitStore: createSlowDailyDebitStore() }
+    );
+    return results.map(({ transaction_id: id, ...rest }) => rest);
+  }
+
+  it('matches the sequential results, in request order', async () => {
+    const sequential = await run(1);
+    assert.ok(sequential.some((r) => r.status_code === 'LM01'));
+    assert.ok(sequential.some((r) => r.status_code === 'SY01'));
+    for (let attempt = 0; attempt < 3; attempt++) {
+      // eslint-disable-next-line no-await-in-loop
+      assert.deepStrictEqual(await run(8), sequential);
+    }
+  });
+
+  it('runs independent items at the same time, up to the pool size', async () => {
+    let active = 0;
+    let peak = 0;
+    const dailyDebitStore = {
+      async getTotal() {
+        active++;
+        peak = Math.max(peak, active);
+        await new Promise((resolve) => setTimeout(resolve, 5));
+        active--;
+        return 0;
+      },
+      async add() {},
+    };
+    const items = [];
+    for (let i = 0; i < 10; i++) {
+      items.push({
+        accounts: [
+          { id: `from${i}`, balance: 100, currency: 'NGN', daily_limit: 50 },
+          { id: `to${i}`, balance: 0, currency: 'NGN' },
+        ],
+        instruction: `DEBIT 10 NGN FROM ACCOUNT from${i} FOR CREDIT TO ACCOUNT to${i}`,
+      });
+    }
+    const { results } = await processBatch({ items }, { concurrency: 4, dailyDebitStore });
+    assert.strictEqual(peak, 4);
+    assert.deepStrictEqual(
+      results.map((r) => r.debit_account),
+      items.map((item) => item.accounts[0].id)
+    );
+  });
+});
@@ -0,0 +1,67 @@
+const assert = require('assert');
+const { scoreConfidence } = require('@app/services/payment-instructions/helpers');
+const paymentInstructions = require('@app/services/payment-instructions/payment-instructions');
+
+describe('payment-instructions: parse confidence', () => {
+  const accounts = [
+    { id: 'acc-00014821', balance: 1000, currency: 'NGN' },
+    { id: 'acc-00027777', balance: 0, currency: 'NGN' },
+  ];
+  const aliases = { salary: 'acc-00014821', rent: 'acc-00027777' };
+
+  function run(instruction, extra = {}) {
+    return paymentInstructions({ accounts, aliases, instruction, ...extra });
+  }
+
+  it('multiplies one penalty per guess', () => {
+    assert.strictEqual(scoreConfidence([]), 1);
+    assert.strictEqual(scoreConfidence(['alias']), 0.9);
+    assert.strictEqual(scoreConfidence(['alias', 'alias']), 0.81);
+    assert.strictEqual(scoreConfidence(['unknown']), 1);
+  });
+
+  it('scores literal instructions 1 and lowers the score with each guess', async () => {
+    const exact = await run(
+      'DEBIT 100 NGN FROM ACCOUNT acc-00014821 FOR CREDIT TO ACCOUNT acc-00027777'
+    );
+    const aliased = await run('DEBIT 100 NGN FROM ACCOUNT salary FOR CREDIT TO ACCOUNT rent');
+    const partial = await run('DEBIT 100 NGN FROM ACCOUNT ***4821 FOR CREDIT TO ACCOUNT ***7777');
+    const inferred = await run('DEBIT half NGN FROM ACCOUNT salary FOR CREDIT TO ACCOUNT rent');
+    assert.strictEqual(exact.status_code, 'AP00');
+    assert.strictEqual(exact.confidence, 1);
+    assert.strictEqual(aliased.status_code, 'AP00');
+    assert.ok(exact.confidence > aliased.confidence, `${aliased.confidence}`);
+    assert.ok(aliased.confidence > partial.confidence, `${partial.confidence}`);
+    assert.ok(inferred.confidence < aliased.confidence, `${inferred.confidence}`);
+  });
+
+  it('counts amounts in words and case-insensitive matches', async () => {
+    const words = await run('DEBIT one hundred NGN FROM ACCOUNT salary FOR CREDIT TO ACCOUNT rent');
+    assert.strictEqual(words.amount, 100);
+    assert.strictEqual(words.confidence, scoreConfidence(['word_amount', 'alias', 'alias']));
+    const upper = await run(
+      'DEBIT 100 NGN FROM ACCOUNT ACC-00014821 FOR CREDIT TO ACCOUNT acc-00027777',
+      { case_insensitive_ids: true }
+    );
+    assert.strictEqual(upper.confidence, scoreConfidence(['case_insensitive']));
+  });
+
+  it('is reported on failures once the accounts are resolved', async () => {
+    const result = await run('DEBIT 5000 NGN FROM ACCOUNT salary FOR CREDIT TO ACCOUNT rent');
+    assert.strictEqual(result.status_code, 'AC01');
+    assert.strictEqual(result.confidence, 0.81);
+  });
+
+  it('scores SPLIT and multi-debit instructions', async () => {
+    const split = await run('SPLIT 100 NGN FROM salary BETWEEN acc-00027777');
+    assert.strictEqual(split.status_code, 'AP00');
+    assert.strictEqual(split.confidence, 0.9);
+    const third = { id: 'acc-00035555', balance: 50, currency: 'NGN' };
+    const multi = await run('TRANSFER 100 NGN TO acc-00027777 FROM acc-00035555 AND acc-00014821', {
+      accounts: [...accounts, third],
+    });
+    assert.strictEqual(multi.type, 'MULTI_DEBIT');
+    assert.strictEqual(multi.status_code, 'AP00');
+    assert.strictEqual(multi.confidence, 1);
+  });
+});
@@ -0,0 +1,122 @@
+const assert = require('assert');
+const {
+  resolveCurrency,
+  isUnknownCurrencyCode,
+  SUPPORTED_CURRENCIES,
+  ISO_4217_CODES,
+} = require('@app/services/payment-instructions/helpers');
+const paymentInstructions = require('@app/services/payment-instructions/payment-instructions');
+
+describe('payment-instructions: currency recognition', () => {
+  it('supports the East African and euro codes', () => {
+    ['NGN', 'USD', 'GBP', 'GHS', 'KES', 'ZAR', 'EUR', 'UGX'].forEach((code) => {
+      assert.ok(Object.prototype.hasOwnProperty.call(SUPPORTED_CURRENCIES, code), code);
+      assert.deepStrictEqual(resolveCurrency(code.toLowerCase()), [code]);
+    });
+  });
+
+  const aliases = [
+    ['naira', ['NGN']],
+    ['Dollars', ['USD']],
+    ['pounds', ['GBP']],
+    ['cedis', ['GHS']],
+    ['rand', ['ZAR']],
+    ['euros', ['EUR']],
+    ['EURO', ['EUR']],
+    ['ksh', ['KES']],
+    ['shillings', ['KES', 'UGX']],
+  ];
+
+  aliases.forEach(([word, expected]) => {
+    it(`maps "${word}" to ${expected.join('/')}`, () => {
+      assert.deepStrictEqual(resolveCurrency(word), expected);
+    });
+  });
+
+  it('returns no candidates for unknown tokens', () => {
+    assert.deepStrictEqual(resolveCurrency('bitcoin'), []);
+    assert.deepStrictEqual(resolveCurrency('CNY'), []);
+  });
+
+  it('executes an instruction written with a currency word', async () => {
+    const result = await paymentInstructions({
+      accounts: [
+        { id: 'jhb', balance: 900, currency: 'ZAR' },
+        { id: 'cpt', balance: 100, currency: 'ZAR' },
+      ],
+      instruction: 'DEBIT 400 rand FROM ACCOUNT jhb FOR CREDIT TO ACCOUNT cpt',
+    });
+    assert.strictEqual(result.status_code, 'AP00');
+    assert.strictEqual(result.currency, 'ZAR');
+    assert.strictEqual(result.accounts[0].balance, 500);
+  });
+
+  it('resolves "shillings" against the debit account currency', async () => {
+    const result = await paymentInstructions({
+      accounts: [
+        { id: 'kla1', balance: 50000, currency: 'UGX' },
+        { id: 'kla2', balance: 0, currency: 'UGX' },
+      ],
+      instruction: 'CREDIT 20000 shillings TO ACCOUNT kla2 FOR DEBIT FROM ACCOUNT kla1',
+    });
+    assert.strictEqual(result.status_code, 'AP00');
+    assert.strictEqual(result.currency, 'UGX');
+  });
+
+  it('returns CU02 when a currency word does not match the debit account', async () => {
+    const result = await paymentInstructions({
+      accounts: [
+        { id: 'lagos', balance: 500, currency: 'NGN' },
+        { id: 'abuja', balance: 0, currency: 'NGN' },
+      ],
+      instruction: 'DEBIT 100 euros FROM ACCOUNT lagos FOR CREDIT TO ACCOUNT abuja',
+    });
+    assert.strictEqual(result.status_code, 'CU02');
+  });
+
+  it('still returns CU02 for unknown currency tokens', async () => {
+    const result = await paymentInstructions({
+      accounts: [
+        { id: 'lagos', balance: 500, currency: 'NGN' },
+        { id: 'abuja', balance: 0, currency: 'NGN' },
+      ],
+      instruction: 'DEBIT 100 bitcoin FROM ACCOUNT lagos FOR CREDIT TO ACCOUNT abuja',
+    });
+    assert.strictEqual(result.status_code, 'CU02');
+    assert.strictEqual(result.currency, 'BITCOIN');
+  });
+
+  it('returns CU04 for a code that is not ISO 4217, before looking at the accounts', async () => {
+    const run = (instruction) =>
+      paymentInstructions({
+        accounts: [
+          { id: 'lagos', balance: 500, currency: 'NGN' },
+          { id: 'abuja', balance: 0, currency: 'NGN' },
+        ],
+        instruction,
+      });
+    const invalid = await run('DEBIT 100 XYZ FROM ACCOUNT lagos FOR CREDIT TO ACCOUNT abuja');
+    assert.strictEqual(invalid.status_code, 'CU04');
+    assert.strictEqual(invalid.status_reason, 'Unknown currency code: not an ISO 4217 code: XYZ');
+    assert.strictEqual(invalid.currency, 'XYZ');
+
+    // Real codes keep CU02 whether unsupported (CNY) or just absent from the accounts (USD)
+    const unsupported = await run('DEBIT 100 cny FROM ACCOUNT lagos FOR CREDIT TO ACCOUNT abuja');
+    assert.strictEqual(unsupported.status_code, 'CU02');
+    const absent = await run('DEBIT 100 USD FROM ACCOUNT lagos FOR CREDIT TO ACCOUNT abuja');
+    assert.strictEqual(absent.status_code, 'CU02');
+  });
+
+  it('checks codes against an extensible ISO 4217 table', () => {
+    assert.strictEqual(isUnknownCurrencyCode('xyz'), true);
+    assert.strictEqual(isUnknownCurrencyCode('CNY'), false);
+    assert.strictEqual(isUnknownCurrencyCode('bitcoin'), false);
+    assert.strictEqual(isUnknownCurrencyCode('$'), false);
+    ISO_4217_CODES.push('XYZ');
+    try {
+      assert.strictEqual(isUnknownCurrencyCode('XYZ'), false);
+    } finally {
+      ISO_4217_CODES.pop();
+    }
+  });
+});
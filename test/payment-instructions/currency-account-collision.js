@@ -0,0 +1,48 @@
+const assert = require('assert');
+const paymentInstructions = require('@app/services/payment-instructions/payment-instructions');
+const { omitsCurrency, reorderAccountFirst } = require('@app/services/payment-instructions/helpers');
+
+describe('payment-instructions: account ids that look like currencies', () => {
+  function makeAccounts() {
+    return [
+      { id: 'acc1', balance: 500, currency: 'USD' },
+      { id: 'usdaccount', balance: 0, currency: 'USD' },
+      { id: 'dollar', balance: 0, currency: 'USD' },
+    ];
+  }
+  function run(instruction) {
+    return paymentInstructions({ accounts: makeAccounts(), instruction });
+  }
+
+  it('credits "usdaccount" without reading a currency out of it', async () => {
+    const transfer = await run('transfer 100 from acc1 to usdaccount');
+    assert.strictEqual(transfer.status_code, 'AP00');
+    assert.strictEqual(transfer.currency, 'USD');
+    assert.strictEqual(transfer.credit_account, 'usdaccount');
+    const multi = await run('transfer 100 to usdaccount from acc1');
+    assert.strictEqual(multi.status_code, 'AP00');
+    assert.strictEqual(multi.credit_account, 'usdaccount');
+  });
+
+  it('reads an account after the amount as a left-out currency', async () => {
+    const result = await run('send 100 usdaccount from acc1');
+    assert.strictEqual(result.status_code, 'SY01');
+    assert.strictEqual(result.currency, 'USD');
+    const unknownId = await run('send 100 usdsavings from acc1 to usdaccount');
+    assert.strictEqual(unknownId.status_code, 'CU02');
+  });
+
+  it('takes a currency word for an account only when it is an account id', async () => {
+    const credit = await run('credit dollar with 100 USD from acc1');
+    assert.strictEqual(credit.status_code, 'AP00');
+    assert.strictEqual(credit.credit_account, 'dollar');
+    const currency = await run('transfer 100 dollar from acc1 to usdaccount');
+    assert.strictEqual(currency.status_code, 'AP00');
+    assert.strictEqual(currency.currency, 'USD');
+    const tokens = 'credit naira 100 NGN from acc1'.split(' ');
+    assert.strictEqual(reorderAccountFirst(tokens, makeAccounts()), null);
+    assert.strictEqual(omitsCurrency('usdaccount', makeAccounts()), true);
+    assert.strictEqual(omitsCurrency('dollar', makeAccounts()), false);
+    assert.strictEqual(omitsCurrency('usdaccount'), false);
+  });
+});
@@ -0,0 +1,74 @@
+const assert = require('assert');
+const paymentInstructions = require('@app/services/payment-instructions/payment-instructions');
+
+describe('payment-instructions: currency consistency', () => {
+  function makeAccounts() {
+    return [
+      { id: 'acc1', balance: 10000, currency: 'NGN' },
+      { id: 'acc2', balance: 0, currency: 'NGN' },
+      { id: 'usd1', balance: 100, currency: 'USD' },
+    ];
+  }
+  function run(instruction, extra = {}) {
+    return paymentInstructions({ accounts: makeAccounts(), instruction, ...extra });
+  }
+  function assertUnchanged(result) {
+    assert.ok(result.accounts.length > 0);
+    assert.ok(result.accounts.every((a) => a.balance === a.balance_before));
+  }
+
+  it('names both currencies when the instruction matches the debit account only', async () => {
+    const result = await run('DEBIT 100 NGN FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT usd1');
+    assert.strictEqual(result.status_code, 'CU01');
+    assert.strictEqual(
+      result.status_reason,
+      'Account currency mismatch: acc1 holds NGN, usd1 holds USD'
+    );
+    assertUnchanged(result);
+  });
+
+  it('names both currencies when the instruction matches the credit account only', async () => {
+    const result = await run('CREDIT 5 USD TO ACCOUNT usd1 FOR DEBIT FROM ACCOUNT acc1');
+    assert.strictEqual(result.status_code, 'CU01');
+    assert.strictEqual(
+      result.status_reason,
+      'Account currency mismatch: acc1 holds NGN, usd1 holds USD'
+    );
+    assertUnchanged(result);
+    const neither = await run('DEBIT 5 GBP FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT usd1');
+    assert.strictEqual(
+      neither.status_reason,
+      'Account currency mismatch: acc1 holds NGN, usd1 holds USD; the instruction is in GBP'
+    );
+  });
+
+  it('names the instruction currency when both accounts hold another one', async () => {
+    const result = await run('DEBIT 5 USD FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc2');
+    assert.strictEqual(result.status_code, 'CU02');
+    assert.strictEqual(
+      result.status_reason,
+      'Instruction currency does not match the accounts: the instruction is in USD, acc1 and acc2 hold NGN'
+    );
+    assertUnchanged(result);
+  });
+
+  it('checks every leg of SPLIT and multi-debit instructions', async () => {
+    const split = await run('SPLIT 100 NGN FROM acc1 BETWEEN acc2 AND usd1');
+    assert.strictEqual(split.status_code, 'CU01');
+    assert.strictEqual(
+      split.status_reason,
+      'Account currency mismatch: acc1 and acc2 hold NGN, usd1 holds USD'
+    );
+    assertUnchanged(split);
+    const multi = await run('TRANSFER 100 NGN TO acc2 FROM acc1 AND usd1');
+    assert.strictEqual(multi.status_code, 'CU01');
+    assertUnchanged(multi);
+  });
+
+  it('lets an FX rate bridge the accounts', async () => {
+    const result = await run('DEBIT 1000 NGN FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT usd1', {
+      fx_rates: { 'NGN/USD': 0.001 },
+    });
+    assert.strictEqual(result.status_code, 'AP00');
+  });
+});
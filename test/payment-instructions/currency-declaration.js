@@ -0,0 +1,64 @@
+const assert = require('assert');
+const paymentInstructions = require('@app/services/payment-instructions/payment-instructions');
+const processBatch = require('@app/services/payment-instructions/process-batch');
+const { parseCurrencyDeclaration } = require('@app/services/payment-instructions/helpers');
+
+describe('payment-instructions: currency declared in a leading sentence', () => {
+  const accounts = [
+    { id: 'acc1', balance: 5000, currency: 'USD' },
+    { id: 'acc2', balance: 0, currency: 'USD' },
+    { id: 'acc3', balance: 0, currency: 'USD' },
+  ];
+  const run = (instruction) => paymentInstructions({ accounts, instruction });
+
+  it('reads "Use USD. Transfer 500 from acc1 to acc2."', async () => {
+    const result = await run('Use USD. Transfer 500 from acc1 to acc2.');
+    assert.strictEqual(result.status_code, 'AP00');
+    assert.strictEqual(result.currency, 'USD');
+    assert.strictEqual(result.credit_account, 'acc2');
+    assert.deepStrictEqual(result.accounts.map((a) => a.balance), [4500, 500]);
+    const split = await run('Currency: dollars. split 500 from acc1 equally between acc2, acc3.');
+    assert.strictEqual(split.status_code, 'AP00');
+    assert.strictEqual(split.currency, 'USD');
+  });
+
+  it('fails a declaration the accounts do not hold as a currency mismatch', async () => {
+    const result = await run('Use NGN. Transfer 500 from acc1 to acc2.');
+    assert.strictEqual(result.status, 'failed');
+    assert.strictEqual(result.status_code, 'CU02');
+    assert.strictEqual(
+      result.status_reason,
+      'Instruction currency does not match the accounts: the instruction is in NGN, acc1 and ' +
+        'acc2 hold USD'
+    );
+    assert.ok(result.accounts.every((a) => a.balance === a.balance_before));
+  });
+
+  it('leaves a currency the transfer names, and other batch items, alone', async () => {
+    const named = await run('Use NGN. Transfer 500 USD from acc1 to acc2.');
+    assert.strictEqual(named.status_code, 'AP00');
+    const batch = await processBatch({
+      accounts,
+      instructions: [
+        'Use USD. Transfer 500 from acc1 to acc2.',
+        'Transfer 500 from acc1 to acc3 for rent',
+      ],
+    });
+    assert.strictEqual(batch.results[0].currency, 'USD');
+    assert.strictEqual(batch.results[0].confidence, 1);
+    // The second item names no currency and infers it from the accounts, as it would alone
+    assert.ok(batch.results[1].confidence < 1);
+  });
+
+  it('only reads a short sentence of declaration words naming one currency', () => {
+    const tokens = (text) => text.split(' ');
+    assert.deepStrictEqual(parseCurrencyDeclaration(tokens('All amounts are in $. pay 5 to b.')), {
+      tokens: ['pay', '5', 'to', 'b'],
+      currency: 'USD',
+    });
+    assert.strictEqual(parseCurrencyDeclaration(tokens('Use shillings. pay 5 to b')), null);
+    assert.strictEqual(parseCurrencyDeclaration(tokens('Pay Mr. Smith 5 USD')), null);
+    assert.strictEqual(parseCurrencyDeclaration(tokens('Use USD.')), null);
+    assert.strictEqual(parseCurrencyDeclaration(tokens('pay 5 USD to acc2.')), null);
+  });
+});
@@ -0,0 +1,71 @@
+const assert = require('assert');
+const paymentInstructions = require('@app/services/payment-instructions/payment-instructions');
+
+describe('payment-instructions: currency inferred from the accounts', () => {
+  function makeAccounts() {
+    return [
+      { id: 'acc1', balance: 10000, currency: 'NGN' },
+      { id: 'acc2', balance: 0, currency: 'NGN' },
+      { id: 'usd1', balance: 100, currency: 'USD' },
+      { id: 'jpy1', balance: 1000, currency: 'JPY' },
+      { id: 'jpy2', balance: 0, currency: 'JPY' },
+    ];
+  }
+  function run(instruction, extra = {}) {
+    return paymentInstructions({ accounts: makeAccounts(), instruction, ...extra });
+  }
+
+  it('reads the currency both accounts hold', async () => {
+    const result = await run('transfer 5000 from acc1 to acc2');
+    assert.strictEqual(result.status_code, 'AP00');
+    assert.strictEqual(result.currency, 'NGN');
+    assert.deepStrictEqual(result.accounts.map((a) => a.balance), [5000, 5000]);
+    assert.ok(result.confidence < 1);
+    const yen = await run('DEBIT 300 FROM ACCOUNT jpy1 FOR CREDIT TO ACCOUNT jpy2');
+    assert.strictEqual(yen.currency, 'JPY');
+  });
+
+  it('infers it for every account of a split or multi-debit transfer', async () => {
+    const split = await run('split 100 from jpy1 equally between jpy2 and acc1');
+    assert.strictEqual(split.status_code, 'CU02');
+    const splitJpy = await paymentInstructions({
+      accounts: [...makeAccounts(), { id: 'jpy3', balance: 0, currency: 'JPY' }],
+      instruction: 'split 100 from jpy1 equally between jpy2 and jpy3',
+    });
+    assert.strictEqual(splitJpy.status_code, 'AP00');
+    assert.strictEqual(splitJpy.currency, 'JPY');
+    const multi = await run('transfer 100 to acc2 from acc1 and usd1');
+    assert.strictEqual(multi.status_code, 'CU02');
+    assert.strictEqual(
+      multi.status_reason,
+      'Instruction names no currency and the accounts hold different ones: acc1 and acc2 ' +
+        'hold NGN, usd1 holds USD'
+    );
+  });
+
+  it('asks for the currency when the accounts hold different ones', async () => {
+    const result = await run('transfer 5000 from acc1 to usd1', {
+      fx_rates: { 'NGN/USD': 0.00065 },
+    });
+    assert.strictEqual(result.status_code, 'CU02');
+    assert.strictEqual(result.currency, null);
+    assert.strictEqual(
+      result.status_reason,
+      'Instruction names no currency and the accounts hold different ones: acc1 holds NGN, ' +
+        'usd1 holds USD'
+    );
+    assert.deepStrictEqual(
+      result.accounts.map((a) => [a.id, a.balance, a.balance_before]),
+      [
+        ['acc1', 10000, 10000],
+        ['usd1', 100, 100],
+      ]
+    );
+  });
+
+  it('holds the amount to the inferred currency decimals', async () => {
+    const result = await run('transfer 10.5 from jpy1 to jpy2');
+    assert.strictEqual(result.status_code, 'CU03');
+    assert.strictEqual(result.currency, 'JPY');
+  });
+});
@@ -0,0 +1,72 @@
+const assert = require('assert');
+const paymentInstructions = require('@app/services/payment-instructions/payment-instructions');
+
+describe('payment-instructions: currency before or after the amount', () => {
+  function makeAccounts() {
+    return [
+      { id: 'acc1', balance: 10000, currency: 'NGN' },
+      { id: 'acc2', balance: 0, currency: 'NGN' },
+      { id: 'NGN-holder', balance: 0, currency: 'NGN' },
+    ];
+  }
+  function run(instruction) {
+    return paymentInstructions({ accounts: makeAccounts(), instruction });
+  }
+  async function amountAndCurrency(instruction) {
+    const result = await run(instruction);
+    assert.strictEqual(result.status_code, 'AP00', instruction);
+    return [result.amount, result.currency];
+  }
+
+  it('reads "NGN 5000", "5000 NGN" and "₦5000" the same way', async () => {
+    const forms = [
+      'DEBIT 5000 NGN FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc2',
+      'DEBIT NGN 5000 FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc2',
+      'DEBIT ngn 5,000.00 FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc2',
+      'DEBIT naira 5000 FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc2',
+      'DEBIT ₦5000 FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc2',
+      'DEBIT 5000₦ FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc2',
+    ];
+    const results = await Promise.all(forms.map(amountAndCurrency));
+    results.forEach((result) => assert.deepStrictEqual(result, [5000, 'NGN']));
+  });
+
+  it('accepts the currency first in transfers, splits and withdrawals', async () => {
+    assert.deepStrictEqual(await amountAndCurrency('transfer NGN 5000 to acc2 from acc1'), [
+      5000,
+      'NGN',
+    ]);
+    assert.deepStrictEqual(await amountAndCurrency('transfer 5000 NGN to acc2 from acc1'), [
+      5000,
+      'NGN',
+    ]);
+    const split = await run('SPLIT NGN 100 FROM acc1 EQUALLY BETWEEN acc2 AND NGN-holder');
+    assert.strictEqual(split.status_code, 'AP00');
+    assert.deepStrictEqual(split.splits, [
+      { account: 'acc2', amount: 50 },
+      { account: 'NGN-holder', amount: 50 },
+    ]);
+    assert.deepStrictEqual(await amountAndCurrency('WITHDRAW NGN 100 FROM acc1'), [100, 'NGN']);
+  });
+
+  it('still rejects an unsupported code written first as a currency', async () => {
+    const result = await run('DEBIT CHF 5 FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc2');
+    assert.strictEqual(result.status_code, 'CU02');
+    assert.strictEqual(result.amount, 5);
+    assert.strictEqual(result.currency, 'CHF');
+  });
+
+  it('does not take an account such as NGN-holder for the currency', async () => {
+    // Without a currency written, it is inferred from the accounts and NGN-holder stays the
+    // account credited
+    const missing = await run('transfer 5000 to NGN-holder from acc1');
+    assert.strictEqual(missing.status_code, 'AP00');
+    assert.strictEqual(missing.credit_account, 'NGN-holder');
+    const keyword = await run('DEBIT 5000 FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT NGN-holder');
+    assert.strictEqual(keyword.status_code, 'AP00');
+    assert.strictEqual(keyword.credit_account, 'NGN-holder');
+    const credited = await run('transfer 5000 NGN to NGN-holder from acc1');
+    assert.strictEqual(credited.status_code, 'AP00');
+    assert.strictEqual(credited.credit_account, 'NGN-holder');
+  });
+});
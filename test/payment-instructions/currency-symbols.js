@@ -0,0 +1,114 @@
+const assert = require('assert');
+const {
+  resolveCurrency,
+  placeCurrencySymbol,
+} = require('@app/services/payment-instructions/helpers');
+const paymentInstructions = require('@app/services/payment-instructions/payment-instructions');
+
+describe('payment-instructions: currency symbols', () => {
+  const symbols = [
+    ['₦', 'NGN'],
+    ['$', 'USD'],
+    ['US$', 'USD'],
+    ['£', 'GBP'],
+    ['€', 'EUR'],
+    ['₵', 'GHS'],
+    ['KSh', 'KES'],
+    ['USh', 'UGX'],
+    ['R', 'ZAR'],
+  ];
+
+  symbols.forEach(([symbol, code]) => {
+    it(`maps "${symbol}" to ${code}, attached to the amount or not`, async () => {
+      const accounts = [
+        { id: 'a', balance: 10000, currency: code },
+        { id: 'b', balance: 0, currency: code },
+      ];
+      assert.deepStrictEqual(resolveCurrency(symbol), [code]);
+      const attached = await paymentInstructions({
+        accounts,
+        instruction: `DEBIT ${symbol}500 FROM ACCOUNT a FOR CREDIT TO ACCOUNT b`,
+      });
+      assert.strictEqual(attached.status_code, 'AP00');
+      assert.strictEqual(attached.currency, code);
+      assert.strictEqual(attached.amount, 500);
+      const spaced = await paymentInstructions({
+        accounts,
+        instruction: `DEBIT ${symbol} 500 FROM ACCOUNT a FOR CREDIT TO ACCOUNT b`,
+      });
+      assert.strictEqual(spaced.currency, code);
+    });
+  });
+
+  it('moves the symbol to the currency position', () => {
+    assert.deepStrictEqual(placeCurrencySymbol(['debit', '₦5k', 'from'], 1), [
+      'debit',
+      '5k',
+      '₦',
+      'from',
+    ]);
+    assert.deepStrictEqual(placeCurrencySymbol(['debit', '20€', 'from'], 1), [
+      'debit',
+      '20',
+      '€',
+      'from',
+    ]);
+    assert.deepStrictEqual(placeCurrencySymbol(['debit', '$', 'two', 'hundred', 'from'], 1), [
+      'debit',
+      'two',
+      'hundred',
+      '$',
+      'from',
+    ]);
+    const plain = ['debit', '500', 'NGN'];
+    assert.strictEqual(placeCurrencySymbol(plain, 1), plain);
+  });
+
+  it('resolves a shared symbol against the currencies the accounts hold', async () => {
+    assert.deepStrictEqual(resolveCurrency('Sh'), ['KES', 'UGX']);
+    assert.deepStrictEqual(resolveCurrency('Sh', ['UGX']), ['UGX']);
+
+    const ugx = await paymentInstructions({
+      accounts: [
+        { id: 'a', balance: 1000, currency: 'UGX' },
+        { id: 'b', balance: 0, currency: 'UGX' },
+      ],
+      instruction: 'DEBIT Sh500 FROM ACCOUNT a FOR CREDIT TO ACCOUNT b',
+    });
+    assert.strictEqual(ugx.status_code, 'AP00');
+    assert.strictEqual(ugx.currency, 'UGX');
+  });
+
+  it('fails with CU06 when the accounts hold several matching currencies', async () => {
+    const result = await paymentInstructions({
+      accounts: [
+        { id: 'a', balance: 1000, currency: 'KES' },
+        { id: 'b', balance: 0, currency: 'UGX' },
+      ],
+      instruction: 'DEBIT Sh500 FROM ACCOUNT a FOR CREDIT TO ACCOUNT b',
+    });
+    assert.strictEqual(result.status, 'failed');
+    assert.strictEqual(result.status_code, 'CU06');
+    assert.strictEqual(result.status_reason, 'Ambiguous currency symbol: "Sh" could be KES or UGX');
+  });
+
+  it('accepts symbols in SPLIT and multi-debit instructions', async () => {
+    const accounts = [
+      { id: 'acc1', balance: 1000, currency: 'NGN' },
+      { id: 'acc2', balance: 1000, currency: 'NGN' },
+      { id: 'acc3', balance: 0, currency: 'NGN' },
+    ];
+    const split = await paymentInstructions({
+      accounts,
+      instruction: 'SPLIT ₦900 FROM acc1 BETWEEN acc2 AND acc3',
+    });
+    assert.strictEqual(split.status_code, 'AP00');
+    assert.strictEqual(split.currency, 'NGN');
+    const multi = await paymentInstructions({
+      accounts,
+      instruction: 'TRANSFER ₦1500 TO acc3 FROM acc1 AND acc2',
+    });
+    assert.strictEqual(multi.status_code, 'AP00');
+    assert.strictEqual(multi.currency, 'NGN');
+  });
+});
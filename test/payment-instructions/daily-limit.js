@@ -0,0 +1,61 @@
+const assert = require('assert');
+const paymentInstructions = require('@app/services/payment-instructions/payment-instructions');
+const createMemoryDailyDebitStore = require('@app/services/payment-instructions/stores/create-memory-daily-debit-store');
+
+describe('payment-instructions: daily limit', () => {
+  const NOW = Date.UTC(2025, 2, 12, 15, 30);
+  let dailyDebitStore;
+
+  beforeEach(() => {
+    dailyDebitStore = createMemoryDailyDebitStore();
+  });
+
+  function makeAccounts() {
+    return [
+      { id: 'acc1', balance: 5000, currency: 'NGN', daily_limit: 1000 },
+      { id: 'acc2', balance: 0, currency: 'NGN' },
+    ];
+  }
+  function run(amount, extraOptions = {}) {
+    const instruction = `DEBIT ${amount} NGN FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc2`;
+    return paymentInstructions(
+      { accounts: makeAccounts(), instruction },
+      { now: NOW, dailyDebitStore, ...extraOptions }
+    );
+  }
+
+  it('fails with LM01 when two debits together exceed the limit', async () => {
+    const first = await run(600);
+    assert.strictEqual(first.status_code, 'AP00');
+    const second = await run(500);
+    assert.strictEqual(second.status, 'failed');
+    assert.strictEqual(second.status_code, 'LM01');
+    assert.strictEqual(
+      second.status_reason,
+      'Daily debit limit exceeded for debit account: limit is 1000 NGN, already debited 600 NGN today, needs 500 NGN'
+    );
+    assert.deepStrictEqual(second.accounts, [
+      { id: 'acc1', balance: 5000, balance_before: 5000, currency: 'NGN' },
+      { id: 'acc2', balance: 0, balance_before: 0, currency: 'NGN' },
+    ]);
+  });
+
+  it('allows debits that add up exactly to the limit', async () => {
+    assert.strictEqual((await run(600)).status_code, 'AP00');
+    assert.strictEqual((await run(400)).status_code, 'AP00');
+    assert.strictEqual(await dailyDebitStore.getTotal('acc1', '2025-03-12'), 1000);
+  });
+
+  it('starts a fresh total on the next UTC day', async () => {
+    await run(900);
+    const nextDay = await run(900, { now: Date.UTC(2025, 2, 13, 0, 0) });
+    assert.strictEqual(nextDay.status_code, 'AP00');
+  });
+
+  it('does not count failed debits or dry runs', async () => {
+    assert.strictEqual((await run(1500)).status_code, 'LM01');
+    assert.strictEqual((await run(800, { dryRun: true })).status_code, 'AP00');
+    assert.strictEqual(await dailyDebitStore.getTotal('acc1', '2025-03-12'), 0);
+    assert.strictEqual((await run(1000)).status_code, 'AP00');
+  });
+});
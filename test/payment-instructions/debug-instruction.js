@@ -0,0 +1,97 @@
+const assert = require('assert');
+const debugInstruction = require('@app/services/payment-instructions/debug-instruction');
+
+describe('payment-instructions: debug breakdown', () => {
+  function makeAccounts() {
+    return [
+      { id: 'acc-00014821', balance: 5000, currency: 'NGN' },
+      { id: 'sav1', balance: 0, currency: 'NGN' },
+    ];
+  }
+
+  it('breaks a mistyped instruction into tokens and candidates', async () => {
+    const accounts = makeAccounts();
+    const instruction = '  Debti ₦1,000.50 from ***4821 for credit to savings';
+    const result = await debugInstruction({
+      accounts,
+      aliases: { savings: 'sav1' },
+      instruction,
+    });
+
+    assert.strictEqual(result.instruction, instruction);
+    assert.deepStrictEqual(
+      result.tokens.map((t) => [t.token, t.offset, t.length]),
+      [
+        ['Debti', 2, 5],
+        ['₦1,000.50', 8, 9],
+        ['from', 18, 4],
+        ['***4821', 23, 7],
+        ['for', 31, 3],
+        ['credit', 35, 6],
+        ['to', 42, 2],
+        ['savings', 45, 7],
+      ]
+    );
+    result.tokens.forEach((t) => {
+      assert.strictEqual(instruction.substr(t.offset, t.length), t.token);
+    });
+
+    assert.deepStrictEqual(result.candidates.verb, {
+      index: 0,
+      token: 'Debti',
+      reads_as: null,
+      suggestion: 'debit',
+    });
+    assert.deepStrictEqual(result.candidates.amounts, [
+      { index: 1, text: '1,000.50', amount: 1000.5, ratio: null },
+    ]);
+    assert.deepStrictEqual(result.candidates.currencies, [
+      { index: 1, token: '₦', codes: ['NGN'] },
+    ]);
+    assert.deepStrictEqual(result.candidates.accounts, [
+      { index: 3, text: '***4821', via: 'suffix', ids: ['acc-00014821'] },
+      { index: 7, text: 'savings', via: 'alias', ids: ['sav1'] },
+    ]);
+
+    assert.strictEqual(result.outcome.status, 'failed');
+    assert.strictEqual(result.outcome.status_code, 'SY01');
+    assert.strictEqual(result.outcome.parse_error.segment, 'verb');
+    assert.strictEqual(result.outcome.parse_error.offset, 2);
+    assert.deepStrictEqual(accounts, makeAccounts());
+  });
+
+  it('reports the outcome of an instruction that parses without executing it', async () => {
+    const accounts = makeAccounts();
+    const result = await debugInstruction({
+      accounts,
+      instruction: 'send 100 NGN from acc-00014821 to sav1',
+    });
+    assert.deepStrictEqual(result.candidates.verb, {
+      index: 0,
+      token: 'send',
+      reads_as: 'transfer',
+      suggestion: null,
+    });
+    assert.deepStrictEqual(
+      result.candidates.accounts.map((c) => [c.text, c.via]),
+      [
+        ['acc-00014821', 'id'],
+        ['sav1', 'id'],
+      ]
+    );
+    assert.strictEqual(result.outcome.status, 'parsed');
+    assert.strictEqual(result.outcome.status_code, null);
+    assert.strictEqual(result.outcome.parse_error, null);
+    assert.deepStrictEqual(accounts, makeAccounts());
+  });
+
+  it('reads the outcome with every field of the request, such as its locale', async () => {
+    const result = await debugInstruction({
+      accounts: makeAccounts(),
+      instruction: 'send 2 lakh NGN from acc-00014821 to sav1',
+      locale: 'en-IN',
+    });
+    assert.strictEqual(result.outcome.status, 'parsed');
+    assert.strictEqual(result.outcome.status_code, null);
+  });
+});
@@ -0,0 +1,76 @@
+const assert = require('assert');
+const paymentInstructions = require('@app/services/payment-instructions/payment-instructions');
+
+describe('payment-instructions: default currency', () => {
+  function makeAccounts() {
+    return [
+      { id: 'acc1', balance: 10000, currency: 'NGN' },
+      { id: 'acc2', balance: 0, currency: 'NGN' },
+      { id: 'acc3', balance: 0, currency: 'NGN' },
+      { id: 'usd1', balance: 0, currency: 'USD' },
+    ];
+  }
+  function run(instruction, extra = {}, options = {}) {
+    return paymentInstructions({ accounts: makeAccounts(), instruction, ...extra }, options);
+  }
+
+  it('fills the currency of an instruction that names none', async () => {
+    const result = await run('transfer 5000 from acc1 to acc2', { default_currency: 'ngn' });
+    assert.strictEqual(result.status_code, 'AP00');
+    assert.strictEqual(result.currency, 'NGN');
+    assert.deepStrictEqual(result.accounts.map((a) => a.balance), [5000, 5000]);
+  });
+
+  it('takes the default from options.defaultCurrency, in every form', async () => {
+    const options = { defaultCurrency: 'NGN' };
+    const debit = await run('DEBIT 500 FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc2', {}, options);
+    assert.strictEqual(debit.status_code, 'AP00');
+    const split = await run('split 9000 from acc1 equally between acc2 and acc3', {}, options);
+    assert.strictEqual(split.status_code, 'AP00');
+    assert.strictEqual(split.currency, 'NGN');
+    const multi = await run('transfer 900 to acc3 from acc1 and acc2', {}, options);
+    assert.strictEqual(multi.status_code, 'AP00');
+    const deposit = await run('deposit 500 into acc1', {}, options);
+    assert.strictEqual(deposit.currency, 'NGN');
+  });
+
+  it('leaves a written currency as it is', async () => {
+    const result = await paymentInstructions({
+      accounts: [
+        { id: 'usd1', balance: 10, currency: 'USD' },
+        { id: 'usd2', balance: 0, currency: 'USD' },
+      ],
+      instruction: 'transfer 5 USD from usd1 to usd2',
+      default_currency: 'NGN',
+    });
+    assert.strictEqual(result.status_code, 'AP00');
+    assert.strictEqual(result.currency, 'USD');
+  });
+
+  it('fails when the accounts are not in the default currency', async () => {
+    const neither = await run('transfer 5000 from acc1 to acc2', { default_currency: 'USD' });
+    assert.strictEqual(neither.status_code, 'CU02');
+    assert.strictEqual(
+      neither.status_reason,
+      'Instruction currency does not match the accounts: the instruction is in USD, acc1 and ' +
+        'acc2 hold NGN'
+    );
+    assert.ok(neither.accounts.every((a) => a.balance === a.balance_before));
+
+    // A defaulted currency is never converted, even with a rate for the pair
+    const fx = await run('transfer 5000 from acc1 to usd1', {
+      default_currency: 'NGN',
+      fx_rates: { 'NGN/USD': 0.00065 },
+    });
+    assert.strictEqual(fx.status_code, 'CU01');
+
+    const withdrawal = await run('withdraw 500 from acc1', { default_currency: 'USD' });
+    assert.strictEqual(withdrawal.status_code, 'CU01');
+  });
+
+  it('infers the currency from the accounts when no default is configured', async () => {
+    const result = await run('transfer 5000 from acc1 to acc2');
+    assert.strictEqual(result.status_code, 'AP00');
+    assert.strictEqual(result.currency, 'NGN');
+  });
+});
@@ -0,0 +1,57 @@
+const assert = require('assert');
+const paymentInstructions = require('@app/services/payment-instructions/payment-instructions');
+
+describe('payment-instructions: dry run', () => {
+  function makeAccounts() {
+    return [
+      { id: 'acc1', balance: 1000, currency: 'NGN' },
+      { id: 'acc2', balance: 200, currency: 'NGN' },
+    ];
+  }
+  const instruction = 'DEBIT 300 NGN FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc2';
+
+  it('projects balances without changing them', async () => {
+    const accounts = makeAccounts();
+    const result = await paymentInstructions({ accounts, instruction, dry_run: true });
+    assert.strictEqual(result.status_code, 'AP00');
+    assert.strictEqual(result.dry_run, true);
+    assert.ok(result.status_reason.indexOf('Dry run') === 0);
+    assert.strictEqual(result.amount, 300);
+    assert.strictEqual(result.debit_account, 'acc1');
+    assert.strictEqual(result.credit_account, 'acc2');
+    assert.deepStrictEqual(result.accounts, [
+      { id: 'acc1', balance: 1000, balance_before: 1000, projected_balance: 700, currency: 'NGN' },
+      { id: 'acc2', balance: 200, balance_before: 200, projected_balance: 500, currency: 'NGN' },
+    ]);
+    assert.deepStrictEqual(accounts, makeAccounts());
+  });
+
+  it('accepts the flag through options', async () => {
+    const result = await paymentInstructions(
+      { accounts: makeAccounts(), instruction },
+      { dryRun: true }
+    );
+    assert.strictEqual(result.dry_run, true);
+    assert.strictEqual(result.accounts[0].balance, 1000);
+  });
+
+  it('reports the same failure a real run would', async () => {
+    const over = 'DEBIT 5000 NGN FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc2';
+    const real = await paymentInstructions({ accounts: makeAccounts(), instruction: over });
+    const preview = await paymentInstructions({
+      accounts: makeAccounts(),
+      instruction: over,
+      dry_run: true,
+    });
+    assert.strictEqual(preview.status_code, real.status_code);
+    assert.strictEqual(preview.status_reason, real.status_reason);
+    assert.deepStrictEqual(preview.accounts, real.accounts);
+  });
+
+  it('leaves real runs without the dry-run fields', async () => {
+    const result = await paymentInstructions({ accounts: makeAccounts(), instruction });
+    assert.strictEqual(result.dry_run, undefined);
+    assert.strictEqual(result.accounts[0].projected_balance, undefined);
+    assert.strictEqual(result.accounts[0].balance, 700);
+  });
+});
@@ -0,0 +1,41 @@
+const assert = require('assert');
+const paymentInstructions = require('@app/services/payment-instructions/payment-instructions');
+
+describe('payment-instructions: duplicate account ids', () => {
+  const instruction = 'DEBIT 100 NGN FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc2';
+
+  it('rejects an id given to two accounts before parsing, naming the id', async () => {
+    const accounts = [
+      { id: 'acc1', balance: 500, currency: 'NGN' },
+      { id: 'acc2', balance: 0, currency: 'NGN' },
+      { id: 'acc1', balance: 900, currency: 'NGN' },
+    ];
+    const result = await paymentInstructions({ accounts, instruction });
+    assert.strictEqual(result.status, 'failed');
+    assert.strictEqual(result.status_code, 'AC07');
+    assert.strictEqual(result.status_reason, 'More than one account has this id: acc1');
+    assert.strictEqual(result.type, null);
+    assert.deepStrictEqual(result.accounts, []);
+    // Caught even when the instruction itself would not parse
+    const garbled = await paymentInstructions({ accounts, instruction: 'HELLO THERE' });
+    assert.strictEqual(garbled.status_code, 'AC07');
+  });
+
+  it('treats ids differing only by case as duplicates with case_insensitive_ids', async () => {
+    const accounts = [
+      { id: 'acc1', balance: 500, currency: 'NGN' },
+      { id: 'acc2', balance: 0, currency: 'NGN' },
+      { id: 'ACC2', balance: 0, currency: 'NGN' },
+    ];
+    const exact = await paymentInstructions({ accounts, instruction });
+    assert.strictEqual(exact.status_code, 'AP00');
+    assert.strictEqual(exact.credit_account, 'acc2');
+
+    const ignoringCase = await paymentInstructions(
+      { accounts, instruction },
+      { caseInsensitiveIds: true }
+    );
+    assert.strictEqual(ignoringCase.status_code, 'AC07');
+    assert.strictEqual(ignoringCase.status_reason, 'More than one account has this id: acc2, ACC2');
+  });
+});
@@ -0,0 +1,31 @@
+const assert = require('assert');
+const paymentInstructions = require('@app/services/payment-instructions/payment-instructions');
+
+describe('payment-instructions: empty instructions', () => {
+  function run(instruction) {
+    return paymentInstructions({
+      accounts: [
+        { id: 'acc1', balance: 1000, currency: 'NGN' },
+        { id: 'acc2', balance: 0, currency: 'NGN' },
+      ],
+      instruction,
+    });
+  }
+
+  ['', '   ', '\t\n ', '???', '- , .'].forEach((instruction) => {
+    it(`fails ${JSON.stringify(instruction)} with SY05`, async () => {
+      const result = await run(instruction);
+      assert.strictEqual(result.status, 'failed');
+      assert.strictEqual(result.status_code, 'SY05');
+      assert.strictEqual(result.status_reason, 'Instruction is empty');
+      assert.strictEqual(result.parse_error, undefined);
+      assert.deepStrictEqual(result.accounts, []);
+    });
+  });
+
+  it('still reads text it cannot parse as a syntax error', async () => {
+    assert.strictEqual((await run('hello')).status_code, 'SY01');
+    assert.strictEqual((await run('₦')).status_code, 'SY01');
+    assert.strictEqual((await run('DEBIT')).status_code, 'SY03');
+  });
+});
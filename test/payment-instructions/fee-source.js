@@ -0,0 +1,68 @@
+const assert = require('assert');
+const paymentInstructions = require('@app/services/payment-instructions/payment-instructions');
+const { parseFeeSource } = require('@app/services/payment-instructions/helpers');
+
+describe('payment-instructions: fee-source clause', () => {
+  const feePolicy = { TRANSFER: { flat: 50 }, DEBIT: { flat: 50 } };
+  function makeAccounts(feeBalance = 100) {
+    return [
+      { id: 'acc1', balance: 2000, currency: 'NGN' },
+      { id: 'acc2', balance: 0, currency: 'NGN' },
+      { id: 'acc3', balance: feeBalance, currency: 'NGN' },
+    ];
+  }
+  function run(instruction, feeBalance, extra = {}) {
+    return paymentInstructions(
+      { accounts: makeAccounts(feeBalance), instruction, ...extra },
+      { feePolicy }
+    );
+  }
+
+  it('debits the fee from a third account', async () => {
+    const result = await run('transfer 1000 NGN from acc1 to acc2, fee from acc3');
+    assert.strictEqual(result.status_code, 'AP00');
+    assert.strictEqual(result.fee, 50);
+    assert.strictEqual(result.fee_account, 'acc3');
+    assert.deepStrictEqual(result.accounts, [
+      { id: 'acc1', balance: 1000, balance_before: 2000, currency: 'NGN' },
+      { id: 'acc2', balance: 1000, balance_before: 0, currency: 'NGN' },
+      { id: 'acc3', balance: 50, balance_before: 100, currency: 'NGN' },
+    ]);
+  });
+
+  it('fails with BL01 naming the fee account when it cannot cover the fee', async () => {
+    const result = await run('transfer 1000 NGN from acc1 to acc2, fee from acc3', 10);
+    assert.strictEqual(result.status, 'failed');
+    assert.strictEqual(result.status_code, 'BL01');
+    assert.strictEqual(
+      result.status_reason,
+      'Insufficient funds in fee account: acc3 has 10 NGN, needs 50 NGN fee'
+    );
+    assert.ok(result.accounts.every((a) => a.balance === a.balance_before));
+    assert.deepStrictEqual(result.accounts.map((a) => a.id), ['acc1', 'acc2', 'acc3']);
+  });
+
+  it('reads the clause in the keyword form, by alias and before a narration', async () => {
+    const instruction =
+      'DEBIT 1000 NGN FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc2 with fees from bills for rent';
+    const result = await run(instruction, 100, { aliases: { bills: 'acc3' } });
+    assert.strictEqual(result.status_code, 'AP00');
+    assert.strictEqual(result.fee_account, 'acc3');
+    assert.strictEqual(result.narration, 'rent');
+    assert.strictEqual(result.accounts[0].balance, 1000);
+  });
+
+  it('fails an unknown fee account with AC03', async () => {
+    const result = await run('transfer 1000 NGN from acc1 to acc2 and pay the fee from acc9');
+    assert.strictEqual(result.status_code, 'AC03');
+    assert.strictEqual(result.status_reason, 'Account not found: fee account acc9');
+  });
+
+  it('leaves a narration that mentions fees alone', () => {
+    const narration = 'transfer 100 NGN from acc1 to acc2 for school fees from dad'.split(' ');
+    assert.strictEqual(parseFeeSource(narration), null);
+    const found = parseFeeSource('send 100 NGN to acc2, fees from account acc3'.split(' '));
+    assert.deepStrictEqual(found.tokens, ['send', '100', 'NGN', 'to', 'acc2']);
+    assert.strictEqual(found.ref.token, 'acc3');
+  });
+});
@@ -0,0 +1,68 @@
+const assert = require('assert');
+const paymentInstructions = require('@app/services/payment-instructions/payment-instructions');
+
+describe('payment-instructions: fees', () => {
+  function makeAccounts() {
+    return [
+      { id: 'acc1', balance: 1000, currency: 'NGN' },
+      { id: 'acc2', balance: 0, currency: 'NGN' },
+      { id: 'acc3', balance: 0, currency: 'NGN' },
+    ];
+  }
+  function run(amount, feePolicy) {
+    const instruction = `DEBIT ${amount} NGN FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc2`;
+    return paymentInstructions({ accounts: makeAccounts(), instruction }, { feePolicy });
+  }
+
+  it('debits a flat fee on top of the amount', async () => {
+    const result = await run(500, { DEBIT: { flat: 25 } });
+    assert.strictEqual(result.status_code, 'AP00');
+    assert.strictEqual(result.amount, 500);
+    assert.strictEqual(result.fee, 25);
+    assert.deepStrictEqual(result.accounts, [
+      { id: 'acc1', balance: 475, balance_before: 1000, currency: 'NGN' },
+      { id: 'acc2', balance: 500, balance_before: 0, currency: 'NGN' },
+    ]);
+  });
+
+  it('rounds a percentage fee to minor units', async () => {
+    const result = await run(333, { DEBIT: { percent: 1.5 } });
+    // 1.5% of 333.00 = 4.995 -> 5.00
+    assert.strictEqual(result.fee, 5);
+    assert.strictEqual(result.accounts[0].balance, 662);
+  });
+
+  it('combines flat and percentage parts', async () => {
+    const result = await run(200, { DEBIT: { flat: 10, percent: 2.5 } });
+    assert.strictEqual(result.fee, 15);
+    assert.strictEqual(result.accounts[0].balance, 785);
+  });
+
+  it('fails with AC01 when the fee tips the debit over the balance', async () => {
+    const result = await run(990, { DEBIT: { flat: 25 } });
+    assert.strictEqual(result.status_code, 'AC01');
+    assert.strictEqual(result.fee, 25);
+    assert.strictEqual(
+      result.status_reason,
+      'Insufficient funds in debit account: has 1000 NGN, needs 1015 NGN (990 NGN + 25 NGN fee)'
+    );
+    assert.strictEqual(result.accounts[0].balance, 1000);
+  });
+
+  it('omits the fee when no policy covers the type', async () => {
+    const disabled = await run(500);
+    assert.strictEqual(disabled.fee, undefined);
+    assert.strictEqual(disabled.accounts[0].balance, 500);
+    const otherType = await run(500, { CREDIT: { flat: 25 } });
+    assert.strictEqual(otherType.fee, undefined);
+  });
+
+  it('charges SPLIT fees to the debit account only', async () => {
+    const result = await paymentInstructions(
+      { accounts: makeAccounts(), instruction: 'SPLIT 600 NGN FROM acc1 BETWEEN acc2 AND acc3' },
+      { feePolicy: { SPLIT: { flat: 10 } } }
+    );
+    assert.strictEqual(result.fee, 10);
+    assert.deepStrictEqual(result.accounts.map((a) => a.balance), [390, 300, 300]);
+  });
+});
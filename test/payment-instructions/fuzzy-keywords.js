@@ -0,0 +1,88 @@
+const assert = require('assert');
+const {
+  correctKeyword,
+  correctCurrencyWord,
+} = require('@app/services/payment-instructions/helpers');
+const paymentInstructions = require('@app/services/payment-instructions/payment-instructions');
+
+describe('payment-instructions: fuzzy keywords', () => {
+  const verbs = ['debit', 'credit', 'transfer', 'split', 'schedule'];
+  function makeAccounts() {
+    return [
+      { id: 'acc1', balance: 1000, currency: 'NGN' },
+      { id: 'acc2', balance: 0, currency: 'NGN' },
+    ];
+  }
+  function run(instruction, extra = { fuzzy_keywords: true }) {
+    return paymentInstructions({ accounts: makeAccounts(), instruction, ...extra });
+  }
+
+  it('corrects common typos of verbs and currency words', () => {
+    assert.strictEqual(correctKeyword('trasnfer', verbs), 'transfer');
+    assert.strictEqual(correctKeyword('Debt', verbs), 'debit');
+    assert.strictEqual(correctKeyword('credti', verbs), 'credit');
+    assert.strictEqual(correctKeyword('spilt', verbs), 'split');
+    assert.strictEqual(correctCurrencyWord('niara'), 'naira');
+    assert.strictEqual(correctCurrencyWord('dolars'), 'dollars');
+  });
+
+  it('rejects short words and clearly different words', () => {
+    assert.strictEqual(correctKeyword('pay', ['day']), null);
+    assert.strictEqual(correctKeyword('deposit', verbs), null);
+    assert.strictEqual(correctKeyword('bedit', verbs), null);
+    assert.strictEqual(correctKeyword('debit', verbs), null);
+    assert.strictEqual(correctCurrencyWord('usx'), null);
+    assert.strictEqual(correctCurrencyWord('rent'), null);
+  });
+
+  it('parses a mistyped instruction and notes the corrections', async () => {
+    const result = await run('debt 100 niara FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc2');
+    assert.strictEqual(result.status_code, 'AP00');
+    assert.strictEqual(result.type, 'DEBIT');
+    assert.strictEqual(result.currency, 'NGN');
+    assert.strictEqual(
+      result.status_reason,
+      'Transaction executed successfully; corrected "debt" to debit; corrected "niara" to naira'
+    );
+    const exact = await run('DEBIT 100 NGN FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc2');
+    assert.ok(result.confidence < exact.confidence, `${result.confidence}`);
+  });
+
+  it('corrects the verb of SCHEDULE, SPLIT and multi-debit instructions', async () => {
+    const scheduled = await run(
+      'SCHEDULE trasnfer 100 NGN FROM ACCOUNT acc1 TO ACCOUNT acc2 ON 2099-01-01'
+    );
+    assert.strictEqual(scheduled.status_code, 'AP02');
+    assert.ok(scheduled.status_reason.endsWith('corrected "trasnfer" to transfer'));
+    const split = await run('spilt 100 NGN FROM acc1 BETWEEN acc2');
+    assert.strictEqual(split.type, 'SPLIT');
+    assert.strictEqual(split.status_code, 'AP00');
+    const multi = await run('trasnfer 100 niara TO acc2 FROM acc1 AND acc3', {
+      fuzzy_keywords: true,
+      accounts: [...makeAccounts(), { id: 'acc3', balance: 0, currency: 'NGN' }],
+    });
+    assert.strictEqual(multi.type, 'MULTI_DEBIT');
+    assert.strictEqual(
+      multi.status_reason,
+      'Transaction executed successfully; corrected "trasnfer" to transfer; corrected "niara" to naira'
+    );
+  });
+
+  it('is off unless requested', async () => {
+    const result = await run('debt 100 NGN FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc2', {});
+    assert.strictEqual(result.status_code, 'SY01');
+    const pay = await run('pay 100 NGN FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc2');
+    assert.strictEqual(pay.status_code, 'SY01');
+  });
+
+  it('locates parse errors in the instruction as typed', async () => {
+    const result = await run('debt 100 XYZ FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc2');
+    assert.strictEqual(result.status_code, 'CU04');
+    assert.deepStrictEqual(result.parse_error, {
+      segment: 'currency',
+      token: 'XYZ',
+      offset: 9,
+      length: 3,
+    });
+  });
+});
@@ -0,0 +1,53 @@
+const assert = require('assert');
+const { convertAmount } = require('@app/services/payment-instructions/helpers');
+const paymentInstructions = require('@app/services/payment-instructions/payment-instructions');
+
+describe('payment-instructions: FX conversion', () => {
+  const accounts = [
+    { id: 'ngn1', balance: 100000, currency: 'NGN' },
+    { id: 'usd1', balance: 10, currency: 'USD' },
+  ];
+
+  it('converts exactly and rounds to the destination minor units', () => {
+    assert.strictEqual(convertAmount(10000, 0.00065, 'NGN', 'USD'), 6.5);
+    assert.strictEqual(convertAmount(1, 0.00065, 'NGN', 'USD'), 0);
+    assert.strictEqual(convertAmount(1234, 0.00065, 'NGN', 'USD'), 0.8);
+  });
+
+  it('debits NGN and credits the converted USD amount', async () => {
+    const result = await paymentInstructions({
+      accounts,
+      instruction: 'DEBIT 10000 NGN FROM ACCOUNT ngn1 FOR CREDIT TO ACCOUNT usd1',
+      fx_rates: { 'NGN/USD': 0.00065 },
+    });
+    assert.strictEqual(result.status_code, 'AP00');
+    assert.strictEqual(result.currency, 'NGN');
+    assert.strictEqual(result.amount, 10000);
+    assert.strictEqual(result.converted_amount, 6.5);
+    assert.strictEqual(result.converted_currency, 'USD');
+    assert.ok(result.status_reason.indexOf('1 NGN = 0.00065 USD') !== -1);
+    assert.deepStrictEqual(result.accounts, [
+      { id: 'ngn1', balance: 90000, balance_before: 100000, currency: 'NGN' },
+      { id: 'usd1', balance: 16.5, balance_before: 10, currency: 'USD' },
+    ]);
+  });
+
+  it('returns CU05 when the pair has no rate', async () => {
+    const result = await paymentInstructions({
+      accounts,
+      instruction: 'DEBIT 10000 NGN FROM ACCOUNT ngn1 FOR CREDIT TO ACCOUNT usd1',
+      fx_rates: { 'USD/NGN': 1540 },
+    });
+    assert.strictEqual(result.status_code, 'CU05');
+    assert.strictEqual(result.accounts[0].balance, 100000);
+    assert.strictEqual(result.accounts[1].balance, 10);
+  });
+
+  it('keeps failing with CU01 when FX mode is off', async () => {
+    const result = await paymentInstructions({
+      accounts,
+      instruction: 'DEBIT 10000 NGN FROM ACCOUNT ngn1 FOR CREDIT TO ACCOUNT usd1',
+    });
+    assert.strictEqual(result.status_code, 'CU01');
+  });
+});
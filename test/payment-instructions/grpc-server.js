@@ -0,0 +1,93 @@
+const assert = require('assert');
+const {
+  createGrpcServer,
+  loadGrpcPackage,
+} = require('@app/services/payment-instructions/grpc/create-grpc-server');
+const {
+  toRequestMessage,
+  fromResponseMessage,
+} = require('@app/services/payment-instructions/grpc/convert-messages');
+const processIdempotentInstruction = require('@app/services/payment-instructions/process-idempotent-instruction');
+
+describe('payment-instructions: gRPC server', () => {
+  const options = {
+    now: new Date(Date.UTC(2025, 4, 28, 9, 0, 0)),
+    idGenerator: { next: () => 'txn_0m87f3k2a0001q9zd' },
+  };
+  const { grpc, proto } = loadGrpcPackage();
+  let server;
+  let client;
+
+  before(() => {
+    server = createGrpcServer(options);
+    return new Promise((resolve, reject) => {
+      server.bindAsync('127.0.0.1:0', grpc.ServerCredentials.createInsecure(), (err, port) => {
+        if (err) {
+          reject(err);
+          return;
+        }
+        client = new proto.PaymentInstructions(
+          `127.0.0.1:${port}`,
+          grpc.credentials.createInsecure()
+        );
+        resolve();
+      });
+    });
+  });
+
+  after(() => {
+    client.close();
+    return new Promise((resolve) => server.tryShutdown(resolve));
+  });
+
+  function call(method, payload, metadata = new grpc.Metadata()) {
+    return new Promise((resolve, reject) => {
+      client[method](toRequestMessage(payload), metadata, (err, response) =>
+        err ? reject(err) : resolve(response)
+      );
+    });
+  }
+  const payload = (instruction, extra = {}) => ({
+    accounts: [
+      { id: 'acc1', balance: 500, currency: 'NGN', overdraft_limit: 0 },
+      { id: 'acc2', balance: 0, currency: 'NGN' },
+    ],
+    instruction,
+    ...extra,
+  });
+
+  it('executes over the wire with the result the service returns', async () => {
+    const cases = [
+      payload('transfer 100 NGN from acc1 to acc2'),
+      payload('transfer 900 NGN from acc1 to acc2'),
+      payload('transfer abc'),
+      payload('schedule transfer 100 NGN from acc1 to acc2 on 2030-01-01', { strict: false }),
+    ];
+    for (let i = 0; i < cases.length; i++) {
+      // eslint-disable-next-line no-await-in-loop
+      const response = await call('Execute', cases[i]);
+      // eslint-disable-next-line no-await-in-loop
+      const expected = await processIdempotentInstruction(cases[i], options);
+      assert.deepStrictEqual(fromResponseMessage(response), expected, cases[i].instruction);
+    }
+  });
+
+  it('parses without executing', async () => {
+    const response = await call('Parse', payload('transfer 100 NGN from acc1 to acc2'));
+    assert.strictEqual(response.status.value, 'parsed');
+    assert.strictEqual(response.amount.value, 100);
+    assert.deepStrictEqual(response.accounts, []);
+  });
+
+  it('replays by idempotency-key metadata and refuses a reused key', async () => {
+    const metadata = new grpc.Metadata();
+    metadata.set('idempotency-key', 'grpc-server-key');
+    const first = await call('Execute', payload('transfer 100 NGN from acc1 to acc2'), metadata);
+    const again = await call('Execute', payload('transfer 100 NGN from acc1 to acc2'), metadata);
+    assert.deepStrictEqual(fromResponseMessage(again), fromResponseMessage(first));
+    const err = await call('Execute', payload('transfer 1 NGN from acc1 to acc2'), metadata).catch(
+      (e) => e
+    );
+    assert.strictEqual(err.code, grpc.status.ALREADY_EXISTS);
+  });
+});
@@ -0,0 +1,139 @@
+const assert = require('assert');
+const http = require('http');
+const createGrpcService = require('@app/services/payment-instructions/grpc/create-grpc-service');
+const createExecuteHandler = require('@app/services/payment-instructions/http/create-execute-handler');
+const createParseHandler = require('@app/services/payment-instructions/http/create-parse-handler');
+const {
+  toRequestMessage,
+  fromRequestMessage,
+  fromResponseMessage,
+  toStruct,
+  fromStruct,
+} = require('@app/services/payment-instructions/grpc/convert-messages');
+
+describe('payment-instructions: gRPC service', () => {
+  const options = {
+    now: new Date(Date.UTC(2025, 4, 28, 9, 0, 0)),
+    idGenerator: { next: () => 'txn_0m87f3k2a0001q9zd' },
+    feePolicy: { TRANSFER: { flat: 10 } },
+  };
+  const service = createGrpcService(options);
+  const handlers = {
+    '/parse': createParseHandler(options),
+    '/execute': createExecuteHandler(options),
+  };
+  let server;
+  let baseUrl;
+
+  before(() => {
+    server = http.createServer((req, res) => handlers[req.url](req, res));
+    return new Promise((resolve) => {
+      server.listen(0, () => {
+        baseUrl = `http://127.0.0.1:${server.address().port}`;
+        resolve();
+      });
+    });
+  });
+
+  after(() => new Promise((resolve) => server.close(resolve)));
+
+  function overHttp(path, payload) {
+    return fetch(`${baseUrl}${path}`, { method: 'POST', body: JSON.stringify(payload) })
+      .then((response) => response.json())
+      .then((body) => body.data);
+  }
+  function overGrpc(method, payload, metadata = {}) {
+    const call = {
+      request: toRequestMessage(payload),
+      metadata: { get: (key) => (metadata[key] === undefined ? [] : [metadata[key]]) },
+    };
+    return new Promise((resolve, reject) => {
+      service[method](call, (err, response) => (err ? reject(err) : resolve(response)));
+    });
+  }
+  const payload = (instruction, extra = {}) => ({
+    accounts: [
+      { id: 'acc1', balance: 500, currency: 'NGN', daily_limit: 1000 },
+      { id: 'acc2', balance: 0, currency: 'NGN', status: 'active' },
+      { id: 'usd1', balance: 100, currency: 'USD' },
+    ],
+    instruction,
+    ...extra,
+  });
+
+  it('returns the HTTP result for the same input, whatever its outcome', async () => {
+    const cases = [
+      payload('transfer 100 NGN from acc1 to acc2 for rent'),
+      payload('transfer 900 NGN from acc1 to acc2'),
+      payload('transfer abc'),
+      payload('schedule transfer 100 NGN from acc1 to acc2 on 2030-01-01'),
+      payload('transfer 100 NGN from acc1 to acc2', { dry_run: true, include_all_accounts: true }),
+      payload('transfer 5 USD from usd1 to acc2', { fx_rates: { 'USD/NGN': 1500 } }),
+      payload('pay 100 from rent to landlord', {
+        aliases: { rent: 'acc1', landlord: 'acc2' },
+        default_currency: 'NGN',
+      }),
+    ];
+    for (let i = 0; i < cases.length; i++) {
+      // eslint-disable-next-line no-await-in-loop
+      const viaGrpc = fromResponseMessage(await overGrpc('Execute', cases[i]));
+      // eslint-disable-next-line no-await-in-loop
+      assert.deepStrictEqual(viaGrpc, await overHttp('/execute', cases[i]), cases[i].instruction);
+    }
+    const parsed = payload('transfer 100 NGN from acc1 to acc2');
+    const viaGrpc = fromResponseMessage(await overGrpc('Parse', parsed));
+    assert.strictEqual(viaGrpc.status, 'parsed');
+    assert.deepStrictEqual(viaGrpc, await overHttp('/parse', parsed));
+  });
+
+  it('types the common fields and keeps nulls and the rest in details', async () => {
+    const response = await overGrpc('Execute', payload('transfer 100 NGN from acc1 to acc2'));
+    assert.deepStrictEqual(response.amount, { value: 100 });
+    assert.deepStrictEqual(response.accounts[0], {
+      id: 'acc1',
+      balance: 390,
+      balance_before: { value: 500 },
+      currency: 'NGN',
+    });
+    assert.strictEqual(response.execute_by, undefined);
+    assert.deepStrictEqual(fromStruct(response.details), {
+      execute_by: null,
+      fee: 10,
+      canonical_instruction: 'TRANSFER 100.00 NGN FROM acc1 TO acc2',
+      matched_verb: 'transfer',
+      confidence: 1,
+    });
+  });
+
+  it('leaves unset wrappers and empty maps out of the payload', () => {
+    const message = toRequestMessage(payload('transfer 1 NGN from acc1 to acc2'));
+    assert.deepStrictEqual(message.accounts[0].daily_limit, { value: 1000 });
+    assert.strictEqual(message.accounts[0].status, undefined);
+    assert.deepStrictEqual(
+      fromRequestMessage({ ...message, fx_rates: {}, aliases: {}, strict: null, locale: null }),
+      payload('transfer 1 NGN from acc1 to acc2')
+    );
+    const nested = { a: [1, 'b', null, { c: false }], d: { e: 0 } };
+    assert.deepStrictEqual(fromStruct(toStruct(nested)), nested);
+  });
+
+  it('takes the idempotency key from metadata', async () => {
+    const first = await overGrpc('Execute', payload('transfer 100 NGN from acc1 to acc2'), {
+      'idempotency-key': 'grpc-key-1',
+    });
+    const again = await overGrpc('Execute', payload('transfer 100 NGN from acc1 to acc2'), {
+      'idempotency-key': 'grpc-key-1',
+    });
+    assert.deepStrictEqual(fromResponseMessage(again), fromResponseMessage(first));
+    const reused = await overGrpc('Execute', payload('transfer 200 NGN from acc1 to acc2'), {
+      'idempotency-key': 'grpc-key-1',
+    }).catch((err) => err);
+    assert.strictEqual(reused.code, 6);
+  });
+
+  it('answers a malformed payload with INVALID_ARGUMENT', async () => {
+    const err = await overGrpc('Execute', { accounts: [], instruction: '' }).catch((e) => e);
+    assert.strictEqual(err.code, 3);
+    assert.strictEqual(typeof err.details, 'string');
+  });
+});
@@ -0,0 +1,127 @@
+const assert = require('assert');
+const http = require('http');
+const createBatchStreamHandler = require('@app/services/payment-instructions/http/create-batch-stream-handler');
+
+describe('payment-instructions: NDJSON batch stream handler', () => {
+  let server;
+  let port;
+
+  before(() => {
+    server = http.createServer(createBatchStreamHandler());
+    return new Promise((resolve) => {
+      server.listen(0, () => {
+        port = server.address().port;
+        resolve();
+      });
+    });
+  });
+
+  after(() => new Promise((resolve) => server.close(resolve)));
+
+  const header = {
+    accounts: [
+      { id: 'acc1', balance: 500, currency: 'NGN' },
+      { id: 'acc2', balance: 0, currency: 'NGN' },
+    ],
+  };
+  const line = (instruction) => JSON.stringify({ instruction });
+
+  function readLines(text) {
+    return text
+      .split('\n')
+      .filter((l) => l !== '')
+      .map((l) => JSON.parse(l));
+  }
+
+  it('streams one result per line in order, balances carrying over', async () => {
+    const body = [
+      JSON.stringify(header),
+      line('transfer 100 NGN from acc1 to acc2'),
+      line('transfer 300 NGN from acc1 to acc2'),
+      '',
+      line('transfer 200 NGN from acc1 to acc2'),
+      'not json',
+      JSON.stringify({
+        accounts: [
+          { id: 'x1', balance: 50, currency: 'USD' },
+          { id: 'x2', balance: 0, currency: 'USD' },
+        ],
+        instruction: 'transfer 50 USD from x1 to x2',
+      }),
+      line('transfer 100 NGN from acc1 to acc2'),
+    ].join('\n');
+    const response = await fetch(`http://127.0.0.1:${port}/`, {
+      method: 'POST',
+      headers: { 'content-type': 'application/x-ndjson' },
+      body,
+    });
+    assert.strictEqual(response.status, 200);
+    assert.strictEqual(response.headers.get('content-type'), 'application/x-ndjson; charset=utf-8');
+    const results = readLines(await response.text());
+    assert.deepStrictEqual(
+      results.map((r) => [r.line, r.status_code]),
+      [
+        [2, 'AP00'],
+        [3, 'AP00'],
+        [5, 'AC01'],
+        [6, 'SY03'],
+        [7, 'AP00'],
+        [8, 'AP00'],
+        [undefined, undefined],
+      ]
+    );
+    assert.deepStrictEqual(results[1].accounts.map((a) => [a.balance_before, a.balance]), [
+      [400, 100],
+      [100, 400],
+    ]);
+    assert.strictEqual(results[3].status_reason, 'Line is not a JSON object');
+    assert.deepStrictEqual(results[4].accounts.map((a) => a.id), ['x1', 'x2']);
+    assert.deepStrictEqual(results[6].accounts, [
+      { id: 'acc1', balance: 0, currency: 'NGN' },
+      { id: 'acc2', balance: 500, currency: 'NGN' },
+    ]);
+  });
+
+  it('answers each line before the next one is sent', async () => {
+    const received = await new Promise((resolve, reject) => {
+      const lines = [];
+      const req = http.request({ port, method: 'POST', path: '/' }, (res) => {
+        let pending = '';
+        res.setEncoding('utf8');
+        res.on('data', (chunk) => {
+          pending += chunk;
+          const parts = pending.split('\n');
+          pending = parts.pop();
+          parts.forEach((part) => {
+            lines.push(JSON.parse(part));
+            // Only send the second instruction once the first has come back
+            if (lines.length === 1) req.end(`${line('transfer 450 NGN from acc1 to acc2')}\n`);
+          });
+        });
+        res.on('end', () => resolve(lines));
+      });
+      req.on('error', reject);
+      req.write(`${JSON.stringify(header)}\n${line('transfer 100 NGN from acc1 to acc2')}\n`);
+    });
+    assert.deepStrictEqual(
+      received.map((r) => r.status_code),
+      ['AP00', 'AC01', undefined]
+    );
+    assert.strictEqual(received[1].line, 3);
+    assert.deepStrictEqual(received[2].accounts.map((a) => a.balance), [400, 100]);
+  });
+
+  it('fails an instruction with no account set to run on', async () => {
+    const response = await fetch(`http://127.0.0.1:${port}/`, {
+      method: 'POST',
+      body: line('transfer 100 NGN from acc1 to acc2'),
+    });
+    const results = readLines(await response.text());
+    assert.strictEqual(results.length, 1);
+    assert.strictEqual(results[0].status_code, 'SY03');
+    assert.strictEqual(
+      results[0].status_reason,
+      'Line has no accounts and no account set came before it'
+    );
+  });
+});
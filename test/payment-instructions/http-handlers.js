@@ -0,0 +1,105 @@
+const assert = require('assert');
+const http = require('http');
+const createParseHandler = require('@app/services/payment-instructions/http/create-parse-handler');
+const createExecuteHandler = require('@app/services/payment-instructions/http/create-execute-handler');
+
+describe('payment-instructions: HTTP handlers', () => {
+  let server;
+  let baseUrl;
+  const parseHandler = createParseHandler();
+  const executeHandler = createExecuteHandler();
+
+  before(() => {
+    // A router of our own, with a middleware in front of the handlers
+    server = http.createServer((req, res) => {
+      if (req.headers.authorization !== 'Bearer token') {
+        res.statusCode = 401;
+        res.end();
+      } else if (req.url === '/parse') {
+        parseHandler(req, res);
+      } else {
+        executeHandler(req, res);
+      }
+    });
+    return new Promise((resolve) => {
+      server.listen(0, () => {
+        baseUrl = `http://127.0.0.1:${server.address().port}`;
+        resolve();
+      });
+    });
+  });
+
+  after(() => new Promise((resolve) => server.close(resolve)));
+
+  function post(path, body) {
+    return fetch(`${baseUrl}${path}`, {
+      method: 'POST',
+      headers: { authorization: 'Bearer token', 'content-type': 'application/json' },
+      body: typeof body === 'string' ? body : JSON.stringify(body),
+    });
+  }
+  const payload = (instruction) => ({
+    accounts: [
+      { id: 'acc1', balance: 500, currency: 'NGN' },
+      { id: 'acc2', balance: 0, currency: 'NGN' },
+    ],
+    instruction,
+  });
+
+  it('executes an instruction with 200 and refuses one with 422', async () => {
+    const ok = await post('/execute', payload('transfer 100 NGN from acc1 to acc2'));
+    assert.strictEqual(ok.status, 200);
+    const okBody = await ok.json();
+    assert.strictEqual(okBody.status, 'success');
+    assert.strictEqual(okBody.data.status_code, 'AP00');
+    assert.deepStrictEqual(okBody.data.accounts.map((a) => a.balance), [400, 100]);
+
+    const failed = await post('/execute', payload('transfer 900 NGN from acc1 to acc2'));
+    assert.strictEqual(failed.status, 422);
+    const failedBody = await failed.json();
+    assert.strictEqual(failedBody.data.status, 'failed');
+    assert.strictEqual(failedBody.data.status_code, 'AC01');
+  });
+
+  it('parses without executing, with 200 or 400 and the parse error', async () => {
+    const ok = await post('/parse', payload('transfer 900 NGN from acc1 to acc2'));
+    assert.strictEqual(ok.status, 200);
+    const okBody = await ok.json();
+    assert.strictEqual(okBody.data.status, 'parsed');
+    assert.strictEqual(okBody.data.amount, 900);
+
+    const failed = await post('/parse', payload('transfer 100 NGN acc1 acc2'));
+    assert.strictEqual(failed.status, 400);
+    const failedBody = await failed.json();
+    assert.strictEqual(failedBody.data.status, 'failed');
+    assert.ok(failedBody.data.status_code.startsWith('SY'));
+    assert.ok(failedBody.data.parse_error);
+  });
+
+  it('answers a malformed body with a 400 error envelope', async () => {
+    const invalidJson = await post('/execute', '{"accounts": [');
+    assert.strictEqual(invalidJson.status, 400);
+    assert.strictEqual((await invalidJson.json()).status, 'error');
+    const missingAccounts = await post('/parse', { instruction: 'transfer 1 NGN from a to b' });
+    assert.strictEqual(missingAccounts.status, 400);
+    const body = await missingAccounts.json();
+    assert.strictEqual(body.status, 'error');
+    assert.strictEqual(typeof body.message, 'string');
+  });
+
+  it('uses a body already parsed by the router', async () => {
+    const req = { url: '/pay', headers: {}, body: payload('transfer 100 NGN from acc1 to acc2') };
+    const res = {
+      headers: {},
+      setHeader(name, value) {
+        this.headers[name] = value;
+      },
+      end(text) {
+        this.text = text;
+      },
+    };
+    await executeHandler(req, res);
+    assert.strictEqual(res.statusCode, 200);
+    assert.strictEqual(JSON.parse(res.text).data.status_code, 'AP00');
+  });
+});
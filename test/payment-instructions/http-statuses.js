@@ -0,0 +1,52 @@
+const assert = require('assert');
+const { HTTPStatusCode } = require('@app-core/server/enums');
+const {
+  StatusCodes,
+  StatusHttpStatuses,
+  getHttpStatus,
+} = require('@app/messages/payment-instruction-status-codes');
+const endpoint = require('../../endpoints/payment-instructions/payment-instructions');
+
+describe('payment-instructions: HTTP status per status code', () => {
+  function accounts() {
+    return [
+      { id: 'acc1', balance: 500, currency: 'NGN' },
+      { id: 'acc2', balance: 0, currency: 'NGN' },
+      { id: 'acc3', balance: 900, currency: 'NGN', status: 'frozen' },
+      { id: 'capped', balance: 500, currency: 'NGN', daily_limit: 300 },
+    ];
+  }
+  async function post(instruction) {
+    const rc = { body: { accounts: accounts(), instruction }, headers: {}, query: {} };
+    const response = await endpoint.handler(rc, { http_statuses: HTTPStatusCode });
+    return [response.status, response.data.status_code];
+  }
+
+  it('maps every code, with 400 for unknown ones', () => {
+    assert.deepStrictEqual(Object.keys(StatusHttpStatuses).sort(), Object.keys(StatusCodes).sort());
+    Object.keys(StatusHttpStatuses).forEach((code) => {
+      assert.ok([200, 400, 422].indexOf(getHttpStatus(code)) !== -1, code);
+    });
+    assert.strictEqual(getHttpStatus('ZZ99'), 400);
+  });
+
+  it('answers 400 for what cannot be read and 422 for what the rules refuse', async () => {
+    const cases = [
+      ['DEBIT 100 NGN FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc2', 200, 'AP00'],
+      ['transfer 100 NGN from acc1 to acc2 on 2999-01-01', 200, 'AP02'],
+      ['DEBIT NGN', 400, 'SY03'],
+      ['DEBIT 100 NGN FROM acc1', 400, 'SY02'],
+      ['DEBIT 100 XYZ FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc2', 400, 'CU04'],
+      ['transfer 100 to 200 NGN from acc1 to acc2', 400, 'AM04'],
+      ['transfer 400 NGN from capped to acc2', 422, 'LM01'],
+      ['transfer 900 NGN from acc2 to acc1', 422, 'AC01'],
+      ['transfer 100 NGN from acc3 to acc2', 422, 'AC08'],
+      ['transfer 100 NGN from acc1 to acc9', 422, 'AC03'],
+    ];
+    for (let i = 0; i < cases.length; i++) {
+      const [instruction, httpStatus, code] = cases[i];
+      // eslint-disable-next-line no-await-in-loop
+      assert.deepStrictEqual(await post(instruction), [httpStatus, code], instruction);
+    }
+  });
+});
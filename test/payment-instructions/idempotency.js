@@ -0,0 +1,106 @@
+const assert = require('assert');
+const processIdempotentInstruction = require('@app/services/payment-instructions/process-idempotent-instruction');
+const createMemoryIdempotencyStore = require('@app/services/payment-instructions/stores/create-memory-idempotency-store');
+const PaymentMessages = require('@app/messages/payment-instructions');
+
+describe('payment-instructions: idempotency keys', () => {
+  let clock;
+  let store;
+  const options = () => ({ idempotencyStore: store, idempotencyTtlMs: 60000 });
+  const request = (accounts, extra = {}) => ({
+    accounts,
+    instruction: 'DEBIT 100 NGN FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc2',
+    idempotency_key: 'retry-123',
+    ...extra,
+  });
+  const fresh = () => [
+    { id: 'acc1', balance: 500, currency: 'NGN' },
+    { id: 'acc2', balance: 0, currency: 'NGN' },
+  ];
+
+  beforeEach(() => {
+    clock = 1000;
+    store = createMemoryIdempotencyStore({ now: () => clock });
+  });
+
+  it('replays the stored result for a retried key', async () => {
+    const first = await processIdempotentInstruction(request(fresh()), options());
+    const replay = await processIdempotentInstruction(request(fresh()), options());
+    assert.strictEqual(first.status_code, 'AP00');
+    assert.deepStrictEqual(replay, first);
+    assert.strictEqual(store.size(), 1);
+  });
+
+  it('rejects the same key with a different instruction', async () => {
+    await processIdempotentInstruction(request(fresh()), options());
+    const changed = request(fresh(), {
+      instruction: 'DEBIT 200 NGN FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc2',
+    });
+    await assert.rejects(
+      () => processIdempotentInstruction(changed, options()),
+      (err) => err.errorCode === 'DUPLICATE_RECORD'
+    );
+  });
+
+  it('executes again once the key has expired', async () => {
+    const accounts = fresh();
+    await processIdempotentInstruction(request(accounts), options());
+    clock += 60001;
+    accounts[0].balance = 400;
+    const second = await processIdempotentInstruction(request(accounts), options());
+    assert.strictEqual(second.accounts[0].balance_before, 400);
+    assert.strictEqual(second.accounts[0].balance, 300);
+  });
+
+  it('purges expired keys', async () => {
+    await processIdempotentInstruction(request(fresh()), options());
+    await processIdempotentInstruction(request(fresh(), { idempotency_key: 'other' }), options());
+    clock += 60001;
+    assert.strictEqual(store.purgeExpired(), 2);
+    assert.strictEqual(store.size(), 0);
+  });
+
+  it('does not store anything without a key', async () => {
+    const payload = request(fresh());
+    delete payload.idempotency_key;
+    await processIdempotentInstruction(payload, options());
+    assert.strictEqual(store.size(), 0);
+  });
+
+  it('executes once when a retry arrives before the first call has finished', async () => {
+    let received = 0;
+    const observer = {
+      notify: (event) => {
+        if (event === 'instruction.received') received++;
+      },
+    };
+    const first = processIdempotentInstruction(request(fresh()), { ...options(), observer });
+    const retry = processIdempotentInstruction(request(fresh()), { ...options(), observer });
+    const [firstResult, retryResult] = await Promise.all([first, retry]);
+    assert.strictEqual(received, 1);
+    assert.strictEqual(firstResult.status_code, 'AP00');
+    assert.deepStrictEqual(retryResult, firstResult);
+  });
+
+  it('refuses a retry while another process sharing the store holds the key', async () => {
+    // Two processes: separate service state, one store behind both
+    const processA = { ...store };
+    const processB = { ...store };
+    const first = processIdempotentInstruction(request(fresh()), {
+      ...options(),
+      idempotencyStore: processA,
+    });
+    await assert.rejects(
+      processIdempotentInstruction(request(fresh()), { ...options(), idempotencyStore: processB }),
+      (err) =>
+        err.errorCode === 'DUPLICATE_RECORD' &&
+        err.message === PaymentMessages.IDEMPOTENCY_KEY_IN_PROGRESS
+    );
+    const firstResult = await first;
+    const later = await processIdempotentInstruction(request(fresh()), {
+      ...options(),
+      idempotencyStore: processB,
+    });
+    assert.deepStrictEqual(later, firstResult);
+  });
+});
@@ -0,0 +1,69 @@
+const assert = require('assert');
+const paymentInstructions = require('@app/services/payment-instructions/payment-instructions');
+const { parseAmount } = require('@app/services/payment-instructions/helpers');
+const indianAmountParser = require('@app/services/payment-instructions/amount-parsers/indian-amount-parser');
+const amountParsersForLocale = require('@app/services/payment-instructions/amount-parsers/locale-amount-parsers');
+const DEFAULT_AMOUNT_PARSERS = require('@app/services/payment-instructions/amount-parsers/default-amount-parsers');
+
+describe('payment-instructions: Indian lakh and crore amounts', () => {
+  const parsers = amountParsersForLocale('en-IN');
+  function run(instruction, locale = 'en-IN') {
+    return paymentInstructions({
+      accounts: [
+        { id: 'acc1', balance: 50000000, currency: 'NGN' },
+        { id: 'acc2', balance: 0, currency: 'NGN' },
+      ],
+      instruction,
+      locale,
+    });
+  }
+
+  it('reads "1,00,000", "2 lakh" and "1.5 crore" under an Indian locale', async () => {
+    const grouped = await run('transfer 1,00,000 NGN from acc1 to acc2');
+    assert.strictEqual(grouped.status_code, 'AP00');
+    assert.strictEqual(grouped.amount, 100000);
+    const instruction = 'DEBIT 2 lakh NGN FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc2';
+    const lakh = await run(instruction, 'hi-IN');
+    assert.strictEqual(lakh.amount, 200000);
+    assert.deepStrictEqual(lakh.accounts.map((a) => a.balance), [49800000, 200000]);
+    const crore = await run('transfer 1.5 crore NGN from acc1 to acc2');
+    assert.strictEqual(crore.amount, 15000000);
+    const before = await run('withdraw NGN 2 lakh from acc1');
+    assert.strictEqual(before.amount, 200000);
+  });
+
+  it('parses Indian grouping and scale words to the exact integer', () => {
+    const cases = [
+      [['1,00,000'], 100000, 1],
+      [['12,34,56,789'], 123456789, 1],
+      [['1,50,00,000.50'], 15000000.5, 1],
+      [['2lakh'], 200000, 1],
+      [['3', 'lacs', 'NGN'], 300000, 2],
+      [['1.5', 'crore', 'NGN'], 15000000, 2],
+      [['12,345', 'crores', 'NGN'], 123450000000, 2],
+    ];
+    for (let i = 0; i < cases.length; i++) {
+      const [tokens, amount, consumed] = cases[i];
+      assert.deepStrictEqual(parseAmount(tokens, 0, '.', parsers), { amount, consumed }, tokens[0]);
+    }
+    assert.strictEqual(indianAmountParser.parse(['1,0,000'], 0, { decimalSeparator: '.' }), null);
+    assert.strictEqual(indianAmountParser.parse(['2', 'lakh'], 0, { decimalSeparator: ',' }), null);
+  });
+
+  it('leaves Western grouping and other locales alone', async () => {
+    assert.strictEqual(amountParsersForLocale('en-GB'), DEFAULT_AMOUNT_PARSERS);
+    assert.strictEqual(amountParsersForLocale(undefined), DEFAULT_AMOUNT_PARSERS);
+    assert.deepStrictEqual(parseAmount(['100,000'], 0, '.', parsers), {
+      amount: 100000,
+      consumed: 1,
+    });
+    assert.deepStrictEqual(parseAmount(['5', 'k', 'NGN'], 0, '.', parsers), {
+      amount: 5000,
+      consumed: 2,
+    });
+    const western = await run('transfer 1,00,000 NGN from acc1 to acc2', 'en-GB');
+    assert.strictEqual(western.status_code, 'AM01');
+    const unscaled = await run('transfer 2 lakh NGN from acc1 to acc2', 'fr');
+    assert.notStrictEqual(unscaled.status_code, 'AP00');
+  });
+});
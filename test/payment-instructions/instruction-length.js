@@ -0,0 +1,53 @@
+const assert = require('assert');
+const paymentInstructions = require('@app/services/payment-instructions/payment-instructions');
+const { MAX_INSTRUCTION_LENGTH } = require('@app/services/payment-instructions/helpers');
+
+describe('payment-instructions: instruction length cap', () => {
+  const base = 'DEBIT 100 NGN FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc2 for ';
+  function makeAccounts() {
+    return [
+      { id: 'acc1', balance: 1000, currency: 'NGN' },
+      { id: 'acc2', balance: 0, currency: 'NGN' },
+    ];
+  }
+  // The instruction padded with narration to exactly `length` characters
+  function ofLength(length) {
+    return base + 'x'.repeat(length - base.length);
+  }
+  function run(instruction, options = {}) {
+    return paymentInstructions({ accounts: makeAccounts(), instruction }, options);
+  }
+
+  it('reads an instruction of exactly the default 512 characters', async () => {
+    assert.strictEqual(MAX_INSTRUCTION_LENGTH, 512);
+    const result = await run(ofLength(512));
+    assert.strictEqual(result.status_code, 'AP00');
+  });
+
+  it('fails one character over with SY04 before parsing', async () => {
+    const result = await run(ofLength(513));
+    assert.strictEqual(result.status, 'failed');
+    assert.strictEqual(result.status_code, 'SY04');
+    assert.strictEqual(
+      result.status_reason,
+      'Instruction is too long: 513 characters, limit is 512'
+    );
+    assert.strictEqual(result.type, null);
+    assert.deepStrictEqual(result.accounts, []);
+  });
+
+  it('does not count the whitespace the instruction is trimmed of', async () => {
+    const result = await run(`   ${ofLength(512)}   `);
+    assert.strictEqual(result.status_code, 'AP00');
+  });
+
+  it('takes its limit from options.maxInstructionLength', async () => {
+    const instruction = 'DEBIT 100 NGN FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc2';
+    const at = await run(instruction, { maxInstructionLength: instruction.length });
+    assert.strictEqual(at.status_code, 'AP00');
+    const over = await run(instruction, { maxInstructionLength: instruction.length - 1 });
+    assert.strictEqual(over.status_code, 'SY04');
+    const longer = await run(ofLength(2000), { maxInstructionLength: 4096 });
+    assert.strictEqual(longer.status_code, 'AP00');
+  });
+});
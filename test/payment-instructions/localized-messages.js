@@ -0,0 +1,60 @@
+const assert = require('assert');
+const paymentInstructions = require('@app/services/payment-instructions/payment-instructions');
+const processBatch = require('@app/services/payment-instructions/process-batch');
+const createMessageResolver = require('@app/services/payment-instructions/message-resolvers/create-message-resolver');
+
+describe('payment-instructions: localized messages', () => {
+  const messageResolver = createMessageResolver({
+    fr: {
+      AP00: 'Transaction effectuée avec succès',
+      AC01: 'Fonds insuffisants sur le compte débiteur',
+    },
+    sw: { AP00: 'Muamala umekamilika' },
+  });
+  const accounts = () => [
+    { id: 'acc1', balance: 500, currency: 'NGN' },
+    { id: 'acc2', balance: 0, currency: 'NGN' },
+  ];
+  const debit = (amount) => `DEBIT ${amount} NGN FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc2`;
+
+  it('gives French reasons for a French locale and keeps the code', async () => {
+    const ok = await paymentInstructions(
+      { accounts: accounts(), instruction: debit(100), locale: 'fr' },
+      { messageResolver }
+    );
+    assert.strictEqual(ok.status_code, 'AP00');
+    assert.strictEqual(ok.status_reason, 'Transaction effectuée avec succès');
+    const declined = await paymentInstructions(
+      { accounts: accounts(), instruction: debit(900), locale: 'fr-CA' },
+      { messageResolver }
+    );
+    assert.strictEqual(declined.status_code, 'AC01');
+    assert.strictEqual(declined.status_reason, 'Fonds insuffisants sur le compte débiteur');
+  });
+
+  it('falls back to English for an unknown locale or a missing code', async () => {
+    const unknown = await paymentInstructions(
+      { accounts: accounts(), instruction: debit(100), locale: 'de' },
+      { messageResolver }
+    );
+    assert.strictEqual(unknown.status_reason, 'Transaction executed successfully');
+    const missing = await paymentInstructions(
+      { accounts: accounts(), instruction: debit(900) },
+      { messageResolver, locale: 'sw' }
+    );
+    assert.strictEqual(missing.status_code, 'AC01');
+    assert.ok(missing.status_reason.startsWith('Insufficient funds in debit account'));
+    assert.strictEqual(messageResolver.resolve('AP00', 'sw-KE'), 'Muamala umekamilika');
+  });
+
+  it('localizes every batch result', async () => {
+    const result = await processBatch(
+      { accounts: accounts(), instructions: [debit(100), debit(900)], locale: 'fr' },
+      { messageResolver }
+    );
+    assert.deepStrictEqual(
+      result.results.map((r) => r.status_reason),
+      ['Transaction effectuée avec succès', 'Fonds insuffisants sur le compte débiteur']
+    );
+  });
+});
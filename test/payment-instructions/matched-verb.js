@@ -0,0 +1,50 @@
+const assert = require('assert');
+const paymentInstructions = require('@app/services/payment-instructions/payment-instructions');
+
+describe('payment-instructions: matched_verb', () => {
+  function makeAccounts() {
+    return [
+      { id: 'acc1', balance: 1000, currency: 'NGN' },
+      { id: 'acc2', balance: 0, currency: 'NGN' },
+      { id: 'acc3', balance: 0, currency: 'NGN' },
+    ];
+  }
+  function run(instruction, extra = {}) {
+    return paymentInstructions({ accounts: makeAccounts(), instruction, ...extra });
+  }
+
+  it('reports a synonym next to the type it reads as', async () => {
+    const result = await run('wire 100 NGN from acc1 to acc2');
+    assert.strictEqual(result.status_code, 'AP00');
+    assert.strictEqual(result.matched_verb, 'wire');
+    assert.strictEqual(result.type, 'TRANSFER');
+  });
+
+  it('reports the verb as written in every flow', async () => {
+    const cases = [
+      ['DEBIT 100 NGN FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc2', 'debit', 'DEBIT'],
+      ['schedule send of 100 NGN from acc1 to acc2 on 2099-01-01', 'send', 'SCHEDULE'],
+      ['remit 100 NGN to acc2 from acc1 and acc3', 'remit', 'MULTI_DEBIT'],
+      ['split 100 NGN from acc1 equally between acc2 and acc3', 'split', 'SPLIT'],
+      ['Withdraw 100 NGN from acc1', 'withdraw', 'WITHDRAW'],
+      ['pay 100 NGN to DSTV from acc1', 'pay', 'PAY'],
+    ];
+    for (let i = 0; i < cases.length; i++) {
+      const [instruction, verb, type] = cases[i];
+      // eslint-disable-next-line no-await-in-loop
+      const result = await run(instruction);
+      assert.deepStrictEqual([result.matched_verb, result.type], [verb, type], instruction);
+    }
+  });
+
+  it('reports a corrected typo as corrected, and no verb for a standing order', async () => {
+    const fuzzy = await run('trasnfer 100 NGN from acc1 to acc2', { fuzzy_keywords: true });
+    assert.strictEqual(fuzzy.matched_verb, 'transfer');
+    const standing = await run('STANDING ORDER 100 NGN FROM acc1 TO acc2 daily');
+    assert.strictEqual(standing.type, 'STANDING_ORDER');
+    assert.strictEqual('matched_verb' in standing, false);
+    const unknown = await run('hello 100 NGN from acc1 to acc2');
+    assert.strictEqual(unknown.status_code, 'SY01');
+    assert.strictEqual('matched_verb' in unknown, false);
+  });
+});
@@ -0,0 +1,81 @@
+const assert = require('assert');
+const paymentInstructions = require('@app/services/payment-instructions/payment-instructions');
+const createMemoryDailyDebitStore = require('@app/services/payment-instructions/stores/create-memory-daily-debit-store');
+
+describe('payment-instructions: per-transaction maximum amount', () => {
+  const NOW = Date.UTC(2025, 2, 12, 15, 30);
+  const maxAmountPolicy = {
+    DEBIT: { NGN: 500000, USD: 1000 },
+    SPLIT: { NGN: 100000 },
+    WITHDRAW: { NGN: 20000 },
+  };
+
+  function makeAccounts() {
+    return [
+      { id: 'acc1', balance: 1000000, currency: 'NGN' },
+      { id: 'acc2', balance: 0, currency: 'NGN' },
+      { id: 'acc3', balance: 0, currency: 'NGN' },
+      { id: 'usd1', balance: 5000, currency: 'USD' },
+      { id: 'usd2', balance: 0, currency: 'USD' },
+    ];
+  }
+  function run(instruction, extraOptions = {}) {
+    return paymentInstructions(
+      { accounts: makeAccounts(), instruction },
+      { now: NOW, dailyDebitStore: createMemoryDailyDebitStore(), maxAmountPolicy, ...extraOptions }
+    );
+  }
+
+  it('allows an amount exactly at the cap', async () => {
+    const result = await run('DEBIT 500000 NGN FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc2');
+    assert.strictEqual(result.status_code, 'AP00');
+    assert.strictEqual(result.accounts[0].balance, 500000);
+  });
+
+  it('blocks an amount over the cap with LM02 and moves nothing', async () => {
+    const result = await run('DEBIT 500000.01 NGN FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc2');
+    assert.strictEqual(result.status, 'failed');
+    assert.strictEqual(result.status_code, 'LM02');
+    assert.strictEqual(
+      result.status_reason,
+      'Amount exceeds per-transaction limit: limit is 500000 NGN for DEBIT'
+    );
+    assert.deepStrictEqual(result.accounts, [
+      { id: 'acc1', balance: 1000000, balance_before: 1000000, currency: 'NGN' },
+      { id: 'acc2', balance: 0, balance_before: 0, currency: 'NGN' },
+    ]);
+  });
+
+  it('caps each currency separately and leaves types without an entry uncapped', async () => {
+    const usd = await run('DEBIT 1001 USD FROM ACCOUNT usd1 FOR CREDIT TO ACCOUNT usd2');
+    assert.strictEqual(usd.status_code, 'LM02');
+    const credit = await run('CREDIT 900000 NGN TO ACCOUNT acc2 FOR DEBIT FROM ACCOUNT acc1');
+    assert.strictEqual(credit.status_code, 'AP00');
+    const uncapped = await run('DEBIT 900000 NGN FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc2', {
+      maxAmountPolicy: { DEBIT: { USD: 1000 } },
+    });
+    assert.strictEqual(uncapped.status_code, 'AP00');
+  });
+
+  it('checks the cap before the balance and for scheduled transfers', async () => {
+    const overBalance = await run('DEBIT 2000 USD FROM ACCOUNT usd1 FOR CREDIT TO ACCOUNT usd2', {
+      maxAmountPolicy: { DEBIT: { USD: 1500 } },
+    });
+    assert.strictEqual(overBalance.status_code, 'LM02');
+    const pending = await run(
+      'DEBIT 600000 NGN FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc2 ON 2025-04-01'
+    );
+    assert.strictEqual(pending.status_code, 'LM02');
+  });
+
+  it('applies to SPLIT totals and cash withdrawals', async () => {
+    const split = await run('SPLIT 100000.5 NGN FROM acc1 EQUALLY BETWEEN acc2 AND acc3');
+    assert.strictEqual(split.status_code, 'LM02');
+    assert.ok(split.accounts.every((a) => a.balance === a.balance_before));
+    const atCap = await run('SPLIT 100000 NGN FROM acc1 EQUALLY BETWEEN acc2 AND acc3');
+    assert.strictEqual(atCap.status_code, 'AP00');
+    const withdrawal = await run('WITHDRAW 20001 NGN FROM acc1');
+    assert.strictEqual(withdrawal.status_code, 'LM02');
+    assert.strictEqual(withdrawal.accounts[0].balance, 1000000);
+  });
+});
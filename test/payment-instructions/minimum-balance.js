@@ -0,0 +1,54 @@
+const assert = require('assert');
+const paymentInstructions = require('@app/services/payment-instructions/payment-instructions');
+
+describe('payment-instructions: minimum balance', () => {
+  function makeAccounts() {
+    return [
+      { id: 'acc1', balance: 1000, currency: 'NGN', minimum_balance: 250 },
+      { id: 'acc2', balance: 50, currency: 'NGN' },
+    ];
+  }
+  function run(amount, accounts = makeAccounts()) {
+    const instruction = `DEBIT ${amount} NGN FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc2`;
+    return paymentInstructions({ accounts, instruction });
+  }
+
+  it('allows a debit exactly down to the floor', async () => {
+    const result = await run(750);
+    assert.strictEqual(result.status_code, 'AP00');
+    assert.deepStrictEqual(result.accounts, [
+      { id: 'acc1', balance: 250, balance_before: 1000, currency: 'NGN' },
+      { id: 'acc2', balance: 800, balance_before: 50, currency: 'NGN' },
+    ]);
+  });
+
+  it('fails with BL02 below the floor and leaves balances untouched', async () => {
+    const accounts = makeAccounts();
+    const result = await run(751, accounts);
+    assert.strictEqual(result.status, 'failed');
+    assert.strictEqual(result.status_code, 'BL02');
+    assert.strictEqual(
+      result.status_reason,
+      'Debit would take the account below its minimum balance: floor is 250 NGN, balance would be 249 NGN'
+    );
+    assert.deepStrictEqual(result.accounts, [
+      { id: 'acc1', balance: 1000, balance_before: 1000, currency: 'NGN' },
+      { id: 'acc2', balance: 50, balance_before: 50, currency: 'NGN' },
+    ]);
+    assert.deepStrictEqual(accounts, makeAccounts());
+  });
+
+  it('takes precedence over an overdraft allowance', async () => {
+    const accounts = makeAccounts();
+    accounts[0].overdraft_limit = 500;
+    const result = await run(1200, accounts);
+    assert.strictEqual(result.status_code, 'BL02');
+  });
+
+  it('still reports AC01 for accounts without a floor', async () => {
+    const accounts = makeAccounts();
+    delete accounts[0].minimum_balance;
+    const result = await run(1001, accounts);
+    assert.strictEqual(result.status_code, 'AC01');
+  });
+});
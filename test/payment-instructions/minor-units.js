@@ -0,0 +1,87 @@
+const assert = require('assert');
+const paymentInstructions = require('@app/services/payment-instructions/payment-instructions');
+const processBatch = require('@app/services/payment-instructions/process-batch');
+
+describe('payment-instructions: minor-unit arithmetic', () => {
+  it('adds 0.1 and 0.2 exactly', async () => {
+    const result = await processBatch({
+      accounts: [
+        { id: 'a', balance: 1, currency: 'USD' },
+        { id: 'b', balance: 0.1, currency: 'USD' },
+      ],
+      instructions: ['DEBIT 0.2 USD FROM ACCOUNT a FOR CREDIT TO ACCOUNT b'],
+    });
+    assert.deepStrictEqual(result.accounts.map((a) => a.balance), [0.8, 0.3]);
+  });
+
+  it('conserves the total to the minor unit over 1000 transfers', async () => {
+    const instructions = [];
+    for (let i = 0; i < 1000; i++) {
+      instructions.push(
+        i % 3 === 2
+          ? 'DEBIT 0.07 NGN FROM ACCOUNT b FOR CREDIT TO ACCOUNT a'
+          : 'DEBIT 0.1 NGN FROM ACCOUNT a FOR CREDIT TO ACCOUNT b'
+      );
+    }
+    const result = await processBatch({
+      accounts: [
+        { id: 'a', balance: 100, currency: 'NGN' },
+        { id: 'b', balance: 0.01, currency: 'NGN' },
+      ],
+      instructions,
+    });
+    const failed = result.results.filter((r) => r.status !== 'successful');
+    assert.strictEqual(failed.length, 0);
+    // 667 debits of 0.10 out of a, 333 of 0.07 back into it
+    assert.deepStrictEqual(result.accounts.map((a) => a.balance), [56.61, 43.4]);
+  });
+
+  it('reports exact balances in the status reason', async () => {
+    const result = await paymentInstructions({
+      accounts: [
+        { id: 'a', balance: 0.3, currency: 'USD', minimum_balance: 0.2 },
+        { id: 'b', balance: 0, currency: 'USD' },
+      ],
+      instruction: 'DEBIT 0.11 USD FROM ACCOUNT a FOR CREDIT TO ACCOUNT b',
+    });
+    assert.strictEqual(result.status_code, 'BL02');
+    assert.ok(result.status_reason.endsWith('balance would be 0.19 USD'), result.status_reason);
+  });
+
+  it('moves whole units of a zero-decimal currency', async () => {
+    const accounts = [
+      { id: 'tokyo', balance: 5000, currency: 'JPY' },
+      { id: 'osaka', balance: 0, currency: 'JPY' },
+    ];
+    const whole = await paymentInstructions({
+      accounts,
+      instruction: 'DEBIT ¥1200 FROM ACCOUNT tokyo FOR CREDIT TO ACCOUNT osaka',
+    });
+    assert.strictEqual(whole.currency, 'JPY');
+    assert.deepStrictEqual(whole.accounts.map((a) => a.balance), [3800, 1200]);
+    const fractional = await paymentInstructions({
+      accounts,
+      instruction: 'DEBIT 10.5 JPY FROM ACCOUNT tokyo FOR CREDIT TO ACCOUNT osaka',
+    });
+    assert.strictEqual(fractional.status_code, 'CU03');
+  });
+
+  it('keeps three decimals for a three-decimal currency', async () => {
+    const result = await processBatch({
+      accounts: [
+        { id: 'kw1', balance: 10, currency: 'KWD' },
+        { id: 'kw2', balance: 0.001, currency: 'KWD' },
+      ],
+      instructions: [
+        'DEBIT 1.125 KWD FROM ACCOUNT kw1 FOR CREDIT TO ACCOUNT kw2',
+        'DEBIT 2.002 dinars FROM ACCOUNT kw1 FOR CREDIT TO ACCOUNT kw2',
+        'DEBIT 0.0005 KWD FROM ACCOUNT kw1 FOR CREDIT TO ACCOUNT kw2',
+      ],
+    });
+    assert.deepStrictEqual(
+      result.results.map((r) => r.status_code),
+      ['AP00', 'AP00', 'CU03']
+    );
+    assert.deepStrictEqual(result.accounts.map((a) => a.balance), [6.873, 3.128]);
+  });
+});
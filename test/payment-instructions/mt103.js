@@ -0,0 +1,88 @@
+const assert = require('assert');
+const fs = require('fs');
+const path = require('path');
+const paymentInstructions = require('@app/services/payment-instructions/payment-instructions');
+const toMt103 = require('@app/services/payment-instructions/exporters/to-mt103');
+
+describe('payment-instructions: MT103 export', () => {
+  const NOW = Date.UTC(2025, 2, 12);
+  const idGenerator = { next: () => 'txn_0m87f3k2a0001q9zd' };
+
+  function run(instruction, accounts) {
+    return paymentInstructions(
+      {
+        accounts: accounts || [
+          { id: 'acc-001', balance: 5000, currency: 'USD' },
+          { id: 'acc-002', balance: 0, currency: 'USD' },
+        ],
+        instruction,
+      },
+      { now: NOW, idGenerator }
+    );
+  }
+
+  it('renders a representative transfer like the golden file', async () => {
+    const result = await run(
+      'DEBIT 1250.5 USD FROM ACCOUNT acc-001 FOR CREDIT TO ACCOUNT acc-002 ON 2025-04-01 ' +
+        'for invoice 2025-118 (March consulting, second instalment)'
+    );
+    const mt103 = toMt103(result, {
+      now: NOW,
+      senderBic: 'ABCDNGLA',
+      receiverBic: 'WXYZUS33XXX',
+      orderingCustomer: 'Ada Lovelace Ltd',
+      beneficiary: 'Northwind Traders Inc',
+    });
+    const golden = fs.readFileSync(path.join(__dirname, 'golden/mt103-transfer.txt'), 'utf8');
+    // SWIFT lines end in CRLF; the golden file is stored with plain newlines
+    assert.strictEqual(mt103.split('\r\n').join('\n'), golden.trimEnd());
+    assert.strictEqual(mt103.split('\r\n').join('').indexOf('\n'), -1);
+  });
+
+  it('takes the value date from now without execute_by and formats minor units', async () => {
+    const result = await run('DEBIT 1000 JPY FROM ACCOUNT j1 FOR CREDIT TO ACCOUNT j2', [
+      { id: 'j1', balance: 5000, currency: 'JPY' },
+      { id: 'j2', balance: 0, currency: 'JPY' },
+    ]);
+    const lines = toMt103(result, { now: NOW }).split('\r\n');
+    assert.strictEqual(lines[0], '{4:');
+    assert.strictEqual(lines[3], ':32A:250312JPY1000,');
+    assert.deepStrictEqual(lines.slice(4, 8), [':50K:/j1', 'j1', ':59:/j2', 'j2']);
+    assert.strictEqual(lines[lines.length - 1], '-}');
+  });
+
+  it('truncates an overlong narration to 4 lines of 35, or refuses it', async () => {
+    const narration = 'monthly office rent and service charge '.repeat(4).trim();
+    const result = await run(
+      `DEBIT 10 USD FROM ACCOUNT acc-001 FOR CREDIT TO ACCOUNT acc-002 for ${narration}`
+    );
+    const lines = toMt103(result, { now: NOW }).split('\r\n');
+    const start = lines.findIndex((line) => line.startsWith(':70:'));
+    const narrative = lines.slice(start, start + 4);
+    assert.strictEqual(lines[start + 4], ':71A:SHA');
+    narrative.forEach((line, i) => assert.ok((i === 0 ? line.substring(4) : line).length <= 35));
+    assert.throws(
+      () => toMt103(result, { now: NOW, truncateNarration: false }),
+      /Too long for SWIFT field: :70:/
+    );
+  });
+
+  it('rejects characters outside the SWIFT set and overlong fields', async () => {
+    const accounts = [
+      { id: 'ada@bank', balance: 5000, currency: 'USD' },
+      { id: 'acc-002', balance: 0, currency: 'USD' },
+    ];
+    const atSign = await run(
+      'DEBIT 10 USD FROM ACCOUNT ada@bank FOR CREDIT TO ACCOUNT acc-002',
+      accounts
+    );
+    assert.throws(() => toMt103(atSign), /Character not allowed in SWIFT field.*: :50K:/);
+    const result = await run('DEBIT 10 USD FROM ACCOUNT acc-001 FOR CREDIT TO ACCOUNT acc-002');
+    assert.throws(
+      () => toMt103(result, { reference: 'REF-2025-0000000001' }),
+      /Too long for SWIFT field: :20:/
+    );
+    assert.throws(() => toMt103(result, { beneficiary: 'Zoë' }), /: :59:/);
+    assert.throws(() => toMt103(result, { charges: 'ALL' }), /Charges must be OUR, SHA or BEN/);
+  });
+});
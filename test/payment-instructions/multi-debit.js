@@ -0,0 +1,103 @@
+const assert = require('assert');
+const paymentInstructions = require('@app/services/payment-instructions/payment-instructions');
+const createMemoryDailyDebitStore = require('@app/services/payment-instructions/stores/create-memory-daily-debit-store');
+
+describe('payment-instructions: multi-debit TRANSFER', () => {
+  function makeAccounts() {
+    return [
+      { id: 'acc1', balance: 6000, currency: 'NGN' },
+      { id: 'acc2', balance: 8000, currency: 'NGN' },
+      { id: 'acc3', balance: 500, currency: 'NGN' },
+      { id: 'acc4', balance: 0, currency: 'USD' },
+    ];
+  }
+  function run(instruction, accounts = makeAccounts(), options = {}) {
+    return paymentInstructions({ accounts, instruction }, options);
+  }
+
+  it('draws on the debit accounts in order and credits the total once', async () => {
+    const result = await run('transfer 10000 NGN to acc3 from acc1 and acc2');
+    assert.strictEqual(result.type, 'MULTI_DEBIT');
+    assert.strictEqual(result.status_code, 'AP00');
+    assert.strictEqual(result.debit_account, null);
+    assert.strictEqual(result.credit_account, 'acc3');
+    assert.deepStrictEqual(result.debits, [
+      { account: 'acc1', amount: 6000 },
+      { account: 'acc2', amount: 4000 },
+    ]);
+    assert.deepStrictEqual(result.accounts, [
+      { id: 'acc1', balance: 0, balance_before: 6000, currency: 'NGN' },
+      { id: 'acc2', balance: 4000, balance_before: 8000, currency: 'NGN' },
+      { id: 'acc3', balance: 10500, balance_before: 500, currency: 'NGN' },
+    ]);
+  });
+
+  it('stops drawing once the amount is covered', async () => {
+    const result = await run('TRANSFER OF 5000 NGN TO ACCOUNT acc3 FROM ACCOUNT acc2, acc1');
+    assert.deepStrictEqual(result.debits, [{ account: 'acc2', amount: 5000 }]);
+    assert.strictEqual(result.accounts[0].balance, 6000);
+    assert.strictEqual(result.accounts[1].balance, 3000);
+  });
+
+  it('fails atomically when the accounts together fall short', async () => {
+    const result = await run('transfer 15000 NGN to acc3 from acc1 and acc2');
+    assert.strictEqual(result.status, 'failed');
+    assert.strictEqual(result.status_code, 'AC01');
+    assert.strictEqual(
+      result.status_reason,
+      'Insufficient funds across debit accounts: has 14000 NGN across acc1, acc2, needs 15000 NGN'
+    );
+    assert.deepStrictEqual(result.debits, []);
+    for (let i = 0; i < result.accounts.length; i++) {
+      assert.strictEqual(result.accounts[i].balance, result.accounts[i].balance_before);
+    }
+  });
+
+  it('keeps each account above its minimum balance and within its daily limit', async () => {
+    const accounts = makeAccounts();
+    accounts[0].minimum_balance = 1000;
+    accounts[1].daily_limit = 2000;
+    const store = createMemoryDailyDebitStore();
+    const result = await run('transfer 7000 NGN to acc3 from acc1 and acc2', accounts, {
+      dailyDebitStore: store,
+    });
+    assert.deepStrictEqual(result.debits, [
+      { account: 'acc1', amount: 5000 },
+      { account: 'acc2', amount: 2000 },
+    ]);
+    // A single account after FROM is a plain TRANSFER, held to the same daily limit
+    const again = await run('transfer 100 NGN to acc3 from acc2', accounts, {
+      dailyDebitStore: store,
+    });
+    assert.strictEqual(again.type, 'TRANSFER');
+    assert.strictEqual(again.status_code, 'LM01');
+  });
+
+  it('reads a single account after FROM as a plain TRANSFER', async () => {
+    const result = await run('transfer 100 NGN to acc3 from acc1 for rent');
+    assert.strictEqual(result.type, 'TRANSFER');
+    assert.strictEqual(result.status_code, 'AP00');
+    assert.strictEqual(result.debit_account, 'acc1');
+    assert.strictEqual(result.credit_account, 'acc3');
+    assert.strictEqual(result.narration, 'rent');
+    assert.strictEqual(result.debits, undefined);
+  });
+
+  it('rejects repeated accounts and mixed currencies', async () => {
+    const repeated = await run('transfer 100 NGN to acc3 from acc1 and acc1');
+    assert.strictEqual(repeated.status_code, 'AC02');
+    const selfCredit = await run('transfer 100 NGN to acc3 from acc1 and acc3');
+    assert.strictEqual(selfCredit.status_code, 'AC02');
+    const mixed = await run('transfer 100 NGN to acc4 from acc1');
+    assert.strictEqual(mixed.status_code, 'CU01');
+  });
+
+  it('previews the draws on a dry run', async () => {
+    const result = await run('transfer 7000 NGN to acc3 from acc1 and acc2', makeAccounts(), {
+      dryRun: true,
+    });
+    assert.strictEqual(result.status_code, 'AP00');
+    assert.strictEqual(result.accounts[1].balance, 8000);
+    assert.strictEqual(result.accounts[1].projected_balance, 7000);
+  });
+});
@@ -0,0 +1,67 @@
+const assert = require('assert');
+const paymentInstructions = require('@app/services/payment-instructions/payment-instructions');
+
+describe('payment-instructions: narration', () => {
+  function makeAccounts() {
+    return [
+      { id: 'acc1', balance: 5000, currency: 'NGN' },
+      { id: 'acc2', balance: 0, currency: 'NGN' },
+      { id: 'acc3', balance: 0, currency: 'NGN' },
+    ];
+  }
+  function run(instruction) {
+    return paymentInstructions({ accounts: makeAccounts(), instruction });
+  }
+
+  it('reads a trailing "for" clause without touching the FOR keyword', async () => {
+    const result = await run(
+      'DEBIT 1000 NGN FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc2 for rent'
+    );
+    assert.strictEqual(result.status_code, 'AP00');
+    assert.strictEqual(result.narration, 'rent');
+    assert.strictEqual(result.amount, 1000);
+    assert.strictEqual(result.debit_account, 'acc1');
+    assert.strictEqual(result.credit_account, 'acc2');
+  });
+
+  it('reads a "ref:" token with or without a space', async () => {
+    const credit = 'CREDIT 10 NGN TO ACCOUNT acc2 FOR DEBIT FROM ACCOUNT acc1';
+    const spaced = await run(`${credit} ref: INV-2301`);
+    assert.strictEqual(spaced.narration, 'INV-2301');
+    assert.strictEqual(spaced.debit_account, 'acc1');
+    const joined = await run(`${credit} REF:INV-2301`);
+    assert.strictEqual(joined.narration, 'INV-2301');
+  });
+
+  it('is empty when there is no narration', async () => {
+    const result = await run('DEBIT 1000 NGN FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc2');
+    assert.strictEqual(result.status_code, 'AP00');
+    assert.strictEqual(result.narration, '');
+  });
+
+  it('keeps dates before the narration and ignores date words inside it', async () => {
+    const result = await paymentInstructions(
+      {
+        accounts: makeAccounts(),
+        instruction: 'SCHEDULE TRANSFER 100 NGN FROM acc1 TO acc2 ON 2025-04-01 for rent on friday',
+      },
+      { now: Date.UTC(2025, 2, 12) }
+    );
+    assert.strictEqual(result.status_code, 'AP02');
+    assert.strictEqual(result.execute_by, Date.UTC(2025, 3, 1) / 1000);
+    assert.strictEqual(result.narration, 'rent on friday');
+  });
+
+  it('caps the narration at 140 characters', async () => {
+    const debit = 'DEBIT 1 NGN FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc2';
+    const result = await run(`${debit} for ${'x'.repeat(200)}`);
+    assert.strictEqual(result.narration.length, 140);
+  });
+
+  it('ends a SPLIT recipient list', async () => {
+    const result = await run('SPLIT 100 NGN FROM acc1 BETWEEN acc2 AND acc3 for team lunch');
+    assert.strictEqual(result.status_code, 'AP00');
+    assert.strictEqual(result.narration, 'team lunch');
+    assert.strictEqual(result.splits.length, 2);
+  });
+});
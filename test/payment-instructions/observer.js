@@ -0,0 +1,77 @@
+const assert = require('assert');
+const paymentInstructions = require('@app/services/payment-instructions/payment-instructions');
+
+describe('payment-instructions: observer', () => {
+  function recordingObserver() {
+    const events = [];
+    return { events, notify: (event, fields) => events.push({ event, ...fields }) };
+  }
+  const debit = (amount) => `DEBIT ${amount} NGN FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc2`;
+  function run(instruction, observer) {
+    return paymentInstructions(
+      {
+        accounts: [
+          { id: 'acc1', balance: 500, currency: 'NGN' },
+          { id: 'acc2', balance: 0, currency: 'NGN' },
+        ],
+        instruction,
+      },
+      { observer }
+    );
+  }
+
+  it('reports every stage of a successful transaction', async () => {
+    const observer = recordingObserver();
+    const result = await run(debit(100), observer);
+    const id = result.transaction_id;
+    assert.deepStrictEqual(observer.events, [
+      { event: 'instruction.received', instruction: debit(100) },
+      {
+        event: 'instruction.parsed',
+        transaction_id: id,
+        type: 'DEBIT',
+        amount: 100,
+        currency: 'NGN',
+      },
+      {
+        event: 'instruction.validated',
+        transaction_id: id,
+        amount: 100,
+        currency: 'NGN',
+        status: 'successful',
+        status_code: 'AP00',
+      },
+      {
+        event: 'instruction.executed',
+        transaction_id: id,
+        amount: 100,
+        currency: 'NGN',
+        status_code: 'AP00',
+        accounts: result.accounts,
+      },
+    ]);
+  });
+
+  it('stops at validation for a declined transaction', async () => {
+    const observer = recordingObserver();
+    const result = await run(debit(900), observer);
+    assert.deepStrictEqual(
+      observer.events.map((e) => e.event),
+      ['instruction.received', 'instruction.parsed', 'instruction.validated']
+    );
+    assert.strictEqual(observer.events[2].status_code, 'AC01');
+    assert.strictEqual(observer.events[2].transaction_id, result.transaction_id);
+
+    const unparsed = recordingObserver();
+    await run('hello', unparsed);
+    assert.deepStrictEqual(
+      unparsed.events.map((e) => e.event),
+      ['instruction.received', 'instruction.validated']
+    );
+  });
+
+  it('stays silent without an observer', async () => {
+    const result = await run(debit(100));
+    assert.strictEqual(result.status_code, 'AP00');
+  });
+});
@@ -0,0 +1,58 @@
+const assert = require('assert');
+const { StatusCodes } = require('@app/messages/payment-instruction-status-codes');
+const buildOpenApiSpec = require('@app/services/payment-instructions/openapi/build-openapi-spec');
+
+describe('payment-instructions: OpenAPI spec', () => {
+  const spec = JSON.parse(JSON.stringify(buildOpenApiSpec()));
+  const { schemas } = spec.components;
+
+  it('describes the endpoint with its 200, 400 and 422 responses', () => {
+    assert.ok(spec.openapi.startsWith('3.'));
+    const operation = spec.paths['/payment-instructions'].post;
+    assert.deepStrictEqual(operation.requestBody.content['application/json'].schema, {
+      $ref: '#/components/schemas/PaymentInstructionRequest',
+    });
+    assert.deepStrictEqual(operation.responses['200'].content['application/json'].schema, {
+      $ref: '#/components/schemas/SuccessResponse',
+    });
+    const failed = operation.responses['400'].content['application/json'].schema.oneOf;
+    assert.deepStrictEqual(
+      failed.map((schema) => schema.$ref),
+      ['#/components/schemas/FailedResponse', '#/components/schemas/ErrorResponse']
+    );
+    assert.deepStrictEqual(operation.responses['422'].content['application/json'].schema, {
+      $ref: '#/components/schemas/FailedResponse',
+    });
+  });
+
+  it('describes the request body and the accounts array', () => {
+    const request = schemas.PaymentInstructionRequest;
+    assert.deepStrictEqual(request.required, ['accounts', 'instruction']);
+    assert.strictEqual(request.properties.accounts.type, 'array');
+    assert.deepStrictEqual(schemas.Account.required, ['id']);
+    assert.deepStrictEqual(schemas.Account.anyOf, [
+      { required: ['balance', 'currency'] },
+      { required: ['balances'] },
+    ]);
+    assert.deepStrictEqual(schemas.AccountResult.required, [
+      'id',
+      'balance',
+      'balance_before',
+      'currency',
+    ]);
+  });
+
+  it('marks the nullable fields and enumerates the status codes', () => {
+    const success = schemas.InstructionResult.properties;
+    const failure = schemas.FailedInstructionResult.properties;
+    assert.strictEqual(success.execute_by.type, 'number');
+    assert.strictEqual(success.execute_by.nullable, true);
+    ['type', 'amount', 'currency', 'debit_account', 'credit_account'].forEach((field) => {
+      assert.strictEqual(failure[field].nullable, true, field);
+    });
+    assert.strictEqual(schemas.ErrorResponse.properties.data.nullable, true);
+    assert.strictEqual(schemas.FailedInstructionResult.required.includes('accounts'), false);
+    assert.deepStrictEqual(success.status_code.enum, Object.keys(StatusCodes));
+    assert.ok(failure.status_code.enum.includes('CU04'));
+  });
+});
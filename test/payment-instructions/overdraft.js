@@ -0,0 +1,109 @@
+const assert = require('assert');
+const paymentInstructions = require('@app/services/payment-instructions/payment-instructions');
+
+describe('payment-instructions: overdraft', () => {
+  function makeAccounts(overdraftLimit) {
+    return [
+      { id: 'acc1', balance: 100, currency: 'NGN', overdraft_limit: overdraftLimit },
+      { id: 'acc2', balance: 50, currency: 'NGN' },
+    ];
+  }
+  function debit(amount) {
+    return `DEBIT ${amount} NGN FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc2`;
+  }
+  function run(amount) {
+    return paymentInstructions({ accounts: makeAccounts(500), instruction: debit(amount) });
+  }
+
+  it('allows a debit that lands exactly at the limit', async () => {
+    const result = await run(600);
+    assert.strictEqual(result.status, 'successful');
+    assert.strictEqual(result.status_code, 'AP00');
+    assert.strictEqual(
+      result.status_reason,
+      'Transaction executed successfully; debit account overdrawn by 500 NGN'
+    );
+    assert.deepStrictEqual(result.accounts, [
+      { id: 'acc1', balance: -500, balance_before: 100, currency: 'NGN' },
+      { id: 'acc2', balance: 650, balance_before: 50, currency: 'NGN' },
+    ]);
+  });
+
+  it('fails with AC01 just over the limit', async () => {
+    const result = await run(601);
+    assert.strictEqual(result.status, 'failed');
+    assert.strictEqual(result.status_code, 'AC01');
+    assert.strictEqual(
+      result.status_reason,
+      'Insufficient funds in debit account: has 100 NGN plus 500 NGN overdraft, needs 601 NGN'
+    );
+    assert.deepStrictEqual(result.accounts, [
+      { id: 'acc1', balance: 100, balance_before: 100, currency: 'NGN' },
+      { id: 'acc2', balance: 50, balance_before: 50, currency: 'NGN' },
+    ]);
+  });
+
+  it('keeps the plain reason when the overdraft is not touched', async () => {
+    const result = await run(100);
+    assert.strictEqual(result.status_reason, 'Transaction executed successfully');
+    assert.strictEqual(result.accounts[0].balance, 0);
+  });
+
+  it('treats a missing limit as no overdraft', async () => {
+    const accounts = makeAccounts(500);
+    delete accounts[0].overdraft_limit;
+    const result = await paymentInstructions({ accounts, instruction: debit(101) });
+    assert.strictEqual(result.status_code, 'AC01');
+    assert.strictEqual(
+      result.status_reason,
+      'Insufficient funds in debit account: has 100 NGN, needs 101 NGN'
+    );
+  });
+});
+
+describe('payment-instructions: negative starting balances', () => {
+  function makeAccounts(balance, overdraftLimit) {
+    return [
+      { id: 'acc1', balance, currency: 'NGN', overdraft_limit: overdraftLimit },
+      { id: 'acc2', balance: 0, currency: 'NGN' },
+    ];
+  }
+  const instruction = 'DEBIT 100 NGN FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc2';
+
+  it('takes a negative balance within the overdraft limit by default', async () => {
+    const result = await paymentInstructions({ accounts: makeAccounts(-200, 500), instruction });
+    assert.strictEqual(result.status_code, 'AP00');
+    assert.deepStrictEqual(result.accounts.map((a) => a.balance), [-300, 100]);
+  });
+
+  it('fails a balance below the overdraft limit with AC10', async () => {
+    const result = await paymentInstructions({ accounts: makeAccounts(-600, 500), instruction });
+    assert.strictEqual(result.status, 'failed');
+    assert.strictEqual(result.status_code, 'AC10');
+    assert.strictEqual(
+      result.status_reason,
+      'Invalid negative balance: acc1 has -600 NGN, overdraft limit is 500 NGN'
+    );
+    assert.deepStrictEqual(result.accounts, []);
+    const noLimit = await paymentInstructions({
+      accounts: [...makeAccounts(100), { id: 'acc3', balance: -1, currency: 'NGN' }],
+      instruction,
+    });
+    assert.strictEqual(noLimit.status_code, 'AC10');
+  });
+
+  it('rejects every negative balance with negativeBalancePolicy "reject"', async () => {
+    const result = await paymentInstructions(
+      { accounts: makeAccounts(-200, 500), instruction },
+      { negativeBalancePolicy: 'reject' }
+    );
+    assert.strictEqual(result.status_code, 'AC10');
+    assert.strictEqual(result.status_reason, 'Invalid negative balance: acc1 has -200 NGN');
+    const zero = await paymentInstructions(
+      { accounts: makeAccounts(0, 500), instruction },
+      { negativeBalancePolicy: 'reject' }
+    );
+    assert.strictEqual(zero.status_code, 'AP00');
+    assert.strictEqual(zero.accounts[0].balance, -100);
+  });
+});
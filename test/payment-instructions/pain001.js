@@ -0,0 +1,133 @@
+const assert = require('assert');
+const paymentInstructions = require('@app/services/payment-instructions/payment-instructions');
+const toPain001Xml = require('@app/services/payment-instructions/exporters/to-pain001-xml');
+
+// Element paths ("Document/CstmrCdtTrfInitn/GrpHdr/MsgId") in document order, with the text of
+// leaf elements; fails on any closing tag that does not match the open one
+function readElements(xml) {
+  const elements = [];
+  const open = [];
+  let i = xml.indexOf('?>') + 2;
+  while (xml.indexOf('<', i) !== -1) {
+    const start = xml.indexOf('<', i);
+    const end = xml.indexOf('>', start);
+    const tag = xml.substring(start + 1, end);
+    if (tag[0] === '/') {
+      const name = open.pop();
+      assert.strictEqual(tag.substring(1), name, `unbalanced </${tag.substring(1)}>`);
+    } else {
+      const name = tag.split(' ')[0];
+      open.push(name);
+      const next = xml.indexOf('<', end);
+      const text = xml[next + 1] === '/' ? xml.substring(end + 1, next) : null;
+      elements.push({ path: open.join('/'), text, tag });
+    }
+    i = end + 1;
+  }
+  assert.deepStrictEqual(open, [], 'unclosed elements');
+  return elements;
+}
+
+describe('payment-instructions: pain.001 export', () => {
+  const NOW = Date.UTC(2025, 2, 12, 15, 30);
+  const accounts = [
+    { id: 'acc-001', balance: 1000, currency: 'USD' },
+    { id: 'acc-002', balance: 0, currency: 'USD' },
+  ];
+
+  it('maps a transfer to the credit transfer initiation structure', async () => {
+    const result = await paymentInstructions(
+      {
+        accounts,
+        instruction: 'DEBIT 250.5 USD FROM ACCOUNT acc-001 FOR CREDIT TO ACCOUNT acc-002 for <rent>',
+      },
+      { now: NOW }
+    );
+    const xml = toPain001Xml(result, { now: NOW, debtorName: 'Ada & Co' });
+    assert.ok(xml.startsWith('<?xml version="1.0" encoding="UTF-8"?>\n<Document xmlns='));
+    const elements = readElements(xml);
+    const text = {};
+    elements.forEach((e) => {
+      if (e.text !== null) text[e.path] = e.text;
+    });
+
+    const header = 'Document/CstmrCdtTrfInitn/GrpHdr';
+    const info = 'Document/CstmrCdtTrfInitn/PmtInf';
+    const transfer = `${info}/CdtTrfTxInf`;
+    assert.deepStrictEqual(
+      elements.filter((e) => e.path.split('/').length === 4).map((e) => e.path),
+      [
+        `${header}/MsgId`,
+        `${header}/CreDtTm`,
+        `${header}/NbOfTxs`,
+        `${header}/CtrlSum`,
+        `${header}/InitgPty`,
+        `${info}/PmtInfId`,
+        `${info}/PmtMtd`,
+        `${info}/NbOfTxs`,
+        `${info}/CtrlSum`,
+        `${info}/ReqdExctnDt`,
+        `${info}/Dbtr`,
+        `${info}/DbtrAcct`,
+        `${info}/DbtrAgt`,
+        `${info}/CdtTrfTxInf`,
+      ]
+    );
+    assert.strictEqual(text[`${header}/MsgId`], result.transaction_id);
+    assert.strictEqual(text[`${header}/CreDtTm`], '2025-03-12T15:30:00');
+    assert.strictEqual(text[`${info}/ReqdExctnDt/Dt`], '2025-03-12');
+    assert.strictEqual(text[`${info}/Dbtr/Nm`], 'Ada &amp; Co');
+    assert.strictEqual(text[`${info}/DbtrAcct/Id/Othr/Id`], 'acc-001');
+    assert.strictEqual(text[`${transfer}/PmtId/EndToEndId`], result.transaction_id);
+    assert.strictEqual(text[`${transfer}/Amt/InstdAmt`], '250.50');
+    assert.strictEqual(
+      elements.find((e) => e.path === `${transfer}/Amt/InstdAmt`).tag,
+      'InstdAmt Ccy="USD"'
+    );
+    assert.strictEqual(text[`${transfer}/CdtrAcct/Id/Othr/Id`], 'acc-002');
+    assert.strictEqual(text[`${transfer}/RmtInf/Ustrd`], '&lt;rent&gt;');
+  });
+
+  it('takes the execution date from execute_by', async () => {
+    const result = await paymentInstructions(
+      {
+        accounts,
+        instruction: 'DEBIT 10 USD FROM ACCOUNT acc-001 FOR CREDIT TO ACCOUNT acc-002 ON 2025-04-01',
+      },
+      { now: NOW }
+    );
+    assert.strictEqual(result.status, 'pending');
+    const dates = readElements(toPain001Xml(result, { now: NOW })).filter((e) =>
+      e.path.endsWith('ReqdExctnDt/Dt')
+    );
+    assert.deepStrictEqual(dates.map((e) => e.text), ['2025-04-01']);
+    const scheduled = await paymentInstructions(
+      {
+        accounts,
+        instruction: 'SCHEDULE TRANSFER OF 10 USD FROM acc-001 TO acc-002 ON 2025-05-02',
+      },
+      { now: NOW }
+    );
+    assert.strictEqual(scheduled.type, 'SCHEDULE');
+    assert.ok(toPain001Xml(scheduled).indexOf('<Dt>2025-05-02</Dt>') !== -1);
+  });
+
+  it('refuses types and results that are not one executed transfer', async () => {
+    const split = await paymentInstructions({
+      accounts: [...accounts, { id: 'acc-003', balance: 0, currency: 'USD' }],
+      instruction: 'SPLIT 100 USD FROM ACCOUNT acc-001 TO acc-002, acc-003',
+    });
+    assert.strictEqual(split.type, 'SPLIT');
+    assert.throws(() => toPain001Xml(split), /Only executed or scheduled single transfers/);
+    const withdrawal = await paymentInstructions({
+      accounts,
+      instruction: 'WITHDRAW 100 USD FROM acc-001',
+    });
+    assert.throws(() => toPain001Xml(withdrawal), /WITHDRAW/);
+    const failed = await paymentInstructions({
+      accounts,
+      instruction: 'DEBIT 5000 USD FROM ACCOUNT acc-001 FOR CREDIT TO ACCOUNT acc-002',
+    });
+    assert.throws(() => toPain001Xml(failed), /DEBIT \(failed\)/);
+  });
+});
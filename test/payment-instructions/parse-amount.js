@@ -0,0 +1,67 @@
+const assert = require('assert');
+const { parseAmount } = require('@app/services/payment-instructions/helpers');
+const paymentInstructions = require('@app/services/payment-instructions/payment-instructions');
+
+describe('payment-instructions: parseAmount', () => {
+  it('parses plain integers', () => {
+    assert.deepStrictEqual(parseAmount(['DEBIT', '5000', 'NGN'], 1), { amount: 5000, consumed: 1 });
+  });
+
+  it('applies k/m/mn/bn suffixes case-insensitively', () => {
+    assert.strictEqual(parseAmount(['DEBIT', '5k', 'NGN'], 1).amount, 5000);
+    assert.strictEqual(parseAmount(['DEBIT', '1.5m', 'NGN'], 1).amount, 1500000);
+    assert.strictEqual(parseAmount(['DEBIT', '3MN', 'NGN'], 1).amount, 3000000);
+    assert.strictEqual(parseAmount(['DEBIT', '2bn', 'NGN'], 1).amount, 2000000000);
+    assert.strictEqual(parseAmount(['DEBIT', '2BN', 'NGN'], 1).amount, 2000000000);
+  });
+
+  it('handles decimal multipliers without float drift', () => {
+    assert.strictEqual(parseAmount(['DEBIT', '0.5k', 'NGN'], 1).amount, 500);
+    assert.strictEqual(parseAmount(['DEBIT', '1.1k', 'NGN'], 1).amount, 1100);
+    assert.strictEqual(parseAmount(['DEBIT', '0.25m', 'NGN'], 1).amount, 250000);
+  });
+
+  it('accepts the suffix as a separate token', () => {
+    assert.deepStrictEqual(parseAmount(['DEBIT', '0.5', 'k', 'NGN'], 1), {
+      amount: 500,
+      consumed: 2,
+    });
+  });
+
+  it('leaves fractional results for the caller to reject', () => {
+    assert.strictEqual(parseAmount(['DEBIT', '1.2345k', 'NGN'], 1).amount, 1234.5);
+  });
+
+  it('does not misread units or other words as amounts', () => {
+    assert.strictEqual(parseAmount(['DEBIT', '5km', 'NGN'], 1), null);
+    assert.strictEqual(parseAmount(['DEBIT', '5kg', 'NGN'], 1), null);
+    assert.strictEqual(parseAmount(['DEBIT', 'k5', 'NGN'], 1), null);
+    assert.strictEqual(parseAmount(['DEBIT', '1.5.0', 'NGN'], 1), null);
+    assert.strictEqual(parseAmount(['DEBIT', '-50', 'NGN'], 1), null);
+  });
+
+  it('executes an instruction with a suffixed amount', async () => {
+    const result = await paymentInstructions({
+      accounts: [
+        { id: 'acc5k', balance: 10000, currency: 'NGN' },
+        { id: 'b', balance: 0, currency: 'NGN' },
+      ],
+      instruction: 'DEBIT 5k NGN FROM ACCOUNT acc5k FOR CREDIT TO ACCOUNT b',
+    });
+    assert.strictEqual(result.status_code, 'AP00');
+    assert.strictEqual(result.amount, 5000);
+    assert.strictEqual(result.debit_account, 'acc5k');
+    assert.strictEqual(result.accounts[0].balance, 5000);
+  });
+
+  it('rejects unit-like tokens with AM01', async () => {
+    const result = await paymentInstructions({
+      accounts: [
+        { id: 'a', balance: 10000, currency: 'NGN' },
+        { id: 'b', balance: 0, currency: 'NGN' },
+      ],
+      instruction: 'DEBIT 5km NGN FROM ACCOUNT a FOR CREDIT TO ACCOUNT b',
+    });
+    assert.strictEqual(result.status_code, 'AM01');
+  });
+});
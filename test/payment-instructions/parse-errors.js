@@ -0,0 +1,88 @@
+const assert = require('assert');
+const paymentInstructions = require('@app/services/payment-instructions/payment-instructions');
+
+describe('payment-instructions: structured parse errors', () => {
+  function run(instruction) {
+    return paymentInstructions({
+      accounts: [
+        { id: 'a', balance: 1000, currency: 'NGN' },
+        { id: 'b', balance: 0, currency: 'NGN' },
+      ],
+      instruction,
+    });
+  }
+  // The instruction text the detail points at
+  function pointed(instruction, detail) {
+    return instruction.substring(detail.offset, detail.offset + detail.length);
+  }
+
+  it('points at an unknown verb', async () => {
+    const instruction = '  PAY 500 NGN FROM ACCOUNT a FOR CREDIT TO ACCOUNT b';
+    const result = await run(instruction);
+    assert.strictEqual(result.status_code, 'SY01');
+    assert.deepStrictEqual(result.parse_error, {
+      segment: 'verb',
+      token: 'PAY',
+      offset: 2,
+      length: 3,
+    });
+  });
+
+  it('covers every word of an invalid number-word amount', async () => {
+    const instruction = 'DEBIT five thousand and and NGN FROM ACCOUNT a FOR CREDIT TO ACCOUNT b';
+    const result = await run(instruction);
+    assert.strictEqual(result.status_code, 'SY03');
+    assert.strictEqual(result.parse_error.segment, 'amount');
+    assert.strictEqual(pointed(instruction, result.parse_error), 'five thousand and and');
+  });
+
+  it('points at an unsupported currency', async () => {
+    const instruction = 'DEBIT 500 XYZ FROM ACCOUNT a FOR CREDIT TO ACCOUNT b';
+    const result = await run(instruction);
+    assert.strictEqual(result.status_code, 'CU04');
+    assert.strictEqual(result.parse_error.segment, 'currency');
+    assert.strictEqual(result.parse_error.offset, 10);
+    assert.strictEqual(pointed(instruction, result.parse_error), 'XYZ');
+  });
+
+  it('points at the keyword out of place in the debit clause', async () => {
+    const instruction = 'DEBIT 500 NGN FROM acct a FOR CREDIT TO ACCOUNT b';
+    const result = await run(instruction);
+    assert.strictEqual(result.status_code, 'SY02');
+    assert.strictEqual(result.parse_error.segment, 'debit');
+    assert.strictEqual(pointed(instruction, result.parse_error), 'acct');
+  });
+
+  it('points at an invalid credit account id', async () => {
+    const instruction = 'CREDIT 500 NGN TO ACCOUNT b!b FOR DEBIT FROM ACCOUNT a';
+    const result = await run(instruction);
+    assert.strictEqual(result.status_code, 'AC04');
+    assert.strictEqual(result.parse_error.segment, 'credit');
+    assert.strictEqual(pointed(instruction, result.parse_error), 'b!b');
+  });
+
+  it('points past the end when a segment is missing', async () => {
+    const instruction = 'DEBIT 500 NGN FROM ACCOUNT a FOR CREDIT TO ACCOUNT ';
+    const result = await run(instruction);
+    assert.strictEqual(result.status_code, 'SY03');
+    assert.deepStrictEqual(result.parse_error, {
+      segment: 'credit',
+      token: null,
+      offset: instruction.trimEnd().length,
+      length: 0,
+    });
+  });
+
+  it('locates an amount written with a currency symbol', async () => {
+    const instruction = 'DEBIT ₦5km FROM ACCOUNT a FOR CREDIT TO ACCOUNT b';
+    const result = await run(instruction);
+    assert.strictEqual(result.status_code, 'AM01');
+    assert.strictEqual(pointed(instruction, result.parse_error), '5km');
+  });
+
+  it('omits the detail when the instruction parses', async () => {
+    const result = await run('DEBIT 500 NGN FROM ACCOUNT a FOR CREDIT TO ACCOUNT b');
+    assert.strictEqual(result.status_code, 'AP00');
+    assert.strictEqual(result.parse_error, undefined);
+  });
+});
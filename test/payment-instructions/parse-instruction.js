@@ -0,0 +1,105 @@
+const assert = require('assert');
+const parseInstruction = require('@app/services/payment-instructions/parse-instruction');
+const paymentInstructions = require('@app/services/payment-instructions/payment-instructions');
+
+describe('payment-instructions: parseInstruction', () => {
+  const NOW = Date.UTC(2025, 2, 12);
+  function makeAccounts() {
+    return [
+      { id: 'acc-001', balance: 100, currency: 'USD', daily_limit: 50 },
+      { id: 'acc-002', balance: 0, currency: 'USD' },
+      { id: 'acc-003', balance: 10, currency: 'USD' },
+      { id: 'gbp-001', balance: 0, currency: 'GBP' },
+    ];
+  }
+  function parse(instruction, context = {}) {
+    return parseInstruction(instruction, { accounts: makeAccounts(), ...context }, { now: NOW });
+  }
+
+  it('resolves a transfer without checking or moving balances', async () => {
+    const dailyDebitStore = {
+      getTotal: () => assert.fail('the daily debit store must not be read'),
+      add: () => assert.fail('the daily debit store must not be written'),
+    };
+    const accounts = makeAccounts();
+    const parsed = await parseInstruction(
+      'DEBIT 500 USD FROM ACCOUNT acc-001 FOR CREDIT TO ACCOUNT acc-002 for rent',
+      { accounts },
+      { now: NOW, dailyDebitStore }
+    );
+    // 500 USD is more than the balance and the daily limit; neither is a parsing concern
+    assert.deepStrictEqual(parsed, {
+      type: 'DEBIT',
+      amount: 500,
+      currency: 'USD',
+      debit_account: 'acc-001',
+      credit_account: 'acc-002',
+      execute_by: null,
+      narration: 'rent',
+      canonical_instruction: 'TRANSFER 500.00 USD FROM acc-001 TO acc-002',
+      confidence: 1,
+      matched_verb: 'debit',
+    });
+    assert.deepStrictEqual(accounts, makeAccounts());
+  });
+
+  it('resolves credits, schedules, aliases and FX rates', async () => {
+    const credit = await parse('CREDIT 10 USD TO ACCOUNT acc-002 FOR DEBIT FROM ACCOUNT acc-003');
+    assert.strictEqual(credit.type, 'CREDIT');
+    assert.strictEqual(credit.debit_account, 'acc-003');
+    assert.strictEqual(credit.credit_account, 'acc-002');
+
+    const scheduled = await parse(
+      'SCHEDULE TRANSFER OF 10 USD FROM acc-001 TO acc-002 ON 2025-05-02'
+    );
+    assert.strictEqual(scheduled.type, 'SCHEDULE');
+    assert.strictEqual(scheduled.execute_by, Date.UTC(2025, 4, 2) / 1000);
+
+    const aliased = await parse('DEBIT 5 USD FROM ACCOUNT savings FOR CREDIT TO ACCOUNT acc-002', {
+      aliases: { savings: 'acc-003' },
+    });
+    assert.strictEqual(aliased.debit_account, 'acc-003');
+    assert.ok(aliased.confidence < 1);
+
+    const fx = await parse('DEBIT 10 USD FROM ACCOUNT acc-001 FOR CREDIT TO ACCOUNT gbp-001', {
+      fx_rates: { 'USD/GBP': 0.8 },
+    });
+    assert.strictEqual(fx.fx_rate, 0.8);
+    assert.strictEqual(fx.converted_amount, undefined);
+  });
+
+  it('resolves SPLIT shares and MULTI_DEBIT sources', async () => {
+    const split = await parse('SPLIT 30 USD FROM acc-001 EQUALLY BETWEEN acc-002 AND acc-003');
+    assert.strictEqual(split.type, 'SPLIT');
+    assert.deepStrictEqual(split.splits, [
+      { account: 'acc-002', amount: 15 },
+      { account: 'acc-003', amount: 15 },
+    ]);
+    const multi = await parse('TRANSFER 200 USD TO acc-002 FROM acc-001 AND acc-003');
+    assert.strictEqual(multi.type, 'MULTI_DEBIT');
+    assert.strictEqual(multi.credit_account, 'acc-002');
+    assert.deepStrictEqual(multi.debits, [
+      { account: 'acc-001', amount: null },
+      { account: 'acc-003', amount: null },
+    ]);
+  });
+
+  it('rejects what cannot be parsed with the status code the service would return', async () => {
+    await assert.rejects(
+      parse('GIVE 10 USD FROM ACCOUNT acc-001 TO ACCOUNT acc-002'),
+      (err) => err.errorCode === 'VALIDATION_ERROR' && err.details.status_code === 'SY01'
+    );
+    await assert.rejects(
+      parse('DEBIT 10 USD FROM ACCOUNT nobody FOR CREDIT TO ACCOUNT acc-002'),
+      (err) => err.details.status_code === 'AC03'
+    );
+  });
+
+  it('yields the fields the executed instruction reports', async () => {
+    const instruction = 'DEBIT 4 USD FROM ACCOUNT acc-003 FOR CREDIT TO ACCOUNT acc-002';
+    const parsed = await parse(instruction);
+    const executed = await paymentInstructions({ accounts: makeAccounts(), instruction });
+    assert.strictEqual(executed.status_code, 'AP00');
+    Object.keys(parsed).forEach((key) => assert.deepStrictEqual(executed[key], parsed[key], key));
+  });
+});
@@ -0,0 +1,83 @@
+const assert = require('assert');
+const { parseWordAmount } = require('@app/services/payment-instructions/helpers');
+const paymentInstructions = require('@app/services/payment-instructions/payment-instructions');
+
+describe('payment-instructions: parseWordAmount', () => {
+  const cases = [
+    ['one', 1],
+    ['nine', 9],
+    ['ten', 10],
+    ['nineteen', 19],
+    ['twenty', 20],
+    ['twenty five', 25],
+    ['twenty-five', 25],
+    ['ninety nine', 99],
+    ['a hundred', 100],
+    ['five hundred', 500],
+    ['one hundred and five', 105],
+    ['two hundred fifty', 250],
+    ['fifteen hundred', 1500],
+    ['a thousand', 1000],
+    ['one thousand and fifty', 1050],
+    ['one thousand two hundred fifty', 1250],
+    ['twelve thousand three hundred forty-five', 12345],
+    ['one hundred thousand', 100000],
+    ['a million', 1000000],
+    ['two million five hundred thousand', 2500000],
+    ['one million two hundred thousand and five', 1200005],
+    ['three billion', 3000000000],
+    ['one billion one million one thousand one', 1001001001],
+    ['Five Hundred', 500],
+  ];
+
+  cases.forEach(([phrase, expected]) => {
+    it(`parses "${phrase}" as ${expected}`, () => {
+      assert.strictEqual(parseWordAmount(phrase.split(' ')), expected);
+    });
+  });
+
+  const rejected = [
+    'five five',
+    'twenty twenty',
+    'ten five',
+    'thousand',
+    'hundred',
+    'one thousand and',
+    'and five',
+    'a',
+    'a five',
+    'five thousand thousand',
+    'one thousand million',
+    'one hundred hundred',
+    'five apples',
+  ];
+
+  rejected.forEach((phrase) => {
+    it(`rejects "${phrase}"`, () => {
+      assert.strictEqual(parseWordAmount(phrase.split(' ')), null);
+    });
+  });
+
+  const accounts = [
+    { id: 'savings', balance: 2000, currency: 'NGN' },
+    { id: 'current', balance: 0, currency: 'NGN' },
+  ];
+
+  it('executes an instruction with a written-out amount', async () => {
+    const result = await paymentInstructions({
+      accounts,
+      instruction: 'DEBIT five hundred NGN FROM ACCOUNT savings FOR CREDIT TO ACCOUNT current',
+    });
+    assert.strictEqual(result.status_code, 'AP00');
+    assert.strictEqual(result.amount, 500);
+  });
+
+  it('returns SY03 for an incomplete word amount', async () => {
+    const result = await paymentInstructions({
+      accounts,
+      instruction: 'DEBIT one thousand and NGN FROM ACCOUNT savings FOR CREDIT TO ACCOUNT current',
+    });
+    assert.strictEqual(result.status_code, 'SY03');
+    assert.strictEqual(result.amount, null);
+  });
+});
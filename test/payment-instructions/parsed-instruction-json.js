@@ -0,0 +1,63 @@
+const assert = require('assert');
+const parseInstruction = require('@app/services/payment-instructions/parse-instruction');
+const toParsedInstructionJson = require('@app/services/payment-instructions/exporters/to-parsed-instruction-json');
+const parseParsedInstructionJson = require('@app/services/payment-instructions/importers/parse-parsed-instruction-json');
+
+describe('payment-instructions: parsed instruction JSON', () => {
+  const NOW = Date.UTC(2025, 2, 12);
+  const accounts = [
+    { id: 'acc1', balance: 1000, currency: 'NGN' },
+    { id: 'acc2', balance: 0, currency: 'NGN' },
+    { id: 'acc3', balance: 0, currency: 'NGN' },
+  ];
+  function parse(instruction) {
+    return parseInstruction(instruction, { accounts }, { now: NOW });
+  }
+
+  it('writes a null execute_by as null and reads it back as null', async () => {
+    const parsed = await parse('DEBIT 100 NGN FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc2');
+    const json = toParsedInstructionJson(parsed);
+    assert.strictEqual(
+      json,
+      '{"type":"DEBIT","amount":100,"currency":"NGN","debit_account":"acc1",' +
+        '"credit_account":"acc2","execute_by":null,"narration":"","fee":null,' +
+        '"canonical_instruction":"TRANSFER 100.00 NGN FROM acc1 TO acc2","confidence":1,' +
+        '"matched_verb":"debit"}'
+    );
+    const read = parseParsedInstructionJson(json);
+    assert.strictEqual(read.execute_by, null);
+    assert.deepStrictEqual(read, { ...parsed, fee: null });
+  });
+
+  it('round-trips scheduled, split and fee-carrying instructions exactly', async () => {
+    const scheduled = await parse(
+      'SCHEDULE DEBIT 50 NGN FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc2 ON 2025-04-01 for rent'
+    );
+    const split = await parse('SPLIT 90 NGN FROM acc1 BETWEEN acc2 AND acc3');
+    const withFee = { ...scheduled, fee: 10.5 };
+    [scheduled, split, withFee].forEach((parsed) => {
+      const read = parseParsedInstructionJson(toParsedInstructionJson(parsed));
+      assert.deepStrictEqual(read, { fee: null, ...parsed });
+      assert.strictEqual(toParsedInstructionJson(read), toParsedInstructionJson(parsed));
+    });
+    assert.strictEqual(parseParsedInstructionJson(toParsedInstructionJson(withFee)).fee, 10.5);
+  });
+
+  it('gives the same string whatever order the fields were set in', () => {
+    const a = { type: 'DEBIT', amount: 1, currency: 'NGN', narration: '', x: { b: 1, a: 2 } };
+    const b = { x: { a: 2, b: 1 }, narration: '', currency: 'NGN', amount: 1, type: 'DEBIT' };
+    assert.strictEqual(toParsedInstructionJson(a), toParsedInstructionJson(b));
+  });
+
+  it('rejects text that is not a parsed instruction', () => {
+    assert.throws(() => parseParsedInstructionJson('{not json'), /Invalid parsed instruction JSON/);
+    assert.throws(() => parseParsedInstructionJson('[]'), /expected an object/);
+    const zeroed =
+      '{"type":"DEBIT","amount":1,"currency":"NGN","debit_account":"acc1",' +
+      '"credit_account":"acc2","execute_by":"0","narration":"","fee":null}';
+    assert.throws(
+      () => parseParsedInstructionJson(zeroed),
+      (err) => err.details.field === 'execute_by'
+    );
+  });
+});
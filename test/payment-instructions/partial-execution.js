@@ -0,0 +1,68 @@
+const assert = require('assert');
+const { largestAffordableAmount } = require('@app/services/payment-instructions/helpers');
+const paymentInstructions = require('@app/services/payment-instructions/payment-instructions');
+
+describe('payment-instructions: partial execution', () => {
+  const instruction = 'DEBIT 800 NGN FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc2';
+  function run(debitAccount, extra = { partial_execution: true }, options = {}) {
+    return paymentInstructions(
+      {
+        accounts: [
+          { id: 'acc1', currency: 'NGN', ...debitAccount },
+          { id: 'acc2', balance: 0, currency: 'NGN' },
+        ],
+        instruction,
+        ...extra,
+      },
+      options
+    );
+  }
+
+  it('sends the whole balance and leaves the debit account at exactly zero', async () => {
+    const result = await run({ balance: 500.25 });
+    assert.strictEqual(result.status, 'successful');
+    assert.strictEqual(result.status_code, 'BL03');
+    assert.strictEqual(result.amount, 500.25);
+    assert.strictEqual(result.requested_amount, 800);
+    assert.strictEqual(
+      result.status_reason,
+      'Transaction partially executed; 500.25 NGN of the 800 NGN requested'
+    );
+    assert.deepStrictEqual(result.accounts.map((a) => a.balance), [0, 500.25]);
+  });
+
+  it('rejects with AC01 when the mode is off', async () => {
+    const result = await run({ balance: 500 }, {});
+    assert.strictEqual(result.status, 'failed');
+    assert.strictEqual(result.status_code, 'AC01');
+    assert.strictEqual(result.requested_amount, undefined);
+    assert.strictEqual(result.accounts[0].balance, 500);
+  });
+
+  it('keeps the requested amount when it can be covered', async () => {
+    const result = await run({ balance: 1000 });
+    assert.strictEqual(result.status_code, 'AP00');
+    assert.strictEqual(result.amount, 800);
+    assert.strictEqual(result.requested_amount, undefined);
+  });
+
+  it('respects minimum balance and overdraft, and leaves room for the fee', async () => {
+    const floored = await run({ balance: 500, minimum_balance: 100 });
+    assert.strictEqual(floored.amount, 400);
+    const overdrawn = await run({ balance: 500, overdraft_limit: 100 });
+    assert.strictEqual(overdrawn.amount, 600);
+    assert.strictEqual(overdrawn.accounts[0].balance, -100);
+
+    const feePolicy = { DEBIT: { flat: 10, percent: 1.5 } };
+    const withFee = await run({ balance: 500 }, { partial_execution: true }, { feePolicy });
+    assert.strictEqual(withFee.amount, 482.76);
+    assert.strictEqual(withFee.fee, 17.24);
+    assert.strictEqual(withFee.accounts[0].balance, 0);
+    assert.strictEqual(largestAffordableAmount(50000, 'DEBIT', feePolicy, 'NGN'), 482.76);
+  });
+
+  it('still fails when the account has nothing to give', async () => {
+    const result = await run({ balance: 0 });
+    assert.strictEqual(result.status_code, 'AC01');
+  });
+});
@@ -0,0 +1,67 @@
+const assert = require('assert');
+const paymentInstructions = require('@app/services/payment-instructions/payment-instructions');
+const { reorderAccountFirst } = require('@app/services/payment-instructions/helpers');
+
+describe('payment-instructions: account-first phrasing', () => {
+  function makeAccounts() {
+    return [
+      { id: 'acc1', balance: 1000, currency: 'NGN' },
+      { id: 'acc2', balance: 0, currency: 'NGN' },
+      { id: 'acc-84821', balance: 0, currency: 'NGN' },
+    ];
+  }
+  function run(instruction, extra = {}) {
+    return paymentInstructions({ accounts: makeAccounts(), instruction, ...extra });
+  }
+
+  [
+    ['DEBIT 100 NGN FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc2', 'DEBIT', 'acc1', 'acc2'],
+    ['CREDIT 100 NGN TO ACCOUNT acc2 FOR DEBIT FROM ACCOUNT acc1', 'CREDIT', 'acc1', 'acc2'],
+    ['credit acc2 with 100 NGN from acc1', 'CREDIT', 'acc1', 'acc2'],
+    ['credit acc1 with 100 NGN from acc2', 'CREDIT', 'acc2', 'acc1'],
+    ['pay acc2 100 NGN from acc1', 'CREDIT', 'acc1', 'acc2'],
+    ['Pay account acc2 100 NGN from account acc1', 'CREDIT', 'acc1', 'acc2'],
+    ['debit acc1 100 NGN to acc2', 'DEBIT', 'acc1', 'acc2'],
+    ['debit acc2 by 100 NGN to acc1', 'DEBIT', 'acc2', 'acc1'],
+    ['DEBIT ACCOUNT acc1 100 NGN TO ACCOUNT acc2', 'DEBIT', 'acc1', 'acc2'],
+    ['pay ***4821 one hundred naira from acc1', 'CREDIT', 'acc1', 'acc-84821'],
+  ].forEach(([instruction, type, debit, credit]) => {
+    it(`reads "${instruction}" as ${debit} -> ${credit}`, async () => {
+      const accounts = makeAccounts();
+      accounts[1].balance = 1000;
+      const result = await paymentInstructions({ accounts, instruction });
+      assert.strictEqual(result.status_code, 'AP00', result.status_reason);
+      assert.strictEqual(result.type, type);
+      assert.strictEqual(result.amount, 100);
+      assert.strictEqual(result.currency, 'NGN');
+      assert.strictEqual(result.debit_account, debit);
+      assert.strictEqual(result.credit_account, credit);
+      const debited = result.accounts.find((a) => a.id === debit);
+      assert.strictEqual(debited.balance, debited.balance_before - 100);
+    });
+  });
+
+  it('keeps dates and narration after the second account', async () => {
+    const result = await run('credit acc2 with 100 NGN from acc1 on 2099-01-01 for rent');
+    assert.strictEqual(result.status_code, 'AP02');
+    assert.strictEqual(result.execute_by, '2099-01-01');
+    assert.strictEqual(result.narration, 'rent');
+  });
+
+  it('leaves amount-first and currency-first instructions to the keyword parser', () => {
+    assert.strictEqual(reorderAccountFirst(['DEBIT', '100', 'NGN', 'TO', 'acc2']), null);
+    assert.strictEqual(reorderAccountFirst(['debit', 'fifty', 'NGN', 'to', 'acc2']), null);
+    assert.strictEqual(reorderAccountFirst(['debit', 'NGN', '100', 'to', 'acc2']), null);
+    assert.strictEqual(reorderAccountFirst(['pay', 'acc2', '100', 'NGN', 'to', 'acc1']), null);
+    assert.deepStrictEqual(
+      reorderAccountFirst(['debit', 'acc1', '100', 'NGN', 'to', 'acc2', 'for', 'rent']).join(' '),
+      'DEBIT 100 NGN FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc2 for rent'
+    );
+  });
+
+  it('still fails an account-first instruction with the wrong preposition', async () => {
+    const result = await run('pay acc2 100 NGN to acc1');
+    assert.strictEqual(result.status, 'failed');
+    assert.strictEqual(result.status_code.substring(0, 2), 'SY');
+  });
+});
@@ -0,0 +1,74 @@
+const assert = require('assert');
+const parseInstruction = require('@app/services/payment-instructions/parse-instruction');
+const previewSchedule = require('@app/services/payment-instructions/preview-schedule');
+const { expandRecurrence } = require('@app/services/payment-instructions/helpers');
+
+// Wednesday 2025-01-15, morning UTC
+const NOW = new Date(Date.UTC(2025, 0, 15, 9, 0, 0));
+
+function ts(year, month, day, hour = 0, minute = 0) {
+  return Date.UTC(year, month - 1, day, hour, minute) / 1000;
+}
+
+describe('payment-instructions: schedule preview', () => {
+  const context = {
+    accounts: [
+      { id: 'acc1', balance: 100000, currency: 'NGN' },
+      { id: 'acc2', balance: 0, currency: 'NGN' },
+    ],
+  };
+  function parse(instruction, extra = {}) {
+    return parseInstruction(instruction, { ...context, ...extra }, { now: NOW });
+  }
+
+  it('moves a monthly run on the 31st to the end of a short month and back', async () => {
+    const parsed = await parse(
+      'STANDING ORDER 5000 NGN FROM acc1 TO acc2 monthly starting 31/01/2025'
+    );
+    assert.deepStrictEqual(previewSchedule(parsed, 4), [
+      ts(2025, 1, 31),
+      ts(2025, 2, 28),
+      ts(2025, 3, 31),
+      ts(2025, 4, 30),
+    ]);
+    const leapYear = expandRecurrence({ year: 2024, month: 1, day: 31 }, parsed.recurrence, 2);
+    assert.deepStrictEqual(leapYear, [
+      { year: 2024, month: 1, day: 31 },
+      { year: 2024, month: 2, day: 29 },
+    ]);
+  });
+
+  it('lists weekly runs on the same weekday and time', async () => {
+    const parsed = await parse('STANDING ORDER 5000 NGN FROM acc1 TO acc2 every friday at 8am');
+    assert.deepStrictEqual(previewSchedule(parsed, 3), [
+      ts(2025, 1, 17, 8),
+      ts(2025, 1, 24, 8),
+      ts(2025, 1, 31, 8),
+    ]);
+    const fortnightly = await parse('STANDING ORDER 5000 NGN FROM acc1 TO acc2 every 2 weeks');
+    assert.deepStrictEqual(previewSchedule(fortnightly, 2), [ts(2025, 1, 15), ts(2025, 1, 29)]);
+  });
+
+  it('keeps the local time of day across a DST change', async () => {
+    const parsed = await parse(
+      'STANDING ORDER 5000 NGN FROM acc1 TO acc2 monthly starting 10/02/2025 at 9am',
+      { timezone: 'America/New_York' }
+    );
+    // 9am is 14:00 UTC in EST and 13:00 UTC in EDT (from 9 March)
+    assert.deepStrictEqual(previewSchedule(parsed, 2, { timeZone: 'America/New_York' }), [
+      ts(2025, 2, 10, 14),
+      ts(2025, 3, 10, 13),
+    ]);
+  });
+
+  it('rejects an instruction without a recurrence and an out-of-range count', async () => {
+    const once = await parse('schedule transfer of 1000 NGN from acc1 to acc2 tomorrow');
+    assert.throws(
+      () => previewSchedule(once, 3),
+      /Only a standing order with a recurrence and a first run has a schedule/
+    );
+    const parsed = await parse('STANDING ORDER 5000 NGN FROM acc1 TO acc2 daily');
+    assert.throws(() => previewSchedule(parsed, 0), /from 1 to 366/);
+    assert.throws(() => previewSchedule(parsed, 2.5), /from 1 to 366/);
+  });
+});
@@ -0,0 +1,57 @@
+const assert = require('assert');
+const paymentInstructions = require('@app/services/payment-instructions/payment-instructions');
+
+// The parser reads tokens with string methods only and builds its lookup tables once at load,
+// so no call leaves state behind for the next one: the same instruction always reads the same.
+describe('payment-instructions: repeated parses', () => {
+  const NOW = Date.UTC(2025, 2, 12, 15, 30);
+  const instructions = [
+    'DEBIT 500 USD FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc2',
+    'DEBIT 1.5k USD FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc2',
+    'DEBIT two hundred and fifty USD FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc2',
+    'DEBIT 10% USD FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc2',
+    'SPLIT 900 USD FROM acc1 EQUALLY BETWEEN acc2 AND acc3',
+    'SCHEDULE DEBIT 100 USD FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc2 ON next friday',
+    'DEBIT 100 USD FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc9',
+    'hello there',
+  ];
+
+  function run(instruction) {
+    return paymentInstructions(
+      {
+        accounts: [
+          { id: 'acc1', balance: 5000, currency: 'USD' },
+          { id: 'acc2', balance: 0, currency: 'USD' },
+          { id: 'acc3', balance: 0, currency: 'USD' },
+        ],
+        instruction,
+      },
+      { now: NOW, idGenerator: { next: () => 'txn-1' } }
+    );
+  }
+
+  it('gives identical results for the same instruction parsed many times', async () => {
+    for (let i = 0; i < instructions.length; i++) {
+      // eslint-disable-next-line no-await-in-loop
+      const first = await run(instructions[i]);
+      for (let round = 0; round < 20; round++) {
+        // eslint-disable-next-line no-await-in-loop
+        assert.deepStrictEqual(await run(instructions[i]), first, instructions[i]);
+      }
+    }
+  });
+
+  it('reads an instruction the same whatever was parsed before it', async () => {
+    const alone = [];
+    for (let i = 0; i < instructions.length; i++) {
+      // eslint-disable-next-line no-await-in-loop
+      alone.push(await run(instructions[i]));
+    }
+    const reversed = [];
+    for (let i = instructions.length - 1; i >= 0; i--) {
+      // eslint-disable-next-line no-await-in-loop
+      reversed.unshift(await run(instructions[i]));
+    }
+    assert.deepStrictEqual(reversed, alone);
+  });
+});
@@ -0,0 +1,114 @@
+const assert = require('assert');
+const paymentInstructions = require('@app/services/payment-instructions/payment-instructions');
+const processReversal = require('@app/services/payment-instructions/process-reversal');
+const createMemoryTransactionStore = require('@app/services/payment-instructions/stores/create-memory-transaction-store');
+
+describe('payment-instructions: reversal', () => {
+  function makeAccounts() {
+    return [
+      { id: 'acc1', balance: 1000, currency: 'NGN' },
+      { id: 'acc2', balance: 50, currency: 'NGN' },
+      { id: 'acc3', balance: 0, currency: 'NGN' },
+    ];
+  }
+  // Balances as the caller would hold them after applying a response
+  function applyBalances(accounts, result) {
+    return accounts.map((a) => {
+      const updated = result.accounts.find((u) => u.id === a.id);
+      return updated ? { ...a, balance: updated.balance } : a;
+    });
+  }
+
+  it('moves the balances back as a new REVERSAL transaction', async () => {
+    const transactionStore = createMemoryTransactionStore();
+    const accounts = makeAccounts();
+    const original = await paymentInstructions(
+      { accounts, instruction: 'DEBIT 300 NGN FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc2' },
+      { transactionStore }
+    );
+    assert.strictEqual(original.status_code, 'AP00');
+    assert.strictEqual(typeof original.transaction_id, 'string');
+
+    const reversal = await processReversal(
+      { accounts: applyBalances(accounts, original), transaction_id: original.transaction_id },
+      { transactionStore }
+    );
+    assert.strictEqual(reversal.type, 'REVERSAL');
+    assert.strictEqual(reversal.status_code, 'AP00');
+    assert.strictEqual(reversal.reversed_transaction_id, original.transaction_id);
+    assert.notStrictEqual(reversal.transaction_id, original.transaction_id);
+    assert.strictEqual(reversal.amount, 300);
+    assert.strictEqual(reversal.debit_account, 'acc2');
+    assert.strictEqual(reversal.credit_account, 'acc1');
+    assert.deepStrictEqual(reversal.accounts, [
+      { id: 'acc1', balance: 1000, balance_before: 700, currency: 'NGN' },
+      { id: 'acc2', balance: 50, balance_before: 350, currency: 'NGN' },
+    ]);
+  });
+
+  it('fails with AC01 when the credited account no longer has the funds', async () => {
+    const transactionStore = createMemoryTransactionStore();
+    const accounts = makeAccounts();
+    const original = await paymentInstructions(
+      { accounts, instruction: 'DEBIT 300 NGN FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc2' },
+      { transactionStore }
+    );
+    const spent = applyBalances(accounts, original);
+    spent[1].balance = 200;
+    const reversal = await processReversal(
+      { accounts: spent, transaction_id: original.transaction_id },
+      { transactionStore }
+    );
+    assert.strictEqual(reversal.status, 'failed');
+    assert.strictEqual(reversal.status_code, 'AC01');
+    assert.strictEqual(
+      reversal.status_reason,
+      'Insufficient funds to reverse the transaction: acc2 has 200 NGN, needs 300 NGN'
+    );
+    assert.strictEqual(reversal.accounts[1].balance, 200);
+    assert.strictEqual(reversal.accounts[1].balance_before, 200);
+  });
+
+  it('replays the first reversal instead of reversing twice', async () => {
+    const transactionStore = createMemoryTransactionStore();
+    const accounts = makeAccounts();
+    const original = await paymentInstructions(
+      { accounts, instruction: 'SPLIT 600 NGN FROM acc1 BETWEEN acc2 AND acc3' },
+      { transactionStore }
+    );
+    const after = applyBalances(accounts, original);
+    const payload = { accounts: after, transaction_id: original.transaction_id };
+    const first = await processReversal(payload, { transactionStore });
+    const second = await processReversal(
+      { ...payload, accounts: applyBalances(after, first) },
+      { transactionStore }
+    );
+    assert.strictEqual(first.status_code, 'AP00');
+    assert.deepStrictEqual(second, first);
+    assert.deepStrictEqual(first.accounts.map((a) => a.balance), [1000, 50, 0]);
+  });
+
+  it('fails with RV01 for unknown ids and records no dry runs', async () => {
+    const transactionStore = createMemoryTransactionStore();
+    const preview = await paymentInstructions(
+      {
+        accounts: makeAccounts(),
+        instruction: 'DEBIT 300 NGN FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc2',
+        dry_run: true,
+      },
+      { transactionStore }
+    );
+    assert.strictEqual(transactionStore.size(), 0);
+    const unrecorded = await processReversal(
+      { accounts: makeAccounts(), transaction_id: preview.transaction_id },
+      { transactionStore }
+    );
+    assert.strictEqual(unrecorded.status_code, 'RV01');
+    const result = await processReversal(
+      { accounts: makeAccounts(), transaction_id: 'txn_missing' },
+      { transactionStore }
+    );
+    assert.strictEqual(result.status_code, 'RV01');
+    assert.strictEqual(result.status_reason, 'Transaction not found: txn_missing');
+  });
+});
@@ -0,0 +1,79 @@
+const assert = require('assert');
+const paymentInstructions = require('@app/services/payment-instructions/payment-instructions');
+const {
+  roundRatio,
+  convertAmount,
+  calculateFee,
+  ROUNDING_POLICY,
+} = require('@app/services/payment-instructions/helpers');
+
+describe('payment-instructions: rounding policy', () => {
+  function run(balance, instruction, options = {}) {
+    return paymentInstructions(
+      {
+        accounts: [
+          { id: 'acc1', balance, currency: 'USD' },
+          { id: 'acc2', balance: 0, currency: 'USD' },
+        ],
+        instruction,
+      },
+      options
+    );
+  }
+
+  it('defaults to half-even', () => {
+    assert.strictEqual(ROUNDING_POLICY, 'half_even');
+    assert.strictEqual(roundRatio(5n, 2n), 2n);
+    assert.strictEqual(roundRatio(7n, 2n), 4n);
+    assert.strictEqual(roundRatio(5n, 2n, 'no_such_policy'), 2n);
+  });
+
+  it('rounds ties and remainders differently under each policy', () => {
+    // 2.5, 3.5 and 1.666...
+    assert.deepStrictEqual(
+      ['half_even', 'half_up', 'truncate'].map((p) => [
+        roundRatio(5n, 2n, p),
+        roundRatio(7n, 2n, p),
+        roundRatio(5n, 3n, p),
+      ]),
+      [
+        [2n, 4n, 2n],
+        [3n, 4n, 2n],
+        [2n, 3n, 1n],
+      ]
+    );
+  });
+
+  it('rounds a balance share under options.roundingPolicy', async () => {
+    const instruction = 'DEBIT a quarter USD FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc2';
+    // a quarter of 0.10 USD is 2.5 cents, of 0.30 USD 7.5 cents
+    const expected = { half_even: [0.02, 0.08], half_up: [0.03, 0.08], truncate: [0.02, 0.07] };
+    const policies = Object.keys(expected);
+    for (let i = 0; i < policies.length; i++) {
+      const roundingPolicy = policies[i];
+      // eslint-disable-next-line no-await-in-loop
+      const low = await run(0.1, instruction, { roundingPolicy });
+      // eslint-disable-next-line no-await-in-loop
+      const high = await run(0.3, instruction, { roundingPolicy });
+      assert.deepStrictEqual([low.amount, high.amount], expected[roundingPolicy], roundingPolicy);
+    }
+    const byDefault = await run(0.1, instruction);
+    assert.strictEqual(byDefault.amount, 0.02);
+  });
+
+  it('rounds FX conversions and percentage fees the same way', async () => {
+    // 1 USD at 1500.005 is 1500.005 NGN
+    assert.strictEqual(convertAmount(1, 1500.005, 'USD', 'NGN'), 1500);
+    assert.strictEqual(convertAmount(1, 1500.005, 'USD', 'NGN', 'half_up'), 1500.01);
+    assert.strictEqual(convertAmount(1, 1500.015, 'USD', 'NGN', 'truncate'), 1500.01);
+    // 1.5% of 3.00 USD is 4.5 cents
+    const policy = { DEBIT: { percent: 1.5 } };
+    assert.strictEqual(calculateFee(3, 'DEBIT', policy, 'USD'), 0.04);
+    assert.strictEqual(calculateFee(3, 'DEBIT', policy, 'USD', 'half_up'), 0.05);
+    assert.strictEqual(calculateFee(3, 'DEBIT', policy, 'USD', 'truncate'), 0.04);
+    const instruction = 'DEBIT 3 USD FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc2';
+    const result = await run(10, instruction, { feePolicy: policy, roundingPolicy: 'half_up' });
+    assert.strictEqual(result.fee, 0.05);
+    assert.strictEqual(result.accounts[0].balance, 6.95);
+  });
+});
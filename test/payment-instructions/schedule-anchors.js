@@ -0,0 +1,94 @@
+const assert = require('assert');
+const { anchorDate, matchScheduleAnchor } = require('@app/services/payment-instructions/helpers');
+const paymentInstructions = require('@app/services/payment-instructions/payment-instructions');
+const previewSchedule = require('@app/services/payment-instructions/preview-schedule');
+const parseInstruction = require('@app/services/payment-instructions/parse-instruction');
+
+// Wednesday 2025-05-28, morning UTC; 31 May 2025 is a Saturday
+const NOW = new Date(Date.UTC(2025, 4, 28, 9, 0, 0));
+
+function ts(year, month, day, hour = 0) {
+  return Date.UTC(year, month - 1, day, hour) / 1000;
+}
+
+describe('payment-instructions: named schedule anchors', () => {
+  const scheduleAnchors = { payday: { day: 25 } };
+  function makeAccounts() {
+    return [
+      { id: 'acc1', balance: 20000, currency: 'NGN' },
+      { id: 'acc2', balance: 0, currency: 'NGN' },
+    ];
+  }
+  function run(instruction) {
+    return paymentInstructions(
+      { accounts: makeAccounts(), instruction },
+      { now: NOW, scheduleAnchors }
+    );
+  }
+
+  it('schedules a configured payday on the 25th, next month once it has passed', async () => {
+    const scheduled = await run('schedule transfer 5000 NGN from acc1 to acc2 on payday');
+    assert.strictEqual(scheduled.status_code, 'AP02');
+    assert.strictEqual(scheduled.execute_by, ts(2025, 6, 25));
+    const standing = await run('standing order 5000 NGN from acc1 to acc2 every payday at 9am');
+    assert.strictEqual(standing.execute_by, ts(2025, 6, 25, 9));
+    assert.deepStrictEqual(standing.recurrence, {
+      unit: 'month',
+      count: 1,
+      weekday: null,
+      anchor: { name: 'payday', rule: { day: 25 } },
+    });
+  });
+
+  it('moves the last business day off a weekend', async () => {
+    const result = await run(
+      'schedule transfer 5000 NGN from acc1 to acc2 on the last business day of the month'
+    );
+    assert.strictEqual(result.status_code, 'AP02');
+    assert.strictEqual(result.execute_by, ts(2025, 5, 30));
+    // 31 August 2025 is a Sunday, 1 November a Saturday
+    const rule = { day: -1, roll: 'previous' };
+    assert.deepStrictEqual(anchorDate(rule, 2025, 8), { year: 2025, month: 8, day: 29 });
+    assert.deepStrictEqual(anchorDate(rule, 2025, 7), { year: 2025, month: 7, day: 31 });
+    assert.deepStrictEqual(anchorDate({ day: 1, roll: 'next' }, 2025, 11), {
+      year: 2025,
+      month: 11,
+      day: 3,
+    });
+  });
+
+  it('previews an anchored standing order month by month', async () => {
+    const parsed = await parseInstruction(
+      'STANDING ORDER 5000 NGN FROM acc1 TO acc2 every last business day',
+      { accounts: makeAccounts() },
+      { now: NOW }
+    );
+    assert.deepStrictEqual(previewSchedule(parsed, 4), [
+      ts(2025, 5, 30),
+      ts(2025, 6, 30),
+      ts(2025, 7, 31),
+      ts(2025, 8, 29),
+    ]);
+  });
+
+  it('reads "first monday" and the like without configuration', async () => {
+    const result = await run('schedule transfer 5000 NGN from acc1 to acc2 on first monday');
+    assert.strictEqual(result.execute_by, ts(2025, 6, 2));
+    assert.deepStrictEqual(matchScheduleAnchor(['last', 'Friday']).rule, {
+      weekday: 'friday',
+      week: -1,
+    });
+    assert.strictEqual(matchScheduleAnchor(['2025-06-01']), null);
+  });
+
+  it('fails an unknown anchor naming the known ones', async () => {
+    const known = '(known: first business day, last business day, last day, payday)';
+    const scheduled = await run('schedule transfer 5000 NGN from acc1 to acc2 on bonus day');
+    assert.strictEqual(scheduled.status_code, 'DT01');
+    assert.strictEqual(scheduled.status_reason, `Unknown schedule anchor: "bonus day" ${known}`);
+    const standing = await run('standing order 5000 NGN from acc1 to acc2 every bonusday');
+    assert.strictEqual(standing.status_code, 'DT03');
+    assert.strictEqual(standing.status_reason, `Unknown schedule anchor: "bonusday" ${known}`);
+    assert.deepStrictEqual(standing.accounts, []);
+  });
+});
@@ -0,0 +1,394 @@
+const assert = require('assert');
+const {
+  parseRelativeDate,
+  parseAbsoluteDate,
+  parseTimeOfDay,
+  parseRecurrence,
+} = require('@app/services/payment-instructions/helpers');
+const paymentInstructions = require('@app/services/payment-instructions/payment-instructions');
+
+// Wednesday 2025-03-12, mid-afternoon UTC
+const NOW = new Date(Date.UTC(2025, 2, 12, 15, 30, 0));
+
+function ts(year, month, day) {
+  return Date.UTC(year, month - 1, day) / 1000;
+}
+
+describe('payment-instructions: relative schedule dates', () => {
+  const cases = [
+    ['today', { year: 2025, month: 3, day: 12 }],
+    ['tomorrow', { year: 2025, month: 3, day: 13 }],
+    ['next friday', { year: 2025, month: 3, day: 14 }],
+    ['next Wednesday', { year: 2025, month: 3, day: 19 }],
+    ['next monday', { year: 2025, month: 3, day: 17 }],
+    ['in 3 days', { year: 2025, month: 3, day: 15 }],
+    ['in twenty days', { year: 2025, month: 4, day: 1 }],
+    ['in 1 day', { year: 2025, month: 3, day: 13 }],
+    ['in 2 weeks', { year: 2025, month: 3, day: 26 }],
+    ['end of month', { year: 2025, month: 3, day: 31 }],
+    ['end of the month', { year: 2025, month: 3, day: 31 }],
+  ];
+
+  cases.forEach(([phrase, expected]) => {
+    it(`resolves "${phrase}"`, () => {
+      assert.deepStrictEqual(parseRelativeDate(phrase.split(' '), NOW), expected);
+    });
+  });
+
+  it('resolves end of month in February', () => {
+    const feb = new Date(Date.UTC(2024, 1, 3));
+    assert.deepStrictEqual(parseRelativeDate(['end', 'of', 'month'], feb), {
+      year: 2024,
+      month: 2,
+      day: 29,
+    });
+  });
+
+  ['yesterday', 'next', 'next week', 'in 0 days', 'in -3 days', 'in three', 'end of year'].forEach(
+    (phrase) => {
+      it(`rejects "${phrase}"`, () => {
+        assert.strictEqual(parseRelativeDate(phrase.split(' '), NOW), null);
+      });
+    }
+  );
+
+  const accounts = [
+    { id: 'acc1', balance: 5000, currency: 'NGN' },
+    { id: 'acc2', balance: 0, currency: 'NGN' },
+  ];
+
+  it('schedules a transfer for tomorrow as pending with a Unix timestamp', async () => {
+    const result = await paymentInstructions(
+      { accounts, instruction: 'schedule transfer of 1000 NGN from acc1 to acc2 tomorrow' },
+      { now: NOW }
+    );
+    assert.strictEqual(result.type, 'SCHEDULE');
+    assert.strictEqual(result.status_code, 'AP02');
+    assert.strictEqual(result.execute_by, ts(2025, 3, 13));
+    assert.strictEqual(result.debit_account, 'acc1');
+    assert.strictEqual(result.credit_account, 'acc2');
+    assert.strictEqual(result.accounts[0].balance, 5000);
+  });
+
+  it('accepts the DEBIT form with an ON clause', async () => {
+    const result = await paymentInstructions(
+      {
+        accounts,
+        instruction:
+          'SCHEDULE DEBIT 500 NGN FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc2 ON next friday',
+      },
+      { now: NOW.getTime() }
+    );
+    assert.strictEqual(result.type, 'SCHEDULE');
+    assert.strictEqual(result.execute_by, ts(2025, 3, 14));
+  });
+
+  it('executes a schedule for today immediately', async () => {
+    const result = await paymentInstructions(
+      {
+        accounts,
+        instruction: 'SCHEDULE TRANSFER 1000 NGN FROM ACCOUNT acc1 TO ACCOUNT acc2 today',
+      },
+      { now: NOW }
+    );
+    assert.strictEqual(result.status_code, 'AP00');
+    assert.strictEqual(result.execute_by, ts(2025, 3, 12));
+    assert.strictEqual(result.accounts[1].balance, 1000);
+  });
+
+  it('returns DT02 for a scheduled date in the past', async () => {
+    const result = await paymentInstructions(
+      { accounts, instruction: 'schedule transfer 1000 NGN from acc1 to acc2 on 2025-03-11' },
+      { now: NOW }
+    );
+    assert.strictEqual(result.status_code, 'DT02');
+    assert.strictEqual(result.execute_by, ts(2025, 3, 11));
+  });
+
+  it('returns DT01 for a missing or unknown schedule date', async () => {
+    const missing = await paymentInstructions(
+      { accounts, instruction: 'schedule transfer 1000 NGN from acc1 to acc2' },
+      { now: NOW }
+    );
+    assert.strictEqual(missing.status_code, 'DT01');
+    const unknown = await paymentInstructions(
+      { accounts, instruction: 'schedule transfer 1000 NGN from acc1 to acc2 someday' },
+      { now: NOW }
+    );
+    assert.strictEqual(unknown.status_code, 'DT01');
+  });
+
+  it('runs a TRANSFER outside a SCHEDULE straight away', async () => {
+    const result = await paymentInstructions({
+      accounts,
+      instruction: 'TRANSFER 1000 NGN FROM ACCOUNT acc1 TO ACCOUNT acc2',
+    });
+    assert.strictEqual(result.status_code, 'AP00');
+    assert.strictEqual(result.type, 'TRANSFER');
+    assert.strictEqual(result.execute_by, null);
+  });
+});
+
+describe('payment-instructions: absolute schedule dates', () => {
+  const formats = [
+    ['2025-03-15', {}, ts(2025, 3, 15), false],
+    ['2025-03-15T14:30', {}, ts(2025, 3, 15) + 14 * 3600 + 30 * 60, true],
+    ['2025-03-15T14:30:15Z', {}, ts(2025, 3, 15) + 14 * 3600 + 30 * 60 + 15, true],
+    ['2025-03-15T09:00+01:00', {}, ts(2025, 3, 15) + 8 * 3600, true],
+    ['2025-03-15T09:00-02:30', {}, ts(2025, 3, 15) + 11 * 3600 + 30 * 60, true],
+    ['15/03/2025', {}, ts(2025, 3, 15), false],
+    ['15-03-2025', {}, ts(2025, 3, 15), false],
+    ['15.03.2025', {}, ts(2025, 3, 15), false],
+    ['5/3/2025', {}, ts(2025, 3, 5), false],
+    ['03/15/2025', {}, ts(2025, 3, 15), false],
+    ['03/04/2025', {}, ts(2025, 4, 3), false],
+    ['03/04/2025', { dayFirst: true }, ts(2025, 4, 3), false],
+    ['03/04/2025', { dayFirst: false }, ts(2025, 3, 4), false],
+    ['15/03/2025', { dayFirst: false }, ts(2025, 3, 15), false],
+    ['2024-02-29', {}, ts(2024, 2, 29), false],
+  ];
+
+  formats.forEach(([token, options, expected, hasTime]) => {
+    it(`parses ${token} ${JSON.stringify(options)}`, () => {
+      const parsed = parseAbsoluteDate(token, options);
+      assert.strictEqual(parsed.valid, true);
+      assert.strictEqual(parsed.timestamp, expected);
+      assert.strictEqual(parsed.hasTime, hasTime);
+    });
+  });
+
+  ['2025-02-30', '2025-02-29', '31/04/2025', '2025-13-01', '00/01/2025', '13/13/2025'].forEach(
+    (token) => {
+      it(`flags the impossible date ${token}`, () => {
+        assert.strictEqual(parseAbsoluteDate(token).valid, false);
+      });
+    }
+  );
+
+  ['2025/03', '15/03/25', '2025-3-15', 'March', '2025-03-15T25:00', '2025-03-15T10:00+1'].forEach(
+    (token) => {
+      it(`does not recognise ${token}`, () => {
+        assert.strictEqual(parseAbsoluteDate(token), null);
+      });
+    }
+  );
+
+  const accounts = [
+    { id: 'acc1', balance: 5000, currency: 'NGN' },
+    { id: 'acc2', balance: 0, currency: 'NGN' },
+  ];
+
+  it('schedules on a DD/MM/YYYY date using the configured policy', async () => {
+    const dayFirst = await paymentInstructions(
+      { accounts, instruction: 'schedule transfer 100 NGN from acc1 to acc2 on 04/05/2025' },
+      { now: NOW }
+    );
+    assert.strictEqual(dayFirst.status_code, 'AP02');
+    assert.strictEqual(dayFirst.execute_by, ts(2025, 5, 4));
+    const monthFirst = await paymentInstructions(
+      { accounts, instruction: 'schedule transfer 100 NGN from acc1 to acc2 on 04/05/2025' },
+      { now: NOW, dayFirst: false }
+    );
+    assert.strictEqual(monthFirst.execute_by, ts(2025, 4, 5));
+  });
+
+  it('rejects an impossible date with DT01', async () => {
+    const result = await paymentInstructions(
+      { accounts, instruction: 'schedule transfer 100 NGN from acc1 to acc2 on 2025-02-30' },
+      { now: NOW }
+    );
+    assert.strictEqual(result.status_code, 'DT01');
+    assert.ok(result.status_reason.indexOf('2025-02-30') !== -1);
+  });
+
+  it('compares a scheduled time of day against now', async () => {
+    const earlier = await paymentInstructions(
+      { accounts, instruction: 'schedule transfer 100 NGN from acc1 to acc2 on 2025-03-12T09:00' },
+      { now: NOW }
+    );
+    assert.strictEqual(earlier.status_code, 'DT02');
+    const later = await paymentInstructions(
+      { accounts, instruction: 'schedule transfer 100 NGN from acc1 to acc2 on 2025-03-12T18:00' },
+      { now: NOW }
+    );
+    assert.strictEqual(later.status_code, 'AP02');
+    assert.strictEqual(later.execute_by, ts(2025, 3, 12) + 18 * 3600);
+  });
+});
+
+describe('payment-instructions: schedule times of day', () => {
+  const accounts = [
+    { id: 'acc1', balance: 1000, currency: 'NGN' },
+    { id: 'acc2', balance: 0, currency: 'NGN' },
+  ];
+  function schedule(when, options = {}) {
+    return paymentInstructions(
+      { accounts, instruction: `schedule transfer 100 NGN from acc1 to acc2 ${when}` },
+      { now: NOW, ...options }
+    );
+  }
+
+  const times = [
+    ['14:30', 14 * 3600 + 30 * 60],
+    ['9:05', 9 * 3600 + 5 * 60],
+    ['23:59:30', 23 * 3600 + 59 * 60 + 30],
+    ['2:30pm', 14 * 3600 + 30 * 60],
+    ['2PM', 14 * 3600],
+    ['12am', 0],
+    ['12:15 pm', 12 * 3600 + 15 * 60],
+  ];
+  times.forEach(([text, seconds]) => {
+    it(`reads "${text}"`, () => {
+      assert.deepStrictEqual(parseTimeOfDay(text.split(' ')), { seconds, valid: true });
+    });
+  });
+
+  it('tells impossible times from words that are no time', () => {
+    ['25:00', '13pm', '0am', '9:75', '14:30:60'].forEach((text) => {
+      assert.strictEqual(parseTimeOfDay([text]).valid, false);
+    });
+    ['14', 'noon', '2:3pm', '14:30pm:00', 'friday'].forEach((text) => {
+      assert.strictEqual(parseTimeOfDay([text]), null);
+    });
+  });
+
+  it('puts "2:30pm" and "14:30" into execute_by', async () => {
+    const twelveHour = await schedule('tomorrow at 2:30pm');
+    assert.strictEqual(twelveHour.status_code, 'AP02');
+    assert.strictEqual(twelveHour.execute_by, ts(2025, 3, 13) + 14 * 3600 + 30 * 60);
+    const dated = await schedule('on 2025-04-01 at 14:30');
+    assert.strictEqual(dated.execute_by, ts(2025, 4, 1) + 14 * 3600 + 30 * 60);
+    const bare = await schedule('on 2025-04-01 2:30 pm');
+    assert.strictEqual(bare.execute_by, dated.execute_by);
+  });
+
+  it('reads the time at options.utcOffsetMinutes', async () => {
+    const lagos = await schedule('tomorrow at 14:30', { utcOffsetMinutes: 60 });
+    assert.strictEqual(lagos.execute_by, ts(2025, 3, 13) + 13 * 3600 + 30 * 60);
+  });
+
+  it('compares a time later today against now', async () => {
+    const past = await schedule('today at 9am');
+    assert.strictEqual(past.status_code, 'DT02');
+    const later = await schedule('today at 6pm');
+    assert.strictEqual(later.status_code, 'AP02');
+    assert.strictEqual(later.execute_by, ts(2025, 3, 12) + 18 * 3600);
+  });
+
+  it('rejects an impossible time with DT01', async () => {
+    const result = await schedule('tomorrow at 25:00');
+    assert.strictEqual(result.status_code, 'DT01');
+    assert.strictEqual(
+      result.status_reason,
+      'Invalid time of day. Expected HH:MM or h[:MM]am/pm: 25:00'
+    );
+    assert.strictEqual((await schedule('tomorrow at 13pm')).status_code, 'DT01');
+    assert.strictEqual((await schedule('tomorrow at teatime')).status_code, 'DT01');
+    assert.strictEqual((await schedule('on 2025-04-01T09:00 at 10:00')).status_code, 'DT01');
+  });
+
+  it('runs a standing order at its time of day', async () => {
+    const result = await paymentInstructions(
+      { accounts, instruction: 'standing order 100 NGN from acc1 to acc2 every friday at 9am' },
+      { now: NOW }
+    );
+    assert.strictEqual(result.status_code, 'AP02');
+    assert.strictEqual(result.execute_by, ts(2025, 3, 14) + 9 * 3600);
+  });
+});
+
+describe('payment-instructions: standing orders', () => {
+  const recurrences = [
+    ['daily', { unit: 'day', count: 1, weekday: null }, [2025, 3, 12]],
+    ['every day', { unit: 'day', count: 1, weekday: null }, [2025, 3, 12]],
+    ['every 3 days', { unit: 'day', count: 3, weekday: null }, [2025, 3, 12]],
+    ['weekly', { unit: 'week', count: 1, weekday: null }, [2025, 3, 12]],
+    ['every two weeks', { unit: 'week', count: 2, weekday: null }, [2025, 3, 12]],
+    ['every friday', { unit: 'week', count: 1, weekday: 'friday' }, [2025, 3, 14]],
+    ['every Wednesday', { unit: 'week', count: 1, weekday: 'wednesday' }, [2025, 3, 12]],
+    ['every other monday', { unit: 'week', count: 2, weekday: 'monday' }, [2025, 3, 17]],
+    ['monthly', { unit: 'month', count: 1, weekday: null }, [2025, 3, 12]],
+    ['every month', { unit: 'month', count: 1, weekday: null }, [2025, 3, 12]],
+    ['every 6 months', { unit: 'month', count: 6, weekday: null }, [2025, 3, 12]],
+  ];
+
+  recurrences.forEach(([phrase, expected, [year, month, day]]) => {
+    it(`extracts "${phrase}"`, () => {
+      const parsed = parseRecurrence(phrase.split(' '), NOW);
+      assert.deepStrictEqual(
+        { unit: parsed.unit, count: parsed.count, weekday: parsed.weekday },
+        expected
+      );
+      assert.deepStrictEqual(parsed.first, { year, month, day });
+    });
+  });
+
+  ['every bluemonday', 'every', 'every 0 days', 'every hour', 'every minute', 'hourly'].forEach(
+    (phrase) => {
+      it(`rejects "${phrase}"`, () => {
+        assert.strictEqual(parseRecurrence(phrase.split(' '), NOW), null);
+      });
+    }
+  );
+
+  const accounts = [
+    { id: 'acc1', balance: 20000, currency: 'NGN' },
+    { id: 'acc2', balance: 0, currency: 'NGN' },
+  ];
+
+  it('returns the recurrence alongside the first execute_by', async () => {
+    const result = await paymentInstructions(
+      { accounts, instruction: 'standing order 5000 NGN from acc1 to acc2 every friday' },
+      { now: NOW }
+    );
+    assert.strictEqual(result.type, 'STANDING_ORDER');
+    assert.strictEqual(result.status_code, 'AP02');
+    assert.strictEqual(result.execute_by, ts(2025, 3, 14));
+    assert.deepStrictEqual(result.recurrence, { unit: 'week', count: 1, weekday: 'friday' });
+  });
+
+  it('honours a STARTING date', async () => {
+    const result = await paymentInstructions(
+      {
+        accounts,
+        instruction: 'STANDING ORDER 5000 NGN FROM acc1 TO acc2 every month starting 01/04/2025',
+      },
+      { now: NOW }
+    );
+    assert.strictEqual(result.status_code, 'AP02');
+    assert.strictEqual(result.execute_by, ts(2025, 4, 1));
+    assert.deepStrictEqual(result.recurrence, { unit: 'month', count: 1, weekday: null });
+  });
+
+  it('runs the first instalment immediately when it falls today', async () => {
+    const result = await paymentInstructions(
+      { accounts, instruction: 'standing order 5000 NGN from acc1 to acc2 daily' },
+      { now: NOW }
+    );
+    assert.strictEqual(result.status_code, 'AP00');
+    assert.strictEqual(result.execute_by, ts(2025, 3, 12));
+    assert.strictEqual(result.accounts[0].balance, 15000);
+  });
+
+  it('returns DT03 for a malformed recurrence', async () => {
+    const malformed = await paymentInstructions(
+      { accounts, instruction: 'standing order 5000 NGN from acc1 to acc2 every bluemonday' },
+      { now: NOW }
+    );
+    assert.strictEqual(malformed.status_code, 'DT03');
+    const missing = await paymentInstructions(
+      { accounts, instruction: 'standing order 5000 NGN from acc1 to acc2' },
+      { now: NOW }
+    );
+    assert.strictEqual(missing.status_code, 'DT03');
+  });
+
+  it('keeps single-date SCHEDULE responses free of a recurrence', async () => {
+    const result = await paymentInstructions(
+      { accounts, instruction: 'schedule transfer 100 NGN from acc1 to acc2 tomorrow' },
+      { now: NOW }
+    );
+    assert.strictEqual(result.type, 'SCHEDULE');
+    assert.strictEqual(result.recurrence, undefined);
+  });
+});
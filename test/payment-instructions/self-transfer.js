@@ -0,0 +1,57 @@
+const assert = require('assert');
+const paymentInstructions = require('@app/services/payment-instructions/payment-instructions');
+
+describe('payment-instructions: self-transfers', () => {
+  function makeAccounts() {
+    return [
+      { id: 'acc1', balance: 500, currency: 'NGN' },
+      { id: 'acc2', balance: 100, currency: 'NGN' },
+    ];
+  }
+  const unchanged = [{ id: 'acc1', balance: 500, balance_before: 500, currency: 'NGN' }];
+
+  it('rejects the same id on both sides and leaves the balance unchanged', async () => {
+    const result = await paymentInstructions({
+      accounts: makeAccounts(),
+      instruction: 'DEBIT 100 NGN FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc1',
+    });
+    assert.strictEqual(result.status, 'failed');
+    assert.strictEqual(result.status_code, 'AC02');
+    assert.strictEqual(result.status_reason, 'Debit and credit accounts cannot be the same');
+    assert.deepStrictEqual(result.accounts, unchanged);
+  });
+
+  it('catches two aliases that point at the same account', async () => {
+    const result = await paymentInstructions({
+      accounts: makeAccounts(),
+      aliases: { rent: 'acc1', savings: 'acc1' },
+      instruction: 'DEBIT 100 NGN FROM ACCOUNT rent FOR CREDIT TO ACCOUNT savings',
+    });
+    assert.strictEqual(result.status_code, 'AC02');
+    assert.strictEqual(
+      result.status_reason,
+      'Debit and credit accounts cannot be the same: rent and savings are both acc1'
+    );
+    assert.strictEqual(result.debit_account, 'acc1');
+    assert.strictEqual(result.credit_account, 'acc1');
+    assert.deepStrictEqual(result.accounts, unchanged);
+  });
+
+  it('catches an alias that names the other side by id', async () => {
+    const result = await paymentInstructions({
+      accounts: makeAccounts(),
+      aliases: { rent: 'acc1' },
+      instruction: 'SCHEDULE TRANSFER 100 NGN FROM rent TO acc1 ON 2099-01-01',
+    });
+    assert.strictEqual(result.status_code, 'AC02');
+    assert.deepStrictEqual(result.accounts, unchanged);
+  });
+
+  it('reports the self-transfer before a currency problem', async () => {
+    const result = await paymentInstructions({
+      accounts: makeAccounts(),
+      instruction: 'DEBIT 100 USD FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc1',
+    });
+    assert.strictEqual(result.status_code, 'AC02');
+  });
+});
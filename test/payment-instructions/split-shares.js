@@ -0,0 +1,77 @@
+const assert = require('assert');
+const paymentInstructions = require('@app/services/payment-instructions/payment-instructions');
+const { splitAmountByWeights } = require('@app/services/payment-instructions/helpers');
+
+describe('payment-instructions: SPLIT by percentage and parts', () => {
+  function makeAccounts() {
+    return [
+      { id: 'acc1', balance: 20000, currency: 'NGN' },
+      { id: 'acc2', balance: 0, currency: 'NGN' },
+      { id: 'acc3', balance: 0, currency: 'NGN' },
+      { id: 'acc4', balance: 0, currency: 'NGN' },
+    ];
+  }
+  function run(instruction) {
+    return paymentInstructions({ accounts: makeAccounts(), instruction });
+  }
+
+  it('splits by percentage shares', async () => {
+    const result = await run('split 10000 NGN from acc1 to acc2 60% and acc3 40%');
+    assert.strictEqual(result.status_code, 'AP00');
+    assert.deepStrictEqual(result.splits, [
+      { account: 'acc2', amount: 6000 },
+      { account: 'acc3', amount: 4000 },
+    ]);
+    assert.deepStrictEqual(result.accounts.map((a) => a.balance), [10000, 6000, 4000]);
+  });
+
+  it('accepts percentages that add up to 100 once rounded', async () => {
+    const result = await run(
+      'SPLIT 100 NGN FROM acc1 TO acc2 33.33%, acc3 33.33 percent AND acc4 33.33%'
+    );
+    assert.strictEqual(result.status_code, 'AP00');
+    assert.deepStrictEqual(result.splits.map((s) => s.amount), [33.34, 33.33, 33.33]);
+  });
+
+  it('fails percentages that do not add up to 100 with their own reason', async () => {
+    const result = await run('SPLIT 100 NGN FROM acc1 TO acc2 60% AND acc3 30%');
+    assert.strictEqual(result.status_code, 'AM02');
+    assert.strictEqual(
+      result.status_reason,
+      'Split percentages must add up to 100: shares total 90%'
+    );
+    assert.ok(result.accounts.every((a) => a.balance === a.balance_before));
+  });
+
+  it('splits by parts written before or after each recipient', async () => {
+    const leading = await run('split 10000 NGN from acc1 2 parts to acc2 1 part to acc3');
+    assert.strictEqual(leading.status_code, 'AP00');
+    assert.deepStrictEqual(leading.splits, [
+      { account: 'acc2', amount: 6666.67 },
+      { account: 'acc3', amount: 3333.33 },
+    ]);
+    const trailing = await run('SPLIT 100 NGN FROM acc1 TO acc2 1 part and acc3 2 parts');
+    assert.deepStrictEqual(trailing.splits.map((s) => s.amount), [33.33, 66.67]);
+  });
+
+  it('hands the leftover minor units to the largest remainders, first on a tie', () => {
+    assert.deepStrictEqual(splitAmountByWeights(100, [1, 1, 1], 'NGN'), [33.34, 33.33, 33.33]);
+    assert.deepStrictEqual(splitAmountByWeights(100, [1, 2], 'NGN'), [33.33, 66.67]);
+    assert.deepStrictEqual(splitAmountByWeights(10, [1, 1, 1, 3], 'JPY'), [2, 2, 1, 5]);
+    assert.deepStrictEqual(splitAmountByWeights(0.05, [7, 3], 'USD'), [0.04, 0.01]);
+  });
+
+  it('rejects shares that are not positive percentages or whole parts', async () => {
+    const reason =
+      'Split shares must be positive percentages with up to two decimals or whole numbers of parts';
+    const fractional = await run('SPLIT 100 NGN FROM acc1 TO acc2 1.5 parts and acc3 2 parts');
+    assert.strictEqual(fractional.status_code, 'AM01');
+    assert.strictEqual(fractional.status_reason, reason);
+    const zero = await run('SPLIT 100 NGN FROM acc1 TO acc2 0% and acc3 100%');
+    assert.strictEqual(zero.status_code, 'AM01');
+    const mixed = await run('SPLIT 100 NGN FROM acc1 TO acc2 60% and acc3 40 parts');
+    assert.strictEqual(mixed.status_code, 'SY03');
+    const equally = await run('SPLIT 100 NGN FROM acc1 EQUALLY BETWEEN acc2 50% and acc3 50%');
+    assert.strictEqual(equally.status_code, 'SY03');
+  });
+});
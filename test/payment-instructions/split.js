@@ -0,0 +1,112 @@
+const assert = require('assert');
+const paymentInstructions = require('@app/services/payment-instructions/payment-instructions');
+
+describe('payment-instructions: SPLIT', () => {
+  function makeAccounts() {
+    return [
+      { id: 'acc1', balance: 10000, currency: 'NGN' },
+      { id: 'acc2', balance: 0, currency: 'NGN' },
+      { id: 'acc3', balance: 100, currency: 'NGN' },
+      { id: 'acc4', balance: 0, currency: 'NGN' },
+    ];
+  }
+  function run(instruction, accounts = makeAccounts()) {
+    return paymentInstructions({ accounts, instruction });
+  }
+
+  it('splits equally and gives the leftover minor unit to the first recipient', async () => {
+    const result = await run('split 10000 NGN from acc1 equally between acc2, acc3 and acc4');
+    assert.strictEqual(result.type, 'SPLIT');
+    assert.strictEqual(result.status_code, 'AP00');
+    assert.strictEqual(result.debit_account, 'acc1');
+    assert.strictEqual(result.credit_account, null);
+    assert.deepStrictEqual(result.splits, [
+      { account: 'acc2', amount: 3333.34 },
+      { account: 'acc3', amount: 3333.33 },
+      { account: 'acc4', amount: 3333.33 },
+    ]);
+    assert.deepStrictEqual(result.accounts, [
+      { id: 'acc1', balance: 0, balance_before: 10000, currency: 'NGN' },
+      { id: 'acc2', balance: 3333.34, balance_before: 0, currency: 'NGN' },
+      { id: 'acc3', balance: 3433.33, balance_before: 100, currency: 'NGN' },
+      { id: 'acc4', balance: 3333.33, balance_before: 0, currency: 'NGN' },
+    ]);
+  });
+
+  it('splits a zero-decimal currency in whole units', async () => {
+    const accounts = [
+      { id: 'u1', balance: 100, currency: 'UGX' },
+      { id: 'u2', balance: 0, currency: 'UGX' },
+      { id: 'u3', balance: 0, currency: 'UGX' },
+    ];
+    const result = await run('SPLIT 100 UGX FROM ACCOUNT u1 AMONG u2 AND u3', accounts);
+    assert.deepStrictEqual(result.splits, [
+      { account: 'u2', amount: 50 },
+      { account: 'u3', amount: 50 },
+    ]);
+    const selfSplit = await run('SPLIT 100 UGX FROM ACCOUNT u1 AMONG u2, u3 AND u1', accounts);
+    assert.strictEqual(selfSplit.status_code, 'AC02');
+  });
+
+  it('credits explicit per-recipient amounts', async () => {
+    const result = await run(
+      'SPLIT 9000 NGN FROM ACCOUNT acc1 TO acc2 5000, acc3 3000 and acc4 1000'
+    );
+    assert.strictEqual(result.status_code, 'AP00');
+    assert.deepStrictEqual(result.splits, [
+      { account: 'acc2', amount: 5000 },
+      { account: 'acc3', amount: 3000 },
+      { account: 'acc4', amount: 1000 },
+    ]);
+    assert.deepStrictEqual(result.accounts.map((a) => a.balance), [1000, 5000, 3100, 1000]);
+  });
+
+  it('rejects explicit amounts that do not add up to the total', async () => {
+    const result = await run('split 9000 NGN from acc1 to acc2 5000, acc3 3000');
+    assert.strictEqual(result.status_code, 'AM02');
+    assert.strictEqual(
+      result.status_reason,
+      'Split amounts must add up to the total amount: shares total 8000 NGN, amount is 9000 NGN'
+    );
+  });
+
+  it('fails atomically when the debit balance cannot cover the total', async () => {
+    const accounts = makeAccounts();
+    const result = await run(
+      'split 12000 NGN from acc1 equally between acc2, acc3 and acc4',
+      accounts
+    );
+    assert.strictEqual(result.status, 'failed');
+    assert.strictEqual(result.status_code, 'AC01');
+    assert.deepStrictEqual(
+      result.accounts.map((a) => [a.id, a.balance, a.balance_before]),
+      [
+        ['acc1', 10000, 10000],
+        ['acc2', 0, 0],
+        ['acc3', 100, 100],
+        ['acc4', 0, 0],
+      ]
+    );
+    assert.deepStrictEqual(accounts, makeAccounts());
+  });
+
+  it('rejects malformed recipient lists', async () => {
+    const missingSeparator = await run('split 100 NGN from acc1 between acc2 acc3');
+    assert.strictEqual(missingSeparator.status_code, 'SY03');
+    const mixed = await run('split 100 NGN from acc1 to acc2 60, acc3');
+    assert.strictEqual(mixed.status_code, 'SY03');
+    const equallyWithAmounts = await run(
+      'split 100 NGN from acc1 equally between acc2 60 and acc3 40'
+    );
+    assert.strictEqual(equallyWithAmounts.status_code, 'SY03');
+  });
+
+  it('reports unknown recipients and currency mismatches', async () => {
+    const unknown = await run('split 100 NGN from acc1 between acc2 and acc9');
+    assert.strictEqual(unknown.status_code, 'AC03');
+    const accounts = makeAccounts();
+    accounts[3].currency = 'USD';
+    const mismatch = await run('split 100 NGN from acc1 between acc2 and acc4', accounts);
+    assert.strictEqual(mismatch.status_code, 'CU01');
+  });
+});
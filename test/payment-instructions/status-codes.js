@@ -0,0 +1,106 @@
+const assert = require('assert');
+const fs = require('fs');
+const path = require('path');
+const {
+  StatusCodes,
+  StatusMessages,
+  ReasonCategories,
+  StatusCategories,
+  getStatusMessage,
+  getReasonCategory,
+} = require('@app/messages/payment-instruction-status-codes');
+const paymentInstructions = require('@app/services/payment-instructions/payment-instructions');
+
+const SERVICE_DIR = path.join(__dirname, '../../services/payment-instructions');
+
+// Every quoted 'XX00'-shaped literal in the service sources
+function emittedCodes(dir) {
+  const codes = new Set();
+  fs.readdirSync(dir, { withFileTypes: true }).forEach((entry) => {
+    const file = path.join(dir, entry.name);
+    if (entry.isDirectory()) {
+      emittedCodes(file).forEach((code) => codes.add(code));
+      return;
+    }
+    if (!entry.name.endsWith('.js')) return;
+    const source = fs.readFileSync(file, 'utf8');
+    for (let i = 0; i + 5 < source.length; i++) {
+      const code = source.substring(i + 1, i + 5);
+      const isCode =
+        source[i] === "'" &&
+        source[i + 5] === "'" &&
+        code[0] >= 'A' &&
+        code[0] <= 'Z' &&
+        code[1] >= 'A' &&
+        code[1] <= 'Z' &&
+        code[2] >= '0' &&
+        code[2] <= '9' &&
+        code[3] >= '0' &&
+        code[3] <= '9';
+      if (isCode) codes.add(code);
+    }
+  });
+  return codes;
+}
+
+describe('payment-instructions: status code catalog', () => {
+  it('registers a message for every code the services can emit', () => {
+    const codes = emittedCodes(SERVICE_DIR);
+    assert.ok(codes.has('AP00'));
+    codes.forEach((code) => {
+      assert.strictEqual(StatusCodes[code], code, `${code} is missing from StatusCodes`);
+      assert.strictEqual(typeof getStatusMessage(code), 'string', `${code} has no message`);
+    });
+    assert.deepStrictEqual(Object.keys(StatusMessages).sort(), Object.keys(StatusCodes).sort());
+  });
+
+  it('looks messages up by code', async () => {
+    assert.strictEqual(getStatusMessage(StatusCodes.AP00), 'Transaction executed successfully');
+    assert.strictEqual(getStatusMessage('ZZ99'), null);
+    const result = await paymentInstructions({
+      accounts: [
+        { id: 'acc1', balance: 10, currency: 'NGN' },
+        { id: 'acc2', balance: 0, currency: 'NGN' },
+      ],
+      instruction: 'DEBIT 100 NGN FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc2',
+    });
+    assert.strictEqual(result.status_code, StatusCodes.AC01);
+    assert.ok(result.status_reason.startsWith(getStatusMessage(result.status_code)));
+  });
+
+  it('maps every status code to a reason category', () => {
+    const categories = Object.values(ReasonCategories);
+    assert.deepStrictEqual(Object.keys(StatusCategories).sort(), Object.keys(StatusCodes).sort());
+    Object.keys(StatusCodes).forEach((code) => {
+      assert.ok(categories.indexOf(getReasonCategory(code)) !== -1, `${code} has no category`);
+    });
+    assert.strictEqual(getReasonCategory('LM02'), 'limits');
+    assert.strictEqual(getReasonCategory('ZZ99'), null);
+  });
+
+  it('puts reason_category on failures only', async () => {
+    const accounts = [
+      { id: 'acc1', balance: 10, currency: 'NGN' },
+      { id: 'acc2', balance: 0, currency: 'NGN' },
+    ];
+    const cases = [
+      ['DEBIT 100 NGN FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc2', 'AC01', 'funds'],
+      ['DEBIT 5 NGN FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc9', 'AC03', 'account'],
+      ['DEBIT 5 NGN FROM acc1', 'SY02', 'parse_error'],
+      ['DEBIT 5 XYZ FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc2', 'CU04', 'validation'],
+    ];
+    for (let i = 0; i < cases.length; i++) {
+      const [instruction, code, category] = cases[i];
+      // eslint-disable-next-line no-await-in-loop
+      const result = await paymentInstructions({ accounts, instruction });
+      assert.strictEqual(result.status_code, code);
+      assert.strictEqual(result.reason_category, category);
+    }
+    const done = await paymentInstructions({
+      accounts,
+      instruction: 'DEBIT 5 NGN FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc2',
+    });
+    assert.strictEqual(done.status_code, 'AP00');
+    assert.strictEqual('reason_category' in done, false);
+  });
+});
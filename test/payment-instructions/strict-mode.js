@@ -0,0 +1,83 @@
+const assert = require('assert');
+const paymentInstructions = require('@app/services/payment-instructions/payment-instructions');
+
+describe('payment-instructions: strict mode', () => {
+  function makeAccounts() {
+    return [
+      { id: 'acc1', balance: 1000, currency: 'NGN' },
+      { id: 'acc2', balance: 0, currency: 'NGN' },
+      { id: 'acc-00014821', balance: 1000, currency: 'NGN' },
+    ];
+  }
+  function run(instruction, extra = {}, options = {}) {
+    return paymentInstructions({ accounts: makeAccounts(), instruction, ...extra }, options);
+  }
+
+  // Each passes leniently and names the guess strict mode refuses
+  const cases = [
+    ['transfer 100 NGN from acc1 to rent', { aliases: { rent: 'acc2' } }, 'account named by alias'],
+    ['trasnfer 100 NGN from acc1 to acc2', { fuzzy_keywords: true }, 'mistyped keyword'],
+    ['transfer 100 from acc1 to acc2', {}, 'currency not named exactly'],
+    ['transfer 100 from acc1 to acc2', { default_currency: 'NGN' }, 'currency not named exactly'],
+    [
+      'transfer 100 NGN from ACC1 to acc2',
+      { case_insensitive_ids: true },
+      'account id in a different case',
+    ],
+    ['transfer 100 NGN from ***4821 to acc2', {}, 'account named by trailing digits'],
+    ['transfer five hundred NGN from acc1 to acc2', {}, 'amount in words'],
+    ['transfer half NGN from acc1 to acc2', {}, 'amount as a share of the balance'],
+  ];
+
+  cases.forEach(([instruction, extra, guess]) => {
+    it(`refuses "${instruction}" (${guess})`, async () => {
+      const lenient = await run(instruction, extra);
+      assert.strictEqual(lenient.status_code, 'AP00');
+      const strict = await run(instruction, { ...extra, strict: true });
+      assert.strictEqual(strict.status, 'failed');
+      assert.strictEqual(strict.status_code, 'SY06');
+      assert.strictEqual(
+        strict.status_reason,
+        `Strict mode: instruction must be literal: ${guess}`
+      );
+      assert.deepStrictEqual(strict.accounts, []);
+    });
+  });
+
+  it('names every kind of guess once and accepts options.strict', async () => {
+    const result = await run(
+      'trasnfer 100 from acc1 to rent',
+      { aliases: { rent: 'acc2' }, fuzzy_keywords: true },
+      { strict: true }
+    );
+    const guesses = 'mistyped keyword, currency not named exactly, account named by alias';
+    assert.strictEqual(result.status_code, 'SY06');
+    assert.strictEqual(result.status_reason.split(': ')[2], guesses);
+  });
+
+  it('still executes a literal instruction', async () => {
+    const result = await run('DEBIT 100 NGN FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc2', {
+      strict: true,
+    });
+    assert.strictEqual(result.status_code, 'AP00');
+    assert.strictEqual(result.confidence, 1);
+  });
+
+  it('applies to splits, withdrawals and the fee account', async () => {
+    const aliases = { rent: 'acc2', bills: 'acc-00014821' };
+    const split = await run('split 100 NGN from acc1 equally between acc2 and rent', {
+      aliases,
+      strict: true,
+    });
+    assert.strictEqual(split.status_code, 'SY06');
+    const withdrawal = await run('withdraw 100 from acc1', { strict: true });
+    assert.strictEqual(withdrawal.status_code, 'SY06');
+    const fee = await run(
+      'transfer 100 NGN from acc1 to acc2, fee from bills',
+      { aliases, strict: true },
+      { feePolicy: { TRANSFER: { flat: 10 } } }
+    );
+    assert.strictEqual(fee.status_code, 'AC03');
+    assert.strictEqual(fee.status_reason, 'Account not found: fee account bills');
+  });
+});
@@ -0,0 +1,76 @@
+const assert = require('assert');
+const paymentInstructions = require('@app/services/payment-instructions/payment-instructions');
+const { zoneOffsetSeconds } = require('@app/services/payment-instructions/helpers');
+
+// Wednesday 2025-03-12, mid-afternoon UTC
+const NOW = new Date(Date.UTC(2025, 2, 12, 15, 30, 0));
+
+function ts(year, month, day, hour = 0, minute = 0) {
+  return Date.UTC(year, month - 1, day, hour, minute) / 1000;
+}
+
+describe('payment-instructions: timezone-aware scheduling', () => {
+  function makeAccounts() {
+    return [
+      { id: 'acc1', balance: 1000, currency: 'NGN' },
+      { id: 'acc2', balance: 0, currency: 'NGN' },
+    ];
+  }
+  function schedule(when, timezone, options = {}) {
+    const payload = {
+      accounts: makeAccounts(),
+      instruction: `schedule transfer 100 NGN from acc1 to acc2 ${when}`,
+    };
+    if (timezone !== undefined) payload.timezone = timezone;
+    return paymentInstructions(payload, { now: NOW, ...options });
+  }
+
+  it('reads the same "tomorrow 9am" as a different UTC instant per timezone', async () => {
+    const lagos = await schedule('tomorrow 9am', 'Africa/Lagos');
+    const newYork = await schedule('tomorrow 9am', 'America/New_York');
+    assert.strictEqual(lagos.status_code, 'AP02');
+    assert.strictEqual(lagos.execute_by, ts(2025, 3, 13, 8));
+    assert.strictEqual(newYork.status_code, 'AP02');
+    assert.strictEqual(newYork.execute_by, ts(2025, 3, 13, 13));
+  });
+
+  it('takes "tomorrow" from the local date, not the UTC one', async () => {
+    const lateEvening = new Date(Date.UTC(2025, 2, 12, 23, 30));
+    const result = await schedule('tomorrow at 9am', 'Asia/Tokyo', { now: lateEvening });
+    // 08:30 on the 13th in Tokyo, so tomorrow is the 14th
+    assert.strictEqual(result.execute_by, ts(2025, 3, 14, 0));
+  });
+
+  it('follows daylight saving on the scheduled day', async () => {
+    // New York moves from UTC-5 to UTC-4 on 2025-03-09
+    const before = new Date(Date.UTC(2025, 2, 1, 12));
+    const winter = await schedule('on 2025-03-08 at 9am', 'America/New_York', { now: before });
+    const summer = await schedule('on 2025-03-10 at 9am', 'America/New_York', { now: before });
+    assert.strictEqual(winter.execute_by, ts(2025, 3, 8, 14));
+    assert.strictEqual(summer.execute_by, ts(2025, 3, 10, 13));
+  });
+
+  it('starts a date without a time at local midnight', async () => {
+    const result = await schedule('on 2025-04-01', 'Africa/Nairobi');
+    assert.strictEqual(result.execute_by, ts(2025, 3, 31, 21));
+    const utc = await schedule('on 2025-04-01');
+    assert.strictEqual(utc.execute_by, ts(2025, 4, 1));
+  });
+
+  it('uses options.timeZone when the request names none', async () => {
+    const result = await schedule('tomorrow 9am', undefined, { timeZone: 'Africa/Lagos' });
+    assert.strictEqual(result.execute_by, ts(2025, 3, 13, 8));
+  });
+
+  it('fails an unknown timezone with DT04', async () => {
+    const result = await schedule('tomorrow 9am', 'Mars/Olympus_Mons');
+    assert.strictEqual(result.status, 'failed');
+    assert.strictEqual(result.status_code, 'DT04');
+    assert.strictEqual(
+      result.status_reason,
+      'Invalid timezone. Expected an IANA name such as Africa/Lagos: Mars/Olympus_Mons'
+    );
+    assert.strictEqual(zoneOffsetSeconds('Mars/Olympus_Mons', NOW.getTime()), null);
+    assert.strictEqual(zoneOffsetSeconds('Asia/Kolkata', NOW.getTime()), 19800);
+  });
+});
@@ -0,0 +1,58 @@
+const assert = require('assert');
+const paymentInstructions = require('@app/services/payment-instructions/payment-instructions');
+const processIdempotentInstruction = require('@app/services/payment-instructions/process-idempotent-instruction');
+const createSortableIdGenerator = require('@app/services/payment-instructions/id-generators/create-sortable-id-generator');
+const createMemoryIdempotencyStore = require('@app/services/payment-instructions/stores/create-memory-idempotency-store');
+
+describe('payment-instructions: transaction ids', () => {
+  const accounts = () => [
+    { id: 'acc1', balance: 500, currency: 'NGN' },
+    { id: 'acc2', balance: 0, currency: 'NGN' },
+  ];
+  const debit = (amount) => `DEBIT ${amount} NGN FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc2`;
+
+  it('gives distinct instructions distinct ids', async () => {
+    const first = await paymentInstructions({ accounts: accounts(), instruction: debit(100) });
+    const second = await paymentInstructions({ accounts: accounts(), instruction: debit(200) });
+    assert.strictEqual(typeof first.transaction_id, 'string');
+    assert.notStrictEqual(first.transaction_id, second.transaction_id);
+  });
+
+  it('puts an id on failed responses too', async () => {
+    const unparseable = await paymentInstructions({ accounts: accounts(), instruction: 'hello' });
+    assert.strictEqual(unparseable.status_code, 'SY01');
+    assert.strictEqual(typeof unparseable.transaction_id, 'string');
+    const declined = await paymentInstructions({ accounts: accounts(), instruction: debit(900) });
+    assert.strictEqual(declined.status_code, 'AC01');
+    assert.strictEqual(typeof declined.transaction_id, 'string');
+  });
+
+  it('makes sortable ids by default', () => {
+    let clock = 5000;
+    const generator = createSortableIdGenerator({ now: () => clock });
+    const ids = [generator.next(), generator.next()];
+    clock = 5001;
+    ids.push(generator.next());
+    assert.deepStrictEqual(ids.slice().sort(), ids);
+    assert.strictEqual(new Set(ids).size, 3);
+  });
+
+  it('uses an injected generator', async () => {
+    let count = 0;
+    const idGenerator = { next: () => `ulid-${++count}` };
+    const result = await paymentInstructions(
+      { accounts: accounts(), instruction: 'SPLIT 100 NGN FROM acc1 BETWEEN acc2' },
+      { idGenerator }
+    );
+    assert.strictEqual(result.type, 'SPLIT');
+    assert.ok(result.transaction_id.startsWith('ulid-'));
+  });
+
+  it('maps an idempotency key to one transaction id', async () => {
+    const options = { idempotencyStore: createMemoryIdempotencyStore() };
+    const request = { accounts: accounts(), instruction: debit(100), idempotency_key: 'k-1' };
+    const first = await processIdempotentInstruction(request, options);
+    const retry = await processIdempotentInstruction(request, options);
+    assert.strictEqual(retry.transaction_id, first.transaction_id);
+  });
+});
@@ -0,0 +1,62 @@
+const assert = require('assert');
+const paymentInstructions = require('@app/services/payment-instructions/payment-instructions');
+const { normalizeInstruction } = require('@app/services/payment-instructions/helpers');
+
+describe('payment-instructions: Unicode input', () => {
+  function makeAccounts() {
+    return [
+      { id: 'acc1', balance: 5000, currency: 'NGN' },
+      { id: 'acc2', balance: 0, currency: 'NGN' },
+    ];
+  }
+  function run(instruction, extra = {}) {
+    return paymentInstructions({ accounts: makeAccounts(), instruction, ...extra });
+  }
+
+  it('reads fullwidth digits as ASCII', async () => {
+    const result = await run('DEBIT １０００ NGN FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc2');
+    assert.strictEqual(result.status_code, 'AP00');
+    assert.strictEqual(result.amount, 1000);
+    const letters = await run('ＤＥＢＩＴ　１，０００．５ ＮＧＮ FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc2');
+    assert.strictEqual(letters.status_code, 'AP00');
+    assert.strictEqual(letters.amount, 1000.5);
+  });
+
+  it('reads Arabic-Indic and Persian digits as ASCII', async () => {
+    const arabic = await run('DEBIT ١٠٠٠ NGN FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc2');
+    assert.strictEqual(arabic.amount, 1000);
+    assert.strictEqual(arabic.status_code, 'AP00');
+    const persian = await run('DEBIT ۲۵۰٫۵ NGN FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc2');
+    assert.strictEqual(persian.amount, 250.5);
+  });
+
+  it('collapses no-break, zero-width and line-break whitespace', async () => {
+    const instruction =
+      'DEBIT\u00a0500 NGN\u200b FROM\nACCOUNT acc1 FOR CREDIT\tTO\u2003 ACCOUNT\u3000acc2';
+    const result = await run(instruction);
+    assert.strictEqual(result.status_code, 'AP00');
+    assert.deepStrictEqual(result.accounts.map((a) => a.balance), [4500, 500]);
+  });
+
+  it('keeps account ids and aliases matching as written', async () => {
+    const exact = await run('DEBIT 10 NGN FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc2');
+    assert.strictEqual(exact.debit_account, 'acc1');
+    assert.strictEqual(exact.credit_account, 'acc2');
+    // An alias name stored with fullwidth letters still matches the same name typed
+    const aliased = await run('DEBIT 10 NGN FROM ACCOUNT ｒｅｎｔ FOR CREDIT TO ACCOUNT épargne', {
+      aliases: { ｒｅｎｔ: 'acc1', Épargne: 'acc2' },
+    });
+    assert.strictEqual(aliased.status_code, 'AP00');
+    assert.strictEqual(aliased.debit_account, 'acc1');
+    assert.strictEqual(aliased.credit_account, 'acc2');
+    // Look-alike letters from other scripts are not folded: Cyrillic "а" is not "a"
+    const cyrillic = await run('DEBIT 10 NGN FROM ACCOUNT аcc1 FOR CREDIT TO ACCOUNT acc2');
+    assert.strictEqual(cyrillic.status_code, 'AC04');
+  });
+
+  it('is the identity on plain ASCII', () => {
+    const ascii = 'DEBIT 1,000.50 USD FROM ACCOUNT a.b-c@d FOR CREDIT TO ACCOUNT x';
+    assert.strictEqual(normalizeInstruction(ascii), ascii);
+    assert.strictEqual(normalizeInstruction('๑๒๓ १२'), '123 12');
+  });
+});
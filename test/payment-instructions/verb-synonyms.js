@@ -0,0 +1,65 @@
+const assert = require('assert');
+const paymentInstructions = require('@app/services/payment-instructions/payment-instructions');
+const { VERB_SYNONYMS } = require('@app/services/payment-instructions/helpers');
+
+describe('payment-instructions: verb synonyms', () => {
+  function makeAccounts() {
+    return [
+      { id: 'acc1', balance: 1000, currency: 'NGN' },
+      { id: 'acc2', balance: 0, currency: 'NGN' },
+    ];
+  }
+  function run(instruction, extra = {}) {
+    return paymentInstructions({ accounts: makeAccounts(), instruction, ...extra });
+  }
+
+  const cases = [
+    ['move', 'move 100 NGN from acc1 to acc2', 'TRANSFER'],
+    ['send', 'send 100 NGN from acc1 to acc2', 'TRANSFER'],
+    ['wire', 'Wire 100 NGN from account acc1 to account acc2', 'TRANSFER'],
+    ['remit', 'remit 100 NGN from acc1 to acc2', 'TRANSFER'],
+    ['collect', 'collect 100 NGN from acc1 to acc2', 'COLLECT'],
+    ['charge', 'charge acc1 100 NGN to acc2', 'COLLECT'],
+    ['settle', 'settle 100 NGN to DSTV from acc1', 'PAY'],
+  ];
+
+  it('covers every synonym in the table', () => {
+    assert.deepStrictEqual(cases.map((c) => c[0]).sort(), Object.keys(VERB_SYNONYMS).sort());
+  });
+
+  cases.forEach(([synonym, instruction, type]) => {
+    it(`reads "${synonym}" as ${type}`, async () => {
+      const result = await run(instruction);
+      assert.strictEqual(result.status_code, 'AP00');
+      assert.strictEqual(result.type, type);
+      assert.strictEqual(result.debit_account, 'acc1');
+      assert.strictEqual(result.accounts[0].balance, 900);
+      assert.strictEqual(result.confidence, 1);
+    });
+  });
+
+  it('keeps the TRANSFER forms a synonym stands in for', async () => {
+    const scheduled = await paymentInstructions(
+      {
+        accounts: makeAccounts(),
+        instruction: 'SCHEDULE send 100 NGN FROM acc1 TO acc2 ON 2025-04-01',
+      },
+      { now: Date.UTC(2025, 2, 12) }
+    );
+    assert.strictEqual(scheduled.type, 'SCHEDULE');
+    assert.strictEqual(scheduled.status_code, 'AP02');
+    const multi = await paymentInstructions({
+      accounts: [...makeAccounts(), { id: 'acc3', balance: 500, currency: 'NGN' }],
+      instruction: 'move 1200 NGN to acc2 from acc1 and acc3',
+    });
+    assert.strictEqual(multi.type, 'MULTI_DEBIT');
+    assert.strictEqual(multi.status_code, 'AP00');
+  });
+
+  it('lowers the confidence only for a mistyped synonym', async () => {
+    const result = await run('sned 100 NGN from acc1 to acc2', { fuzzy_keywords: true });
+    assert.strictEqual(result.status_code, 'AP00');
+    assert.strictEqual(result.type, 'TRANSFER');
+    assert.ok(result.confidence < 1);
+  });
+});
@@ -0,0 +1,95 @@
+const assert = require('assert');
+const paymentInstructions = require('@app/services/payment-instructions/payment-instructions');
+const processBatch = require('@app/services/payment-instructions/process-batch');
+const processStoredInstruction = require('@app/services/payment-instructions/process-stored-instruction');
+const createMemoryAccountStore = require('@app/services/payment-instructions/stores/create-memory-account-store');
+const { selectWalletPockets } = require('@app/services/payment-instructions/helpers');
+
+describe('payment-instructions: multi-currency wallets', () => {
+  function makeAccounts() {
+    return [
+      { id: 'wallet1', balances: { NGN: 5000, USD: 100 } },
+      { id: 'acc2', balance: 0, currency: 'USD' },
+      { id: 'acc3', balance: 0, currency: 'EUR' },
+    ];
+  }
+  function run(instruction, extra = {}) {
+    return paymentInstructions({ accounts: makeAccounts(), instruction, ...extra });
+  }
+
+  it('debits the pocket in the instruction currency and leaves the others alone', async () => {
+    const result = await run('transfer 40 USD from wallet1 to acc2');
+    assert.strictEqual(result.status_code, 'AP00');
+    assert.deepStrictEqual(result.accounts, [
+      { id: 'wallet1', balance: 60, balance_before: 100, currency: 'USD' },
+      { id: 'acc2', balance: 40, balance_before: 0, currency: 'USD' },
+    ]);
+    const naira = await run('withdraw 1000 NGN from wallet1');
+    assert.deepStrictEqual(naira.accounts, [
+      { id: 'wallet1', balance: 4000, balance_before: 5000, currency: 'NGN' },
+    ]);
+  });
+
+  it('fails with AC11 when the wallet has no pocket in the currency', async () => {
+    const result = await run('transfer 40 EUR from wallet1 to acc3');
+    assert.strictEqual(result.status, 'failed');
+    assert.strictEqual(result.status_code, 'AC11');
+    assert.strictEqual(
+      result.status_reason,
+      'No wallet pocket in the instruction currency: wallet1 has no EUR pocket (holds NGN, USD)'
+    );
+    assert.strictEqual(result.reason_category, 'account');
+    assert.deepStrictEqual(result.accounts, []);
+  });
+
+  it('checks a pocket against its own balance and selects pockets ignoring case', async () => {
+    const short = await run('transfer 200 USD from wallet1 to acc2');
+    assert.strictEqual(short.status_code, 'AC01');
+    const all = await run('DEBIT 10 EUR FROM ACCOUNT acc3 FOR CREDIT TO ACCOUNT acc3', {
+      include_all_accounts: true,
+    });
+    assert.strictEqual(all.status_code, 'AC02');
+    assert.deepStrictEqual(
+      selectWalletPockets(makeAccounts(), 'usd')[0],
+      { id: 'wallet1', balance: 100, currency: 'USD' }
+    );
+  });
+
+  it('runs wallets through a batch, carrying each pocket between shared instructions', async () => {
+    const shared = await processBatch({
+      accounts: makeAccounts(),
+      instructions: ['transfer 40 USD from wallet1 to acc2', 'withdraw 1000 NGN from wallet1'],
+    });
+    assert.deepStrictEqual(shared.results.map((r) => r.status_code), ['AP00', 'AP00']);
+    assert.deepStrictEqual(shared.accounts[0], {
+      id: 'wallet1',
+      balances: { NGN: 4000, USD: 60 },
+    });
+    assert.strictEqual(shared.accounts[1].balance, 40);
+    const items = await processBatch({
+      items: [{ accounts: makeAccounts(), instruction: 'transfer 40 EUR from wallet1 to acc3' }],
+    });
+    assert.strictEqual(items.results[0].status_code, 'AC11');
+    await assert.rejects(
+      processBatch({
+        accounts: [{ id: 'acc1', currency: 'USD' }],
+        instructions: ['withdraw 10 USD from acc1'],
+      }),
+      (err) => err.errorCode === 'VALIDATION_ERROR'
+    );
+  });
+
+  it('writes a stored wallet back to the pocket it moved', async () => {
+    const accountStore = createMemoryAccountStore(makeAccounts());
+    const result = await processStoredInstruction(
+      { instruction: 'transfer 40 USD from wallet1 to acc2' },
+      { accountStore }
+    );
+    assert.strictEqual(result.status_code, 'AP00');
+    assert.deepStrictEqual(await accountStore.getAccount('wallet1'), {
+      id: 'wallet1',
+      balances: { NGN: 5000, USD: 60 },
+    });
+    assert.strictEqual((await accountStore.getAccount('acc2')).balance, 40);
+  });
+});
@@ -0,0 +1,59 @@
+const assert = require('assert');
+const paymentInstructions = require('@app/services/payment-instructions/payment-instructions');
+const parseInstruction = require('@app/services/payment-instructions/parse-instruction');
+
+describe('payment-instructions: warnings', () => {
+  function makeAccounts() {
+    return [
+      { id: 'acc1', balance: 100, currency: 'NGN' },
+      { id: 'acc2', balance: 0, currency: 'NGN' },
+      { id: 'acc3', balance: 0, currency: 'NGN' },
+    ];
+  }
+  function run(instruction, extra = {}) {
+    return paymentInstructions({ accounts: makeAccounts(), instruction, ...extra });
+  }
+
+  it('warns about an inferred currency and a rounded amount without failing', async () => {
+    const result = await run('transfer a third from acc1 to acc2');
+    assert.strictEqual(result.status, 'successful');
+    assert.strictEqual(result.status_code, 'AP00');
+    assert.strictEqual(result.amount, 33.33);
+    assert.strictEqual(result.currency, 'NGN');
+    assert.deepStrictEqual(result.warnings, [
+      { code: 'currency_inferred', message: 'Currency was inferred; check it is the one meant' },
+      { code: 'amount_rounded', message: "Amount was rounded to the currency's minor units" },
+    ]);
+  });
+
+  it('notes an alias and a corrected keyword once each', async () => {
+    const result = await run('trasnfer 10 NGN from acc1 to rent', {
+      aliases: { rent: 'acc2' },
+      fuzzy_keywords: true,
+    });
+    assert.strictEqual(result.status_code, 'AP00');
+    assert.deepStrictEqual(result.warnings.map((w) => w.code), ['keyword_corrected', 'alias_used']);
+  });
+
+  it('leaves the field off a literal instruction and an exact share', async () => {
+    const literal = await run('DEBIT 10 NGN FROM ACCOUNT acc1 FOR CREDIT TO ACCOUNT acc2');
+    assert.strictEqual(literal.status_code, 'AP00');
+    assert.strictEqual('warnings' in literal, false);
+    const half = await run('transfer half NGN from acc1 to acc2');
+    assert.strictEqual(half.amount, 50);
+    assert.strictEqual('warnings' in half, false);
+  });
+
+  it('carries the warnings on split and parsed results', async () => {
+    const split = await run('split a third from acc1 equally between acc2 and acc3');
+    assert.strictEqual(split.status_code, 'AP00');
+    assert.deepStrictEqual(split.warnings.map((w) => w.code), [
+      'currency_inferred',
+      'amount_rounded',
+    ]);
+    const parsed = await parseInstruction('transfer 10 from acc1 to acc2', {
+      accounts: makeAccounts(),
+    });
+    assert.deepStrictEqual(parsed.warnings.map((w) => w.code), ['currency_inferred']);
+  });
+});